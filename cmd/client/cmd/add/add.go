@@ -8,6 +8,8 @@ import (
 const (
 	markFlag        = "mark"
 	descriptionFlag = "description"
+	folderFlag      = "folder"
+	tagFlag         = "tag"
 )
 
 // addCmd represents the add command.
@@ -22,9 +24,22 @@ func init() {
 
 	addCmd.PersistentFlags().StringP(markFlag, "m", "", "Пометка для объекта данных")
 	addCmd.PersistentFlags().StringP(descriptionFlag, "d", "", "Дополнительное описание для объекта данных")
+	addCmd.PersistentFlags().String(folderFlag, "", "Папка для объекта данных, вложенные папки разделяются /")
+	addCmd.PersistentFlags().StringSlice(tagFlag, nil, "Метки объекта данных (флаг можно повторять)")
 	_ = addCmd.MarkPersistentFlagRequired(markFlag)
 }
 
+// getLabels возвращает папку и метки объекта данных из флагов команды.
+func getLabels(cmd *cobra.Command) (string, []string) {
+	folder, _ := cmd.Flags().GetString(folderFlag)
+	tags, _ := cmd.Flags().GetStringSlice(tagFlag)
+	if len(tags) == 0 {
+		tags = nil
+	}
+
+	return folder, tags
+}
+
 func printFailed(cmd *cobra.Command, err error) {
 	cmd.Printf("Failed: %s", err)
 }
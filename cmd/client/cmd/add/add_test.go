@@ -0,0 +1,15 @@
+package add
+
+import "github.com/spf13/pflag"
+
+// resetAddFlags сбрасывает значения общих флагов команды add, которые сохраняются между запусками в тестах.
+func resetAddFlags() {
+	addCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			_ = v.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
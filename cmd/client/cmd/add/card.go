@@ -19,6 +19,7 @@ var cardCmd = &cobra.Command{
 		cvv2, _ := cmd.Flags().GetString("cvv2")
 		mark, _ := cmd.Flags().GetString(markFlag)
 		description, _ := cmd.Flags().GetString(descriptionFlag)
+		folder, tags := getLabels(cmd)
 
 		req := models.AddCardRequest{
 			Number:      number,
@@ -27,6 +28,8 @@ var cardCmd = &cobra.Command{
 			CVV2:        cvv2,
 			Mark:        mark,
 			Description: description,
+			Folder:      folder,
+			Tags:        tags,
 		}
 
 		if err := root.Services.AddCard(&req); err != nil {
@@ -2,6 +2,7 @@ package add
 
 import (
 	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/spf13/cobra"
 )
 
@@ -15,8 +16,16 @@ var fileCmd = &cobra.Command{
 		file, _ := cmd.Flags().GetString("file")
 		mark, _ := cmd.Flags().GetString(markFlag)
 		description, _ := cmd.Flags().GetString(descriptionFlag)
+		folder, tags := getLabels(cmd)
 
-		if err := root.Services.AddFile(file, mark, description); err != nil {
+		req := models.AddFileRequest{
+			Mark:        mark,
+			Description: description,
+			Folder:      folder,
+			Tags:        tags,
+		}
+
+		if err := root.Services.AddFile(file, req); err != nil {
 			printFailed(cmd, err)
 			return
 		}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
 	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +23,7 @@ func TestAddFileCmd(t *testing.T) {
 	description := "test"
 
 	type addFile struct {
+		req models.AddFileRequest
 		err error
 	}
 	tests := []struct {
@@ -34,6 +36,18 @@ func TestAddFileCmd(t *testing.T) {
 			name: "add file success",
 			args: []string{"add", "file", "-f", file, "-m", mark, "-d", description},
 			addFile: addFile{
+				req: models.AddFileRequest{Mark: mark, Description: description},
+				err: nil,
+			},
+			output: "Add file OK\n",
+		},
+		{
+			name: "add file with folder and tags success",
+			args: []string{
+				"add", "file", "-f", file, "-m", mark, "--folder", "work/docs", "--tag", "prod", "--tag", "team,scan",
+			},
+			addFile: addFile{
+				req: models.AddFileRequest{Mark: mark, Folder: "work/docs", Tags: []string{"prod", "team", "scan"}},
 				err: nil,
 			},
 			output: "Add file OK\n",
@@ -42,6 +56,7 @@ func TestAddFileCmd(t *testing.T) {
 			name: "add file failed",
 			args: []string{"add", "file", "-f", file, "-m", mark, "-d", description},
 			addFile: addFile{
+				req: models.AddFileRequest{Mark: mark, Description: description},
 				err: errors.New("some error"),
 			},
 			output: "Failed: some error",
@@ -49,7 +64,10 @@ func TestAddFileCmd(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s.EXPECT().AddFile(file, mark, description).Times(1).Return(test.addFile.err)
+			resetAddFlags()
+			t.Cleanup(resetAddFlags)
+
+			s.EXPECT().AddFile(file, test.addFile.req).Times(1).Return(test.addFile.err)
 
 			cmd.RootCmd.SetArgs(test.args)
 
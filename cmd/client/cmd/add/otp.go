@@ -0,0 +1,63 @@
+package add
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// otpCmd represents the otp command.
+var otpCmd = &cobra.Command{
+	Use:   "otp",
+	Short: "Загрузить секрет TOTP",
+	Long: `Загрузить секрет для одноразовых паролей (TOTP) на сервер.
+Секрет можно передать ссылкой otpauth:// из QR-кода или отдельными параметрами`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		uri, _ := cmd.Flags().GetString("uri")
+		issuer, _ := cmd.Flags().GetString("issuer")
+		account, _ := cmd.Flags().GetString("account")
+		secret, _ := cmd.Flags().GetString("secret")
+		algorithm, _ := cmd.Flags().GetString("algorithm")
+		digits, _ := cmd.Flags().GetInt("digits")
+		period, _ := cmd.Flags().GetInt("period")
+		mark, _ := cmd.Flags().GetString(markFlag)
+		description, _ := cmd.Flags().GetString(descriptionFlag)
+		folder, tags := getLabels(cmd)
+
+		req := models.AddOTPRequest{
+			URI:         uri,
+			Issuer:      issuer,
+			Account:     account,
+			Secret:      secret,
+			Algorithm:   algorithm,
+			Digits:      digits,
+			Period:      period,
+			Mark:        mark,
+			Description: description,
+			Folder:      folder,
+			Tags:        tags,
+		}
+
+		if err := root.Services.AddOTP(req); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Add otp OK")
+	},
+}
+
+func init() {
+	addCmd.AddCommand(otpCmd)
+
+	otpCmd.Flags().StringP("uri", "u", "", "Ссылка otpauth:// из QR-кода")
+	otpCmd.Flags().StringP("secret", "s", "", "Секрет в base32")
+	otpCmd.Flags().String("issuer", "", "Название сервиса")
+	otpCmd.Flags().String("account", "", "Имя учетной записи")
+	otpCmd.Flags().String("algorithm", "", "Алгоритм HMAC: SHA1, SHA256 или SHA512 (по умолчанию SHA1)")
+	otpCmd.Flags().Int("digits", 0, "Количество цифр в пароле (по умолчанию 6)")
+	otpCmd.Flags().Int("period", 0, "Время действия пароля в секундах (по умолчанию 30)")
+	otpCmd.MarkFlagsOneRequired("uri", "secret")
+	otpCmd.MarkFlagsMutuallyExclusive("uri", "secret")
+}
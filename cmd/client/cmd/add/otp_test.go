@@ -0,0 +1,76 @@
+package add
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddOTPCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	req := models.AddOTPRequest{
+		Issuer:      "GitHub",
+		Account:     "bot",
+		Secret:      "SECRET",
+		Algorithm:   "SHA256",
+		Digits:      8,
+		Period:      60,
+		Mark:        "test",
+		Description: "test",
+	}
+	args := []string{
+		"add", "otp", "-s", "SECRET", "--issuer", "GitHub", "--account", "bot",
+		"--algorithm", "SHA256", "--digits", "8", "--period", "60", "-m", "test", "-d", "test",
+	}
+
+	type addOTP struct {
+		err error
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		addOTP addOTP
+		output string
+	}{
+		{
+			name: "add otp success",
+			args: args,
+			addOTP: addOTP{
+				err: nil,
+			},
+			output: "Add otp OK\n",
+		},
+		{
+			name: "add otp failed",
+			args: args,
+			addOTP: addOTP{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().AddOTP(req).Times(1).Return(test.addOTP.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -17,12 +17,15 @@ var passwordCmd = &cobra.Command{
 		password, _ := cmd.Flags().GetString("password")
 		mark, _ := cmd.Flags().GetString(markFlag)
 		description, _ := cmd.Flags().GetString(descriptionFlag)
+		folder, tags := getLabels(cmd)
 
 		req := models.AddPasswordRequest{
 			Login:       login,
 			Password:    password,
 			Mark:        mark,
 			Description: description,
+			Folder:      folder,
+			Tags:        tags,
 		}
 
 		if err := root.Services.AddPassword(req); err != nil {
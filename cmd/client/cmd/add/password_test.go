@@ -25,7 +25,12 @@ func TestAddPasswordCmd(t *testing.T) {
 		Description: "test",
 	}
 
+	labeledReq := req
+	labeledReq.Folder = "work/aws"
+	labeledReq.Tags = []string{"prod"}
+
 	type addPassword struct {
+		req models.AddPasswordRequest
 		err error
 	}
 	tests := []struct {
@@ -38,6 +43,18 @@ func TestAddPasswordCmd(t *testing.T) {
 			name: "add password success",
 			args: []string{"add", "password", "-l", "test", "-p", "test", "-m", "test", "-d", "test"},
 			addPassword: addPassword{
+				req: req,
+				err: nil,
+			},
+			output: "Add password OK\n",
+		},
+		{
+			name: "add password with folder and tags success",
+			args: []string{
+				"add", "password", "-l", "test", "-p", "test", "-m", "test", "-d", "test", "--folder", "work/aws", "--tag", "prod",
+			},
+			addPassword: addPassword{
+				req: labeledReq,
 				err: nil,
 			},
 			output: "Add password OK\n",
@@ -46,6 +63,7 @@ func TestAddPasswordCmd(t *testing.T) {
 			name: "add password failed",
 			args: []string{"add", "password", "-l", "test", "-p", "test", "-m", "test", "-d", "test"},
 			addPassword: addPassword{
+				req: req,
 				err: errors.New("some error"),
 			},
 			output: "Failed: some error",
@@ -53,7 +71,10 @@ func TestAddPasswordCmd(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s.EXPECT().AddPassword(req).Times(1).Return(test.addPassword.err)
+			resetAddFlags()
+			t.Cleanup(resetAddFlags)
+
+			s.EXPECT().AddPassword(test.addPassword.req).Times(1).Return(test.addPassword.err)
 
 			cmd.RootCmd.SetArgs(test.args)
 
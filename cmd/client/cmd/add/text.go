@@ -16,11 +16,14 @@ var textCmd = &cobra.Command{
 		text, _ := cmd.Flags().GetString("text")
 		mark, _ := cmd.Flags().GetString(markFlag)
 		description, _ := cmd.Flags().GetString(descriptionFlag)
+		folder, tags := getLabels(cmd)
 
 		req := models.AddTextRequest{
 			Data:        text,
 			Mark:        mark,
 			Description: description,
+			Folder:      folder,
+			Tags:        tags,
 		}
 
 		if err := root.Services.AddText(req); err != nil {
@@ -0,0 +1,26 @@
+package del
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/spf13/cobra"
+)
+
+// cardCmd represents the card command.
+var cardCmd = &cobra.Command{
+	Use:   "card [ID]",
+	Short: "Удалить банковскую карту",
+	Long:  "Удалить банковскую карту по ее идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := root.Services.DeleteCard(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Delete card OK")
+	},
+}
+
+func init() {
+	deleteCmd.AddCommand(cardCmd)
+}
@@ -0,0 +1,60 @@
+package del
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteCardCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type deleteCard struct {
+		err error
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		deleteCard deleteCard
+		output     string
+	}{
+		{
+			name: "delete card success",
+			args: []string{"delete", "card", "1"},
+			deleteCard: deleteCard{
+				err: nil,
+			},
+			output: "Delete card OK\n",
+		},
+		{
+			name: "delete card failed",
+			args: []string{"delete", "card", "1"},
+			deleteCard: deleteCard{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteCard("1").Times(1).Return(test.deleteCard.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package del
+
+import (
+	"errors"
+
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services"
+	"github.com/spf13/cobra"
+)
+
+// deleteCmd represents the delete command.
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Удалить данные",
+	Long:  "Удалить данные с сервера и из локального кеша",
+}
+
+func init() {
+	root.RootCmd.AddCommand(deleteCmd)
+}
+
+// printFailed выводит ошибку команды. Изменение, сохраненное без связи с сервером, ошибкой не считается.
+func printFailed(cmd *cobra.Command, err error) {
+	if errors.Is(err, services.ErrChangeQueued) {
+		cmd.Printf("Queued: %s\n", err)
+		return
+	}
+
+	cmd.Printf("Failed: %s", err)
+}
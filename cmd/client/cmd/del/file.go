@@ -0,0 +1,26 @@
+package del
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/spf13/cobra"
+)
+
+// fileCmd represents the file command.
+var fileCmd = &cobra.Command{
+	Use:   "file [ID]",
+	Short: "Удалить файл",
+	Long:  "Удалить файл по его ID, метку можно указать вместо ID, если она есть только у одного файла",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := root.Services.DeleteFile(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Delete file OK")
+	},
+}
+
+func init() {
+	deleteCmd.AddCommand(fileCmd)
+}
@@ -0,0 +1,60 @@
+package del
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteFileCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type deleteFile struct {
+		err error
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		deleteFile deleteFile
+		output     string
+	}{
+		{
+			name: "delete file success",
+			args: []string{"delete", "file", "test"},
+			deleteFile: deleteFile{
+				err: nil,
+			},
+			output: "Delete file OK\n",
+		},
+		{
+			name: "delete file failed",
+			args: []string{"delete", "file", "test"},
+			deleteFile: deleteFile{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteFile("test").Times(1).Return(test.deleteFile.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package del
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/spf13/cobra"
+)
+
+// otpCmd represents the otp command.
+var otpCmd = &cobra.Command{
+	Use:   "otp [ID]",
+	Short: "Удалить секрет TOTP",
+	Long:  "Удалить секрет TOTP по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := root.Services.DeleteOTP(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Delete otp OK")
+	},
+}
+
+func init() {
+	deleteCmd.AddCommand(otpCmd)
+}
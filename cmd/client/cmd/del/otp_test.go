@@ -0,0 +1,60 @@
+package del
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteOTPCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type deleteOTP struct {
+		err error
+	}
+	tests := []struct {
+		name      string
+		args      []string
+		deleteOTP deleteOTP
+		output    string
+	}{
+		{
+			name: "delete otp success",
+			args: []string{"delete", "otp", "1"},
+			deleteOTP: deleteOTP{
+				err: nil,
+			},
+			output: "Delete otp OK\n",
+		},
+		{
+			name: "delete otp failed",
+			args: []string{"delete", "otp", "1"},
+			deleteOTP: deleteOTP{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteOTP("1").Times(1).Return(test.deleteOTP.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
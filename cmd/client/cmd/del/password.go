@@ -0,0 +1,26 @@
+package del
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/spf13/cobra"
+)
+
+// passwordCmd represents the password command.
+var passwordCmd = &cobra.Command{
+	Use:   "password [ID]",
+	Short: "Удалить логин-пароль",
+	Long:  "Удалить логин-пароль по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := root.Services.DeletePassword(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Delete password OK")
+	},
+}
+
+func init() {
+	deleteCmd.AddCommand(passwordCmd)
+}
@@ -0,0 +1,69 @@
+package del
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletePasswordCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type deletePassword struct {
+		err error
+	}
+	tests := []struct {
+		name           string
+		args           []string
+		deletePassword deletePassword
+		output         string
+	}{
+		{
+			name: "delete password success",
+			args: []string{"delete", "password", "1"},
+			deletePassword: deletePassword{
+				err: nil,
+			},
+			output: "Delete password OK\n",
+		},
+		{
+			name: "delete password failed",
+			args: []string{"delete", "password", "1"},
+			deletePassword: deletePassword{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+		{
+			name: "delete password queued without server",
+			args: []string{"delete", "password", "1"},
+			deletePassword: deletePassword{
+				err: services.ErrChangeQueued,
+			},
+			output: "Queued: " + services.ErrChangeQueued.Error() + "\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeletePassword("1").Times(1).Return(test.deletePassword.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
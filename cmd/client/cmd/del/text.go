@@ -0,0 +1,26 @@
+package del
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/spf13/cobra"
+)
+
+// textCmd represents the text command.
+var textCmd = &cobra.Command{
+	Use:   "text [ID]",
+	Short: "Удалить текст",
+	Long:  "Удалить текст по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := root.Services.DeleteText(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Delete text OK")
+	},
+}
+
+func init() {
+	deleteCmd.AddCommand(textCmd)
+}
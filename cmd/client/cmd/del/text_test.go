@@ -0,0 +1,60 @@
+package del
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteTextCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type deleteText struct {
+		err error
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		deleteText deleteText
+		output     string
+	}{
+		{
+			name: "delete text success",
+			args: []string{"delete", "text", "1"},
+			deleteText: deleteText{
+				err: nil,
+			},
+			output: "Delete text OK\n",
+		},
+		{
+			name: "delete text failed",
+			args: []string{"delete", "text", "1"},
+			deleteText: deleteText{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteText("1").Times(1).Return(test.deleteText.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package edit
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// cardCmd represents the card command.
+var cardCmd = &cobra.Command{
+	Use:   "card [ID]",
+	Short: "Изменить данные банковской карты",
+	Long:  "Изменить данные банковской карты по ее идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := models.UpdateCardRequest{
+			Number:      changedFlag(cmd, "number"),
+			Owner:       changedFlag(cmd, "owner"),
+			ExpiryDate:  changedFlag(cmd, "expiry-date"),
+			CVV2:        changedFlag(cmd, "cvv2"),
+			Mark:        changedFlag(cmd, markFlag),
+			Description: changedFlag(cmd, descriptionFlag),
+		}
+
+		if err := root.Services.EditCard(args[0], req); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Edit card OK")
+	},
+}
+
+func init() {
+	editCmd.AddCommand(cardCmd)
+
+	cardCmd.Flags().StringP("number", "n", "", "Новый номер карты")
+	cardCmd.Flags().StringP("owner", "o", "", "Новый владелец карты")
+	cardCmd.Flags().StringP("expiry-date", "e", "", "Новая дата окончания карты")
+	cardCmd.Flags().String("cvv2", "", "Новый CVV2 карты")
+}
@@ -0,0 +1,64 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditCardCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	value := "test"
+	req := models.UpdateCardRequest{Owner: &value, CVV2: &value, Mark: &value, Description: &value}
+
+	type editCard struct {
+		err error
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		editCard editCard
+		output   string
+	}{
+		{
+			name: "edit card success",
+			args: []string{"edit", "card", "1", "-o", "test", "--cvv2", "test", "-m", "test", "-d", "test"},
+			editCard: editCard{
+				err: nil,
+			},
+			output: "Edit card OK\n",
+		},
+		{
+			name: "edit card failed",
+			args: []string{"edit", "card", "1", "-o", "test", "--cvv2", "test", "-m", "test", "-d", "test"},
+			editCard: editCard{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EditCard("1", req).Times(1).Return(test.editCard.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
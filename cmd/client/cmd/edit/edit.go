@@ -0,0 +1,49 @@
+package edit
+
+import (
+	"errors"
+
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services"
+	"github.com/spf13/cobra"
+)
+
+const (
+	markFlag        = "mark"
+	descriptionFlag = "description"
+)
+
+// editCmd represents the edit command.
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Изменить данные",
+	Long:  "Изменить данные на сервере, передаются только указанные поля",
+}
+
+func init() {
+	root.RootCmd.AddCommand(editCmd)
+
+	editCmd.PersistentFlags().StringP(markFlag, "m", "", "Новая пометка для объекта данных")
+	editCmd.PersistentFlags().StringP(descriptionFlag, "d", "", "Новое описание для объекта данных")
+}
+
+// changedFlag возвращает значение флага, если он был указан, иначе nil.
+func changedFlag(cmd *cobra.Command, name string) *string {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+
+	value, _ := cmd.Flags().GetString(name)
+
+	return &value
+}
+
+// printFailed выводит ошибку команды. Изменение, сохраненное без связи с сервером, ошибкой не считается.
+func printFailed(cmd *cobra.Command, err error) {
+	if errors.Is(err, services.ErrChangeQueued) {
+		cmd.Printf("Queued: %s\n", err)
+		return
+	}
+
+	cmd.Printf("Failed: %s", err)
+}
@@ -0,0 +1,36 @@
+package edit
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// fileCmd represents the file command.
+var fileCmd = &cobra.Command{
+	Use:   "file [ID]",
+	Short: "Изменить файл",
+	Long:  "Изменить файл по его ID или уникальной метке, без флага --file меняются только метка и описание",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+
+		req := models.UpdateFileRequest{
+			Mark:        changedFlag(cmd, markFlag),
+			Description: changedFlag(cmd, descriptionFlag),
+		}
+
+		if err := root.Services.EditFile(args[0], file, req); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Edit file OK")
+	},
+}
+
+func init() {
+	editCmd.AddCommand(fileCmd)
+
+	fileCmd.Flags().StringP("file", "f", "", "Новый файл для сохранения")
+}
@@ -0,0 +1,64 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditFileCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	value := "test"
+	req := models.UpdateFileRequest{Mark: &value, Description: &value}
+
+	type editFile struct {
+		err error
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		editFile editFile
+		output   string
+	}{
+		{
+			name: "edit file success",
+			args: []string{"edit", "file", "test", "-f", "file.txt", "-m", "test", "-d", "test"},
+			editFile: editFile{
+				err: nil,
+			},
+			output: "Edit file OK\n",
+		},
+		{
+			name: "edit file failed",
+			args: []string{"edit", "file", "test", "-f", "file.txt", "-m", "test", "-d", "test"},
+			editFile: editFile{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EditFile("test", "file.txt", req).Times(1).Return(test.editFile.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
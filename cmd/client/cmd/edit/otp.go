@@ -0,0 +1,35 @@
+package edit
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// otpCmd represents the otp command.
+var otpCmd = &cobra.Command{
+	Use:   "otp [ID]",
+	Short: "Изменить секрет TOTP",
+	Long:  "Изменить секрет TOTP по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := models.UpdateOTPRequest{
+			URI:         changedFlag(cmd, "uri"),
+			Mark:        changedFlag(cmd, markFlag),
+			Description: changedFlag(cmd, descriptionFlag),
+		}
+
+		if err := root.Services.EditOTP(args[0], req); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Edit otp OK")
+	},
+}
+
+func init() {
+	editCmd.AddCommand(otpCmd)
+
+	otpCmd.Flags().StringP("uri", "u", "", "Новая ссылка otpauth://")
+}
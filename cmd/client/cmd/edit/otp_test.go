@@ -0,0 +1,64 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditOTPCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	value := "test"
+	req := models.UpdateOTPRequest{URI: &value, Mark: &value, Description: &value}
+
+	type editOTP struct {
+		err error
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		editOTP editOTP
+		output  string
+	}{
+		{
+			name: "edit otp success",
+			args: []string{"edit", "otp", "1", "-u", "test", "-m", "test", "-d", "test"},
+			editOTP: editOTP{
+				err: nil,
+			},
+			output: "Edit otp OK\n",
+		},
+		{
+			name: "edit otp failed",
+			args: []string{"edit", "otp", "1", "-u", "test", "-m", "test", "-d", "test"},
+			editOTP: editOTP{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EditOTP("1", req).Times(1).Return(test.editOTP.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
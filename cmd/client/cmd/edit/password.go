@@ -0,0 +1,37 @@
+package edit
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// passwordCmd represents the password command.
+var passwordCmd = &cobra.Command{
+	Use:   "password [ID]",
+	Short: "Изменить данные логин-пароля",
+	Long:  "Изменить данные логин-пароля по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := models.UpdatePasswordRequest{
+			Login:       changedFlag(cmd, "login"),
+			Password:    changedFlag(cmd, "password"),
+			Mark:        changedFlag(cmd, markFlag),
+			Description: changedFlag(cmd, descriptionFlag),
+		}
+
+		if err := root.Services.EditPassword(args[0], req); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Edit password OK")
+	},
+}
+
+func init() {
+	editCmd.AddCommand(passwordCmd)
+
+	passwordCmd.Flags().StringP("login", "l", "", "Новый логин")
+	passwordCmd.Flags().StringP("password", "p", "", "Новый пароль")
+}
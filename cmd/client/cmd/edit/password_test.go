@@ -0,0 +1,73 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditPasswordCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	value := "test"
+	req := models.UpdatePasswordRequest{Password: &value, Mark: &value, Description: &value}
+
+	type editPassword struct {
+		err error
+	}
+	tests := []struct {
+		name         string
+		args         []string
+		editPassword editPassword
+		output       string
+	}{
+		{
+			name: "edit password success",
+			args: []string{"edit", "password", "1", "-p", "test", "-m", "test", "-d", "test"},
+			editPassword: editPassword{
+				err: nil,
+			},
+			output: "Edit password OK\n",
+		},
+		{
+			name: "edit password failed",
+			args: []string{"edit", "password", "1", "-p", "test", "-m", "test", "-d", "test"},
+			editPassword: editPassword{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+		{
+			name: "edit password queued without server",
+			args: []string{"edit", "password", "1", "-p", "test", "-m", "test", "-d", "test"},
+			editPassword: editPassword{
+				err: services.ErrChangeQueued,
+			},
+			output: "Queued: " + services.ErrChangeQueued.Error() + "\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EditPassword("1", req).Times(1).Return(test.editPassword.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
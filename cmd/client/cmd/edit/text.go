@@ -0,0 +1,35 @@
+package edit
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// textCmd represents the text command.
+var textCmd = &cobra.Command{
+	Use:   "text [ID]",
+	Short: "Изменить текст",
+	Long:  "Изменить текст по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		req := models.UpdateTextRequest{
+			Data:        changedFlag(cmd, "text"),
+			Mark:        changedFlag(cmd, markFlag),
+			Description: changedFlag(cmd, descriptionFlag),
+		}
+
+		if err := root.Services.EditText(args[0], req); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Edit text OK")
+	},
+}
+
+func init() {
+	editCmd.AddCommand(textCmd)
+
+	textCmd.Flags().StringP("text", "t", "", "Новый текст")
+}
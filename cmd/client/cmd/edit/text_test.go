@@ -0,0 +1,64 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditTextCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	value := "test"
+	req := models.UpdateTextRequest{Data: &value, Mark: &value, Description: &value}
+
+	type editText struct {
+		err error
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		editText editText
+		output   string
+	}{
+		{
+			name: "edit text success",
+			args: []string{"edit", "text", "1", "-t", "test", "-m", "test", "-d", "test"},
+			editText: editText{
+				err: nil,
+			},
+			output: "Edit text OK\n",
+		},
+		{
+			name: "edit text failed",
+			args: []string{"edit", "text", "1", "-t", "test", "-m", "test", "-d", "test"},
+			editText: editText{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EditText("1", req).Times(1).Return(test.editText.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
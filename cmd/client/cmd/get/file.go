@@ -1,20 +1,27 @@
 package get
 
 import (
+	"fmt"
+	"io"
+
 	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
 	"github.com/spf13/cobra"
 )
 
 // fileCmd represents the file command.
 var fileCmd = &cobra.Command{
-	Use:   "file [MARK]",
+	Use:   "file [ID]",
 	Short: "Получить файл",
-	Long:  "Получить файл по его метке (MARK)",
+	Long:  "Получить файл по его ID, метку можно указать вместо ID, если она есть только у одного файла",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		fileMark := args[0]
+		fileRef := args[0]
 		dir, _ := cmd.Flags().GetString("upload-dir")
-		if err := root.Services.GetFile(fileMark, dir); err != nil {
+
+		progress := downloadProgress(cmd.ErrOrStderr())
+		err := root.Services.GetFile(fileRef, dir, progress.report)
+		progress.finish()
+		if err != nil {
 			printFailed(cmd, err)
 			return
 		}
@@ -28,3 +35,44 @@ func init() {
 
 	fileCmd.Flags().StringP("upload-dir", "u", ".", "upload file directory (default is ${pwd})")
 }
+
+// progressPrinter выводит ход загрузки файла одной обновляемой строкой.
+type progressPrinter struct {
+	w       io.Writer
+	last    int64
+	printed bool
+}
+
+func downloadProgress(w io.Writer) *progressPrinter {
+	return &progressPrinter{w: w, last: -1}
+}
+
+// report печатает процент загрузки, а если размер неизвестен - количество полученных байт.
+// Строка обновляется только при изменении процента или каждом полученном мегабайте.
+func (p *progressPrinter) report(done, total int64) {
+	const mb = 1 << 20
+
+	var step int64
+	var line string
+	if total > 0 {
+		step = done * 100 / total //nolint:gomnd // проценты
+		line = fmt.Sprintf("\rDownloading: %d%% (%d/%d bytes)", step, done, total)
+	} else {
+		step = done / mb
+		line = fmt.Sprintf("\rDownloading: %d bytes", done)
+	}
+
+	if step == p.last {
+		return
+	}
+	p.last = step
+	p.printed = true
+	fmt.Fprint(p.w, line)
+}
+
+// finish завершает строку прогресса, если она выводилась.
+func (p *progressPrinter) finish() {
+	if p.printed {
+		fmt.Fprintln(p.w)
+	}
+}
@@ -21,7 +21,8 @@ func TestGetFileCmd(t *testing.T) {
 	dir := "."
 
 	type getFile struct {
-		err error
+		progress [][2]int64
+		err      error
 	}
 	tests := []struct {
 		name    string
@@ -37,6 +38,25 @@ func TestGetFileCmd(t *testing.T) {
 			},
 			output: "File load in .",
 		},
+		{
+			name: "get file success with progress",
+			args: []string{"get", "file", fileMark},
+			getFile: getFile{
+				progress: [][2]int64{{1, 4}, {2, 4}, {2, 4}, {4, 4}},
+				err:      nil,
+			},
+			output: "\rDownloading: 25% (1/4 bytes)\rDownloading: 50% (2/4 bytes)" +
+				"\rDownloading: 100% (4/4 bytes)\nFile load in .",
+		},
+		{
+			name: "get file success with unknown size",
+			args: []string{"get", "file", fileMark},
+			getFile: getFile{
+				progress: [][2]int64{{10, -1}, {20, -1}},
+				err:      nil,
+			},
+			output: "\rDownloading: 10 bytes\nFile load in .",
+		},
 		{
 			name: "get file failed",
 			args: []string{"get", "file", fileMark},
@@ -48,7 +68,13 @@ func TestGetFileCmd(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s.EXPECT().GetFile(fileMark, dir).Times(1).Return(test.getFile.err)
+			s.EXPECT().GetFile(fileMark, dir, gomock.Any()).Times(1).
+				DoAndReturn(func(_, _ string, progress func(done, total int64)) error {
+					for _, p := range test.getFile.progress {
+						progress(p[0], p[1])
+					}
+					return test.getFile.err
+				})
 
 			cmd.RootCmd.SetArgs(test.args)
 
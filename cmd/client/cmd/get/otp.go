@@ -0,0 +1,30 @@
+package get
+
+import (
+	root "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/spf13/cobra"
+)
+
+// otpCmd represents the otp command.
+var otpCmd = &cobra.Command{
+	Use:   "otp [ID]",
+	Short: "Получить одноразовый пароль",
+	Long:  "Получить текущий одноразовый пароль (TOTP) и время его действия по ID секрета",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		code, err := root.Services.GetOTPCode(args[0])
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		if code.Issuer != "" || code.Account != "" {
+			cmd.Printf("%s %s\n", code.Issuer, code.Account)
+		}
+		cmd.Printf("%s (%ds remaining)\n", code.Code, code.Remaining)
+	},
+}
+
+func init() {
+	getCmd.AddCommand(otpCmd)
+}
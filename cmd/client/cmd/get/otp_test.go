@@ -0,0 +1,75 @@
+package get
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOTPCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	otpID := "1"
+
+	type getOTPCode struct {
+		resp models.OTPCode
+		err  error
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		getOTPCode getOTPCode
+		output     string
+	}{
+		{
+			name: "get otp success",
+			args: []string{"get", "otp", otpID},
+			getOTPCode: getOTPCode{
+				resp: models.OTPCode{Code: "123456", Issuer: "GitHub", Account: "bot", Remaining: 17, Period: 30},
+				err:  nil,
+			},
+			output: "GitHub bot\n123456 (17s remaining)\n",
+		},
+		{
+			name: "get otp without label",
+			args: []string{"get", "otp", otpID},
+			getOTPCode: getOTPCode{
+				resp: models.OTPCode{Code: "654321", Remaining: 5, Period: 30},
+				err:  nil,
+			},
+			output: "654321 (5s remaining)\n",
+		},
+		{
+			name: "get otp failed",
+			args: []string{"get", "otp", otpID},
+			getOTPCode: getOTPCode{
+				resp: models.OTPCode{},
+				err:  errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetOTPCode(otpID).Times(1).Return(test.getOTPCode.resp, test.getOTPCode.err)
+
+			cmd.RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			cmd.RootCmd.SetOutput(&outBuf)
+
+			cmd.Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command.
+var historyCmd = &cobra.Command{
+	Use:   "history [ID]",
+	Short: "Показать историю версий",
+	Long:  "Показать прежние версии объекта данных по его идентификатору (ID), начиная с последней",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		revisions, err := Services.GetHistory(args[0])
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tMARK\tDESCRIPTION\tCHANGED")
+
+		for _, r := range revisions {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Version, r.Mark, r.Description, r.CreatedAt.Local().Format(time.DateTime))
+		}
+
+		_ = w.Flush()
+	},
+}
+
+// restoreCmd represents the restore command.
+var restoreCmd = &cobra.Command{
+	Use:   "restore [ID]",
+	Short: "Восстановить версию",
+	Long:  "Восстановить прежнюю версию объекта данных, текущая версия сохраняется в истории",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, _ := cmd.Flags().GetInt(revisionFlag)
+
+		if err := Services.RestoreRevision(args[0], version); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Restore OK")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(historyCmd)
+	RootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().Int(revisionFlag, 0, "Версия для восстановления")
+	_ = restoreCmd.MarkFlagRequired(revisionFlag)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	changedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	revisions := []models.UserDataRevision{
+		{Version: 2, Mark: "bank", Description: "main card", CreatedAt: changedAt},
+		{Version: 1, Mark: "old", CreatedAt: changedAt},
+	}
+
+	type getHistory struct {
+		resp []models.UserDataRevision
+		err  error
+	}
+	tests := []struct {
+		name       string
+		getHistory getHistory
+		output     string
+	}{
+		{
+			name: "show history success",
+			getHistory: getHistory{
+				resp: revisions,
+				err:  nil,
+			},
+			output: "VERSION  MARK  DESCRIPTION  CHANGED\n" +
+				"2        bank  main card    2024-01-02 03:04:05\n" +
+				"1        old                2024-01-02 03:04:05\n",
+		},
+		{
+			name: "show history failed",
+			getHistory: getHistory{
+				resp: nil,
+				err:  errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetHistory("1").Times(1).Return(test.getHistory.resp, test.getHistory.err)
+
+			RootCmd.SetArgs([]string{"history", "1"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
+
+func TestRestoreCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	tests := []struct {
+		name   string
+		err    error
+		output string
+	}{
+		{
+			name:   "restore success",
+			err:    nil,
+			output: "Restore OK\n",
+		},
+		{
+			name:   "restore failed",
+			err:    errors.New("some error"),
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().RestoreRevision("1", 2).Times(1).Return(test.err)
+
+			RootCmd.SetArgs([]string{"restore", "1", "--rev", "2"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
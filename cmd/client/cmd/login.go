@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"bufio"
+	"os"
+	"strings"
+
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/spf13/cobra"
 )
@@ -14,13 +18,20 @@ var loginCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		login, _ := cmd.Flags().GetString(loginFlag)
 		password, _ := cmd.Flags().GetString(passwordFlag)
+		device, _ := cmd.Flags().GetString(deviceFlag)
+
+		if device == "" {
+			device, _ = os.Hostname()
+		}
 
 		req := models.CreateUserTokenRequest{
-			Login:    login,
-			Password: password,
+			Login:         login,
+			Password:      password,
+			DeviceName:    device,
+			ClientVersion: version,
 		}
 
-		if err := Services.LoginUser(req); err != nil {
+		if err := Services.LoginUser(req, oneTimeCode(cmd)); err != nil {
 			printFailed(cmd, err)
 			return
 		}
@@ -39,6 +50,27 @@ func init() {
 
 	loginCmd.Flags().StringP(loginFlag, "l", "", "Логин пользователя")
 	loginCmd.Flags().StringP(passwordFlag, "p", "", "Пароль пользователя")
+	loginCmd.Flags().String(deviceFlag, "", "Название устройства в списке сессий (по умолчанию имя хоста)")
+	loginCmd.Flags().String(otpFlag, "", "Одноразовый пароль или код восстановления (если не указан, будет запрошен)")
 	_ = loginCmd.MarkFlagRequired(loginFlag)
 	_ = loginCmd.MarkFlagRequired(passwordFlag)
 }
+
+// oneTimeCode возвращает функцию получения одноразового пароля:
+// из флага --otp, а если он не указан — запросом у пользователя.
+func oneTimeCode(cmd *cobra.Command) func() (string, error) {
+	return func() (string, error) {
+		if otp, _ := cmd.Flags().GetString(otpFlag); otp != "" {
+			return otp, nil
+		}
+
+		cmd.Print("One-time code: ")
+
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+
+		return strings.TrimSpace(line), nil
+	}
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bytes"
 	"errors"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
@@ -17,9 +19,12 @@ func TestLoginCmd(t *testing.T) {
 
 	s := mocks.NewMockServicer(mockCtrl)
 
+	hostname, _ := os.Hostname()
 	req := models.CreateUserTokenRequest{
-		Login:    "qwe",
-		Password: "123",
+		Login:         "qwe",
+		Password:      "123",
+		DeviceName:    hostname,
+		ClientVersion: version,
 	}
 
 	type loginUser struct {
@@ -75,7 +80,7 @@ func TestLoginCmd(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s.EXPECT().LoginUser(req).Times(1).Return(test.loginUser.err)
+			s.EXPECT().LoginUser(req, gomock.Any()).Times(1).Return(test.loginUser.err)
 			s.EXPECT().SyncData().Times(test.syncData.count).Return(test.syncData.err)
 
 			RootCmd.SetArgs(test.args)
@@ -88,4 +93,65 @@ func TestLoginCmd(t *testing.T) {
 			assert.Equal(t, test.output, outBuf.String())
 		})
 	}
+
+	t.Run("login with device name", func(t *testing.T) {
+		deviceReq := req
+		deviceReq.DeviceName = "laptop"
+
+		s.EXPECT().LoginUser(deviceReq, gomock.Any()).Times(1).Return(nil)
+		s.EXPECT().SyncData().Times(1).Return(nil)
+
+		RootCmd.SetArgs([]string{"login", "-l", "qwe", "-p", "123", "--device", "laptop"})
+
+		var outBuf bytes.Buffer
+		RootCmd.SetOutput(&outBuf)
+
+		Execute(s)
+
+		assert.Equal(t, "Login OK\n", outBuf.String())
+	})
+
+	t.Run("login with one-time code flag", func(t *testing.T) {
+		var code string
+
+		s.EXPECT().LoginUser(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ models.CreateUserTokenRequest, otp func() (string, error)) error {
+				code, _ = otp()
+				return nil
+			})
+		s.EXPECT().SyncData().Times(1).Return(nil)
+
+		RootCmd.SetArgs([]string{"login", "-l", "qwe", "-p", "123", "--otp", "123456"})
+
+		var outBuf bytes.Buffer
+		RootCmd.SetOutput(&outBuf)
+
+		Execute(s)
+
+		assert.Equal(t, "123456", code)
+		assert.Equal(t, "Login OK\n", outBuf.String())
+	})
+
+	t.Run("login with one-time code prompt", func(t *testing.T) {
+		var code string
+
+		s.EXPECT().LoginUser(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ models.CreateUserTokenRequest, otp func() (string, error)) error {
+				code, _ = otp()
+				return nil
+			})
+		s.EXPECT().SyncData().Times(1).Return(nil)
+
+		RootCmd.SetArgs([]string{"login", "-l", "qwe", "-p", "123", "--otp", ""})
+		RootCmd.SetIn(strings.NewReader("654321\n"))
+		defer RootCmd.SetIn(nil)
+
+		var outBuf bytes.Buffer
+		RootCmd.SetOutput(&outBuf)
+
+		Execute(s)
+
+		assert.Equal(t, "654321", code)
+		assert.Equal(t, "One-time code: Login OK\n", outBuf.String())
+	})
 }
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command.
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Перенести записи старых версий клиента",
+	Long: "Однократно зашифровать заново записи, сохраненные старыми версиями клиента без шифрования " +
+		"или без привязки к записи. Без переноса такие записи не открываются",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		migrated, err := Services.MigrateSecrets()
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Printf("Migrated %d records\n", migrated)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(migrateCmd)
+}
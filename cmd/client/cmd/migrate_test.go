@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type migrateSecrets struct {
+		migrated int
+		err      error
+	}
+	tests := []struct {
+		name           string
+		migrateSecrets migrateSecrets
+		output         string
+	}{
+		{
+			name: "migrate success",
+			migrateSecrets: migrateSecrets{
+				migrated: 2,
+				err:      nil,
+			},
+			output: "Migrated 2 records\n",
+		},
+		{
+			name: "migrate failed",
+			migrateSecrets: migrateSecrets{
+				migrated: 0,
+				err:      errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().MigrateSecrets().Times(1).Return(test.migrateSecrets.migrated, test.migrateSecrets.err)
+
+			RootCmd.SetArgs([]string{"migrate"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -49,17 +49,31 @@ func (mr *MockServicerMockRecorder) AddCard(req interface{}) *gomock.Call {
 }
 
 // AddFile mocks base method.
-func (m *MockServicer) AddFile(filePath, mark, description string) error {
+func (m *MockServicer) AddFile(filePath string, req models.AddFileRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFile", filePath, mark, description)
+	ret := m.ctrl.Call(m, "AddFile", filePath, req)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFile indicates an expected call of AddFile.
-func (mr *MockServicerMockRecorder) AddFile(filePath, mark, description interface{}) *gomock.Call {
+func (mr *MockServicerMockRecorder) AddFile(filePath, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFile", reflect.TypeOf((*MockServicer)(nil).AddFile), filePath, mark, description)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFile", reflect.TypeOf((*MockServicer)(nil).AddFile), filePath, req)
+}
+
+// AddOTP mocks base method.
+func (m *MockServicer) AddOTP(req models.AddOTPRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOTP", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOTP indicates an expected call of AddOTP.
+func (mr *MockServicerMockRecorder) AddOTP(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOTP", reflect.TypeOf((*MockServicer)(nil).AddOTP), req)
 }
 
 // AddPassword mocks base method.
@@ -90,6 +104,175 @@ func (mr *MockServicerMockRecorder) AddText(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddText", reflect.TypeOf((*MockServicer)(nil).AddText), req)
 }
 
+// DeleteCard mocks base method.
+func (m *MockServicer) DeleteCard(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCard", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCard indicates an expected call of DeleteCard.
+func (mr *MockServicerMockRecorder) DeleteCard(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCard", reflect.TypeOf((*MockServicer)(nil).DeleteCard), id)
+}
+
+// DeleteFile mocks base method.
+func (m *MockServicer) DeleteFile(fileRef string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", fileRef)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockServicerMockRecorder) DeleteFile(fileRef interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockServicer)(nil).DeleteFile), fileRef)
+}
+
+// DeleteOTP mocks base method.
+func (m *MockServicer) DeleteOTP(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOTP", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOTP indicates an expected call of DeleteOTP.
+func (mr *MockServicerMockRecorder) DeleteOTP(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOTP", reflect.TypeOf((*MockServicer)(nil).DeleteOTP), id)
+}
+
+// DeletePassword mocks base method.
+func (m *MockServicer) DeletePassword(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePassword", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePassword indicates an expected call of DeletePassword.
+func (mr *MockServicerMockRecorder) DeletePassword(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePassword", reflect.TypeOf((*MockServicer)(nil).DeletePassword), id)
+}
+
+// DeleteText mocks base method.
+func (m *MockServicer) DeleteText(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteText", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteText indicates an expected call of DeleteText.
+func (mr *MockServicerMockRecorder) DeleteText(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteText", reflect.TypeOf((*MockServicer)(nil).DeleteText), id)
+}
+
+// EditCard mocks base method.
+func (m *MockServicer) EditCard(id string, req models.UpdateCardRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditCard", id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditCard indicates an expected call of EditCard.
+func (mr *MockServicerMockRecorder) EditCard(id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditCard", reflect.TypeOf((*MockServicer)(nil).EditCard), id, req)
+}
+
+// EditFile mocks base method.
+func (m *MockServicer) EditFile(fileRef, filePath string, req models.UpdateFileRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditFile", fileRef, filePath, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditFile indicates an expected call of EditFile.
+func (mr *MockServicerMockRecorder) EditFile(fileRef, filePath, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditFile", reflect.TypeOf((*MockServicer)(nil).EditFile), fileRef, filePath, req)
+}
+
+// EditOTP mocks base method.
+func (m *MockServicer) EditOTP(id string, req models.UpdateOTPRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditOTP", id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditOTP indicates an expected call of EditOTP.
+func (mr *MockServicerMockRecorder) EditOTP(id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditOTP", reflect.TypeOf((*MockServicer)(nil).EditOTP), id, req)
+}
+
+// EditPassword mocks base method.
+func (m *MockServicer) EditPassword(id string, req models.UpdatePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditPassword", id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditPassword indicates an expected call of EditPassword.
+func (mr *MockServicerMockRecorder) EditPassword(id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditPassword", reflect.TypeOf((*MockServicer)(nil).EditPassword), id, req)
+}
+
+// EditText mocks base method.
+func (m *MockServicer) EditText(id string, req models.UpdateTextRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditText", id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditText indicates an expected call of EditText.
+func (mr *MockServicerMockRecorder) EditText(id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditText", reflect.TypeOf((*MockServicer)(nil).EditText), id, req)
+}
+
+// EmptyTrash mocks base method.
+func (m *MockServicer) EmptyTrash() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmptyTrash")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmptyTrash indicates an expected call of EmptyTrash.
+func (mr *MockServicerMockRecorder) EmptyTrash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmptyTrash", reflect.TypeOf((*MockServicer)(nil).EmptyTrash))
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockServicer) EnrollTwoFactor() (models.EnrollTwoFactorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor")
+	ret0, _ := ret[0].(models.EnrollTwoFactorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockServicerMockRecorder) EnrollTwoFactor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockServicer)(nil).EnrollTwoFactor))
+}
+
 // GetCard mocks base method.
 func (m *MockServicer) GetCard(id string) (models.Card, error) {
 	m.ctrl.T.Helper()
@@ -120,17 +303,47 @@ func (mr *MockServicerMockRecorder) GetData() *gomock.Call {
 }
 
 // GetFile mocks base method.
-func (m *MockServicer) GetFile(id, dir string) error {
+func (m *MockServicer) GetFile(fileRef, dir string, progress func(int64, int64)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFile", id, dir)
+	ret := m.ctrl.Call(m, "GetFile", fileRef, dir, progress)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GetFile indicates an expected call of GetFile.
-func (mr *MockServicerMockRecorder) GetFile(id, dir interface{}) *gomock.Call {
+func (mr *MockServicerMockRecorder) GetFile(fileRef, dir, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockServicer)(nil).GetFile), fileRef, dir, progress)
+}
+
+// GetHistory mocks base method.
+func (m *MockServicer) GetHistory(id string) ([]models.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", id)
+	ret0, _ := ret[0].([]models.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockServicerMockRecorder) GetHistory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockServicer)(nil).GetHistory), id)
+}
+
+// GetOTPCode mocks base method.
+func (m *MockServicer) GetOTPCode(id string) (models.OTPCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOTPCode", id)
+	ret0, _ := ret[0].(models.OTPCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOTPCode indicates an expected call of GetOTPCode.
+func (mr *MockServicerMockRecorder) GetOTPCode(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockServicer)(nil).GetFile), id, dir)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOTPCode", reflect.TypeOf((*MockServicer)(nil).GetOTPCode), id)
 }
 
 // GetPassword mocks base method.
@@ -148,6 +361,21 @@ func (mr *MockServicerMockRecorder) GetPassword(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassword", reflect.TypeOf((*MockServicer)(nil).GetPassword), id)
 }
 
+// GetSessions mocks base method.
+func (m *MockServicer) GetSessions() ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions")
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockServicerMockRecorder) GetSessions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockServicer)(nil).GetSessions))
+}
+
 // GetText mocks base method.
 func (m *MockServicer) GetText(id string) (models.Text, error) {
 	m.ctrl.T.Helper()
@@ -163,18 +391,48 @@ func (mr *MockServicerMockRecorder) GetText(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetText", reflect.TypeOf((*MockServicer)(nil).GetText), id)
 }
 
+// GetTrash mocks base method.
+func (m *MockServicer) GetTrash() ([]models.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash")
+	ret0, _ := ret[0].([]models.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockServicerMockRecorder) GetTrash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockServicer)(nil).GetTrash))
+}
+
+// GetUsage mocks base method.
+func (m *MockServicer) GetUsage() (models.UserUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage")
+	ret0, _ := ret[0].(models.UserUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockServicerMockRecorder) GetUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockServicer)(nil).GetUsage))
+}
+
 // LoginUser mocks base method.
-func (m *MockServicer) LoginUser(req models.CreateUserTokenRequest) error {
+func (m *MockServicer) LoginUser(req models.CreateUserTokenRequest, code func() (string, error)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoginUser", req)
+	ret := m.ctrl.Call(m, "LoginUser", req, code)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LoginUser indicates an expected call of LoginUser.
-func (mr *MockServicerMockRecorder) LoginUser(req interface{}) *gomock.Call {
+func (mr *MockServicerMockRecorder) LoginUser(req, code interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockServicer)(nil).LoginUser), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockServicer)(nil).LoginUser), req, code)
 }
 
 // LogoutUser mocks base method.
@@ -191,6 +449,21 @@ func (mr *MockServicerMockRecorder) LogoutUser() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockServicer)(nil).LogoutUser))
 }
 
+// MigrateSecrets mocks base method.
+func (m *MockServicer) MigrateSecrets() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateSecrets")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateSecrets indicates an expected call of MigrateSecrets.
+func (mr *MockServicerMockRecorder) MigrateSecrets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateSecrets", reflect.TypeOf((*MockServicer)(nil).MigrateSecrets))
+}
+
 // RegisterUser mocks base method.
 func (m *MockServicer) RegisterUser(req models.RegisterUserRequest) error {
 	m.ctrl.T.Helper()
@@ -205,6 +478,63 @@ func (mr *MockServicerMockRecorder) RegisterUser(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockServicer)(nil).RegisterUser), req)
 }
 
+// RestoreRevision mocks base method.
+func (m *MockServicer) RestoreRevision(id string, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockServicerMockRecorder) RestoreRevision(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockServicer)(nil).RestoreRevision), id, version)
+}
+
+// RestoreTrash mocks base method.
+func (m *MockServicer) RestoreTrash(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTrash", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTrash indicates an expected call of RestoreTrash.
+func (mr *MockServicerMockRecorder) RestoreTrash(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTrash", reflect.TypeOf((*MockServicer)(nil).RestoreTrash), id)
+}
+
+// RevokeSession mocks base method.
+func (m *MockServicer) RevokeSession(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockServicerMockRecorder) RevokeSession(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockServicer)(nil).RevokeSession), id)
+}
+
+// SearchData mocks base method.
+func (m *MockServicer) SearchData(filter models.UserDataFilter) (models.UserDataPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchData", filter)
+	ret0, _ := ret[0].(models.UserDataPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchData indicates an expected call of SearchData.
+func (mr *MockServicerMockRecorder) SearchData(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchData", reflect.TypeOf((*MockServicer)(nil).SearchData), filter)
+}
+
 // SyncData mocks base method.
 func (m *MockServicer) SyncData() error {
 	m.ctrl.T.Helper()
@@ -218,3 +548,18 @@ func (mr *MockServicerMockRecorder) SyncData() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncData", reflect.TypeOf((*MockServicer)(nil).SyncData))
 }
+
+// VerifyTwoFactor mocks base method.
+func (m *MockServicer) VerifyTwoFactor(code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockServicerMockRecorder) VerifyTwoFactor(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockServicer)(nil).VerifyTwoFactor), code)
+}
@@ -11,6 +11,18 @@ import (
 const (
 	loginFlag    = "login"
 	passwordFlag = "password"
+	deviceFlag   = "device"
+	otpFlag      = "otp"
+	revisionFlag = "rev"
+	typeFlag     = "type"
+	markFlag     = "mark"
+	searchFlag   = "search"
+	sortFlag     = "sort"
+	limitFlag    = "limit"
+	offsetFlag   = "offset"
+	tagFlag      = "tag"
+	folderFlag   = "folder"
+	treeFlag     = "tree"
 )
 
 var version = "0.0.1"
@@ -19,25 +31,49 @@ var cfgFile string
 // Servicer интерфейс для сервисов клиента.
 type Servicer interface {
 	RegisterUser(req models.RegisterUserRequest) error
-	LoginUser(req models.CreateUserTokenRequest) error
+	LoginUser(req models.CreateUserTokenRequest, code func() (string, error)) error
+	EnrollTwoFactor() (models.EnrollTwoFactorResponse, error)
+	VerifyTwoFactor(code string) ([]string, error)
 	SyncData() error
 	LogoutUser() error
+	GetSessions() ([]models.Session, error)
+	RevokeSession(id string) error
+	GetUsage() (models.UserUsage, error)
 	GetData() []models.UserData
+	SearchData(filter models.UserDataFilter) (models.UserDataPage, error)
+	GetHistory(id string) ([]models.UserDataRevision, error)
+	RestoreRevision(id string, version int) error
+	GetTrash() ([]models.TrashItem, error)
+	RestoreTrash(id string) error
+	EmptyTrash() error
+	MigrateSecrets() (int, error)
 	AddPassword(req models.AddPasswordRequest) error
 	GetPassword(id string) (models.Password, error)
+	EditPassword(id string, req models.UpdatePasswordRequest) error
+	DeletePassword(id string) error
 	AddCard(req *models.AddCardRequest) error
 	GetCard(id string) (models.Card, error)
+	EditCard(id string, req models.UpdateCardRequest) error
+	DeleteCard(id string) error
 	AddText(req models.AddTextRequest) error
 	GetText(id string) (models.Text, error)
-	AddFile(filePath, mark, description string) error
-	GetFile(id, dir string) error
+	EditText(id string, req models.UpdateTextRequest) error
+	DeleteText(id string) error
+	AddOTP(req models.AddOTPRequest) error
+	GetOTPCode(id string) (models.OTPCode, error)
+	EditOTP(id string, req models.UpdateOTPRequest) error
+	DeleteOTP(id string) error
+	AddFile(filePath string, req models.AddFileRequest) error
+	GetFile(fileRef, dir string, progress func(done, total int64)) error
+	EditFile(fileRef, filePath string, req models.UpdateFileRequest) error
+	DeleteFile(fileRef string) error
 }
 
 // RootCmd represents the base command when called without any subcommands.
 var RootCmd = &cobra.Command{
 	Use:   "client",
 	Short: "Клиент для GophKeeper",
-	Long: `Клиент для GophKeeper позволяющий добавлять и просматривать:
+	Long: `Клиент для GophKeeper позволяющий добавлять, просматривать, изменять и удалять:
 	- логин-пароли;
 	- банковские карты;
 	- тексты;
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// sessionsCmd represents the sessions command.
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Управление сессиями",
+	Long:  "Просмотр активных сессий пользователя и завершение сессий на других устройствах",
+}
+
+// sessionsListCmd represents the sessions list command.
+var sessionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Показать активные сессии",
+	Long:  "Показать активные сессии пользователя, текущая сессия отмечена звездочкой",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sessions, err := Services.GetSessions()
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\tID\tDEVICE\tVERSION\tIP\tLAST SEEN")
+
+		for _, s := range sessions {
+			current := ""
+			if s.Current {
+				current = "*"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				current, s.ID, s.DeviceName, s.ClientVersion, s.IP, s.LastUsedAt.Local().Format(time.DateTime))
+		}
+
+		_ = w.Flush()
+	},
+}
+
+// sessionsRevokeCmd represents the sessions revoke command.
+var sessionsRevokeCmd = &cobra.Command{
+	Use:   "revoke [ID]",
+	Short: "Завершить сессию",
+	Long:  "Завершить сессию по ее идентификатору (ID), устройство потеряет доступ к данным",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := Services.RevokeSession(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Revoke session OK")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(sessionsCmd)
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsRevokeCmd)
+}
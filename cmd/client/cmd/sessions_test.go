@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsListCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	lastUsedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	sessions := []models.Session{
+		{ID: "s1", DeviceName: "laptop", ClientVersion: "0.0.1", IP: "192.0.2.1", LastUsedAt: lastUsedAt, Current: true},
+		{ID: "s2", DeviceName: "ci", ClientVersion: "0.0.1", IP: "192.0.2.2", LastUsedAt: lastUsedAt},
+	}
+
+	type getSessions struct {
+		resp []models.Session
+		err  error
+	}
+	tests := []struct {
+		name        string
+		getSessions getSessions
+		output      string
+	}{
+		{
+			name: "list sessions success",
+			getSessions: getSessions{
+				resp: sessions,
+				err:  nil,
+			},
+			output: "   ID  DEVICE  VERSION  IP         LAST SEEN\n" +
+				"*  s1  laptop  0.0.1    192.0.2.1  2024-01-02 03:04:05\n" +
+				"   s2  ci      0.0.1    192.0.2.2  2024-01-02 03:04:05\n",
+		},
+		{
+			name: "list sessions failed",
+			getSessions: getSessions{
+				resp: nil,
+				err:  errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetSessions().Times(1).Return(test.getSessions.resp, test.getSessions.err)
+
+			RootCmd.SetArgs([]string{"sessions", "list"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
+
+func TestSessionsRevokeCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	tests := []struct {
+		name   string
+		err    error
+		output string
+	}{
+		{
+			name:   "revoke session success",
+			err:    nil,
+			output: "Revoke session OK\n",
+		},
+		{
+			name:   "revoke session failed",
+			err:    errors.New("some error"),
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().RevokeSession("s2").Times(1).Return(test.err)
+
+			RootCmd.SetArgs([]string{"sessions", "revoke", "s2"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
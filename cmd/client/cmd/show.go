@@ -2,7 +2,13 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/spf13/cobra"
 )
 
@@ -10,7 +16,9 @@ import (
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Показать сохранненную информацию",
-	Long:  "Показать сохранненную информацию для дальнейшей загрузки",
+	Long: "Показать сохранненную информацию для дальнейшей загрузки. " +
+		"При указании фильтров поиск выполняется на сервере и результат выводится таблицей, " +
+		"флаг --tree выводит данные деревом папок",
 	Run: func(cmd *cobra.Command, args []string) {
 		sync, _ := cmd.Flags().GetBool("sync")
 
@@ -21,7 +29,18 @@ var showCmd = &cobra.Command{
 			}
 		}
 
+		if hasSearchFlags(cmd) {
+			searchData(cmd)
+			return
+		}
+
 		userData := Services.GetData()
+
+		if tree, _ := cmd.Flags().GetBool(treeFlag); tree {
+			printTree(cmd, userData)
+			return
+		}
+
 		b, err := json.MarshalIndent(userData, "", "  ")
 		if err != nil {
 			printFailed(cmd, err)
@@ -32,8 +51,125 @@ var showCmd = &cobra.Command{
 	},
 }
 
+// hasSearchFlags проверяет, указан ли хотя бы один флаг поиска.
+func hasSearchFlags(cmd *cobra.Command) bool {
+	for _, f := range []string{typeFlag, markFlag, searchFlag, sortFlag, limitFlag, offsetFlag, tagFlag, folderFlag} {
+		if cmd.Flags().Changed(f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchData выполняет поиск данных на сервере и выводит найденную страницу таблицей.
+func searchData(cmd *cobra.Command) {
+	filter := models.UserDataFilter{}
+	filter.Type, _ = cmd.Flags().GetString(typeFlag)
+	filter.MarkPrefix, _ = cmd.Flags().GetString(markFlag)
+	filter.Search, _ = cmd.Flags().GetString(searchFlag)
+	filter.Sort, _ = cmd.Flags().GetString(sortFlag)
+	filter.Limit, _ = cmd.Flags().GetInt(limitFlag)
+	filter.Offset, _ = cmd.Flags().GetInt(offsetFlag)
+	filter.Tag, _ = cmd.Flags().GetString(tagFlag)
+	filter.Folder, _ = cmd.Flags().GetString(folderFlag)
+
+	page, err := Services.SearchData(filter)
+	if err != nil {
+		printFailed(cmd, err)
+		return
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tMARK\tDESCRIPTION\tUPDATED")
+
+	for _, i := range page.Items {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			i.ID, i.Type, i.Mark, i.Description, i.UpdatedAt.Local().Format(time.DateTime))
+	}
+
+	_ = w.Flush()
+
+	cmd.Printf("Shown %d of %d\n", len(page.Items), page.Total)
+}
+
+// folderNode тип для узла дерева папок.
+type folderNode struct {
+	folders map[string]*folderNode
+	data    []models.UserData
+}
+
+// printTree выводит данные пользователя деревом папок.
+func printTree(cmd *cobra.Command, userData []models.UserData) {
+	root := &folderNode{folders: map[string]*folderNode{}}
+
+	for _, d := range userData {
+		node := root
+
+		for _, name := range strings.Split(d.Folder, "/") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			next, ok := node.folders[name]
+			if !ok {
+				next = &folderNode{folders: map[string]*folderNode{}}
+				node.folders[name] = next
+			}
+
+			node = next
+		}
+
+		node.data = append(node.data, d)
+	}
+
+	printFolderNode(cmd, root, "")
+}
+
+// printFolderNode выводит содержимое узла дерева: сначала вложенные папки, затем данные.
+func printFolderNode(cmd *cobra.Command, node *folderNode, indent string) {
+	names := make([]string, 0, len(node.folders))
+	for name := range node.folders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd.Printf("%s%s/\n", indent, name)
+		printFolderNode(cmd, node.folders[name], indent+"  ")
+	}
+
+	sort.Slice(node.data, func(i, j int) bool {
+		if node.data[i].Mark != node.data[j].Mark {
+			return node.data[i].Mark < node.data[j].Mark
+		}
+
+		return node.data[i].ID < node.data[j].ID
+	})
+
+	for _, d := range node.data {
+		line := fmt.Sprintf("%s[%d] %s %s", indent, d.ID, d.Type, d.Mark)
+		if len(d.Tags) > 0 {
+			line += " #" + strings.Join(d.Tags, " #")
+		}
+
+		cmd.Println(line)
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(showCmd)
 
 	showCmd.Flags().BoolP("sync", "s", false, "Обновить синхронизацию базовой информацииы")
+	showCmd.Flags().String(typeFlag, "", "Тип данных (password, card, text, file, otp)")
+	showCmd.Flags().String(markFlag, "", "Начало метки")
+	showCmd.Flags().String(searchFlag, "", "Полнотекстовый поиск по метке и описанию")
+	showCmd.Flags().String(sortFlag, "", "Сортировка: id, type, mark, created, updated (префикс - для обратного порядка)")
+	showCmd.Flags().Int(limitFlag, 0, "Максимальное количество записей (по умолчанию 50)")
+	showCmd.Flags().Int(offsetFlag, 0, "Количество пропускаемых записей")
+	showCmd.Flags().String(tagFlag, "", "Тег")
+	showCmd.Flags().String(folderFlag, "", "Папка (включая вложенные папки)")
+	showCmd.Flags().Bool(treeFlag, false, "Вывести данные деревом папок")
 }
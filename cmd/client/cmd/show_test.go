@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/golang/mock/gomock"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,6 +25,7 @@ func TestShowCmd(t *testing.T) {
 			Type:        "text",
 			Mark:        "test",
 			Description: "test",
+			Version:     1,
 		},
 	}
 	expectedOutput := `[
@@ -30,7 +33,8 @@ func TestShowCmd(t *testing.T) {
     "mark": "test",
     "description": "test",
     "type": "text",
-    "id": 1
+    "id": 1,
+    "version": 1
   }
 ]
 `
@@ -106,3 +110,137 @@ func TestShowCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestShowSearchCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	page := models.UserDataPage{
+		Items: []models.UserDataItem{
+			{ID: 2, Type: "card", Mark: "aws", Description: "main card", UpdatedAt: updatedAt},
+		},
+		Total: 3,
+	}
+
+	type searchData struct {
+		filter models.UserDataFilter
+		resp   models.UserDataPage
+		err    error
+	}
+	tests := []struct {
+		name       string
+		args       []string
+		searchData searchData
+		output     string
+	}{
+		{
+			name: "search success",
+			args: []string{"show", "--type", "card", "--search", "aws", "--limit", "1"},
+			searchData: searchData{
+				filter: models.UserDataFilter{Type: "card", Search: "aws", Limit: 1},
+				resp:   page,
+				err:    nil,
+			},
+			output: "ID  TYPE  MARK  DESCRIPTION  UPDATED\n" +
+				"2   card  aws   main card    2024-01-02 03:04:05\n" +
+				"Shown 1 of 3\n",
+		},
+		{
+			name: "search with mark and sort success",
+			args: []string{"show", "--mark", "aw", "--sort", "-updated", "--offset", "10"},
+			searchData: searchData{
+				filter: models.UserDataFilter{MarkPrefix: "aw", Sort: "-updated", Offset: 10},
+				resp:   models.UserDataPage{Items: []models.UserDataItem{}, Total: 3},
+				err:    nil,
+			},
+			output: "ID  TYPE  MARK  DESCRIPTION  UPDATED\n" +
+				"Shown 0 of 3\n",
+		},
+		{
+			name: "search by tag and folder success",
+			args: []string{"show", "--tag", "prod", "--folder", "work/aws"},
+			searchData: searchData{
+				filter: models.UserDataFilter{Tag: "prod", Folder: "work/aws"},
+				resp:   models.UserDataPage{Items: []models.UserDataItem{}, Total: 0},
+				err:    nil,
+			},
+			output: "ID  TYPE  MARK  DESCRIPTION  UPDATED\n" +
+				"Shown 0 of 0\n",
+		},
+		{
+			name: "search failed",
+			args: []string{"show", "--search", "aws"},
+			searchData: searchData{
+				filter: models.UserDataFilter{Search: "aws"},
+				resp:   models.UserDataPage{},
+				err:    errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetShowFlags()
+			t.Cleanup(resetShowFlags)
+
+			s.EXPECT().GetData().Times(0)
+			s.EXPECT().SearchData(test.searchData.filter).Times(1).Return(test.searchData.resp, test.searchData.err)
+
+			RootCmd.SetArgs(test.args)
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
+
+func TestShowTreeCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	data := []models.UserData{
+		{ID: 4, Type: "text", Mark: "notes"},
+		{ID: 3, Type: "card", Mark: "visa", Folder: "work", Tags: []string{"bank"}},
+		{ID: 2, Type: "password", Mark: "root", Folder: "work/aws", Tags: []string{"prod", "team"}},
+		{ID: 1, Type: "password", Mark: "console", Folder: "work/aws"},
+		{ID: 5, Type: "otp", Mark: "github", Folder: "home"},
+	}
+
+	resetShowFlags()
+	t.Cleanup(resetShowFlags)
+
+	s.EXPECT().SyncData().Times(0)
+	s.EXPECT().GetData().Times(1).Return(data)
+
+	RootCmd.SetArgs([]string{"show", "--tree"})
+
+	var outBuf bytes.Buffer
+	RootCmd.SetOutput(&outBuf)
+
+	Execute(s)
+
+	assert.Equal(t, "home/\n"+
+		"  [5] otp github\n"+
+		"work/\n"+
+		"  aws/\n"+
+		"    [1] password console\n"+
+		"    [2] password root #prod #team\n"+
+		"  [3] card visa #bank\n"+
+		"[4] text notes\n", outBuf.String())
+}
+
+func resetShowFlags() {
+	showCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
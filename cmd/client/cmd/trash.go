@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// trashCmd represents the trash command.
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Управление корзиной",
+	Long: "Просмотр удаленных объектов данных, их восстановление и окончательное удаление. " +
+		"Объекты удаляются из корзины окончательно по истечении срока хранения на сервере",
+}
+
+// trashListCmd represents the trash list command.
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Показать корзину",
+	Long:  "Показать удаленные объекты данных, начиная с последних удаленных",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		items, err := Services.GetTrash()
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTYPE\tMARK\tDESCRIPTION\tDELETED")
+
+		for _, i := range items {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+				i.ID, i.Type, i.Mark, i.Description, i.DeletedAt.Local().Format(time.DateTime))
+		}
+
+		_ = w.Flush()
+	},
+}
+
+// trashRestoreCmd represents the trash restore command.
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore [ID]",
+	Short: "Восстановить из корзины",
+	Long:  "Восстановить удаленный объект данных по его идентификатору (ID)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := Services.RestoreTrash(args[0]); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Restore OK")
+	},
+}
+
+// trashEmptyCmd represents the trash empty command.
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Очистить корзину",
+	Long:  "Окончательно удалить все объекты данных из корзины, восстановить их будет нельзя",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := Services.EmptyTrash(); err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Empty trash OK")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+}
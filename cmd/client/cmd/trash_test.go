@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashListCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	items := []models.TrashItem{
+		{ID: 2, Type: "card", Mark: "bank", Description: "main card", DeletedAt: deletedAt},
+		{ID: 10, Type: "file", Mark: "report.pdf", DeletedAt: deletedAt},
+	}
+
+	type getTrash struct {
+		resp []models.TrashItem
+		err  error
+	}
+	tests := []struct {
+		name     string
+		getTrash getTrash
+		output   string
+	}{
+		{
+			name: "list trash success",
+			getTrash: getTrash{
+				resp: items,
+				err:  nil,
+			},
+			output: "ID  TYPE  MARK        DESCRIPTION  DELETED\n" +
+				"2   card  bank        main card    2024-01-02 03:04:05\n" +
+				"10  file  report.pdf               2024-01-02 03:04:05\n",
+		},
+		{
+			name: "list trash failed",
+			getTrash: getTrash{
+				resp: nil,
+				err:  errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetTrash().Times(1).Return(test.getTrash.resp, test.getTrash.err)
+
+			RootCmd.SetArgs([]string{"trash", "list"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
+
+func TestTrashRestoreCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	tests := []struct {
+		name   string
+		err    error
+		output string
+	}{
+		{
+			name:   "restore success",
+			err:    nil,
+			output: "Restore OK\n",
+		},
+		{
+			name:   "restore failed",
+			err:    errors.New("some error"),
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().RestoreTrash("2").Times(1).Return(test.err)
+
+			RootCmd.SetArgs([]string{"trash", "restore", "2"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
+
+func TestTrashEmptyCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	tests := []struct {
+		name   string
+		err    error
+		output string
+	}{
+		{
+			name:   "empty trash success",
+			err:    nil,
+			output: "Empty trash OK\n",
+		},
+		{
+			name:   "empty trash failed",
+			err:    errors.New("some error"),
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EmptyTrash().Times(1).Return(test.err)
+
+			RootCmd.SetArgs([]string{"trash", "empty"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// twoFactorCmd represents the 2fa command.
+var twoFactorCmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Двухфакторная аутентификация",
+	Long:  "Подключение двухфакторной аутентификации с одноразовыми паролями (TOTP)",
+}
+
+// twoFactorEnrollCmd represents the 2fa enroll command.
+var twoFactorEnrollCmd = &cobra.Command{
+	Use:   "enroll",
+	Short: "Получить секрет для приложения-аутентификатора",
+	Long: `Получить секрет для приложения-аутентификатора (Google Authenticator, Aegis и т.п.).
+Двухфакторная аутентификация включится после подтверждения командой 2fa verify`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		enroll, err := Services.EnrollTwoFactor()
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Printf("Secret: %s\n", enroll.Secret)
+		cmd.Printf("URI: %s\n", enroll.URI)
+		cmd.Println("Add the secret to your authenticator app and run: client 2fa verify [CODE]")
+	},
+}
+
+// twoFactorVerifyCmd represents the 2fa verify command.
+var twoFactorVerifyCmd = &cobra.Command{
+	Use:   "verify [CODE]",
+	Short: "Подтвердить и включить двухфакторную аутентификацию",
+	Long:  "Подтвердить одноразовым паролем из приложения-аутентификатора и получить коды восстановления",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		codes, err := Services.VerifyTwoFactor(args[0])
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		cmd.Println("Two-factor authentication enabled. Recovery codes (each can be used once):")
+		for _, code := range codes {
+			cmd.Println(code)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(twoFactorCmd)
+	twoFactorCmd.AddCommand(twoFactorEnrollCmd)
+	twoFactorCmd.AddCommand(twoFactorVerifyCmd)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorEnrollCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	enroll := models.EnrollTwoFactorResponse{Secret: "SECRET", URI: "otpauth://totp/GophKeeper:qwe?secret=SECRET"}
+
+	tests := []struct {
+		name   string
+		err    error
+		output string
+	}{
+		{
+			name: "enroll success",
+			err:  nil,
+			output: "Secret: SECRET\n" +
+				"URI: otpauth://totp/GophKeeper:qwe?secret=SECRET\n" +
+				"Add the secret to your authenticator app and run: client 2fa verify [CODE]\n",
+		},
+		{
+			name:   "enroll failed",
+			err:    errors.New("some error"),
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EnrollTwoFactor().Times(1).Return(enroll, test.err)
+
+			RootCmd.SetArgs([]string{"2fa", "enroll"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
+
+func TestTwoFactorVerifyCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	tests := []struct {
+		name   string
+		codes  []string
+		err    error
+		output string
+	}{
+		{
+			name:  "verify success",
+			codes: []string{"AAAA-BBBB-CCCC-DDDD", "EEEE-FFFF-GGGG-HHHH"},
+			err:   nil,
+			output: "Two-factor authentication enabled. Recovery codes (each can be used once):\n" +
+				"AAAA-BBBB-CCCC-DDDD\nEEEE-FFFF-GGGG-HHHH\n",
+		},
+		{
+			name:   "verify failed",
+			err:    errors.New("some error"),
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().VerifyTwoFactor("123456").Times(1).Return(test.codes, test.err)
+
+			RootCmd.SetArgs([]string{"2fa", "verify", "123456"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
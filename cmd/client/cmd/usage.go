@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+const unlimited = "без ограничений"
+
+// usageCmd represents the usage command.
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Показать использование хранилища",
+	Long:  "Показать занятый объем и количество записей пользователя и доступные ему ограничения",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		usage, err := Services.GetUsage()
+		if err != nil {
+			printFailed(cmd, err)
+			return
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\tUSED\tALLOWED")
+		fmt.Fprintf(w, "Storage\t%s\t%s\n", formatBytes(usage.UsedBytes), formatLimit(usage.MaxBytes, formatBytes))
+		fmt.Fprintf(w, "Items\t%d\t%s\n", usage.Items, formatLimit(int64(usage.MaxItems), formatCount))
+		fmt.Fprintf(w, "File size\t\t%s\n", formatLimit(usage.MaxFileSize, formatBytes))
+
+		_ = w.Flush()
+	},
+}
+
+// formatLimit форматирует ограничение, нулевое ограничение означает его отсутствие.
+func formatLimit(limit int64, format func(int64) string) string {
+	if limit == 0 {
+		return unlimited
+	}
+
+	return format(limit)
+}
+
+func formatCount(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// formatBytes форматирует размер в байтах в двоичных единицах.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	RootCmd.AddCommand(usageCmd)
+}
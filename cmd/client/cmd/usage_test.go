@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageCmd(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+
+	type getUsage struct {
+		resp models.UserUsage
+		err  error
+	}
+	tests := []struct {
+		name     string
+		getUsage getUsage
+		output   string
+	}{
+		{
+			name: "show usage success",
+			getUsage: getUsage{
+				resp: models.UserUsage{UsedBytes: 1536, MaxBytes: 1 << 30, Items: 3, MaxItems: 100, MaxFileSize: 256 << 20},
+				err:  nil,
+			},
+			output: "           USED     ALLOWED\n" +
+				"Storage    1.5 KiB  1.0 GiB\n" +
+				"Items      3        100\n" +
+				"File size           256.0 MiB\n",
+		},
+		{
+			name: "show usage without limits",
+			getUsage: getUsage{
+				resp: models.UserUsage{UsedBytes: 10, Items: 1},
+				err:  nil,
+			},
+			output: "           USED  ALLOWED\n" +
+				"Storage    10 B  без ограничений\n" +
+				"Items      1     без ограничений\n" +
+				"File size        без ограничений\n",
+		},
+		{
+			name: "show usage failed",
+			getUsage: getUsage{
+				err: errors.New("some error"),
+			},
+			output: "Failed: some error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetUsage().Times(1).Return(test.getUsage.resp, test.getUsage.err)
+
+			RootCmd.SetArgs([]string{"usage"})
+
+			var outBuf bytes.Buffer
+			RootCmd.SetOutput(&outBuf)
+
+			Execute(s)
+
+			assert.Equal(t, test.output, outBuf.String())
+		})
+	}
+}
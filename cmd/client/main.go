@@ -1,18 +1,53 @@
 package main //nolint:typecheck // false-positive
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
 	"github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd"
 	_ "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/add"
+	_ "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/del"
+	_ "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/edit"
 	_ "github.com/MihailSergeenkov/GophKeeper/cmd/client/cmd/get"
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/offline"
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/services"
+	"golang.org/x/term"
 )
 
+// passphraseEnv переменная окружения с парольной фразой хранилища.
+const passphraseEnv = "GOPH_KEEPER_PASSPHRASE"
+
 func main() {
 	cfg := config.GetConfig()
 	httpRequests := requests.NewRequests(cfg)
-	s := services.Init(cfg, httpRequests)
+	s := services.Init(cfg, httpRequests, offline.New(cfg), readPassphrase)
 
 	cmd.Execute(s)
 }
+
+// readPassphrase получает парольную фразу хранилища из окружения или запрашивает ее в терминале.
+func readPassphrase() (string, error) {
+	if passphrase, ok := os.LookupEnv(passphraseEnv); ok {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("passphrase is required: set %s", passphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Vault passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return "", errors.New("passphrase is empty")
+	}
+
+	return string(passphrase), nil
+}
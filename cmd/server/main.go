@@ -14,6 +14,7 @@ import (
 	"github.com/MihailSergeenkov/GophKeeper/internal/logger"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/filestore"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/s3"
@@ -78,7 +79,10 @@ func run(baseCtx context.Context, withFlags bool) error {
 	}
 
 	l.Info("Running server on", zap.String("addr", c.RunAddr))
-	l.Info("S3 server on", zap.String("addr", c.S3.Endpoint))
+
+	if c.S3.MigrateBuckets {
+		return migrateS3Buckets(ctx, c)
+	}
 
 	store, err := storage.NewStorage(ctx, l, c.DatabaseURI)
 	if err != nil {
@@ -96,9 +100,9 @@ func run(baseCtx context.Context, withFlags bool) error {
 		return nil
 	})
 
-	fs, err := s3.NewClient(ctx, &c.S3)
+	fs, err := newFileStorage(ctx, c, l)
 	if err != nil {
-		return fmt.Errorf("s3 error: %w", err)
+		return fmt.Errorf("file storage error: %w", err)
 	}
 
 	cr, err := crypt.NewCrypt(c)
@@ -107,6 +111,54 @@ func run(baseCtx context.Context, withFlags bool) error {
 	}
 
 	s := services.NewServices(store, fs, cr, c)
+
+	if c.Rotation.Enabled {
+		err := rotateMasterKey(ctx, c, s)
+		cancelCtx()
+
+		if waitErr := g.Wait(); waitErr != nil {
+			return fmt.Errorf("some errorgroup error: %w", waitErr)
+		}
+
+		return err
+	}
+
+	if c.Rotation.Reencrypt {
+		err := reencryptLegacyData(ctx, s)
+		cancelCtx()
+
+		if waitErr := g.Wait(); waitErr != nil {
+			return fmt.Errorf("some errorgroup error: %w", waitErr)
+		}
+
+		return err
+	}
+
+	if c.FileGC.Report {
+		err := reportFiles(ctx, s)
+		cancelCtx()
+
+		if waitErr := g.Wait(); waitErr != nil {
+			return fmt.Errorf("some errorgroup error: %w", waitErr)
+		}
+
+		return err
+	}
+
+	if c.FileGC.Interval > 0 {
+		g.Go(func() error {
+			collectFileGarbage(ctx, s, c.FileGC.Interval, l)
+			return nil
+		})
+	}
+
+	if c.Trash.Interval > 0 {
+		g.Go(func() error {
+			purgeTrash(ctx, s, c.Trash.Interval, l)
+			return nil
+		})
+	}
+
 	h := handlers.NewHandlers(s, l)
 	r := routes.NewRouter(h, c, l, store)
 
@@ -148,6 +200,166 @@ func run(baseCtx context.Context, withFlags bool) error {
 	return nil
 }
 
+// newFileStorage создает хранилище файлов, выбранное в настройках.
+func newFileStorage(ctx context.Context, c *config.Settings, l *zap.Logger) (services.FileStorager, error) {
+	switch c.FileStorage.Backend {
+	case config.FileStorageS3, "":
+		l.Info("S3 server on", zap.String("addr", c.S3.Endpoint))
+
+		fs, err := s3.NewClient(ctx, &c.S3)
+		if err != nil {
+			return nil, fmt.Errorf("s3 error: %w", err)
+		}
+		if err := fs.CheckBucket(ctx); err != nil {
+			return nil, fmt.Errorf("s3 error: %w", err)
+		}
+		return fs, nil
+	case config.FileStorageLocal:
+		l.Info("Local file storage in", zap.String("dir", c.FileStorage.Dir))
+
+		fs, err := filestore.NewLocal(c.FileStorage.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("local file storage error: %w", err)
+		}
+		return fs, nil
+	case config.FileStorageMemory:
+		l.Warn("Files are stored in memory and will be lost on restart")
+
+		return filestore.NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown file storage %q", c.FileStorage.Backend)
+	}
+}
+
+// migrateS3Buckets переносит файлы из бакетов пользователей старого формата в общий бакет.
+func migrateS3Buckets(ctx context.Context, c *config.Settings) error {
+	fs, err := s3.NewClient(ctx, &c.S3)
+	if err != nil {
+		return fmt.Errorf("s3 error: %w", err)
+	}
+	if err := fs.CheckBucket(ctx); err != nil {
+		return fmt.Errorf("s3 error: %w", err)
+	}
+
+	count, err := fs.MigrateLegacyBuckets(ctx, func(backetName, objectName string) {
+		log.Printf("Moved file %s from backet %s", objectName, backetName)
+	})
+	if err != nil {
+		return fmt.Errorf("migration error: %w", err)
+	}
+	log.Printf("S3 migration finished, moved files: %d", count)
+
+	return nil
+}
+
+// collectFileGarbage периодически удаляет потерянные объекты файлового хранилища
+// и отмечает данные файлов, объекты которых отсутствуют, пока не завершится ctx.
+func collectFileGarbage(ctx context.Context, s *services.Services, interval time.Duration, l *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, err := s.CheckFiles(ctx, true)
+		if err != nil {
+			l.Error("failed to collect file garbage", zap.Error(err))
+			continue
+		}
+
+		l.Info("file garbage collected",
+			zap.Int("users", report.Users),
+			zap.Int("orphaned", len(report.OrphanedObjects)),
+			zap.Int("deleted", report.DeletedObjects),
+			zap.Int("missing", len(report.MissingObjects)),
+			zap.Int("stale_pending", report.StalePending),
+		)
+	}
+}
+
+// purgeTrash периодически окончательно удаляет данные пользователей, которые находятся в корзине
+// дольше срока хранения, вместе с объектами файлов, пока не завершится ctx.
+func purgeTrash(ctx context.Context, s *services.Services, interval time.Duration, l *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		count, err := s.PurgeTrash(ctx)
+		if err != nil {
+			l.Error("failed to purge trash", zap.Error(err))
+			continue
+		}
+
+		l.Info("trash purged", zap.Int("deleted", count))
+	}
+}
+
+// reportFiles выводит отчет о несогласованности данных файлов и файлового хранилища, ничего не изменяя.
+func reportFiles(ctx context.Context, s *services.Services) error {
+	report, err := s.CheckFiles(ctx, false)
+	if err != nil {
+		return fmt.Errorf("file check error: %w", err)
+	}
+
+	for _, issue := range report.OrphanedObjects {
+		log.Printf("Orphaned object %s of user %d", issue.ObjectName, issue.UserID)
+	}
+	for _, issue := range report.MissingObjects {
+		log.Printf("Missing object %s of user %d data %d", issue.ObjectName, issue.UserID, issue.DataID)
+	}
+	log.Printf("File check finished, users: %d, orphaned objects: %d, missing objects: %d, stale pending objects: %d",
+		report.Users, len(report.OrphanedObjects), len(report.MissingObjects), report.StalePending)
+
+	return nil
+}
+
+// rotateMasterKey перешифровывает ключи данных пользователей новым мастер-ключом
+// и переводит данные в устаревших форматах на ключи пользователей.
+func rotateMasterKey(ctx context.Context, c *config.Settings, s *services.Services) error {
+	if c.Rotation.OldMasterKey == "" || c.Rotation.OldMasterKey == c.MasterKey {
+		return errors.New("rotation error: old master key must be set and differ from master key")
+	}
+
+	oldSettings := *c
+	oldSettings.MasterKey = c.Rotation.OldMasterKey
+
+	oldCr, err := crypt.NewCrypt(&oldSettings)
+	if err != nil {
+		return fmt.Errorf("crypt error: %w", err)
+	}
+
+	count, err := s.RotateMasterKey(ctx, oldCr, c.Rotation.BatchSize, func(done, total int) {
+		log.Printf("Rotated user keys: %d/%d", done, total)
+	})
+	if err != nil {
+		return fmt.Errorf("rotation error: %w", err)
+	}
+	log.Printf("Master key rotation finished, rotated user keys: %d", count)
+
+	return reencryptLegacyData(ctx, s)
+}
+
+// reencryptLegacyData перешифровывает ключами пользователей данные, сохраненные в устаревших форматах.
+func reencryptLegacyData(ctx context.Context, s *services.Services) error {
+	count, err := s.ReencryptLegacyData(ctx)
+	if err != nil {
+		return fmt.Errorf("reencrypt error: %w", err)
+	}
+	log.Printf("Legacy user data reencrypted: %d", count)
+
+	return nil
+}
+
 func configureServer(r chi.Router, enableHTTPS bool, runAddr string) *http.Server {
 	if enableHTTPS {
 		certManager := autocert.Manager{
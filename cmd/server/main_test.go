@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	webmock "github.com/MihailSergeenkov/GophKeeper/cmd/server/mocks"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/filestore"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
 	"github.com/golang/mock/gomock"
@@ -21,13 +23,26 @@ func TestConfigureServer(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	handlers := mocks.NewMockHandlerer(mockCtrl)
+	t.Setenv("JWT_KEY", "jwt key")
+	t.Setenv("MASTER_KEY", "master key")
 	settings, err := config.Setup(false)
 	require.NoError(t, err)
 
 	handlers.EXPECT().Ping().Times(1)
 	handlers.EXPECT().RegisterUser().Times(1)
 	handlers.EXPECT().CreateUserToken().Times(1)
+	handlers.EXPECT().RefreshUserToken().Times(1)
+	handlers.EXPECT().LoginTwoFactor().Times(1)
+	handlers.EXPECT().EnrollTwoFactor().Times(1)
+	handlers.EXPECT().VerifyTwoFactor().Times(1)
+	handlers.EXPECT().LogoutUser().Times(1)
+	handlers.EXPECT().GetSessions().Times(1)
+	handlers.EXPECT().RevokeSession().Times(1)
+	handlers.EXPECT().GetUsage().Times(1)
 	handlers.EXPECT().FetchUserData().Times(1)
+	handlers.EXPECT().ReserveDataID().Times(1)
+	handlers.EXPECT().GetVault().Times(1)
+	handlers.EXPECT().SetVaultCheck().Times(1)
 	handlers.EXPECT().GetPassword().Times(1)
 	handlers.EXPECT().AddPassword().Times(1)
 	handlers.EXPECT().GetCard().Times(1)
@@ -35,7 +50,34 @@ func TestConfigureServer(t *testing.T) {
 	handlers.EXPECT().GetText().Times(1)
 	handlers.EXPECT().AddText().Times(1)
 	handlers.EXPECT().GetFile().Times(1)
+	handlers.EXPECT().InitFileUpload().Times(1)
+	handlers.EXPECT().GetFileUpload().Times(1)
+	handlers.EXPECT().UploadFilePart().Times(1)
+	handlers.EXPECT().CompleteFileUpload().Times(1)
+	handlers.EXPECT().AbortFileUpload().Times(1)
 	handlers.EXPECT().AddFile().Times(1)
+	handlers.EXPECT().UpdatePassword().Times(1)
+	handlers.EXPECT().PatchPassword().Times(1)
+	handlers.EXPECT().DeletePassword().Times(1)
+	handlers.EXPECT().UpdateCard().Times(1)
+	handlers.EXPECT().PatchCard().Times(1)
+	handlers.EXPECT().DeleteCard().Times(1)
+	handlers.EXPECT().UpdateText().Times(1)
+	handlers.EXPECT().PatchText().Times(1)
+	handlers.EXPECT().DeleteText().Times(1)
+	handlers.EXPECT().GetOTP().Times(1)
+	handlers.EXPECT().AddOTP().Times(1)
+	handlers.EXPECT().UpdateOTP().Times(1)
+	handlers.EXPECT().PatchOTP().Times(1)
+	handlers.EXPECT().DeleteOTP().Times(1)
+	handlers.EXPECT().UpdateFile().Times(1)
+	handlers.EXPECT().PatchFile().Times(1)
+	handlers.EXPECT().DeleteFile().Times(1)
+	handlers.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Times(5)
+	handlers.EXPECT().RestoreRevision(gomock.Any(), gomock.Any()).Times(5)
+	handlers.EXPECT().GetTrash().Times(1)
+	handlers.EXPECT().RestoreTrash().Times(1)
+	handlers.EXPECT().EmptyTrash().Times(1)
 
 	logger := zap.NewNop()
 	storage := mocks.NewMockStorager(mockCtrl)
@@ -119,3 +161,47 @@ func TestRunServer_Failed(t *testing.T) {
 		require.ErrorContains(t, err, "listen and server has failed")
 	})
 }
+
+func TestNewFileStorage(t *testing.T) {
+	tests := []struct {
+		want     any
+		name     string
+		settings config.FileStorageSettings
+		wantErr  bool
+	}{
+		{
+			name:     "local",
+			settings: config.FileStorageSettings{Backend: config.FileStorageLocal, Dir: t.TempDir()},
+			want:     (*filestore.Local)(nil),
+		},
+		{
+			name:     "memory",
+			settings: config.FileStorageSettings{Backend: config.FileStorageMemory},
+			want:     (*filestore.Memory)(nil),
+		},
+		{
+			name:     "local without dir",
+			settings: config.FileStorageSettings{Backend: config.FileStorageLocal},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown",
+			settings: config.FileStorageSettings{Backend: "ftp"},
+			wantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := &config.Settings{FileStorage: test.settings}
+
+			fs, err := newFileStorage(context.Background(), settings, zap.NewNop())
+
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.IsType(t, test.want, fs)
+			}
+		})
+	}
+}
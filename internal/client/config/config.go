@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"maps"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/spf13/viper"
@@ -21,8 +23,11 @@ type Config struct {
 	Data           map[string]models.UserData
 	ServerAPI      string `mapstructure:"server_api"`
 	Token          string
-	RequestRetry   int `mapstructure:"request_retry"`
-	RequestTimeout int `mapstructure:"request_timeout"`
+	RefreshToken   string `mapstructure:"refresh_token"`
+	RequestRetry   int    `mapstructure:"request_retry"`
+	RequestTimeout int    `mapstructure:"request_timeout"`
+	QueuePath      string `mapstructure:"queue_path"`
+	SyncCursor     int64  `mapstructure:"sync_cursor"`
 }
 
 func Initializer(cfgFile *string) func() {
@@ -39,6 +44,7 @@ func Initializer(cfgFile *string) func() {
 			viper.SetDefault("request_retry", requestRetryDefault)
 			viper.SetDefault("request_timeout", requestTimeoutDefault)
 			viper.SetDefault("token", "")
+			viper.SetDefault("refresh_token", "")
 
 			if err := viper.SafeWriteConfig(); err != nil {
 				fmt.Println("Error while creating config file:", err)
@@ -69,18 +75,44 @@ func (cfg Config) GetToken() string {
 	return cfg.Token
 }
 
+func (cfg Config) GetRefreshToken() string {
+	return cfg.RefreshToken
+}
+
+// GetQueuePath возвращает путь к очереди изменений, сделанных без связи с сервером.
+// По умолчанию очередь лежит рядом с файлом конфигурации.
+func (cfg Config) GetQueuePath() string {
+	if cfg.QueuePath != "" {
+		return cfg.QueuePath
+	}
+
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(configFile, filepath.Ext(configFile)) + ".queue"
+}
+
+// GetSyncCursor возвращает курсор синхронизации: данные с сервера получены до этого изменения включительно.
+func (cfg Config) GetSyncCursor() int64 {
+	return cfg.SyncCursor
+}
+
 func (cfg Config) GetData() map[string]models.UserData {
 	return cfg.Data
 }
 
-func (cfg *Config) UpdateToken(token string) error {
+func (cfg *Config) UpdateTokens(token, refreshToken string) error {
 	viper.Set("token", token)
+	viper.Set("refresh_token", refreshToken)
 
 	if err := viper.WriteConfig(); err != nil {
 		return fmt.Errorf("failed update config file: %w", err)
 	}
 
 	cfg.Token = token
+	cfg.RefreshToken = refreshToken
 
 	return nil
 }
@@ -88,20 +120,43 @@ func (cfg *Config) UpdateToken(token string) error {
 func (cfg *Config) UpdateData(data []models.UserData) error {
 	updateData := make(map[string]models.UserData, len(data))
 	for _, v := range data {
-		if v.Type == "file" {
-			updateData[v.Mark] = v
-			continue
-		}
+		updateData[getKey(v)] = v
+	}
+	viper.Set("data", updateData)
+	viper.Set("sync_cursor", 0)
+
+	if err := viper.WriteConfig(); err != nil {
+		return fmt.Errorf("failed update config file: %w", err)
+	}
+
+	cfg.Data = updateData
+	cfg.SyncCursor = 0
+
+	return nil
+}
 
-		updateData[strconv.Itoa(v.ID)] = v
+// ApplyDataChanges применяет изменения данных с сервера и сохраняет новый курсор синхронизации.
+// Полный список изменений заменяет данные целиком.
+func (cfg *Config) ApplyDataChanges(changes models.UserDataChanges) error {
+	updateData := make(map[string]models.UserData, 0)
+	if !changes.Full {
+		maps.Copy(updateData, cfg.Data)
+	}
+	for _, id := range changes.Deleted {
+		delete(updateData, strconv.Itoa(id))
+	}
+	for _, v := range changes.Changes {
+		updateData[getKey(v)] = v
 	}
 	viper.Set("data", updateData)
+	viper.Set("sync_cursor", changes.Cursor)
 
 	if err := viper.WriteConfig(); err != nil {
 		return fmt.Errorf("failed update config file: %w", err)
 	}
 
 	cfg.Data = updateData
+	cfg.SyncCursor = changes.Cursor
 
 	return nil
 }
@@ -122,6 +177,21 @@ func (cfg *Config) AddData(data models.UserData) error {
 	return nil
 }
 
+func (cfg *Config) DeleteData(key string) error {
+	updateData := make(map[string]models.UserData, 0)
+	maps.Copy(updateData, cfg.Data)
+	delete(updateData, key)
+	viper.Set("data", updateData)
+
+	if err := viper.WriteConfig(); err != nil {
+		return fmt.Errorf("failed update config file: %w", err)
+	}
+
+	cfg.Data = updateData
+
+	return nil
+}
+
 func GetConfig() *Config {
 	return &cfg
 }
@@ -138,9 +208,5 @@ func initConfigFile(cfgFile *string) {
 }
 
 func getKey(data models.UserData) string {
-	if data.Type == "file" {
-		return data.Mark
-	}
-
 	return strconv.Itoa(data.ID)
 }
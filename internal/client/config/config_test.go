@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -59,18 +60,21 @@ func TestGetRequestTimeout(t *testing.T) {
 	})
 }
 
-func TestUpdateToken(t *testing.T) {
-	t.Run("update token", func(t *testing.T) {
+func TestUpdateTokens(t *testing.T) {
+	t.Run("update tokens", func(t *testing.T) {
 		cfgFile := ""
 		init := Initializer(&cfgFile)
 		init()
 
 		token := "test"
+		refreshToken := "refresh"
 
 		cfg := GetConfig()
-		err := cfg.UpdateToken(token)
+		err := cfg.UpdateTokens(token, refreshToken)
 
 		require.NoError(t, err)
+		assert.Equal(t, token, cfg.GetToken())
+		assert.Equal(t, refreshToken, cfg.GetRefreshToken())
 	})
 }
 
@@ -88,9 +92,9 @@ func TestUpdateData(t *testing.T) {
 				Description: "test",
 			},
 			{
-				ID:          1,
+				ID:          2,
 				Type:        "file",
-				Mark:        "filetest",
+				Mark:        "test",
 				Description: "test",
 			},
 		}
@@ -99,6 +103,75 @@ func TestUpdateData(t *testing.T) {
 		err := cfg.UpdateData(data)
 
 		require.NoError(t, err)
+		assert.Equal(t, data[0], cfg.GetData()["1"])
+		assert.Equal(t, data[1], cfg.GetData()["2"])
+	})
+}
+
+func TestApplyDataChanges(t *testing.T) {
+	cfgFile := ""
+	init := Initializer(&cfgFile)
+	init()
+
+	cfg := GetConfig()
+	require.NoError(t, cfg.UpdateData([]models.UserData{
+		{ID: 1, Type: "text", Mark: "test", Version: 1},
+		{ID: 2, Type: "password", Mark: "test", Version: 1},
+	}))
+
+	t.Run("apply changes after cursor", func(t *testing.T) {
+		err := cfg.ApplyDataChanges(models.UserDataChanges{
+			Changes: []models.UserData{{ID: 1, Type: "text", Mark: "new", Version: 2}, {ID: 3, Type: "card"}},
+			Deleted: []int{2},
+			Cursor:  5,
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, cfg.GetData(), 2)
+		assert.Equal(t, "new", cfg.GetData()["1"].Mark)
+		assert.Equal(t, 2, cfg.GetData()["1"].Version)
+		assert.Contains(t, cfg.GetData(), "3")
+		assert.Equal(t, int64(5), cfg.GetSyncCursor())
+	})
+
+	t.Run("replace data on full changes", func(t *testing.T) {
+		err := cfg.ApplyDataChanges(models.UserDataChanges{
+			Changes: []models.UserData{{ID: 4, Type: "otp"}},
+			Cursor:  7,
+			Full:    true,
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, cfg.GetData(), 1)
+		assert.Contains(t, cfg.GetData(), "4")
+		assert.Equal(t, int64(7), cfg.GetSyncCursor())
+	})
+
+	t.Run("reset cursor on update data", func(t *testing.T) {
+		require.NoError(t, cfg.UpdateData(nil))
+
+		assert.Empty(t, cfg.GetData())
+		assert.Zero(t, cfg.GetSyncCursor())
+	})
+}
+
+func TestGetQueuePath(t *testing.T) {
+	t.Run("queue next to config file", func(t *testing.T) {
+		prev := viper.ConfigFileUsed()
+		t.Cleanup(func() {
+			viper.SetConfigFile(prev)
+		})
+		viper.SetConfigFile("/home/user/.goph-keeper.yaml")
+
+		cfg := Config{}
+
+		assert.Equal(t, "/home/user/.goph-keeper.queue", cfg.GetQueuePath())
+	})
+
+	t.Run("queue path from config", func(t *testing.T) {
+		cfg := Config{QueuePath: "/tmp/keeper.queue"}
+
+		assert.Equal(t, "/tmp/keeper.queue", cfg.GetQueuePath())
 	})
 }
 
@@ -139,3 +212,21 @@ func TestAddData(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteData(t *testing.T) {
+	t.Run("delete data", func(t *testing.T) {
+		cfgFile := ""
+		init := Initializer(&cfgFile)
+		init()
+
+		cfg := GetConfig()
+		err := cfg.AddData(models.UserData{ID: 1, Type: "text", Mark: "test"})
+		require.NoError(t, err)
+
+		err = cfg.DeleteData("1")
+		require.NoError(t, err)
+
+		_, ok := cfg.GetData()["1"]
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,156 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Параметры Argon2id для получения ключа хранилища из парольной фразы.
+const (
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+
+	KeySize = 32
+)
+
+// Формат зашифрованных на клиенте данных: сигнатура | версия | nonce | шифротекст.
+// Данные версии 3 привязаны к дополнительным данным (например, типу и ID записи),
+// поэтому их нельзя незаметно подставить вместо других данных.
+// Версия 2 занята потоковым форматом файлов (streamV1), номера версий не должны совпадать.
+const (
+	sealV1 byte = 0x01
+	sealV2 byte = 0x03
+)
+
+var (
+	// sealMagic позволяет отличить зашифрованные на клиенте данные от данных, сохраненных до шифрования.
+	sealMagic = []byte("GKV")
+
+	// checkData известные данные, по которым проверяется парольная фраза.
+	checkData = []byte("goph-keeper vault check")
+
+	ErrInvalidSealedData = errors.New("invalid sealed data")
+	ErrWrongPassphrase   = errors.New("wrong passphrase")
+)
+
+// DeriveKey функция для получения ключа хранилища из парольной фразы и соли пользователя.
+func DeriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, argonTime, argonMemory, argonThreads, KeySize)
+}
+
+// Seal функция для шифрования данных ключом хранилища.
+func Seal(key, data []byte) ([]byte, error) {
+	return seal(key, data, sealV1, nil)
+}
+
+// Open функция для расшифровки данных ключом хранилища.
+func Open(key, sealed []byte) ([]byte, error) {
+	if !IsSealed(sealed) {
+		return nil, ErrInvalidSealedData
+	}
+
+	return open(key, sealed, nil)
+}
+
+// SealFor функция для шифрования данных ключом хранилища с привязкой к дополнительным данным aad.
+func SealFor(key, data, aad []byte) ([]byte, error) {
+	return seal(key, data, sealV2, aad)
+}
+
+// OpenFor функция для расшифровки данных, привязанных к дополнительным данным aad.
+// Данные без привязки или привязанные к другим дополнительным данным не расшифровываются.
+func OpenFor(key, sealed, aad []byte) ([]byte, error) {
+	if !IsBound(sealed) {
+		return nil, ErrInvalidSealedData
+	}
+
+	return open(key, sealed, aad)
+}
+
+// IsSealed функция для проверки, зашифрованы ли данные на клиенте без привязки.
+func IsSealed(data []byte) bool {
+	return hasVersion(data, sealV1)
+}
+
+// IsBound функция для проверки, зашифрованы ли данные на клиенте с привязкой к дополнительным данным.
+func IsBound(data []byte) bool {
+	return hasVersion(data, sealV2)
+}
+
+// NewCheck функция для создания проверочного блока ключа хранилища.
+func NewCheck(key []byte) ([]byte, error) {
+	return Seal(key, checkData)
+}
+
+// VerifyCheck функция для проверки ключа хранилища по проверочному блоку.
+func VerifyCheck(key, check []byte) error {
+	data, err := Open(key, check)
+	if err != nil || !bytes.Equal(data, checkData) {
+		return ErrWrongPassphrase
+	}
+
+	return nil
+}
+
+func seal(key, data []byte, version byte, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(sealMagic)+1+aead.NonceSize())
+	header = append(header, sealMagic...)
+	header = append(header, version)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header = append(header, nonce...)
+
+	return aead.Seal(header, nonce, data, aad), nil
+}
+
+func open(key, sealed, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	body := sealed[len(sealMagic)+1:]
+	if len(body) < aead.NonceSize() {
+		return nil, ErrInvalidSealedData
+	}
+
+	data, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data: %w", err)
+	}
+
+	return data, nil
+}
+
+func hasVersion(data []byte, version byte) bool {
+	return len(data) > len(sealMagic) && bytes.HasPrefix(data, sealMagic) && data[len(sealMagic)] == version
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+
+	return aead, nil
+}
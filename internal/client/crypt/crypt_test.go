@@ -0,0 +1,282 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveKey(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	key := DeriveKey("passphrase", salt)
+
+	assert.Len(t, key, KeySize)
+	assert.Equal(t, key, DeriveKey("passphrase", salt))
+	assert.NotEqual(t, key, DeriveKey("other", salt))
+	assert.NotEqual(t, key, DeriveKey("passphrase", []byte("fedcba9876543210")))
+}
+
+func TestSealOpen(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+	data := []byte(`{"login":"test","password":"test"}`)
+
+	sealed, err := Seal(key, data)
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "password")
+
+	other, err := Seal(key, data)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, other)
+
+	opened, err := Open(key, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	_, err = Open(DeriveKey("other", []byte("salt")), sealed)
+	require.Error(t, err)
+
+	_, err = Open(key, data)
+	require.ErrorIs(t, err, ErrInvalidSealedData)
+	assert.False(t, IsSealed(data))
+}
+
+func TestSealOpenFor(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+	data := []byte(`{"login":"test","password":"test"}`)
+	aad := []byte("password:1")
+
+	sealed, err := SealFor(key, data, aad)
+	require.NoError(t, err)
+	assert.True(t, IsBound(sealed))
+	assert.False(t, IsSealed(sealed))
+	assert.False(t, IsStream(sealed))
+
+	opened, err := OpenFor(key, sealed, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	_, err = OpenFor(key, sealed, []byte("password:2"))
+	require.Error(t, err)
+
+	_, err = Open(key, sealed)
+	require.ErrorIs(t, err, ErrInvalidSealedData)
+
+	unbound, err := Seal(key, data)
+	require.NoError(t, err)
+
+	_, err = OpenFor(key, unbound, aad)
+	require.ErrorIs(t, err, ErrInvalidSealedData)
+
+	_, err = OpenFor(key, data, aad)
+	require.ErrorIs(t, err, ErrInvalidSealedData)
+}
+
+func TestCheck(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+
+	check, err := NewCheck(key)
+	require.NoError(t, err)
+
+	require.NoError(t, VerifyCheck(key, check))
+	require.ErrorIs(t, VerifyCheck(DeriveKey("other", []byte("salt")), check), ErrWrongPassphrase)
+}
+
+func TestEncryptDecryptStream(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+	aad := []byte("goph-keeper:file:1")
+
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "small", size: 100},
+		{name: "exact chunk", size: ChunkSize},
+		{name: "several chunks", size: 3*ChunkSize + 17},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("secret file data "), tt.size/17+1)[:tt.size]
+
+			var sealed bytes.Buffer
+			w, err := NewEncryptWriter(key, aad, &sealed)
+			require.NoError(t, err)
+			_, err = io.Copy(w, bytes.NewReader(data))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			assert.True(t, IsStream(sealed.Bytes()))
+			assert.False(t, IsSealed(sealed.Bytes()))
+			assert.False(t, IsBound(sealed.Bytes()))
+			if tt.size > 0 {
+				assert.NotContains(t, sealed.String(), "secret")
+			}
+
+			r, err := NewDecryptReader(key, aad, bytes.NewReader(sealed.Bytes()))
+			require.NoError(t, err)
+			opened, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, data, opened)
+		})
+	}
+}
+
+func TestDecryptStreamErrors(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+	aad := []byte("goph-keeper:file:1")
+	data := bytes.Repeat([]byte{0x42}, 2*ChunkSize+10)
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(key, aad, &buf)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	sealed := buf.Bytes()
+
+	chunk := ChunkSize + 16
+	headerSize := len(sealed) - 2*chunk - (10 + 16)
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := NewDecryptReader(DeriveKey("other", []byte("salt")), aad, bytes.NewReader(sealed))
+		require.Error(t, err)
+	})
+
+	t.Run("other record", func(t *testing.T) {
+		_, err := NewDecryptReader(key, []byte("goph-keeper:file:2"), bytes.NewReader(sealed))
+		require.ErrorIs(t, err, ErrInvalidSealedData)
+	})
+
+	t.Run("not stream", func(t *testing.T) {
+		_, err := NewDecryptReader(key, aad, bytes.NewReader(data))
+		require.ErrorIs(t, err, ErrInvalidSealedData)
+	})
+
+	header := sealed[:headerSize]
+	first, second, last := sealed[headerSize:headerSize+chunk], sealed[headerSize+chunk:headerSize+2*chunk],
+		sealed[headerSize+2*chunk:]
+
+	corrupted := map[string][]byte{
+		"truncated after full chunk": concat(header, first),
+		"truncated last chunk":       sealed[:len(sealed)-1],
+		"modified chunk":             concat(header, flipByte(first), second, last),
+		"reordered chunks":           concat(header, second, first, last),
+		"dropped chunk":              concat(header, first, last),
+	}
+
+	for name, c := range corrupted {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewDecryptReader(key, aad, bytes.NewReader(c))
+			require.NoError(t, err)
+
+			_, err = io.ReadAll(r)
+			require.ErrorIs(t, err, ErrInvalidSealedData)
+		})
+	}
+}
+
+func TestDecryptStreamAt(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+	aad := []byte("goph-keeper:file:1")
+	data := bytes.Repeat([]byte("secret file data "), 2*ChunkSize/17+1)[:2*ChunkSize+10]
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(key, aad, &buf)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	sealed := buf.Bytes()
+
+	src := bytes.NewReader(sealed)
+	header, err := ReadStreamHeader(src)
+	require.NoError(t, err)
+	assert.Equal(t, len(sealed)-src.Len(), len(header))
+
+	chunkSize, err := StreamChunkSize(header)
+	require.NoError(t, err)
+	assert.Equal(t, int64(ChunkSize), chunkSize)
+
+	offset, err := StreamChunkOffset(header, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(header)+ChunkSize+16), offset)
+
+	t.Run("decrypt from chunk", func(t *testing.T) {
+		r, err := NewDecryptReaderAt(key, aad, header, 1, bytes.NewReader(sealed[offset:]))
+		require.NoError(t, err)
+
+		opened, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data[ChunkSize:], opened)
+	})
+
+	t.Run("chunk does not match its number", func(t *testing.T) {
+		r, err := NewDecryptReaderAt(key, aad, header, 0, bytes.NewReader(sealed[offset:]))
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrInvalidSealedData)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		_, err := NewDecryptReaderAt(key, aad, header[:len(header)-1], 1, bytes.NewReader(sealed[offset:]))
+		require.ErrorIs(t, err, ErrInvalidSealedData)
+	})
+
+	t.Run("failed read header", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+
+		_, err := ReadStreamHeader(io.MultiReader(bytes.NewReader(sealed[:4]), iotest.ErrReader(readErr)))
+
+		require.ErrorIs(t, err, readErr)
+		assert.NotErrorIs(t, err, ErrInvalidSealedData)
+	})
+}
+
+func TestDecryptLegacyStream(t *testing.T) {
+	key := DeriveKey("passphrase", []byte("salt"))
+	data := []byte("secret file data")
+
+	// Ключ файла в потоках старых клиентов зашифрован без привязки к записи.
+	fileKey := bytes.Repeat([]byte{0x01}, KeySize)
+	wrapped, err := Seal(key, fileKey)
+	require.NoError(t, err)
+
+	header := make([]byte, streamHeaderSize, streamHeaderSize+len(wrapped))
+	copy(header, sealMagic)
+	header[len(sealMagic)] = streamV1
+	binary.BigEndian.PutUint32(header[len(sealMagic)+1:], ChunkSize)
+	binary.BigEndian.PutUint16(header[len(sealMagic)+5:], uint16(len(wrapped)))
+	header = append(header, wrapped...)
+
+	aead, err := newAEAD(fileKey)
+	require.NoError(t, err)
+	sealed := aead.Seal(slices.Clone(header), chunkNonce(aead.NonceSize(), 0, true), data, header)
+
+	r, err := NewDecryptReader(key, []byte("goph-keeper:file:1"), bytes.NewReader(sealed))
+	require.NoError(t, err)
+	opened, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func flipByte(data []byte) []byte {
+	res := append([]byte{}, data...)
+	res[0] ^= 0xff
+
+	return res
+}
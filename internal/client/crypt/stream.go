@@ -0,0 +1,322 @@
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Формат потокового шифрования файлов:
+// сигнатура | версия | размер части | длина ключа | ключ файла, зашифрованный ключом хранилища | части.
+// Ключ файла шифруется с привязкой к записи (см. SealFor), поэтому файл другой записи не расшифровывается.
+// Каждая часть шифруется отдельно ключом файла. Nonce части состоит из ее номера и признака последней части,
+// а заголовок используется как дополнительные данные, поэтому части нельзя переставить, отрезать или подменить.
+const (
+	streamV1 byte = 0x02
+
+	// ChunkSize размер части открытых данных при потоковом шифровании.
+	ChunkSize = 64 * 1024
+
+	maxChunkSize      = 16 * 1024 * 1024
+	maxWrappedKeySize = 1024
+	lastChunkFlag     = 0x01
+
+	// chunkOverhead размер тега AES-GCM, на который зашифрованная часть больше открытой.
+	chunkOverhead = 16
+)
+
+var (
+	// StreamPrefixSize размер начала данных, по которому IsStream распознает потоковое шифрование.
+	StreamPrefixSize = len(sealMagic) + 1
+
+	// streamHeaderSize размер заголовка до зашифрованного ключа файла.
+	streamHeaderSize = StreamPrefixSize + 4 + 2
+)
+
+// IsStream функция для проверки, зашифрованы ли данные на клиенте потоково.
+func IsStream(data []byte) bool {
+	return len(data) > len(sealMagic) && bytes.HasPrefix(data, sealMagic) && data[len(sealMagic)] == streamV1
+}
+
+// encryptWriter шифрует данные частями по мере записи.
+type encryptWriter struct {
+	dst     io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	buf     []byte
+	out     []byte
+	counter uint64
+	closed  bool
+}
+
+// NewEncryptWriter функция для потокового шифрования данных, записываемых в dst.
+// Для данных создается случайный ключ, который шифруется ключом хранилища с привязкой к aad
+// и записывается в заголовок. Последняя часть записывается при вызове Close, поэтому Close обязателен.
+func NewEncryptWriter(key, aad []byte, dst io.Writer) (io.WriteCloser, error) {
+	fileKey := make([]byte, KeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, fmt.Errorf("failed to generate file key: %w", err)
+	}
+
+	wrapped, err := SealFor(key, fileKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap file key: %w", err)
+	}
+
+	aead, err := newAEAD(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, streamHeaderSize, streamHeaderSize+len(wrapped))
+	copy(header, sealMagic)
+	header[len(sealMagic)] = streamV1
+	binary.BigEndian.PutUint32(header[len(sealMagic)+1:], ChunkSize)
+	keySize := uint16(len(wrapped)) //nolint:gosec // длина зашифрованного ключа фиксирована
+	binary.BigEndian.PutUint16(header[len(sealMagic)+5:], keySize)
+	header = append(header, wrapped...)
+
+	if _, err := dst.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	return &encryptWriter{
+		dst:    dst,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, 0, ChunkSize),
+		out:    make([]byte, 0, ChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encrypt writer")
+	}
+
+	n := 0
+	for len(p) > 0 {
+		// Заполненная часть шифруется только когда появились следующие данные:
+		// до этого неизвестно, последняя ли она.
+		if len(e.buf) == ChunkSize {
+			if err := e.flush(false); err != nil {
+				return n, err
+			}
+		}
+
+		c := copy(e.buf[len(e.buf):ChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+		n += c
+	}
+
+	return n, nil
+}
+
+// Close шифрует и записывает последнюю часть данных.
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	return e.flush(true)
+}
+
+func (e *encryptWriter) flush(last bool) error {
+	e.out = e.aead.Seal(e.out[:0], chunkNonce(e.aead.NonceSize(), e.counter, last), e.buf, e.header)
+	if _, err := e.dst.Write(e.out); err != nil {
+		return fmt.Errorf("failed to write chunk: %w", err)
+	}
+
+	e.counter++
+	e.buf = e.buf[:0]
+
+	return nil
+}
+
+// decryptReader расшифровывает данные частями по мере чтения.
+type decryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	buf     []byte
+	plain   []byte
+	pending []byte
+	counter uint64
+	done    bool
+}
+
+// NewDecryptReader функция для потоковой расшифровки данных из src ключом хранилища.
+// Ключ файла должен быть привязан к aad. Ошибка ErrInvalidSealedData возвращается при чтении,
+// если данные повреждены или обрезаны.
+func NewDecryptReader(key, aad []byte, src io.Reader) (io.Reader, error) {
+	r := bufio.NewReader(src)
+
+	header, err := ReadStreamHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDecryptReaderAt(key, aad, header, 0, r)
+}
+
+// NewDecryptReaderAt функция для потоковой расшифровки данных с заголовком header, начиная с части
+// с номером chunk. Данные src должны начинаться с этой части, ее смещение возвращает StreamChunkOffset.
+// Так расшифровку можно продолжить после обрыва загрузки, не сохраняя уже расшифрованные части в зашифрованном виде.
+func NewDecryptReaderAt(key, aad, header []byte, chunk int64, src io.Reader) (io.Reader, error) {
+	chunkSize, err := StreamChunkSize(header)
+	if err != nil || chunk < 0 {
+		return nil, ErrInvalidSealedData
+	}
+
+	fileKey, err := unwrapFileKey(key, aad, header[streamHeaderSize:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unwrap file key: %w", ErrInvalidSealedData, err)
+	}
+
+	aead, err := newAEAD(fileKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSealedData, err)
+	}
+
+	return &decryptReader{
+		src:     bufio.NewReader(src),
+		aead:    aead,
+		header:  header,
+		buf:     make([]byte, int(chunkSize)+aead.Overhead()),
+		plain:   make([]byte, 0, chunkSize),
+		counter: uint64(chunk),
+	}, nil
+}
+
+// unwrapFileKey расшифровывает ключ файла, привязанный к aad. Ключи файлов, загруженных клиентами
+// до привязки к записи, расшифровываются без нее: такие файлы, как и файлы старых форматов,
+// открываются без проверки записи.
+func unwrapFileKey(key, aad, wrapped []byte) ([]byte, error) {
+	if IsBound(wrapped) {
+		return OpenFor(key, wrapped, aad)
+	}
+
+	return Open(key, wrapped)
+}
+
+// ReadStreamHeader функция для чтения заголовка потоково зашифрованных данных из src.
+// После чтения src указывает на первую часть. Обрезанный или неверный заголовок - ErrInvalidSealedData,
+// остальные ошибки чтения src возвращаются как есть.
+func ReadStreamHeader(src io.Reader) ([]byte, error) {
+	header := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return nil, headerReadError(err)
+	}
+
+	if _, err := StreamChunkSize(header); err != nil {
+		return nil, err
+	}
+
+	wrapped := make([]byte, binary.BigEndian.Uint16(header[len(sealMagic)+5:]))
+	if _, err := io.ReadFull(src, wrapped); err != nil {
+		return nil, headerReadError(err)
+	}
+
+	return append(header, wrapped...), nil
+}
+
+// StreamChunkSize функция для получения размера части открытых данных по заголовку header
+// потоково зашифрованных данных (заголовок может быть и без ключа файла).
+func StreamChunkSize(header []byte) (int64, error) {
+	if len(header) < streamHeaderSize || !IsStream(header) {
+		return 0, ErrInvalidSealedData
+	}
+
+	chunkSize := binary.BigEndian.Uint32(header[len(sealMagic)+1:])
+	keySize := binary.BigEndian.Uint16(header[len(sealMagic)+5:])
+	if chunkSize == 0 || chunkSize > maxChunkSize || keySize > maxWrappedKeySize {
+		return 0, ErrInvalidSealedData
+	}
+	if len(header) > streamHeaderSize && len(header) != streamHeaderSize+int(keySize) {
+		return 0, ErrInvalidSealedData
+	}
+
+	return int64(chunkSize), nil
+}
+
+// StreamChunkOffset функция для получения смещения части с номером chunk в потоково зашифрованных данных
+// с заголовком header.
+func StreamChunkOffset(header []byte, chunk int64) (int64, error) {
+	chunkSize, err := StreamChunkSize(header)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(header)) + chunk*(chunkSize+chunkOverhead), nil
+}
+
+func headerReadError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrInvalidSealedData
+	}
+
+	return fmt.Errorf("failed to read header: %w", err)
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	n, err := io.ReadFull(d.src, d.buf)
+
+	last := false
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return fmt.Errorf("failed to read chunk: %w", err)
+	default:
+		if _, pErr := d.src.Peek(1); errors.Is(pErr, io.EOF) {
+			last = true
+		} else if pErr != nil {
+			return fmt.Errorf("failed to read chunk: %w", pErr)
+		}
+	}
+
+	plain, err := d.aead.Open(d.plain[:0], chunkNonce(d.aead.NonceSize(), d.counter, last), d.buf[:n], d.header)
+	if err != nil {
+		return fmt.Errorf("%w: chunk %d: %w", ErrInvalidSealedData, d.counter, err)
+	}
+
+	d.counter++
+	d.pending = plain
+	d.done = last
+
+	return nil
+}
+
+// chunkNonce возвращает nonce части с номером counter.
+func chunkNonce(size int, counter uint64, last bool) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-9:], counter)
+	if last {
+		nonce[size-1] = lastChunkFlag
+	}
+
+	return nonce
+}
@@ -0,0 +1,176 @@
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+// Configurer интерфейс для конфигурации очереди изменений.
+type Configurer interface {
+	GetQueuePath() string
+}
+
+// Queue локальная очередь изменений, сделанных клиентом без связи с сервером.
+// Очередь - один JSON-файл: в нем лежат изменения, данные которых уже зашифрованы ключом хранилища,
+// и открытые параметры этого ключа (соль и проверочный блок), чтобы без связи с сервером
+// проверить парольную фразу. Если путь к файлу не задан, очередь ничего не сохраняет.
+type Queue struct {
+	cfg Configurer
+}
+
+// queueFile формат файла очереди изменений.
+type queueFile struct {
+	Salt    []byte                 `json:"salt"`
+	Check   []byte                 `json:"check"`
+	Changes []models.PendingChange `json:"changes"`
+}
+
+// New функция инициализации очереди изменений. Путь к файлу берется из конфигурации при каждом обращении,
+// поэтому конфигурацию можно прочитать после создания очереди.
+func New(cfg Configurer) *Queue {
+	return &Queue{cfg: cfg}
+}
+
+// KeyParams возвращает параметры ключа хранилища, с которыми зашифрованы данные изменений.
+func (q *Queue) KeyParams() (models.Vault, error) {
+	f, err := q.read()
+	if err != nil {
+		return models.Vault{}, err
+	}
+
+	return models.Vault{Salt: f.Salt, Check: f.Check}, nil
+}
+
+// SetKeyParams сохраняет параметры ключа хранилища. Файл перезаписывается, только если они изменились.
+func (q *Queue) SetKeyParams(params models.Vault) error {
+	f, err := q.read()
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(f.Salt, params.Salt) && bytes.Equal(f.Check, params.Check) {
+		return nil
+	}
+	f.Salt = params.Salt
+	f.Check = params.Check
+
+	return q.write(f)
+}
+
+// QueueChange добавляет изменение в конец очереди.
+func (q *Queue) QueueChange(change models.PendingChange) error {
+	f, err := q.read()
+	if err != nil {
+		return err
+	}
+
+	f.Changes = append(f.Changes, change)
+
+	return q.write(f)
+}
+
+// PendingChanges возвращает очередь изменений в порядке их добавления.
+func (q *Queue) PendingChanges() ([]models.PendingChange, error) {
+	f, err := q.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Changes, nil
+}
+
+// SetPendingChanges заменяет очередь изменений, например, оставляя в ней только неотправленные изменения.
+func (q *Queue) SetPendingChanges(changes []models.PendingChange) error {
+	f, err := q.read()
+	if err != nil {
+		return err
+	}
+
+	if len(f.Changes) == 0 && len(changes) == 0 {
+		return nil
+	}
+	f.Changes = changes
+
+	return q.write(f)
+}
+
+// Clear удаляет очередь вместе с параметрами ключа.
+func (q *Queue) Clear() error {
+	path := q.path()
+	if path == "" {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove offline queue: %w", err)
+	}
+
+	return nil
+}
+
+func (q *Queue) path() string {
+	return q.cfg.GetQueuePath()
+}
+
+// read читает файл очереди. Если файла нет, возвращается пустая очередь.
+func (q *Queue) read() (queueFile, error) {
+	var f queueFile
+
+	path := q.path()
+	if path == "" {
+		return f, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return f, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("failed to parse offline queue: %w", err)
+	}
+
+	return f, nil
+}
+
+// write записывает файл очереди через временный файл, чтобы при сбое не потерять прежнее содержимое.
+func (q *Queue) write(f queueFile) error {
+	path := q.path()
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode offline queue: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package offline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig string
+
+func (c testConfig) GetQueuePath() string {
+	return string(c)
+}
+
+func TestQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keeper.queue")
+	q := New(testConfig(path))
+
+	t.Run("empty queue", func(t *testing.T) {
+		params, err := q.KeyParams()
+		require.NoError(t, err)
+		assert.Empty(t, params.Salt)
+
+		queue, err := q.PendingChanges()
+		require.NoError(t, err)
+		assert.Empty(t, queue)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("set key params", func(t *testing.T) {
+		require.NoError(t, q.SetKeyParams(models.Vault{Salt: []byte("salt"), Check: []byte("check")}))
+
+		params, err := q.KeyParams()
+		require.NoError(t, err)
+		assert.Equal(t, models.Vault{Salt: []byte("salt"), Check: []byte("check")}, params)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("queue changes", func(t *testing.T) {
+		mark := "new"
+		first := models.PendingChange{ID: 1, Type: "password", Request: models.UpdateSecretRequest{Mark: &mark, Version: 2}}
+		second := models.PendingChange{ID: 2, Type: "text", Deleted: true}
+
+		require.NoError(t, q.QueueChange(first))
+		require.NoError(t, q.QueueChange(second))
+
+		queue, err := q.PendingChanges()
+		require.NoError(t, err)
+		assert.Equal(t, []models.PendingChange{first, second}, queue)
+
+		require.NoError(t, q.SetPendingChanges(queue[1:]))
+
+		queue, err = q.PendingChanges()
+		require.NoError(t, err)
+		assert.Equal(t, []models.PendingChange{second}, queue)
+
+		params, err := q.KeyParams()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("salt"), params.Salt)
+	})
+
+	t.Run("clear queue", func(t *testing.T) {
+		require.NoError(t, q.Clear())
+		require.NoError(t, q.Clear())
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("corrupted queue", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+		_, err := q.PendingChanges()
+		require.ErrorContains(t, err, "failed to parse offline queue")
+	})
+}
+
+func TestQueueWithoutPath(t *testing.T) {
+	q := New(testConfig(""))
+
+	require.NoError(t, q.SetKeyParams(models.Vault{Salt: []byte("salt")}))
+	require.NoError(t, q.QueueChange(models.PendingChange{ID: 1}))
+
+	queue, err := q.PendingChanges()
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+	require.NoError(t, q.Clear())
+}
@@ -1,15 +1,48 @@
 package requests
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/go-resty/resty/v2"
 )
 
+const (
+	authHeader  = "X-AUTH-TOKEN"
+	refreshPath = "/user/token/refresh"
+)
+
+var ErrTooManyRequests = errors.New("too many requests")
+
+// TooManyRequestsError ошибка ответа 429, сервер ограничил частоту запросов или заблокировал вход.
+type TooManyRequestsError struct {
+	RetryAfter time.Duration
+}
+
+// Error возвращает текст ошибки.
+func (e *TooManyRequestsError) Error() string {
+	if e.RetryAfter <= 0 {
+		return fmt.Sprintf("%s, retry later", ErrTooManyRequests)
+	}
+
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyRequests, e.RetryAfter)
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrTooManyRequests.
+func (e *TooManyRequestsError) Unwrap() error {
+	return ErrTooManyRequests
+}
+
 // Request определяет тип HTTP запроса.
 type Request struct {
-	r *resty.Request
+	cfg    *config.Config
+	client *resty.Client
+	r      *resty.Request
 }
 
 // RequestOptionFunc определяет тип функции для опций.
@@ -17,33 +50,107 @@ type RequestOptionFunc func(*Request)
 
 // NewRequests инициализатор для HTTP запросов.
 func NewRequests(cfg *config.Config) *Request {
-	r := resty.New().
+	client := resty.New().
 		SetTimeout(time.Duration(cfg.GetRequestTimeout()) * time.Second).
-		SetRetryCount(cfg.GetRequestRetry()).R()
+		SetRetryCount(cfg.GetRequestRetry())
 
-	return &Request{r: r}
+	return &Request{cfg: cfg, client: client, r: client.R()}
 }
 
 // Get функция для выполнения get HTTP запросов.
 func (o *Request) Get(url string, opts ...RequestOptionFunc) (*resty.Response, error) {
+	return o.execute(http.MethodGet, url, opts...)
+}
+
+// Post функция для выполнения post HTTP запросов.
+func (o *Request) Post(url string, opts ...RequestOptionFunc) (*resty.Response, error) {
+	return o.execute(http.MethodPost, url, opts...)
+}
+
+// Put функция для выполнения put HTTP запросов.
+func (o *Request) Put(url string, opts ...RequestOptionFunc) (*resty.Response, error) {
+	return o.execute(http.MethodPut, url, opts...)
+}
+
+// Patch функция для выполнения patch HTTP запросов.
+func (o *Request) Patch(url string, opts ...RequestOptionFunc) (*resty.Response, error) {
+	return o.execute(http.MethodPatch, url, opts...)
+}
+
+// Delete функция для выполнения delete HTTP запросов.
+func (o *Request) Delete(url string, opts ...RequestOptionFunc) (*resty.Response, error) {
+	return o.execute(http.MethodDelete, url, opts...)
+}
+
+// execute выполняет HTTP запрос. Если сервер ответил 401 на запрос с ключом доступа,
+// ключ обновляется по refresh токену и запрос повторяется один раз.
+// Ответ 429 возвращается как ошибка TooManyRequestsError.
+func (o *Request) execute(method, url string, opts ...RequestOptionFunc) (*resty.Response, error) {
+	// Каждый запрос собирается заново, чтобы заголовки и опции прошлых запросов не переносились.
+	o.r = o.client.R()
 	for _, opt := range opts {
 		opt(o)
 	}
 
-	resp, err := o.r.Get(url)
+	resp, err := o.r.Execute(method, url)
+	if err == nil && resp.StatusCode() == http.StatusUnauthorized && o.r.Header.Get(authHeader) != "" {
+		if token, ok := o.refreshToken(); ok {
+			closeRawBody(resp)
+			o.r.SetHeader(authHeader, token)
+			resp, err = o.r.Execute(method, url)
+		}
+	}
+
+	if err == nil && resp.StatusCode() == http.StatusTooManyRequests {
+		return resp, &TooManyRequestsError{RetryAfter: parseRetryAfter(resp.Header().Get("Retry-After"))}
+	}
 
 	return resp, err //nolint:wrapcheck // Нужно обернуть, но возврат должен остаться оригинальным
 }
 
-// Post функция для выполнения post HTTP запросов.
-func (o *Request) Post(url string, opts ...RequestOptionFunc) (*resty.Response, error) {
-	for _, opt := range opts {
-		opt(o)
+// closeRawBody закрывает тело ответа, которое не было прочитано при потоковом получении.
+func closeRawBody(resp *resty.Response) {
+	if resp.RawResponse != nil && resp.RawResponse.Body != nil {
+		_ = resp.RawResponse.Body.Close()
 	}
+}
 
-	resp, err := o.r.Post(url)
+// parseRetryAfter разбирает заголовок Retry-After в секундах или в виде даты.
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
 
-	return resp, err //nolint:wrapcheck // Нужно обернуть, но возврат должен остаться оригинальным
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date).Round(time.Second)
+	}
+
+	return 0
+}
+
+// refreshToken запрашивает новую пару токенов и сохраняет ее в конфигурации.
+func (o *Request) refreshToken() (string, bool) {
+	refreshToken := o.cfg.GetRefreshToken()
+	if refreshToken == "" {
+		return "", false
+	}
+
+	tokens := models.CreateUserTokenResponse{}
+
+	resp, err := o.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(models.RefreshUserTokenRequest{RefreshToken: refreshToken}).
+		SetResult(&tokens).
+		Post(o.cfg.GetServerAPI() + refreshPath)
+	if err != nil || resp.StatusCode() != http.StatusOK {
+		return "", false
+	}
+
+	if err := o.cfg.UpdateTokens(tokens.AuthToken, tokens.RefreshToken); err != nil {
+		return "", false
+	}
+
+	return tokens.AuthToken, true
 }
 
 // WithHeader добавляет header к запросу.
@@ -74,6 +181,13 @@ func WithPathParams(params map[string]string) RequestOptionFunc {
 	}
 }
 
+// WithQueryParams добавляет параметры строки запроса.
+func WithQueryParams(params map[string]string) RequestOptionFunc {
+	return func(o *Request) {
+		o.r.SetQueryParams(params)
+	}
+}
+
 // WithOutput добавляет возможность сохранять ответ в файл.
 func WithOutput(file string) RequestOptionFunc {
 	return func(o *Request) {
@@ -81,6 +195,14 @@ func WithOutput(file string) RequestOptionFunc {
 	}
 }
 
+// WithStream отключает чтение тела ответа, чтобы его можно было читать потоком через RawBody.
+// Тело ответа должен закрыть вызывающий код.
+func WithStream() RequestOptionFunc {
+	return func(o *Request) {
+		o.r.SetDoNotParseResponse(true)
+	}
+}
+
 // WithFile добавляет возможность отправлять файл в запросе.
 func WithFile(filePath string) RequestOptionFunc {
 	return func(o *Request) {
@@ -1,9 +1,18 @@
 package requests
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,3 +48,206 @@ func TestPost(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestPut(t *testing.T) {
+	t.Run("put request", func(t *testing.T) {
+		cfg := config.GetConfig()
+		r := NewRequests(cfg)
+
+		_, err := r.Put("http://localhost/api")
+
+		require.Error(t, err)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	t.Run("patch request", func(t *testing.T) {
+		cfg := config.GetConfig()
+		r := NewRequests(cfg)
+
+		_, err := r.Patch("http://localhost/api")
+
+		require.Error(t, err)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("delete request", func(t *testing.T) {
+		cfg := config.GetConfig()
+		r := NewRequests(cfg)
+
+		_, err := r.Delete("http://localhost/api")
+
+		require.Error(t, err)
+	})
+}
+
+func initTestConfig(t *testing.T, serverAPI, refreshToken string) *config.Config {
+	t.Helper()
+
+	cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server_api: " + serverAPI + "\ntoken: old\nrefresh_token: " + refreshToken + "\n"
+	require.NoError(t, os.WriteFile(cfgFile, []byte(content), 0o600))
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	config.Initializer(&cfgFile)()
+
+	return config.GetConfig()
+}
+
+func TestRefreshOnUnauthorized(t *testing.T) {
+	refreshCalls := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/user/token/refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshCalls++
+
+		var req models.RefreshUserTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken != "refresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CreateUserTokenResponse{AuthToken: "new", RefreshToken: "new refresh"})
+	})
+	mux.HandleFunc("/api/user/data", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authHeader) != "new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	t.Run("refresh token and retry request", func(t *testing.T) {
+		refreshCalls = 0
+		cfg := initTestConfig(t, ts.URL+"/api", "refresh")
+		r := NewRequests(cfg)
+
+		resp, err := r.Get(ts.URL+"/api/user/data", WithHeader(authHeader, cfg.GetToken()))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, 1, refreshCalls)
+		assert.Equal(t, "new", cfg.GetToken())
+		assert.Equal(t, "new refresh", cfg.GetRefreshToken())
+	})
+
+	t.Run("return 401 when refresh failed", func(t *testing.T) {
+		refreshCalls = 0
+		cfg := initTestConfig(t, ts.URL+"/api", "revoked")
+		r := NewRequests(cfg)
+
+		resp, err := r.Get(ts.URL+"/api/user/data", WithHeader(authHeader, cfg.GetToken()))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Equal(t, 1, refreshCalls)
+		assert.Equal(t, "old", cfg.GetToken())
+	})
+
+	t.Run("do not refresh request without token", func(t *testing.T) {
+		refreshCalls = 0
+		cfg := initTestConfig(t, ts.URL+"/api", "refresh")
+		r := NewRequests(cfg)
+
+		resp, err := r.Get(ts.URL + "/api/user/data")
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Equal(t, 0, refreshCalls)
+	})
+}
+
+func TestTooManyRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/user/token", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "90")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/api/user/register", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	t.Run("return error with retry after", func(t *testing.T) {
+		cfg := initTestConfig(t, ts.URL+"/api", "refresh")
+		r := NewRequests(cfg)
+
+		_, err := r.Post(ts.URL + "/api/user/token")
+
+		var tooMany *TooManyRequestsError
+		require.ErrorAs(t, err, &tooMany)
+		require.ErrorIs(t, err, ErrTooManyRequests)
+		assert.Equal(t, 90*time.Second, tooMany.RetryAfter)
+		assert.Equal(t, "too many requests, retry after 1m30s", err.Error())
+	})
+
+	t.Run("return error without retry after", func(t *testing.T) {
+		cfg := initTestConfig(t, ts.URL+"/api", "refresh")
+		r := NewRequests(cfg)
+
+		_, err := r.Post(ts.URL + "/api/user/register")
+
+		require.ErrorIs(t, err, ErrTooManyRequests)
+		assert.Equal(t, "too many requests, retry later", err.Error())
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30"))
+	assert.Zero(t, parseRetryAfter(""))
+	assert.Zero(t, parseRetryAfter("soon"))
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	assert.InDelta(t, time.Minute.Seconds(), parseRetryAfter(date).Seconds(), 2)
+}
+
+func TestWithStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("some data"))
+	}))
+	defer ts.Close()
+
+	t.Run("read raw body", func(t *testing.T) {
+		cfg := initTestConfig(t, ts.URL+"/api", "")
+		r := NewRequests(cfg)
+
+		resp, err := r.Get(ts.URL, WithStream())
+		require.NoError(t, err)
+		defer func() { require.NoError(t, resp.RawBody().Close()) }()
+
+		assert.Empty(t, resp.Body())
+		data, err := io.ReadAll(resp.RawBody())
+		require.NoError(t, err)
+		assert.Equal(t, "some data", string(data))
+	})
+}
+
+func TestOptionsNotCarriedOver(t *testing.T) {
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	t.Run("headers apply to one request only", func(t *testing.T) {
+		cfg := initTestConfig(t, ts.URL+"/api", "")
+		r := NewRequests(cfg)
+
+		_, err := r.Get(ts.URL, WithHeader("Range", "bytes=10-"))
+		require.NoError(t, err)
+		_, err = r.Get(ts.URL)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"bytes=10-", ""}, ranges)
+	})
+}
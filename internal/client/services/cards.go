@@ -1,76 +1,97 @@
 package services
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
-
-	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 )
 
+const (
+	cardsPath        = "/user/cards"
+	cardPath         = "/user/cards/{id}"
+	cardNotFoundText = "card id not found"
+)
+
 // AddCard сервис добавления данных банковской карты.
 func (s *Services) AddCard(req *models.AddCardRequest) error {
-	const path = "/user/cards"
-
-	body, err := json.Marshal(req)
-	if err != nil {
-		return failedCreateBody(err)
+	data := models.EncryptCardData{
+		Number:     req.Number,
+		Owner:      req.Owner,
+		ExpiryDate: req.ExpiryDate,
+		CVV2:       req.CVV2,
 	}
 
-	addResp := models.AddResponse{}
-
-	resp, err := s.httpRequests.Post(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, JSONContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithBody(body),
-		requests.WithResult(&addResp),
-	)
-	if err != nil {
-		return failedRequest(err)
+	if err := validateCardData(data); err != nil {
+		return failedValidateFields(err)
 	}
-	if resp.StatusCode() != http.StatusCreated {
-		return failedResponseStatus(resp.Status())
+	if err := validateMarkAndDescription(req.Mark, req.Description); err != nil {
+		return failedValidateFields(err)
 	}
 
-	d := models.UserData{
-		ID:          addResp.ID,
+	return s.addSecret(cardsPath, data, models.UserData{
+		Type:        "card",
 		Mark:        req.Mark,
 		Description: req.Description,
-		Type:        "card",
-	}
-
-	if err := s.cfg.AddData(d); err != nil {
-		return failedDumpData(err)
-	}
-
-	return nil
+		Folder:      req.Folder,
+		Tags:        req.Tags,
+	})
 }
 
 // GetCard сервис получения данных банковской карты.
 func (s *Services) GetCard(id string) (models.Card, error) {
-	const path = "/user/cards/{id}"
+	data := models.EncryptCardData{}
 
-	card := models.Card{}
-
-	if _, ok := s.cfg.GetData()[id]; !ok {
-		return card, errors.New("card id not found")
+	secret, err := s.getSecret(cardPath, id, cardNotFoundText, &data)
+	if err != nil {
+		return models.Card{}, err
 	}
 
-	resp, err := s.httpRequests.Get(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, JSONContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithPathParams(map[string]string{"id": id}),
-		requests.WithResult(&card),
-	)
-	if err != nil {
-		return card, failedRequest(err)
+	return models.Card{
+		ID:          secret.ID,
+		Number:      data.Number,
+		Owner:       data.Owner,
+		ExpiryDate:  data.ExpiryDate,
+		CVV2:        data.CVV2,
+		Mark:        secret.Mark,
+		Description: secret.Description,
+	}, nil
+}
+
+// EditCard сервис изменения данных банковской карты.
+// Секретные поля изменяются на клиенте: текущие данные расшифровываются, дополняются и шифруются заново.
+func (s *Services) EditCard(id string, req models.UpdateCardRequest) error {
+	if err := validateMarkAndDescription(valueOrEmpty(req.Mark), valueOrEmpty(req.Description)); err != nil {
+		return failedValidateFields(err)
 	}
-	if resp.StatusCode() != http.StatusOK {
-		return card, failedResponseStatus(resp.Status())
+
+	var data any
+
+	if req.Number != nil || req.Owner != nil || req.ExpiryDate != nil || req.CVV2 != nil {
+		card, err := s.GetCard(id)
+		if err != nil {
+			return err
+		}
+
+		newData := models.EncryptCardData{
+			Number:     card.Number,
+			Owner:      card.Owner,
+			ExpiryDate: card.ExpiryDate,
+			CVV2:       card.CVV2,
+		}
+		patchValue(&newData.Number, req.Number)
+		patchValue(&newData.Owner, req.Owner)
+		patchValue(&newData.ExpiryDate, req.ExpiryDate)
+		patchValue(&newData.CVV2, req.CVV2)
+
+		if err := validateCardData(newData); err != nil {
+			return failedValidateFields(err)
+		}
+
+		data = newData
 	}
 
-	return card, nil
+	return s.editSecret(cardPath, id, cardNotFoundText, data, req.Mark, req.Description)
+}
+
+// DeleteCard сервис удаления данных банковской карты.
+func (s *Services) DeleteCard(id string) error {
+	return s.deleteData(cardPath, id, cardNotFoundText)
 }
@@ -19,94 +19,84 @@ func TestAddCard(t *testing.T) {
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
 
 	url := "http://some/api"
-	req := &models.AddCardRequest{}
+	req := &models.AddCardRequest{
+		Number:     "1234123412341234",
+		Owner:      "test",
+		ExpiryDate: "11/2030",
+		CVV2:       "123",
+	}
 
-	type postResponse struct {
+	type response struct {
 		resp *resty.Response
 		err  error
 	}
-	type addData struct {
-		count int
-		err   error
-	}
 	tests := []struct {
 		name         string
-		postResponse postResponse
-		addData      addData
+		reserve      response
+		post         response
+		postCount    int
+		addDataCount int
+		addDataErr   error
 		wantErr      bool
 		errText      string
 	}{
 		{
-			name: "add card success",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusCreated},
-				},
-				err: nil,
-			},
-			addData: addData{
-				count: 1,
-				err:   nil,
-			},
-			wantErr: false,
-			errText: "",
+			name:         "add card success",
+			reserve:      response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:         response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			postCount:    1,
+			addDataCount: 1,
 		},
 		{
-			name: "add card failed",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusCreated},
-				},
-				err: nil,
-			},
-			addData: addData{
-				count: 1,
-				err:   errors.New("some error"),
-			},
-			wantErr: true,
-			errText: "failed to dump data",
+			name:         "add card failed",
+			reserve:      response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:         response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			postCount:    1,
+			addDataCount: 1,
+			addDataErr:   errors.New("some error"),
+			wantErr:      true,
+			errText:      "failed to dump data",
 		},
 		{
-			name: "add card failed when response status not 201",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusForbidden},
-				},
-				err: nil,
-			},
-			addData: addData{
-				count: 0,
-				err:   nil,
-			},
+			name:    "add card failed when reserve id failed",
+			reserve: response{resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusInternalServerError}}},
 			wantErr: true,
 			errText: "response status",
 		},
 		{
-			name: "add card failed when request failed",
-			postResponse: postResponse{
-				resp: nil,
-				err:  errors.New("some error"),
-			},
-			addData: addData{
-				count: 0,
-				err:   nil,
-			},
-			wantErr: true,
-			errText: "failed request",
+			name:      "add card failed when response status not 201",
+			reserve:   response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:      response{resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusForbidden}}},
+			postCount: 1,
+			wantErr:   true,
+			errText:   "response status",
+		},
+		{
+			name:      "add card failed when request failed",
+			reserve:   response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:      response{err: errors.New("some error")},
+			postCount: 1,
+			wantErr:   true,
+			errText:   "failed request",
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cfg.EXPECT().GetToken().Times(1).Return("token")
-			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			cfg.EXPECT().GetToken().Times(1 + test.postCount).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(1 + test.postCount).Return(url)
 
-			r.EXPECT().Post(url+"/user/cards", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-				Times(1).Return(test.postResponse.resp, test.postResponse.err)
+			r.EXPECT().Post(url+"/user/data/ids", gomock.Any(), gomock.Any()).
+				Times(1).Return(test.reserve.resp, test.reserve.err)
+			r.EXPECT().Post(url+"/user/cards", gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(test.postCount).Return(test.post.resp, test.post.err)
+			r.EXPECT().Patch(gomock.Any(), gomock.Any()).Times(0)
 
-			cfg.EXPECT().AddData(gomock.Any()).Times(test.addData.count).Return(test.addData.err)
+			cfg.EXPECT().AddData(models.UserData{ID: 5, Type: "card", Mark: req.Mark, Version: 1}).
+				Times(test.addDataCount).Return(test.addDataErr)
 
 			err := s.AddCard(req)
 
@@ -120,13 +110,65 @@ func TestAddCard(t *testing.T) {
 	}
 }
 
+func TestAddCardValidationFailed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	tests := []struct {
+		name    string
+		req     models.AddCardRequest
+		wantErr error
+	}{
+		{
+			name:    "when card number invalid",
+			req:     models.AddCardRequest{Number: "1234", ExpiryDate: "11/2030", CVV2: "123"},
+			wantErr: ErrCardNumberInvalid,
+		},
+		{
+			name:    "when card expiry date invalid",
+			req:     models.AddCardRequest{Number: "1234123412341234", ExpiryDate: "11/30", CVV2: "123"},
+			wantErr: ErrCardExpiryDateInvalid,
+		},
+		{
+			name:    "when card cvv2 invalid",
+			req:     models.AddCardRequest{Number: "1234123412341234", ExpiryDate: "11/2030", CVV2: "12"},
+			wantErr: ErrCardCVV2Invalid,
+		},
+		{
+			name: "when mark very big",
+			req: models.AddCardRequest{
+				Number:     "1234123412341234",
+				ExpiryDate: "11/2030",
+				CVV2:       "123",
+				Mark:       string(make([]rune, maxMarkSize+1)),
+			},
+			wantErr: ErrMarkIsTooBig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r.EXPECT().Post(gomock.Any(), gomock.Any()).Times(0)
+
+			err := s.AddCard(&test.req)
+
+			require.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
 func TestGetCard(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
 
 	url := "http://some/api"
 	cardID := "1"
@@ -147,15 +189,14 @@ func TestGetCard(t *testing.T) {
 			name: "get card success",
 			data: map[string]models.UserData{
 				"1": {
-					ID: 1,
+					ID:   1,
+					Type: "card",
 				},
 			},
 			getResponse: getResponse{
 				count: 1,
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusOK},
-				},
-				err: nil,
+				resp:  newSecretResponse(t, models.EncryptCardData{Number: "1234123412341234", Owner: "test", ExpiryDate: "11/2030", CVV2: "123"}),
+				err:   nil,
 			},
 			wantErr: false,
 			errText: "",
@@ -179,7 +220,8 @@ func TestGetCard(t *testing.T) {
 			name: "get card failed when response status not 200",
 			data: map[string]models.UserData{
 				"1": {
-					ID: 1,
+					ID:   1,
+					Type: "card",
 				},
 			},
 			getResponse: getResponse{
@@ -196,7 +238,8 @@ func TestGetCard(t *testing.T) {
 			name: "get card failed when request failed",
 			data: map[string]models.UserData{
 				"1": {
-					ID: 1,
+					ID:   1,
+					Type: "card",
 				},
 			},
 			getResponse: getResponse{
@@ -214,7 +257,7 @@ func TestGetCard(t *testing.T) {
 			cfg.EXPECT().GetToken().Times(test.getResponse.count).Return("token")
 			cfg.EXPECT().GetServerAPI().Times(test.getResponse.count).Return(url)
 
-			r.EXPECT().Get(url+"/user/cards/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			r.EXPECT().Get(url+"/user/cards/{id}", gomock.Any(), gomock.Any(), gomock.Any()).
 				Times(test.getResponse.count).Return(test.getResponse.resp, test.getResponse.err)
 
 			_, err := s.GetCard(cardID)
@@ -228,3 +271,211 @@ func TestGetCard(t *testing.T) {
 		})
 	}
 }
+
+func TestEditCard(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	mark := "new"
+	req := models.UpdateCardRequest{Mark: &mark}
+
+	type patchResponse struct {
+		count int
+		resp  *resty.Response
+		err   error
+	}
+	tests := []struct {
+		name          string
+		data          map[string]models.UserData
+		patchResponse patchResponse
+		addDataCount  int
+		wantErr       bool
+		errText       string
+	}{
+		{
+			name: "edit card success",
+			data: map[string]models.UserData{"1": {ID: 1, Mark: "old"}},
+			patchResponse: patchResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusOK},
+				},
+				err: nil,
+			},
+			addDataCount: 1,
+			wantErr:      false,
+			errText:      "",
+		},
+		{
+			name: "card not found",
+			data: map[string]models.UserData{"2": {ID: 2}},
+			patchResponse: patchResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
+			addDataCount: 0,
+			wantErr:      true,
+			errText:      "card id not found",
+		},
+		{
+			name: "edit card failed when response status not 200",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			patchResponse: patchResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				err: nil,
+			},
+			addDataCount: 0,
+			wantErr:      true,
+			errText:      "response status",
+		},
+		{
+			name: "edit card failed when request failed",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			patchResponse: patchResponse{
+				count: 1,
+				resp:  nil,
+				err:   errors.New("some error"),
+			},
+			addDataCount: 0,
+			wantErr:      true,
+			errText:      "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetData().Times(1).Return(test.data)
+			cfg.EXPECT().GetToken().Times(test.patchResponse.count).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(test.patchResponse.count).Return(url)
+
+			r.EXPECT().Patch(url+"/user/cards/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(test.patchResponse.count).Return(test.patchResponse.resp, test.patchResponse.err)
+
+			cfg.EXPECT().AddData(models.UserData{ID: 1, Mark: mark}).Times(test.addDataCount).Return(nil)
+
+			err := s.EditCard("1", req)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeleteCard(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+
+	type deleteResponse struct {
+		count int
+		resp  *resty.Response
+		err   error
+	}
+	type deleteData struct {
+		count int
+		err   error
+	}
+	tests := []struct {
+		name           string
+		data           map[string]models.UserData
+		deleteResponse deleteResponse
+		deleteData     deleteData
+		wantErr        bool
+		errText        string
+	}{
+		{
+			name: "delete card success",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			deleteResponse: deleteResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNoContent},
+				},
+				err: nil,
+			},
+			deleteData: deleteData{count: 1, err: nil},
+			wantErr:    false,
+			errText:    "",
+		},
+		{
+			name: "delete card failed when dump data failed",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			deleteResponse: deleteResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNoContent},
+				},
+				err: nil,
+			},
+			deleteData: deleteData{count: 1, err: errors.New("some error")},
+			wantErr:    true,
+			errText:    "failed to dump data",
+		},
+		{
+			name: "card not found",
+			data: map[string]models.UserData{"2": {ID: 2}},
+			deleteResponse: deleteResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
+			deleteData: deleteData{count: 0, err: nil},
+			wantErr:    true,
+			errText:    "card id not found",
+		},
+		{
+			name: "delete card failed when response status not 204",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			deleteResponse: deleteResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				err: nil,
+			},
+			deleteData: deleteData{count: 0, err: nil},
+			wantErr:    true,
+			errText:    "response status",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetData().Times(1).Return(test.data)
+			cfg.EXPECT().GetToken().Times(test.deleteResponse.count).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(test.deleteResponse.count).Return(url)
+
+			r.EXPECT().Delete(url+"/user/cards/{id}", gomock.Any(), gomock.Any()).
+				Times(test.deleteResponse.count).Return(test.deleteResponse.resp, test.deleteResponse.err)
+
+			cfg.EXPECT().DeleteData("1").Times(test.deleteData.count).Return(test.deleteData.err)
+
+			err := s.DeleteCard("1")
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,45 +1,131 @@
 package services
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 )
 
-// SyncData сервис синхронизации данных с сервера.
-func (s *Services) SyncData() error {
-	const path = "/user/data"
+// GetData сервис получения данных из кеша.
+func (s *Services) GetData() []models.UserData {
 	userData := []models.UserData{}
 
-	resp, err := s.httpRequests.Get(
+	for _, v := range s.cfg.GetData() {
+		userData = append(userData, v)
+	}
+
+	return userData
+}
+
+// editData сервис частичного изменения данных пользователя. Изменение отправляется с версией записи из кеша:
+// если запись уже изменил другой клиент, сервер отклоняет изменение. Если сервер недоступен,
+// изменение сохраняется в очередь и отправляется при следующей синхронизации.
+func (s *Services) editData(path, id, notFoundText string, req models.UpdateSecretRequest) error {
+	d, ok := s.cfg.GetData()[id]
+	if !ok {
+		return errors.New(notFoundText)
+	}
+	req.Version = d.Version
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return failedCreateBody(err)
+	}
+
+	resp, err := s.httpRequests.Patch(
 		s.cfg.GetServerAPI()+path,
 		requests.WithHeader(ContentTypeHeader, JSONContentType),
 		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithResult(&userData),
+		requests.WithPathParams(map[string]string{"id": id}),
+		requests.WithBody(body),
 	)
 	if err != nil {
-		return failedRequest(err)
+		return s.queueEdit(d, req, err)
 	}
 
-	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusNoContent {
+	switch resp.StatusCode() {
+	case http.StatusOK:
+	case http.StatusConflict:
+		return ErrDataChanged
+	default:
 		return failedResponseStatus(resp.Status())
 	}
 
-	if err := s.cfg.UpdateData(userData); err != nil {
-		return fmt.Errorf("failed to update data: %w", err)
+	d = patchUserData(d, req)
+	if d.Version != 0 {
+		d.Version++
+	}
+
+	if err := s.cfg.AddData(d); err != nil {
+		return failedDumpData(err)
 	}
+
 	return nil
 }
 
-// GetData сервис получения данных из кеша.
-func (s *Services) GetData() []models.UserData {
-	userData := []models.UserData{}
+// deleteData сервис удаления данных пользователя. Если сервер недоступен,
+// удаление сохраняется в очередь и отправляется при следующей синхронизации.
+func (s *Services) deleteData(path, id, notFoundText string) error {
+	d, ok := s.cfg.GetData()[id]
+	if !ok {
+		return errors.New(notFoundText)
+	}
 
-	for _, v := range s.cfg.GetData() {
-		userData = append(userData, v)
+	resp, err := s.httpRequests.Delete(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+	)
+	if err != nil {
+		return s.queueDelete(d, err)
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return failedResponseStatus(resp.Status())
 	}
 
-	return userData
+	if err := s.cfg.DeleteData(id); err != nil {
+		return failedDumpData(err)
+	}
+
+	return nil
+}
+
+// reserveDataID сервис резервирования на сервере ID новой записи, чтобы зашифровать ее данные
+// с привязкой к ID до добавления.
+func (s *Services) reserveDataID() (int, error) {
+	const path = "/user/data/ids"
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return 0, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusCreated {
+		return 0, failedResponseStatus(resp.Status())
+	}
+
+	reserved := models.AddResponse{}
+	if err := json.Unmarshal(resp.Body(), &reserved); err != nil {
+		return 0, failedParseBody(err)
+	}
+
+	return reserved.ID, nil
+}
+
+// patchUserData применяет к данным пользователя из кеша новые метку и описание из запроса.
+func patchUserData(d models.UserData, req models.UpdateSecretRequest) models.UserData {
+	if req.Mark != nil {
+		d.Mark = *req.Mark
+	}
+	if req.Description != nil {
+		d.Description = *req.Description
+	}
+
+	return d
 }
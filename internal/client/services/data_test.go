@@ -19,34 +19,33 @@ func TestSuncData(t *testing.T) {
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
 
 	url := "http://some/api"
+	changes := models.UserDataChanges{Changes: []models.UserData{{ID: 1, Type: "file", Version: 1}}, Cursor: 3}
 
 	type getResponse struct {
 		resp *resty.Response
 		err  error
 	}
-	type updateData struct {
+	type applyChanges struct {
 		count int
 		err   error
 	}
 	tests := []struct {
-		name        string
-		getResponse getResponse
-		updateData  updateData
-		wantErr     bool
-		errText     string
+		name         string
+		getResponse  getResponse
+		applyChanges applyChanges
+		wantErr      bool
+		errText      string
 	}{
 		{
 			name: "sync data success",
 			getResponse: getResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusOK},
-				},
-				err: nil,
+				resp: newJSONResponse(t, http.StatusOK, changes),
+				err:  nil,
 			},
-			updateData: updateData{
+			applyChanges: applyChanges{
 				count: 1,
 				err:   nil,
 			},
@@ -55,28 +54,41 @@ func TestSuncData(t *testing.T) {
 		},
 		{
 			name: "sync data failed",
+			getResponse: getResponse{
+				resp: newJSONResponse(t, http.StatusOK, changes),
+				err:  nil,
+			},
+			applyChanges: applyChanges{
+				count: 1,
+				err:   errors.New("some error"),
+			},
+			wantErr: true,
+			errText: "failed to update data",
+		},
+		{
+			name: "sync data failed when response body invalid",
 			getResponse: getResponse{
 				resp: &resty.Response{
 					RawResponse: &http.Response{StatusCode: http.StatusOK},
 				},
 				err: nil,
 			},
-			updateData: updateData{
-				count: 1,
-				err:   errors.New("some error"),
+			applyChanges: applyChanges{
+				count: 0,
+				err:   nil,
 			},
 			wantErr: true,
-			errText: "failed to update data",
+			errText: "failed to parse body",
 		},
 		{
-			name: "sync data failed when response status not 200 or 204",
+			name: "sync data failed when response status not 200",
 			getResponse: getResponse{
 				resp: &resty.Response{
 					RawResponse: &http.Response{StatusCode: http.StatusForbidden},
 				},
 				err: nil,
 			},
-			updateData: updateData{
+			applyChanges: applyChanges{
 				count: 0,
 				err:   nil,
 			},
@@ -89,7 +101,7 @@ func TestSuncData(t *testing.T) {
 				resp: nil,
 				err:  errors.New("some error"),
 			},
-			updateData: updateData{
+			applyChanges: applyChanges{
 				count: 0,
 				err:   nil,
 			},
@@ -101,11 +113,12 @@ func TestSuncData(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			cfg.EXPECT().GetToken().Times(1).Return("token")
 			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			cfg.EXPECT().GetSyncCursor().Times(1).Return(int64(2))
 
 			r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).
 				Times(1).Return(test.getResponse.resp, test.getResponse.err)
 
-			cfg.EXPECT().UpdateData(gomock.Any()).Times(test.updateData.count).Return(test.updateData.err)
+			cfg.EXPECT().ApplyDataChanges(changes).Times(test.applyChanges.count).Return(test.applyChanges.err)
 
 			err := s.SyncData()
 
@@ -126,7 +139,7 @@ func TestGetData(t *testing.T) {
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
 
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
 
 	data := map[string]models.UserData{
 		"1": {
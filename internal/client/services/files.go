@@ -1,44 +1,52 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
 )
 
-// AddFile сервис добавления файла.
-func (s *Services) AddFile(filePath, mark, description string) error {
-	const path = "/user/files"
+// headSuffix суффикс файла с заголовком потоково зашифрованного файла, который загружается.
+const headSuffix = ".head"
 
-	addResp := models.AddResponse{}
+var (
+	// errDownloadInterrupted ошибка прерванной загрузки файла, которую можно продолжить повторной попыткой.
+	errDownloadInterrupted = errors.New("download interrupted")
+	// ErrInvalidFileName метка файла не подходит для имени файла в каталоге загрузки.
+	ErrInvalidFileName = errors.New("file mark is not a valid file name")
+)
 
-	preparedMark := strings.ReplaceAll(strings.ToLower(mark), " ", "_")
+// AddFile сервис добавления файла. Содержимое файла шифруется ключом хранилища и загружается
+// на сервер по частям. Прерванная загрузка продолжается при повторном запуске.
+// Из req используются метка, описание, папка и метки, папка и метки передаются при начале загрузки.
+func (s *Services) AddFile(filePath string, req models.AddFileRequest) error {
+	req.Mark = prepareFileMark(req.Mark)
 
-	resp, err := s.httpRequests.Post(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, FormDataContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithFile(filePath),
-		requests.WithFormData(map[string]string{
-			"mark":        preparedMark,
-			"description": description,
-		}),
-		requests.WithResult(&addResp),
-	)
+	id, err := s.uploadFile(filePath, req)
 	if err != nil {
-		return failedRequest(err)
-	}
-	if resp.StatusCode() != http.StatusCreated {
-		return failedResponseStatus(resp.Status())
+		return err
 	}
 
 	d := models.UserData{
-		ID:          addResp.ID,
-		Mark:        preparedMark,
-		Description: description,
+		ID:          id,
+		Mark:        req.Mark,
+		Description: req.Description,
+		Folder:      req.Folder,
+		Tags:        req.Tags,
 		Type:        "file",
 	}
 
@@ -49,27 +57,599 @@ func (s *Services) AddFile(filePath, mark, description string) error {
 	return nil
 }
 
-// GetFile сервис получения файла.
-func (s *Services) GetFile(fileMark, dir string) error {
-	const path = "/user/files/{fileMark}"
+// GetFile сервис получения файла. Потоково зашифрованный файл расшифровывается ключом хранилища
+// по мере загрузки и сохраняется в dir под своей меткой, о ходе загрузки сообщается через progress
+// (если он задан). Прерванная загрузка продолжается с места остановки как при повторных попытках,
+// так и при следующем запуске команды. Файлы старых форматов сначала загружаются целиком и
+// расшифровываются после загрузки. Файл указывается по ID или по метке, если она есть только у одного файла.
+func (s *Services) GetFile(fileRef, dir string, progress func(done, total int64)) error {
+	d, err := s.findFile(fileRef)
+	if err != nil {
+		return err
+	}
+
+	filePath, err := downloadPath(dir, d.Mark)
+	if err != nil {
+		return err
+	}
+
+	partPath := filePath + ".part"
+	etagPath := partPath + ".etag"
+	retries := max(s.cfg.GetRequestRetry(), 0)
+
+	for attempt := 0; ; attempt++ {
+		err := s.downloadFile(d.ID, partPath, etagPath, progress)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, crypt.ErrInvalidSealedData) {
+			// Поврежденный файл не продолжить, следующая загрузка начнется заново.
+			removeDownload(partPath, etagPath)
+			return err
+		}
+		if !errors.Is(err, errDownloadInterrupted) || attempt >= retries {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(partPath + headSuffix); err == nil {
+		// Потоково зашифрованный файл уже расшифрован при загрузке.
+		if err := os.Rename(partPath, filePath); err != nil {
+			return fmt.Errorf("failed to replace file: %w", err)
+		}
+	} else if err := s.openFile(d.ID, partPath, filePath); err != nil {
+		if errors.Is(err, crypt.ErrInvalidSealedData) {
+			removeDownload(partPath, etagPath)
+		}
+		return err
+	}
+	removeDownload(partPath, etagPath)
+
+	return nil
+}
+
+// EditFile сервис изменения файла. Если путь к файлу не указан, изменяются только метка, описание,
+// папка и метки, иначе папка и метки не меняются.
+func (s *Services) EditFile(fileRef, filePath string, req models.UpdateFileRequest) error {
+	const path = "/user/files/{id}"
+
+	d, err := s.findFile(fileRef)
+	if err != nil {
+		return err
+	}
+
+	if req.Mark != nil {
+		d.Mark = prepareFileMark(*req.Mark)
+		req.Mark = &d.Mark
+	}
+	if req.Description != nil {
+		d.Description = *req.Description
+	}
+	if filePath == "" && req.Folder != nil {
+		d.Folder = *req.Folder
+	}
+	if filePath == "" && req.Tags != nil {
+		d.Tags = *req.Tags
+	}
+
+	opts := []requests.RequestOptionFunc{
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": strconv.Itoa(d.ID)}),
+	}
+
+	var resp *resty.Response
+
+	if filePath != "" {
+		sealedPath, cleanup, sErr := s.sealFile(filePath, d.ID)
+		if sErr != nil {
+			return sErr
+		}
+		defer cleanup()
+
+		opts = append(opts,
+			requests.WithHeader(ContentTypeHeader, FormDataContentType),
+			requests.WithFile(sealedPath),
+			requests.WithFormData(map[string]string{
+				"mark":        d.Mark,
+				"description": d.Description,
+			}),
+		)
+		resp, err = s.httpRequests.Put(s.cfg.GetServerAPI()+path, opts...)
+	} else {
+		body, mErr := json.Marshal(req)
+		if mErr != nil {
+			return failedCreateBody(mErr)
+		}
+
+		opts = append(opts,
+			requests.WithHeader(ContentTypeHeader, JSONContentType),
+			requests.WithBody(body),
+		)
+		resp, err = s.httpRequests.Patch(s.cfg.GetServerAPI()+path, opts...)
+	}
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return failedResponseStatus(resp.Status())
+	}
+
+	if err := s.cfg.AddData(d); err != nil {
+		return failedDumpData(err)
+	}
+
+	return nil
+}
+
+// DeleteFile сервис удаления файла.
+func (s *Services) DeleteFile(fileRef string) error {
+	const path = "/user/files/{id}"
 
-	if _, ok := s.cfg.GetData()[fileMark]; !ok {
-		return errors.New("file mark not found")
+	d, err := s.findFile(fileRef)
+	if err != nil {
+		return err
 	}
 
-	resp, err := s.httpRequests.Get(
+	resp, err := s.httpRequests.Delete(
 		s.cfg.GetServerAPI()+path,
 		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithPathParams(map[string]string{"fileMark": fileMark}),
-		requests.WithOutput(dir+"/"+fileMark),
+		requests.WithPathParams(map[string]string{"id": strconv.Itoa(d.ID)}),
 	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return failedResponseStatus(resp.Status())
+	}
+
+	if err := s.cfg.DeleteData(strconv.Itoa(d.ID)); err != nil {
+		return failedDumpData(err)
+	}
+
+	return nil
+}
+
+// findFile ищет файл по ID, а если файла с таким ID нет - по метке. Метка подходит,
+// только если она есть ровно у одного файла, иначе нужно указать ID.
+func (s *Services) findFile(fileRef string) (models.UserData, error) {
+	data := s.cfg.GetData()
+
+	if d, ok := data[fileRef]; ok && d.Type == "file" {
+		return d, nil
+	}
+
+	mark := prepareFileMark(fileRef)
+
+	var found []models.UserData
+	for _, d := range data {
+		if d.Type == "file" && d.Mark == mark {
+			found = append(found, d)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return models.UserData{}, errors.New("file not found")
+	case 1:
+		return found[0], nil
+	default:
+		ids := make([]int, len(found))
+		for i, d := range found {
+			ids[i] = d.ID
+		}
+		sort.Ints(ids)
+
+		return models.UserData{}, fmt.Errorf("file mark %q is ambiguous, use one of IDs %v", mark, ids)
+	}
+}
+
+// downloadPath возвращает путь для сохранения файла в каталоге dir под меткой mark.
+// Метку задает сервер, поэтому она должна быть именем файла без каталогов и не выводить за пределы dir.
+func downloadPath(dir, mark string) (string, error) {
+	if mark == "" || mark == "." || mark == ".." || strings.ContainsAny(mark, `/\`) || filepath.Base(mark) != mark {
+		return "", ErrInvalidFileName
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve download dir: %w", err)
+	}
+
+	filePath := filepath.Join(absDir, mark)
+	if rel, err := filepath.Rel(absDir, filePath); err != nil || rel != mark {
+		return "", ErrInvalidFileName
+	}
+
+	return filePath, nil
+}
+
+// prepareFileMark приводит метку файла к виду, в котором она хранится на сервере.
+func prepareFileMark(mark string) string {
+	return strings.ReplaceAll(strings.ToLower(mark), " ", "_")
+}
+
+// sealFile шифрует файл записи id ключом хранилища во временный файл с тем же именем.
+func (s *Services) sealFile(filePath string, id int) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "goph-keeper-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+
+	sealedPath := filepath.Join(dir, filepath.Base(filePath))
+	if err := s.sealFileTo(filePath, sealedPath, id); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return sealedPath, cleanup, nil
+}
+
+// sealFileTo шифрует файл записи id ключом хранилища и записывает результат в sealedPath.
+// Файл шифруется потоково по частям, поэтому целиком в память не загружается,
+// а ключ файла привязывается к записи, как и данные остальных записей.
+func (s *Services) sealFileTo(filePath, sealedPath string, id int) error {
+	key, err := s.getVaultKey()
+	if err != nil {
+		return failedVaultKey(err)
+	}
+
+	src, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	dst, err := os.OpenFile(sealedPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write encrypted file: %w", err)
+	}
+
+	err = encryptTo(key, secretAAD("file", id), dst, src)
+	if closeErr := dst.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("failed to close encrypted file: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(sealedPath)
+		return err
+	}
+
+	return nil
+}
+
+// encryptTo шифрует данные из src ключом хранилища с привязкой к aad и записывает их в dst.
+func encryptTo(key, aad []byte, dst io.Writer, src io.Reader) error {
+	w, err := crypt.NewEncryptWriter(key, aad, dst)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt file: %w", err)
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("failed to encrypt file: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt file: %w", err)
+	}
+
+	return nil
+}
+
+// downloadFile загружает файл во временный файл partPath, чтобы не затереть существующий файл
+// недокачанными данными. В etagPath сохраняется ETag файла, по которому сервер решает,
+// можно ли продолжить загрузку.
+// Потоково зашифрованный файл сразу расшифровывается: в partPath записываются только расшифрованные
+// целые части, а заголовок сохраняется рядом (headSuffix), чтобы продолжить загрузку со следующей части.
+// Файлы других форматов записываются в partPath в зашифрованном виде.
+func (s *Services) downloadFile(id int, partPath, etagPath string, progress func(done, total int64)) error {
+	const path = "/user/files/{id}"
 
+	headPath := partPath + headSuffix
+	offset, etag, header, chunk := partialDownload(partPath, etagPath, headPath)
+
+	opts := []requests.RequestOptionFunc{
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": strconv.Itoa(id)}),
+		requests.WithStream(),
+	}
+	if offset > 0 {
+		opts = append(opts,
+			requests.WithHeader(RangeHeader, fmt.Sprintf("bytes=%d-", offset)),
+			requests.WithHeader(IfRangeHeader, etag),
+		)
+	}
+
+	resp, err := s.httpRequests.Get(s.cfg.GetServerAPI()+path, opts...)
 	if err != nil {
 		return failedRequest(err)
 	}
-	if resp.StatusCode() != http.StatusOK {
+	defer func() {
+		if body := resp.RawBody(); body != nil {
+			_ = body.Close()
+		}
+	}()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := resp.RawResponse.ContentLength
+
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		// Файл изменился или сервер не поддерживает диапазоны, загрузка начинается заново.
+		offset, header, chunk = 0, nil, 0
+		flags |= os.O_TRUNC
+		_ = os.Remove(headPath)
+		if err := os.WriteFile(etagPath, []byte(resp.Header().Get(ETagHeader)), 0o600); err != nil {
+			return fmt.Errorf("failed to write file etag: %w", err)
+		}
+	case http.StatusPartialContent:
+		if start := contentRangeStart(resp.Header().Get(ContentRangeHeader)); start != offset {
+			removeDownload(partPath, etagPath)
+			return fmt.Errorf("%w: unexpected range start %d", errDownloadInterrupted, start)
+		}
+		flags |= os.O_APPEND
+		if total >= 0 {
+			total += offset
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		removeDownload(partPath, etagPath)
+		return fmt.Errorf("%w: partial file is stale", errDownloadInterrupted)
+	default:
 		return failedResponseStatus(resp.Status())
 	}
 
+	var body io.Reader = &downloadReader{src: resp.RawBody(), done: offset, total: total, progress: progress}
+	if offset == 0 {
+		if body, header, err = readDownloadHeader(body, headPath); err != nil {
+			return err
+		}
+	}
+
+	if header != nil {
+		key, kErr := s.getVaultKey()
+		if kErr != nil {
+			return failedVaultKey(kErr)
+		}
+
+		if body, err = crypt.NewDecryptReaderAt(key, secretAAD("file", id), header, chunk, body); err != nil {
+			return fmt.Errorf("failed to decrypt file: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(partPath, flags, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	_, err = io.Copy(f, body)
+	if closeErr := f.Close(); closeErr != nil && err == nil {
+		return fmt.Errorf("failed to close file: %w", closeErr)
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, crypt.ErrInvalidSealedData):
+		return fmt.Errorf("failed to decrypt file: %w", err)
+	case errors.Is(err, errDownloadInterrupted):
+		return err
+	default:
+		return fmt.Errorf("%w: %w", errDownloadInterrupted, err)
+	}
+}
+
+// readDownloadHeader читает заголовок потоково зашифрованного файла из начала body и сохраняет его в headPath.
+// Для файлов других форматов заголовок nil. Возвращает данные body после заголовка.
+func readDownloadHeader(body io.Reader, headPath string) (io.Reader, []byte, error) {
+	r := bufio.NewReader(body)
+
+	prefix, _ := r.Peek(crypt.StreamPrefixSize)
+	if !crypt.IsStream(prefix) {
+		return r, nil, nil
+	}
+
+	header, err := crypt.ReadStreamHeader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	if err := os.WriteFile(headPath, header, 0o600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write file header: %w", err)
+	}
+
+	return r, header, nil
+}
+
+// partialDownload возвращает смещение, с которого продолжается загрузка, ETag файла, а для потоково
+// зашифрованного файла - его заголовок и номер следующей части. Недописанная часть отбрасывается.
+// Без сохраненного ETag загрузка начинается заново.
+func partialDownload(partPath, etagPath, headPath string) (int64, string, []byte, int64) {
+	etag, err := os.ReadFile(etagPath)
+	if err != nil || len(etag) == 0 {
+		return 0, "", nil, 0
+	}
+
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return 0, "", nil, 0
+	}
+
+	header, err := os.ReadFile(headPath)
+	if err != nil {
+		return info.Size(), string(etag), nil, 0
+	}
+
+	chunkSize, err := crypt.StreamChunkSize(header)
+	if err != nil {
+		return 0, "", nil, 0
+	}
+
+	chunk := info.Size() / chunkSize
+	if err := os.Truncate(partPath, chunk*chunkSize); err != nil {
+		return 0, "", nil, 0
+	}
+
+	offset, err := crypt.StreamChunkOffset(header, chunk)
+	if err != nil {
+		return 0, "", nil, 0
+	}
+
+	return offset, string(etag), header, chunk
+}
+
+// removeDownload удаляет недокачанный файл, его ETag и заголовок.
+func removeDownload(partPath, etagPath string) {
+	_ = os.Remove(partPath)
+	_ = os.Remove(etagPath)
+	_ = os.Remove(partPath + headSuffix)
+}
+
+// contentRangeStart возвращает начало диапазона из заголовка Content-Range или -1.
+func contentRangeStart(header string) int64 {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return -1
+	}
+
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return -1
+	}
+
+	return start
+}
+
+// downloadReader читает тело ответа с файлом, сообщает о полученных байтах в progress (если он задан)
+// и отличает обрыв связи от конца файла: ошибки чтения и преждевременный конец данных
+// возвращаются как errDownloadInterrupted.
+type downloadReader struct {
+	src      io.Reader
+	progress func(done, total int64)
+	done     int64
+	total    int64
+}
+
+func (d *downloadReader) Read(p []byte) (int, error) {
+	n, err := d.src.Read(p)
+	d.done += int64(n)
+	if n > 0 && d.progress != nil {
+		d.progress(d.done, d.total)
+	}
+
+	switch {
+	case err == nil:
+		return n, nil
+	case errors.Is(err, io.EOF):
+		if d.total >= 0 && d.done < d.total {
+			return n, fmt.Errorf("%w: got %d of %d bytes", errDownloadInterrupted, d.done, d.total)
+		}
+		return n, io.EOF
+	default:
+		return n, fmt.Errorf("%w: %w", errDownloadInterrupted, err)
+	}
+}
+
+// openFile расшифровывает загруженный файл srcPath ключом хранилища и записывает результат в filePath.
+// Файлы, загруженные до появления шифрования на клиенте, остаются как есть.
+func (s *Services) openFile(id int, srcPath, filePath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	head := make([]byte, 4)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	head = head[:n]
+
+	if !crypt.IsStream(head) && !crypt.IsSealed(head) {
+		if err := os.Rename(srcPath, filePath); err != nil {
+			return fmt.Errorf("failed to replace file: %w", err)
+		}
+		return nil
+	}
+
+	key, err := s.getVaultKey()
+	if err != nil {
+		return failedVaultKey(err)
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var r io.Reader
+	if crypt.IsStream(head) {
+		r, err = crypt.NewDecryptReader(key, secretAAD("file", id), src)
+	} else {
+		// Файлы, зашифрованные целиком, расшифровываются в памяти.
+		r, err = openSealed(key, src)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	// Расшифрованные данные записываются во временный файл, чтобы при ошибке не потерять полученный файл.
+	tmpPath := filePath + ".tmp"
+	if err := writeFile(tmpPath, r); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	return nil
+}
+
+// openSealed расшифровывает данные, зашифрованные целиком. В этом формате тег проверяется только
+// по всему шифротексту, поэтому файл читается в память целиком: отдать часть данных до проверки нельзя.
+// Так зашифрованы только файлы старых клиентов, новые файлы шифруются потоково.
+func openSealed(key []byte, src io.Reader) (io.Reader, error) {
+	sealed, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	data, err := crypt.Open(key, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", crypt.ErrInvalidSealedData, err)
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// writeFile записывает данные из r в файл filePath.
+func writeFile(filePath string, r io.Reader) error {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if errors.Is(err, crypt.ErrInvalidSealedData) {
+			return fmt.Errorf("failed to decrypt file: %w", err)
+		}
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
 	return nil
 }
@@ -1,10 +1,19 @@
 package services
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/go-resty/resty/v2"
@@ -13,155 +22,148 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestAddFile(t *testing.T) {
+func TestGetFile(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
 
 	url := "http://some/api"
-	filePath := "test.txt"
-	mark := "test"
-	description := "test"
+	fileMark := "test"
+	dir := t.TempDir()
 
-	type postResponse struct {
-		resp *resty.Response
-		err  error
-	}
-	type addData struct {
+	cfg.EXPECT().GetRequestRetry().AnyTimes().Return(0)
+
+	sealed := encryptContent(t, "file content")
+	legacy, err := crypt.Seal(testVaultKey, []byte("file content"))
+	require.NoError(t, err)
+	corrupted := append([]byte{}, sealed...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	var otherRecord bytes.Buffer
+	require.NoError(t, encryptTo(testVaultKey, secretAAD("file", 2), &otherRecord, strings.NewReader("file content")))
+
+	type getResponse struct {
 		count int
+		resp  *resty.Response
 		err   error
 	}
 	tests := []struct {
-		name         string
-		postResponse postResponse
-		addData      addData
-		wantErr      bool
-		errText      string
+		name        string
+		data        map[string]models.UserData
+		getResponse getResponse
+		wantErr     bool
+		errText     string
 	}{
 		{
-			name: "add file success",
-			postResponse: postResponse{
+			name: "file text success",
+			data: map[string]models.UserData{
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
+				},
+			},
+			getResponse: getResponse{
+				count: 1,
 				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusCreated},
+					RawResponse: &http.Response{
+						StatusCode:    http.StatusOK,
+						Body:          io.NopCloser(bytes.NewReader(sealed)),
+						ContentLength: int64(len(sealed)),
+					},
 				},
 				err: nil,
 			},
-			addData: addData{
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name: "legacy sealed file success",
+			data: map[string]models.UserData{
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
+				},
+			},
+			getResponse: getResponse{
 				count: 1,
+				resp:  newStreamResponse(http.StatusOK, legacy),
 				err:   nil,
 			},
 			wantErr: false,
 			errText: "",
 		},
 		{
-			name: "add file failed",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusCreated},
+			name: "legacy unencrypted file success",
+			data: map[string]models.UserData{
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
 				},
-				err: nil,
 			},
-			addData: addData{
+			getResponse: getResponse{
 				count: 1,
-				err:   errors.New("some error"),
+				resp:  newStreamResponse(http.StatusOK, []byte("file content")),
+				err:   nil,
 			},
-			wantErr: true,
-			errText: "failed to dump data",
+			wantErr: false,
+			errText: "",
 		},
 		{
-			name: "add file failed when response status not 201",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusForbidden},
+			name: "get file failed when file corrupted",
+			data: map[string]models.UserData{
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
 				},
-				err: nil,
 			},
-			addData: addData{
-				count: 0,
+			getResponse: getResponse{
+				count: 1,
+				resp:  newStreamResponse(http.StatusOK, corrupted),
 				err:   nil,
 			},
 			wantErr: true,
-			errText: "response status",
+			errText: "failed to decrypt file",
 		},
 		{
-			name: "add file failed when request failed",
-			postResponse: postResponse{
-				resp: nil,
-				err:  errors.New("some error"),
+			name: "get file failed when file belongs to other record",
+			data: map[string]models.UserData{
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
+				},
 			},
-			addData: addData{
-				count: 0,
+			getResponse: getResponse{
+				count: 1,
+				resp:  newStreamResponse(http.StatusOK, otherRecord.Bytes()),
 				err:   nil,
 			},
 			wantErr: true,
-			errText: "failed request",
+			errText: "failed to decrypt file",
 		},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			cfg.EXPECT().GetToken().Times(1).Return("token")
-			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
-
-			r.EXPECT().Post(url+"/user/files", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-				Times(1).Return(test.postResponse.resp, test.postResponse.err)
-
-			cfg.EXPECT().AddData(gomock.Any()).Times(test.addData.count).Return(test.addData.err)
-
-			err := s.AddFile(filePath, mark, description)
-
-			if test.wantErr {
-				require.Error(t, err)
-				assert.ErrorContains(t, err, test.errText)
-			} else {
-				require.NoError(t, err)
-			}
-		})
-	}
-}
-
-func TestGetFile(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	cfg := mocks.NewMockConfigurer(mockCtrl)
-	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
-
-	url := "http://some/api"
-	fileMark := "test"
-	dir := "."
-
-	type getResponse struct {
-		count int
-		resp  *resty.Response
-		err   error
-	}
-	tests := []struct {
-		name        string
-		data        map[string]models.UserData
-		getResponse getResponse
-		wantErr     bool
-		errText     string
-	}{
 		{
-			name: "file text success",
+			name: "get file failed when mark escapes dir",
 			data: map[string]models.UserData{
-				fileMark: {
-					ID: 1,
+				"1": {
+					ID:   1,
+					Mark: "../../.bashrc",
+					Type: "file",
 				},
 			},
 			getResponse: getResponse{
-				count: 1,
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusOK},
-				},
-				err: nil,
+				count: 0,
+				resp:  nil,
+				err:   nil,
 			},
-			wantErr: false,
-			errText: "",
+			wantErr: true,
+			errText: "file mark is not a valid file name",
 		},
 		{
 			name: "file not found",
@@ -176,19 +178,21 @@ func TestGetFile(t *testing.T) {
 				err:   nil,
 			},
 			wantErr: true,
-			errText: "file mark not found",
+			errText: "file not found",
 		},
 		{
 			name: "get file failed when response status not 200",
 			data: map[string]models.UserData{
-				fileMark: {
-					ID: 1,
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
 				},
 			},
 			getResponse: getResponse{
 				count: 1,
 				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusForbidden},
+					RawResponse: &http.Response{StatusCode: http.StatusForbidden, Body: http.NoBody},
 				},
 				err: nil,
 			},
@@ -198,8 +202,10 @@ func TestGetFile(t *testing.T) {
 		{
 			name: "get file failed when request failed",
 			data: map[string]models.UserData{
-				fileMark: {
-					ID: 1,
+				"1": {
+					ID:   1,
+					Mark: fileMark,
+					Type: "file",
 				},
 			},
 			getResponse: getResponse{
@@ -217,17 +223,380 @@ func TestGetFile(t *testing.T) {
 			cfg.EXPECT().GetToken().Times(test.getResponse.count).Return("token")
 			cfg.EXPECT().GetServerAPI().Times(test.getResponse.count).Return(url)
 
-			r.EXPECT().Get(url+"/user/files/{fileMark}", gomock.Any(), gomock.Any(), gomock.Any()).
-				Times(test.getResponse.count).Return(test.getResponse.resp, test.getResponse.err)
+			r.EXPECT().Get(url+"/user/files/{id}", gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(test.getResponse.count).
+				Return(test.getResponse.resp, test.getResponse.err)
 
-			err := s.GetFile(fileMark, dir)
+			var done, total int64
+			err := s.GetFile("1", dir, func(d, t int64) { done, total = d, t })
 
 			if test.wantErr {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, test.errText)
 			} else {
 				require.NoError(t, err)
+
+				content, err := os.ReadFile(filepath.Join(dir, fileMark))
+				require.NoError(t, err)
+				assert.Equal(t, "file content", string(content))
+				assert.Equal(t, test.getResponse.resp.RawResponse.ContentLength, done)
+				assert.Equal(t, test.getResponse.resp.RawResponse.ContentLength, total)
+			}
+
+			assert.NoFileExists(t, filepath.Join(dir, fileMark+".part"))
+			assert.NoFileExists(t, filepath.Join(dir, fileMark+".tmp"))
+		})
+	}
+}
+
+func TestDownloadPath(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		mark    string
+		want    string
+		wantErr bool
+	}{
+		{name: "file name", mark: "report.pdf", want: filepath.Join(dir, "report.pdf"), wantErr: false},
+		{name: "parent dir", mark: "..", want: "", wantErr: true},
+		{name: "relative path", mark: "../../.bashrc", want: "", wantErr: true},
+		{name: "absolute path", mark: "/etc/passwd", want: "", wantErr: true},
+		{name: "nested path", mark: "a/b", want: "", wantErr: true},
+		{name: "windows path", mark: `..\evil`, want: "", wantErr: true},
+		{name: "empty mark", mark: "", want: "", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := downloadPath(dir, test.mark)
+
+			if test.wantErr {
+				require.ErrorIs(t, err, ErrInvalidFileName)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+// encryptContent шифрует содержимое файла ключом хранилища.
+func encryptContent(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, encryptTo(testVaultKey, secretAAD("file", 1), &buf, strings.NewReader(content)))
+
+	return buf.Bytes()
+}
+
+func newStreamResponse(status int, body []byte) *resty.Response {
+	return &resty.Response{
+		RawResponse: &http.Response{
+			StatusCode:    status,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+		},
+	}
+}
+
+func TestGetFileResume(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	fileMark := "test"
+
+	content := strings.Repeat("file content ", 2*crypt.ChunkSize/13+1)
+	sealed := encryptContent(t, content)
+	half := int64(len(sealed) / 2)
+
+	header, err := crypt.ReadStreamHeader(bytes.NewReader(sealed))
+	require.NoError(t, err)
+	// Смещение второй части: после обрыва загрузки внутри нее продолжение начинается с ее начала.
+	secondChunk, err := crypt.StreamChunkOffset(header, 1)
+	require.NoError(t, err)
+
+	fullResponse := func(body io.Reader) *resty.Response {
+		return &resty.Response{RawResponse: &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Etag": []string{`"etag"`}},
+			Body:          io.NopCloser(body),
+			ContentLength: int64(len(sealed)),
+		}}
+	}
+	partialResponse := func(start int64) *resty.Response {
+		return &resty.Response{RawResponse: &http.Response{
+			StatusCode: http.StatusPartialContent,
+			Header: http.Header{"Content-Range": []string{
+				fmt.Sprintf("bytes %d-%d/%d", start, len(sealed)-1, len(sealed)),
+			}},
+			Body:          io.NopCloser(bytes.NewReader(sealed[start:])),
+			ContentLength: int64(len(sealed)) - start,
+		}}
+	}
+	notSatisfiable := &resty.Response{RawResponse: &http.Response{
+		StatusCode: http.StatusRequestedRangeNotSatisfiable,
+		Body:       http.NoBody,
+	}}
+	droppedResponse := func() *resty.Response {
+		return fullResponse(io.MultiReader(
+			bytes.NewReader(sealed[:secondChunk+100]),
+			iotest.ErrReader(errors.New("connection reset")),
+		))
+	}
+	truncatedResponse := func() *resty.Response {
+		return fullResponse(bytes.NewReader(sealed[:secondChunk+100]))
+	}
+
+	tests := []struct {
+		name      string
+		part      []byte
+		head      []byte
+		etag      string
+		retries   int
+		responses []*resty.Response
+		wantErr   bool
+	}{
+		{
+			name:      "resume partial file from previous run",
+			part:      []byte(content[:crypt.ChunkSize+10]),
+			head:      header,
+			etag:      `"etag"`,
+			responses: []*resty.Response{partialResponse(secondChunk)},
+		},
+		{
+			name:      "resume encrypted partial file from previous client version",
+			part:      sealed[:half],
+			etag:      `"etag"`,
+			responses: []*resty.Response{partialResponse(half)},
+		},
+		{
+			name:      "restart when file changed on server",
+			part:      []byte("stale data"),
+			head:      header,
+			etag:      `"old"`,
+			responses: []*resty.Response{fullResponse(bytes.NewReader(sealed))},
+		},
+		{
+			name:      "restart when partial file is bigger than file on server",
+			part:      append(append([]byte{}, sealed...), sealed...),
+			etag:      `"etag"`,
+			retries:   1,
+			responses: []*resty.Response{notSatisfiable, fullResponse(bytes.NewReader(sealed))},
+		},
+		{
+			name:      "resume after connection drop",
+			retries:   1,
+			responses: []*resty.Response{droppedResponse(), partialResponse(secondChunk)},
+		},
+		{
+			name:      "resume after response ended early",
+			retries:   1,
+			responses: []*resty.Response{truncatedResponse(), partialResponse(secondChunk)},
+		},
+		{
+			name:      "keep decrypted chunks when retries exhausted",
+			retries:   0,
+			responses: []*resty.Response{droppedResponse()},
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			partPath := filepath.Join(dir, fileMark+".part")
+			if test.part != nil {
+				require.NoError(t, os.WriteFile(partPath, test.part, 0o600))
+				require.NoError(t, os.WriteFile(partPath+".etag", []byte(test.etag), 0o600))
+			}
+			if test.head != nil {
+				require.NoError(t, os.WriteFile(partPath+".head", test.head, 0o600))
+			}
+
+			calls := len(test.responses)
+			cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Mark: fileMark, Type: "file"}})
+			cfg.EXPECT().GetRequestRetry().Times(1).Return(test.retries)
+			cfg.EXPECT().GetToken().Times(calls).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(calls).Return(url)
+
+			call := 0
+			r.EXPECT().Get(url+"/user/files/{id}", gomock.Any()).Times(calls).
+				DoAndReturn(func(string, ...requests.RequestOptionFunc) (*resty.Response, error) {
+					resp := test.responses[call]
+					call++
+					return resp, nil
+				})
+
+			err := s.GetFile(fileMark, dir, nil)
+
+			if test.wantErr {
+				require.ErrorIs(t, err, errDownloadInterrupted)
+
+				part, err := os.ReadFile(partPath)
+				require.NoError(t, err)
+				assert.Equal(t, content[:crypt.ChunkSize], string(part))
+				assert.FileExists(t, partPath+".head")
+				return
 			}
+
+			require.NoError(t, err)
+
+			got, err := os.ReadFile(filepath.Join(dir, fileMark))
+			require.NoError(t, err)
+			assert.Equal(t, content, string(got))
+			assert.NoFileExists(t, partPath)
+			assert.NoFileExists(t, partPath+".etag")
+			assert.NoFileExists(t, partPath+".head")
 		})
 	}
 }
+
+func TestEditFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	data := map[string]models.UserData{"1": {ID: 1, Mark: "old", Type: "file"}}
+	filePath := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("test"), 0o600))
+	okResp := &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}
+
+	t.Run("edit file description", func(t *testing.T) {
+		description := "test"
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Patch(url+"/user/files/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(okResp, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 1, Mark: "old", Description: "test", Type: "file"}).
+			Times(1).Return(nil)
+
+		err := s.EditFile("old", "", models.UpdateFileRequest{Description: &description})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("edit file content and mark", func(t *testing.T) {
+		mark := "New Mark"
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Put(url+"/user/files/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(okResp, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 1, Mark: "new_mark", Type: "file"}).Times(1).Return(nil)
+
+		err := s.EditFile("1", filePath, models.UpdateFileRequest{Mark: &mark})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+
+		err := s.EditFile("unknown", "", models.UpdateFileRequest{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "file not found")
+	})
+
+	t.Run("edit file failed when response status not 200", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Patch(url+"/user/files/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil)
+
+		err := s.EditFile("old", "", models.UpdateFileRequest{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+}
+
+func TestDeleteFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	data := map[string]models.UserData{
+		"1": {ID: 1, Mark: "test", Type: "file"},
+		"2": {ID: 2, Mark: "copy", Type: "file"},
+		"3": {ID: 3, Mark: "copy", Type: "file"},
+		"4": {ID: 4, Mark: "test", Type: "text"},
+	}
+
+	t.Run("delete file success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Delete(url+"/user/files/{id}", gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil)
+		cfg.EXPECT().DeleteData("1").Times(1).Return(nil)
+
+		err := s.DeleteFile("test")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("delete file by id success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Delete(url+"/user/files/{id}", gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil)
+		cfg.EXPECT().DeleteData("3").Times(1).Return(nil)
+
+		err := s.DeleteFile("3")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("delete file failed when mark is ambiguous", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+
+		err := s.DeleteFile("copy")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `file mark "copy" is ambiguous, use one of IDs [2 3]`)
+	})
+
+	t.Run("delete file failed when id is not a file", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+
+		err := s.DeleteFile("4")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "file not found")
+	})
+
+	t.Run("delete file failed when request failed", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Delete(url+"/user/files/{id}", gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+
+		err := s.DeleteFile("test")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+const (
+	historyPath  = "/history"
+	restorePath  = "/history/{version}/restore"
+	fileDataPath = "/user/files/{id}"
+)
+
+// errNoHistory запись не найдена в кеше или для ее типа история версий не ведется.
+var errNoHistory = errors.New("data not found or has no version history")
+
+// GetHistory сервис получения прежних версий записи, начиная с последней.
+func (s *Services) GetHistory(id string) ([]models.UserDataRevision, error) {
+	path, err := s.historyPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+path+historyPath,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+	)
+	if err != nil {
+		return nil, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, failedResponseStatus(resp.Status())
+	}
+
+	revisions := []models.UserDataRevision{}
+	if err := json.Unmarshal(resp.Body(), &revisions); err != nil {
+		return nil, failedParseBody(err)
+	}
+
+	return revisions, nil
+}
+
+// RestoreRevision сервис восстановления прежней версии записи. Текущая версия при этом сохраняется в истории,
+// после восстановления данные синхронизируются с сервером.
+func (s *Services) RestoreRevision(id string, version int) error {
+	path, err := s.historyPath(id)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+path+restorePath,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id, "version": strconv.Itoa(version)}),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return failedResponseStatus(resp.Status())
+	}
+
+	return s.pullChanges()
+}
+
+// historyPath возвращает путь записи по ее типу из кеша.
+func (s *Services) historyPath(id string) (string, error) {
+	d, ok := s.cfg.GetData()[id]
+	if !ok {
+		return "", errNoHistory
+	}
+	if d.Type == "file" {
+		return fileDataPath, nil
+	}
+
+	path, ok := secretPaths[d.Type]
+	if !ok {
+		return "", errNoHistory
+	}
+
+	return path, nil
+}
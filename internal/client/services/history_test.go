@@ -0,0 +1,176 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	data := map[string]models.UserData{
+		"1": {ID: 1, Type: "password"},
+		"2": {ID: 2, Type: "file"},
+		"4": {ID: 4, Type: "unknown"},
+	}
+
+	t.Run("get history success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/passwords/{id}/history", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, []models.UserDataRevision{{Version: 1, Mark: "old"}}), nil)
+
+		revisions, err := s.GetHistory("1")
+
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "old", revisions[0].Mark)
+	})
+
+	t.Run("get file history success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/files/{id}/history", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, []models.UserDataRevision{{Version: 2, Mark: "old.txt"}}), nil)
+
+		revisions, err := s.GetHistory("2")
+
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "old.txt", revisions[0].Mark)
+	})
+
+	t.Run("get history failed for unknown type", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+
+		_, err := s.GetHistory("4")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "has no version history")
+	})
+
+	t.Run("get history failed when data not found", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+
+		_, err := s.GetHistory("3")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "data not found")
+	})
+
+	t.Run("get history failed when response status not 200", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/passwords/{id}/history", gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil)
+
+		_, err := s.GetHistory("1")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("get history failed when request failed", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/passwords/{id}/history", gomock.Any(), gomock.Any()).Times(1).
+			Return(nil, errors.New("some error"))
+
+		_, err := s.GetHistory("1")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
+
+func TestRestoreRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	data := map[string]models.UserData{"1": {ID: 1, Type: "text", Version: 3}, "2": {ID: 2, Type: "file", Version: 2}}
+	changes := models.UserDataChanges{Changes: []models.UserData{{ID: 1, Type: "text", Version: 4}}, Cursor: 5}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	tests := []struct {
+		name      string
+		resp      *resty.Response
+		err       error
+		pullTimes int
+		wantErr   bool
+		errText   string
+	}{
+		{
+			name:      "restore revision success",
+			resp:      &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}},
+			pullTimes: 1,
+		},
+		{
+			name:    "restore revision failed when revision not found",
+			resp:    &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNotFound}},
+			wantErr: true,
+			errText: "response status",
+		},
+		{
+			name:    "restore revision failed when request failed",
+			err:     errors.New("some error"),
+			wantErr: true,
+			errText: "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetData().Times(1).Return(data)
+			r.EXPECT().Post(url+"/user/texts/{id}/history/{version}/restore", gomock.Any(), gomock.Any()).Times(1).
+				Return(test.resp, test.err)
+			cfg.EXPECT().GetSyncCursor().Times(test.pullTimes).Return(int64(4))
+			r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).Times(test.pullTimes).
+				Return(newJSONResponse(t, http.StatusOK, changes), nil)
+			cfg.EXPECT().ApplyDataChanges(changes).Times(test.pullTimes).Return(nil)
+
+			err := s.RestoreRevision("1", 2)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+	t.Run("restore file revision success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Post(url+"/user/files/{id}/history/{version}/restore", gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}, nil)
+		cfg.EXPECT().GetSyncCursor().Times(1).Return(int64(4))
+		r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, changes), nil)
+		cfg.EXPECT().ApplyDataChanges(changes).Times(1).Return(nil)
+
+		require.NoError(t, s.RestoreRevision("2", 1))
+	})
+}
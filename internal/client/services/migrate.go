@@ -0,0 +1,89 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+var errInvalidLegacySecret = errors.New("legacy data is not valid json")
+
+// MigrateSecrets сервис однократного переноса записей, сохраненных старыми версиями клиента:
+// незашифрованных на клиенте или зашифрованных без привязки к записи. Такие данные шифруются заново
+// с привязкой к типу и ID записи. Возвращает количество перенесенных записей.
+//
+// При переносе данные с сервера принимаются без проверки привязки, поэтому переносить записи нужно
+// один раз после обновления клиента, пока сервер заслуживает доверия.
+func (s *Services) MigrateSecrets() (int, error) {
+	data := s.cfg.GetData()
+
+	ids := make([]int, 0, len(data))
+	for _, d := range data {
+		if _, ok := secretPaths[d.Type]; ok {
+			ids = append(ids, d.ID)
+		}
+	}
+	slices.Sort(ids)
+
+	migrated := 0
+
+	for _, id := range ids {
+		d := data[strconv.Itoa(id)]
+
+		ok, err := s.migrateSecret(d)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to migrate data %d: %w", id, err)
+		}
+		if ok {
+			migrated++
+		}
+	}
+
+	return migrated, nil
+}
+
+// migrateSecret шифрует заново данные одной записи, если они не привязаны к записи.
+func (s *Services) migrateSecret(d models.UserData) (bool, error) {
+	path := secretPaths[d.Type]
+	id := strconv.Itoa(d.ID)
+
+	secret, err := s.fetchSecret(path, id)
+	if err != nil {
+		return false, err
+	}
+
+	if crypt.IsBound(secret.Data) {
+		return false, nil
+	}
+
+	plain := secret.Data
+
+	if crypt.IsSealed(secret.Data) {
+		key, err := s.getVaultKey()
+		if err != nil {
+			return false, failedVaultKey(err)
+		}
+
+		if plain, err = crypt.Open(key, secret.Data); err != nil {
+			return false, fmt.Errorf("failed to decrypt data: %w", err)
+		}
+	} else if !json.Valid(plain) {
+		return false, errInvalidLegacySecret
+	}
+
+	sealed, err := s.sealPlain(d.Type, d.ID, plain)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.editData(path, id, "data id not found", models.UpdateSecretRequest{Data: sealed}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateSecrets(t *testing.T) {
+	sealed, err := crypt.Seal(testVaultKey, []byte(`{"data":"sealed"}`))
+	require.NoError(t, err)
+
+	data := map[string]models.UserData{
+		"1": {ID: 1, Type: "password", Version: 1},
+		"2": {ID: 2, Type: "text", Version: 1},
+		"3": {ID: 3, Type: "card", Version: 1},
+		"4": {ID: 4, Type: "file", Version: 1},
+	}
+
+	tests := []struct {
+		name     string
+		secrets  map[string][]byte
+		migrated int
+		wantErr  bool
+		errText  string
+	}{
+		{
+			name: "migrate legacy records",
+			secrets: map[string][]byte{
+				"/user/passwords/1": []byte(`{"login":"test"}`),
+				"/user/texts/2":     sealed,
+				"/user/cards/3":     sealTestSecret(t, 3, models.EncryptCardData{}),
+			},
+			migrated: 2,
+			wantErr:  false,
+			errText:  "",
+		},
+		{
+			name: "migrate failed with invalid legacy data",
+			secrets: map[string][]byte{
+				"/user/passwords/1": []byte("not json"),
+			},
+			migrated: 0,
+			wantErr:  true,
+			errText:  "failed to migrate data 1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			patched := map[string][]byte{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPatch {
+					body, _ := io.ReadAll(r.Body)
+					req := models.UpdateSecretRequest{}
+					_ = json.Unmarshal(body, &req)
+					patched[r.URL.Path] = req.Data
+					return
+				}
+
+				w.Header().Set(ContentTypeHeader, JSONContentType)
+				_ = json.NewEncoder(w).Encode(models.Secret{Data: test.secrets[r.URL.Path]})
+			}))
+			defer srv.Close()
+
+			cfg := mocks.NewMockConfigurer(mockCtrl)
+			s := Init(cfg, requests.NewRequests(&config.Config{RequestTimeout: 5}), nil, nil)
+			s.vaultKey = testVaultKey
+
+			cfg.EXPECT().GetData().AnyTimes().Return(data)
+			cfg.EXPECT().GetToken().AnyTimes().Return("token")
+			cfg.EXPECT().GetServerAPI().AnyTimes().Return(srv.URL)
+			cfg.EXPECT().AddData(gomock.Any()).Times(test.migrated).Return(nil)
+
+			migrated, err := s.MigrateSecrets()
+
+			assert.Equal(t, test.migrated, migrated)
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, patched, test.migrated)
+
+			password := models.EncryptPasswordData{}
+			require.NoError(t, s.openSecret("password", 1, patched["/user/passwords/1"], &password))
+			assert.Equal(t, "test", password.Login)
+
+			text := models.EncryptTextData{}
+			require.NoError(t, s.openSecret("text", 2, patched["/user/texts/2"], &text))
+			assert.Equal(t, "sealed", text.Data)
+		})
+	}
+}
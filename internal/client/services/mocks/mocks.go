@@ -50,6 +50,34 @@ func (mr *MockConfigurerMockRecorder) AddData(data interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddData", reflect.TypeOf((*MockConfigurer)(nil).AddData), data)
 }
 
+// ApplyDataChanges mocks base method.
+func (m *MockConfigurer) ApplyDataChanges(changes models.UserDataChanges) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDataChanges", changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyDataChanges indicates an expected call of ApplyDataChanges.
+func (mr *MockConfigurerMockRecorder) ApplyDataChanges(changes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDataChanges", reflect.TypeOf((*MockConfigurer)(nil).ApplyDataChanges), changes)
+}
+
+// DeleteData mocks base method.
+func (m *MockConfigurer) DeleteData(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteData", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteData indicates an expected call of DeleteData.
+func (mr *MockConfigurerMockRecorder) DeleteData(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteData", reflect.TypeOf((*MockConfigurer)(nil).DeleteData), key)
+}
+
 // GetData mocks base method.
 func (m *MockConfigurer) GetData() map[string]models.UserData {
 	m.ctrl.T.Helper()
@@ -106,6 +134,20 @@ func (mr *MockConfigurerMockRecorder) GetServerAPI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerAPI", reflect.TypeOf((*MockConfigurer)(nil).GetServerAPI))
 }
 
+// GetSyncCursor mocks base method.
+func (m *MockConfigurer) GetSyncCursor() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncCursor")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetSyncCursor indicates an expected call of GetSyncCursor.
+func (mr *MockConfigurerMockRecorder) GetSyncCursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncCursor", reflect.TypeOf((*MockConfigurer)(nil).GetSyncCursor))
+}
+
 // GetToken mocks base method.
 func (m *MockConfigurer) GetToken() string {
 	m.ctrl.T.Helper()
@@ -134,18 +176,18 @@ func (mr *MockConfigurerMockRecorder) UpdateData(data interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateData", reflect.TypeOf((*MockConfigurer)(nil).UpdateData), data)
 }
 
-// UpdateToken mocks base method.
-func (m *MockConfigurer) UpdateToken(token string) error {
+// UpdateTokens mocks base method.
+func (m *MockConfigurer) UpdateTokens(token, refreshToken string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateToken", token)
+	ret := m.ctrl.Call(m, "UpdateTokens", token, refreshToken)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateToken indicates an expected call of UpdateToken.
-func (mr *MockConfigurerMockRecorder) UpdateToken(token interface{}) *gomock.Call {
+// UpdateTokens indicates an expected call of UpdateTokens.
+func (mr *MockConfigurerMockRecorder) UpdateTokens(token, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToken", reflect.TypeOf((*MockConfigurer)(nil).UpdateToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTokens", reflect.TypeOf((*MockConfigurer)(nil).UpdateTokens), token, refreshToken)
 }
 
 // MockRequester is a mock of Requester interface.
@@ -171,6 +213,26 @@ func (m *MockRequester) EXPECT() *MockRequesterMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockRequester) Delete(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{url}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(*resty.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRequesterMockRecorder) Delete(url interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{url}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRequester)(nil).Delete), varargs...)
+}
+
 // Get mocks base method.
 func (m *MockRequester) Get(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error) {
 	m.ctrl.T.Helper()
@@ -191,6 +253,26 @@ func (mr *MockRequesterMockRecorder) Get(url interface{}, opts ...interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRequester)(nil).Get), varargs...)
 }
 
+// Patch mocks base method.
+func (m *MockRequester) Patch(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{url}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Patch", varargs...)
+	ret0, _ := ret[0].(*resty.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockRequesterMockRecorder) Patch(url interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{url}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockRequester)(nil).Patch), varargs...)
+}
+
 // Post mocks base method.
 func (m *MockRequester) Post(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error) {
 	m.ctrl.T.Helper()
@@ -210,3 +292,132 @@ func (mr *MockRequesterMockRecorder) Post(url interface{}, opts ...interface{})
 	varargs := append([]interface{}{url}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockRequester)(nil).Post), varargs...)
 }
+
+// Put mocks base method.
+func (m *MockRequester) Put(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{url}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Put", varargs...)
+	ret0, _ := ret[0].(*resty.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockRequesterMockRecorder) Put(url interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{url}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockRequester)(nil).Put), varargs...)
+}
+
+// MockOfflineQueuer is a mock of OfflineQueuer interface.
+type MockOfflineQueuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfflineQueuerMockRecorder
+}
+
+// MockOfflineQueuerMockRecorder is the mock recorder for MockOfflineQueuer.
+type MockOfflineQueuerMockRecorder struct {
+	mock *MockOfflineQueuer
+}
+
+// NewMockOfflineQueuer creates a new mock instance.
+func NewMockOfflineQueuer(ctrl *gomock.Controller) *MockOfflineQueuer {
+	mock := &MockOfflineQueuer{ctrl: ctrl}
+	mock.recorder = &MockOfflineQueuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfflineQueuer) EXPECT() *MockOfflineQueuerMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockOfflineQueuer) Clear() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockOfflineQueuerMockRecorder) Clear() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockOfflineQueuer)(nil).Clear))
+}
+
+// KeyParams mocks base method.
+func (m *MockOfflineQueuer) KeyParams() (models.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyParams")
+	ret0, _ := ret[0].(models.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyParams indicates an expected call of KeyParams.
+func (mr *MockOfflineQueuerMockRecorder) KeyParams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyParams", reflect.TypeOf((*MockOfflineQueuer)(nil).KeyParams))
+}
+
+// PendingChanges mocks base method.
+func (m *MockOfflineQueuer) PendingChanges() ([]models.PendingChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingChanges")
+	ret0, _ := ret[0].([]models.PendingChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingChanges indicates an expected call of PendingChanges.
+func (mr *MockOfflineQueuerMockRecorder) PendingChanges() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingChanges", reflect.TypeOf((*MockOfflineQueuer)(nil).PendingChanges))
+}
+
+// QueueChange mocks base method.
+func (m *MockOfflineQueuer) QueueChange(change models.PendingChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueChange", change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueChange indicates an expected call of QueueChange.
+func (mr *MockOfflineQueuerMockRecorder) QueueChange(change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueChange", reflect.TypeOf((*MockOfflineQueuer)(nil).QueueChange), change)
+}
+
+// SetKeyParams mocks base method.
+func (m *MockOfflineQueuer) SetKeyParams(params models.Vault) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeyParams", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKeyParams indicates an expected call of SetKeyParams.
+func (mr *MockOfflineQueuerMockRecorder) SetKeyParams(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyParams", reflect.TypeOf((*MockOfflineQueuer)(nil).SetKeyParams), params)
+}
+
+// SetPendingChanges mocks base method.
+func (m *MockOfflineQueuer) SetPendingChanges(changes []models.PendingChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingChanges", changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingChanges indicates an expected call of SetPendingChanges.
+func (mr *MockOfflineQueuerMockRecorder) SetPendingChanges(changes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingChanges", reflect.TypeOf((*MockOfflineQueuer)(nil).SetPendingChanges), changes)
+}
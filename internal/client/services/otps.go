@@ -0,0 +1,173 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/totp"
+)
+
+const (
+	otpsPath        = "/user/otps"
+	otpPath         = "/user/otps/{id}"
+	otpNotFoundText = "otp id not found"
+)
+
+// AddOTP сервис добавления секрета TOTP.
+func (s *Services) AddOTP(req models.AddOTPRequest) error {
+	data, err := newOTPData(req)
+	if err != nil {
+		return failedValidateFields(err)
+	}
+	if err := validateMarkAndDescription(req.Mark, req.Description); err != nil {
+		return failedValidateFields(err)
+	}
+
+	return s.addSecret(otpsPath, data, models.UserData{
+		Type:        "otp",
+		Mark:        req.Mark,
+		Description: req.Description,
+		Folder:      req.Folder,
+		Tags:        req.Tags,
+	})
+}
+
+// GetOTP сервис получения секрета TOTP.
+func (s *Services) GetOTP(id string) (models.OTP, error) {
+	data := models.EncryptOTPData{}
+
+	secret, err := s.getSecret(otpPath, id, otpNotFoundText, &data)
+	if err != nil {
+		return models.OTP{}, err
+	}
+
+	return models.OTP{
+		ID:          secret.ID,
+		Issuer:      data.Issuer,
+		Account:     data.Account,
+		Secret:      data.Secret,
+		Algorithm:   data.Algorithm,
+		Digits:      data.Digits,
+		Period:      data.Period,
+		Mark:        secret.Mark,
+		Description: secret.Description,
+	}, nil
+}
+
+// GetOTPCode сервис получения текущего одноразового пароля по секрету TOTP.
+func (s *Services) GetOTPCode(id string) (models.OTPCode, error) {
+	otp, err := s.GetOTP(id)
+	if err != nil {
+		return models.OTPCode{}, err
+	}
+
+	return generateOTPCode(otp, time.Now())
+}
+
+// EditOTP сервис изменения секрета TOTP.
+func (s *Services) EditOTP(id string, req models.UpdateOTPRequest) error {
+	if err := validateMarkAndDescription(valueOrEmpty(req.Mark), valueOrEmpty(req.Description)); err != nil {
+		return failedValidateFields(err)
+	}
+
+	var data any
+
+	if req.URI != nil {
+		newData, err := newOTPData(models.AddOTPRequest{URI: *req.URI})
+		if err != nil {
+			return failedValidateFields(err)
+		}
+
+		data = newData
+	}
+
+	return s.editSecret(otpPath, id, otpNotFoundText, data, req.Mark, req.Description)
+}
+
+// DeleteOTP сервис удаления секрета TOTP.
+func (s *Services) DeleteOTP(id string) error {
+	return s.deleteData(otpPath, id, otpNotFoundText)
+}
+
+// newOTPData собирает данные секрета TOTP из ссылки otpauth:// или отдельных параметров.
+// Не указанные параметры принимают значения по умолчанию.
+func newOTPData(req models.AddOTPRequest) (models.EncryptOTPData, error) {
+	key := totp.Key{
+		Issuer:  req.Issuer,
+		Account: req.Account,
+		Options: totp.DefaultOptions(),
+	}
+
+	if req.URI != "" {
+		var err error
+
+		key, err = totp.ParseURI(req.URI)
+		if err != nil {
+			return models.EncryptOTPData{}, err
+		}
+	} else {
+		secret, err := totp.DecodeSecret(req.Secret)
+		if err != nil {
+			return models.EncryptOTPData{}, err
+		}
+
+		key.Secret = secret
+
+		if req.Algorithm != "" {
+			key.Algorithm = strings.ToUpper(req.Algorithm)
+		}
+		if req.Digits != 0 {
+			key.Digits = req.Digits
+		}
+		if req.Period != 0 {
+			key.Period = req.Period
+		}
+
+		if err := key.Validate(); err != nil {
+			return models.EncryptOTPData{}, err
+		}
+	}
+
+	data := models.EncryptOTPData{
+		Issuer:    key.Issuer,
+		Account:   key.Account,
+		Secret:    totp.EncodeSecret(key.Secret),
+		Algorithm: key.Algorithm,
+		Digits:    key.Digits,
+		Period:    key.Period,
+	}
+
+	if err := validateOTPData(data); err != nil {
+		return models.EncryptOTPData{}, err
+	}
+
+	return data, nil
+}
+
+// generateOTPCode вычисляет одноразовый пароль для момента времени.
+func generateOTPCode(otp models.OTP, t time.Time) (models.OTPCode, error) {
+	secret, err := totp.DecodeSecret(otp.Secret)
+	if err != nil {
+		return models.OTPCode{}, failedGenerateOTPCode(err)
+	}
+
+	opts := totp.Options{
+		Algorithm: otp.Algorithm,
+		Digits:    otp.Digits,
+		Period:    otp.Period,
+	}
+
+	code, err := totp.Generate(secret, t, opts)
+	if err != nil {
+		return models.OTPCode{}, failedGenerateOTPCode(err)
+	}
+
+	return models.OTPCode{
+		Code:      code,
+		Issuer:    otp.Issuer,
+		Account:   otp.Account,
+		Remaining: totp.Remaining(t, opts.Period),
+		Period:    opts.Period,
+	}, nil
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/totp"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestAddOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	req := models.AddOTPRequest{
+		URI:  "otpauth://totp/GitHub:deploy-bot?secret=" + testOTPSecret,
+		Mark: "github",
+	}
+
+	type response struct {
+		resp *resty.Response
+		err  error
+	}
+	tests := []struct {
+		name         string
+		reserve      response
+		post         response
+		postCount    int
+		addDataCount int
+		addDataErr   error
+		wantErr      bool
+		errText      string
+	}{
+		{
+			name:         "add otp success",
+			reserve:      response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:         response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			postCount:    1,
+			addDataCount: 1,
+		},
+		{
+			name:         "add otp failed",
+			reserve:      response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:         response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			postCount:    1,
+			addDataCount: 1,
+			addDataErr:   errors.New("some error"),
+			wantErr:      true,
+			errText:      "failed to dump data",
+		},
+		{
+			name:    "add otp failed when reserve id failed",
+			reserve: response{resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusInternalServerError}}},
+			wantErr: true,
+			errText: "response status",
+		},
+		{
+			name:      "add otp failed when response status not 201",
+			reserve:   response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:      response{resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusForbidden}}},
+			postCount: 1,
+			wantErr:   true,
+			errText:   "response status",
+		},
+		{
+			name:      "add otp failed when request failed",
+			reserve:   response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:      response{err: errors.New("some error")},
+			postCount: 1,
+			wantErr:   true,
+			errText:   "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetToken().Times(1 + test.postCount).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(1 + test.postCount).Return(url)
+
+			r.EXPECT().Post(url+"/user/data/ids", gomock.Any(), gomock.Any()).
+				Times(1).Return(test.reserve.resp, test.reserve.err)
+			r.EXPECT().Post(url+"/user/otps", gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(test.postCount).Return(test.post.resp, test.post.err)
+			r.EXPECT().Patch(gomock.Any(), gomock.Any()).Times(0)
+
+			cfg.EXPECT().AddData(models.UserData{ID: 5, Type: "otp", Mark: req.Mark, Version: 1}).
+				Times(test.addDataCount).Return(test.addDataErr)
+
+			err := s.AddOTP(req)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("add otp failed with invalid uri", func(t *testing.T) {
+		err := s.AddOTP(models.AddOTPRequest{URI: "https://example.com"})
+
+		require.ErrorIs(t, err, totp.ErrInvalidURI)
+	})
+}
+
+func TestNewOTPData(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.AddOTPRequest
+		want    models.EncryptOTPData
+		wantErr error
+	}{
+		{
+			name: "from uri",
+			req: models.AddOTPRequest{
+				URI: "otpauth://totp/GitHub:deploy-bot?secret=" + testOTPSecret + "&digits=8&algorithm=SHA256",
+			},
+			want: models.EncryptOTPData{
+				Issuer:    "GitHub",
+				Account:   "deploy-bot",
+				Secret:    testOTPSecret,
+				Algorithm: totp.AlgorithmSHA256,
+				Digits:    8,
+				Period:    totp.Period,
+			},
+		},
+		{
+			name: "from fields with defaults",
+			req: models.AddOTPRequest{
+				Issuer:  "AWS",
+				Account: "root",
+				Secret:  "gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+			},
+			want: models.EncryptOTPData{
+				Issuer:    "AWS",
+				Account:   "root",
+				Secret:    testOTPSecret,
+				Algorithm: totp.AlgorithmSHA1,
+				Digits:    totp.Digits,
+				Period:    totp.Period,
+			},
+		},
+		{
+			name:    "without secret",
+			req:     models.AddOTPRequest{Issuer: "AWS"},
+			wantErr: totp.ErrInvalidSecret,
+		},
+		{
+			name:    "with unsupported algorithm",
+			req:     models.AddOTPRequest{Secret: testOTPSecret, Algorithm: "md5"},
+			wantErr: totp.ErrInvalidAlgorithm,
+		},
+		{
+			name: "with too long issuer",
+			req: models.AddOTPRequest{
+				Secret: testOTPSecret,
+				Issuer: string(make([]rune, otpLabelMaxSize+1)),
+			},
+			wantErr: ErrOTPLabelIsTooBig,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := newOTPData(test.req)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestGetOTPCode(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	data := models.EncryptOTPData{
+		Issuer:    "GitHub",
+		Account:   "deploy-bot",
+		Secret:    testOTPSecret,
+		Algorithm: totp.AlgorithmSHA1,
+		Digits:    totp.Digits,
+		Period:    totp.Period,
+	}
+
+	t.Run("get otp code success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "otp"}})
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/otps/{id}", gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(newSecretResponse(t, data), nil)
+
+		code, err := s.GetOTPCode("1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "GitHub", code.Issuer)
+		assert.Equal(t, "deploy-bot", code.Account)
+		assert.Len(t, code.Code, totp.Digits)
+		assert.Equal(t, totp.Period, code.Period)
+		assert.True(t, code.Remaining > 0 && code.Remaining <= totp.Period)
+	})
+
+	t.Run("otp not found", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"2": {ID: 2}})
+
+		_, err := s.GetOTPCode("1")
+
+		assert.ErrorContains(t, err, "otp id not found")
+	})
+}
+
+func TestGenerateOTPCode(t *testing.T) {
+	otp := models.OTP{
+		Issuer:    "GitHub",
+		Account:   "deploy-bot",
+		Secret:    testOTPSecret,
+		Algorithm: totp.AlgorithmSHA1,
+		Digits:    8,
+		Period:    totp.Period,
+	}
+
+	t.Run("generate code", func(t *testing.T) {
+		code, err := generateOTPCode(otp, time.Unix(1111111109, 0))
+
+		require.NoError(t, err)
+		assert.Equal(t, models.OTPCode{
+			Code:      "07081804",
+			Issuer:    "GitHub",
+			Account:   "deploy-bot",
+			Remaining: 1,
+			Period:    totp.Period,
+		}, code)
+	})
+
+	t.Run("generate code failed with broken secret", func(t *testing.T) {
+		broken := otp
+		broken.Secret = "not base32!"
+
+		_, err := generateOTPCode(broken, time.Unix(59, 0))
+
+		assert.ErrorContains(t, err, "failed to generate otp code")
+	})
+}
+
+func TestEditOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	mark := "new"
+	uri := "otpauth://totp/GitHub:deploy-bot?secret=" + testOTPSecret
+
+	t.Run("edit otp success", func(t *testing.T) {
+		cfg.EXPECT().GetData().Times(2).Return(map[string]models.UserData{"1": {ID: 1, Type: "otp", Mark: "old"}})
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Patch(url+"/user/otps/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 1, Type: "otp", Mark: mark}).Times(1).Return(nil)
+
+		err := s.EditOTP("1", models.UpdateOTPRequest{URI: &uri, Mark: &mark})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("edit otp failed with invalid uri", func(t *testing.T) {
+		bad := "otpauth://hotp/user?secret=" + testOTPSecret
+
+		err := s.EditOTP("1", models.UpdateOTPRequest{URI: &bad})
+
+		require.ErrorIs(t, err, totp.ErrInvalidURI)
+	})
+}
+
+func TestDeleteOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+
+	cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1}})
+	cfg.EXPECT().GetToken().Times(1).Return("token")
+	cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+	r.EXPECT().Delete(url+"/user/otps/{id}", gomock.Any(), gomock.Any()).
+		Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil)
+	cfg.EXPECT().DeleteData("1").Times(1).Return(nil)
+
+	err := s.DeleteOTP("1")
+
+	require.NoError(t, err)
+}
@@ -1,76 +1,89 @@
 package services
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
-
-	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 )
 
+const (
+	passwordsPath        = "/user/passwords"
+	passwordPath         = "/user/passwords/{id}"
+	passwordNotFoundText = "password id not found"
+)
+
 // AddPassword сервис добавления данных логин-пароль.
 func (s *Services) AddPassword(req models.AddPasswordRequest) error {
-	const path = "/user/passwords"
-
-	body, err := json.Marshal(req)
-	if err != nil {
-		return failedCreateBody(err)
+	data := models.EncryptPasswordData{
+		Login:    req.Login,
+		Password: req.Password,
 	}
 
-	addResp := models.AddResponse{}
-
-	resp, err := s.httpRequests.Post(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, JSONContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithBody(body),
-		requests.WithResult(&addResp),
-	)
-	if err != nil {
-		return failedRequest(err)
+	if err := validatePasswordData(data); err != nil {
+		return failedValidateFields(err)
 	}
-	if resp.StatusCode() != http.StatusCreated {
-		return failedResponseStatus(resp.Status())
+	if err := validateMarkAndDescription(req.Mark, req.Description); err != nil {
+		return failedValidateFields(err)
 	}
 
-	d := models.UserData{
-		ID:          addResp.ID,
+	return s.addSecret(passwordsPath, data, models.UserData{
+		Type:        "password",
 		Mark:        req.Mark,
 		Description: req.Description,
-		Type:        "password",
-	}
-
-	if err := s.cfg.AddData(d); err != nil {
-		return failedDumpData(err)
-	}
-
-	return nil
+		Folder:      req.Folder,
+		Tags:        req.Tags,
+	})
 }
 
 // GetPassword сервис получения данных логин-пароль.
 func (s *Services) GetPassword(id string) (models.Password, error) {
-	const path = "/user/passwords/{id}"
+	data := models.EncryptPasswordData{}
 
-	password := models.Password{}
-
-	if _, ok := s.cfg.GetData()[id]; !ok {
-		return password, errors.New("password id not found")
+	secret, err := s.getSecret(passwordPath, id, passwordNotFoundText, &data)
+	if err != nil {
+		return models.Password{}, err
 	}
 
-	resp, err := s.httpRequests.Get(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, JSONContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithPathParams(map[string]string{"id": id}),
-		requests.WithResult(&password),
-	)
-	if err != nil {
-		return password, failedRequest(err)
+	return models.Password{
+		ID:          secret.ID,
+		Login:       data.Login,
+		Password:    data.Password,
+		Mark:        secret.Mark,
+		Description: secret.Description,
+	}, nil
+}
+
+// EditPassword сервис изменения данных логин-пароль.
+// Секретные поля изменяются на клиенте: текущие данные расшифровываются, дополняются и шифруются заново.
+func (s *Services) EditPassword(id string, req models.UpdatePasswordRequest) error {
+	if err := validateMarkAndDescription(valueOrEmpty(req.Mark), valueOrEmpty(req.Description)); err != nil {
+		return failedValidateFields(err)
 	}
-	if resp.StatusCode() != http.StatusOK {
-		return password, failedResponseStatus(resp.Status())
+
+	var data any
+
+	if req.Login != nil || req.Password != nil {
+		password, err := s.GetPassword(id)
+		if err != nil {
+			return err
+		}
+
+		newData := models.EncryptPasswordData{
+			Login:    password.Login,
+			Password: password.Password,
+		}
+		patchValue(&newData.Login, req.Login)
+		patchValue(&newData.Password, req.Password)
+
+		if err := validatePasswordData(newData); err != nil {
+			return failedValidateFields(err)
+		}
+
+		data = newData
 	}
 
-	return password, nil
+	return s.editSecret(passwordPath, id, passwordNotFoundText, data, req.Mark, req.Description)
+}
+
+// DeletePassword сервис удаления данных логин-пароль.
+func (s *Services) DeletePassword(id string) error {
+	return s.deleteData(passwordPath, id, passwordNotFoundText)
 }
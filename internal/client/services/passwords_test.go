@@ -1,10 +1,14 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/go-resty/resty/v2"
@@ -19,94 +23,79 @@ func TestAddPassword(t *testing.T) {
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
 
 	url := "http://some/api"
 	req := models.AddPasswordRequest{}
 
-	type postResponse struct {
+	type response struct {
 		resp *resty.Response
 		err  error
 	}
-	type addData struct {
-		count int
-		err   error
-	}
 	tests := []struct {
 		name         string
-		postResponse postResponse
-		addData      addData
+		reserve      response
+		post         response
+		postCount    int
+		addDataCount int
+		addDataErr   error
 		wantErr      bool
 		errText      string
 	}{
 		{
-			name: "add password success",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusCreated},
-				},
-				err: nil,
-			},
-			addData: addData{
-				count: 1,
-				err:   nil,
-			},
-			wantErr: false,
-			errText: "",
+			name:         "add password success",
+			reserve:      response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:         response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			postCount:    1,
+			addDataCount: 1,
 		},
 		{
-			name: "add password failed",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusCreated},
-				},
-				err: nil,
-			},
-			addData: addData{
-				count: 1,
-				err:   errors.New("some error"),
-			},
-			wantErr: true,
-			errText: "failed to dump data",
+			name:         "add password failed",
+			reserve:      response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:         response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			postCount:    1,
+			addDataCount: 1,
+			addDataErr:   errors.New("some error"),
+			wantErr:      true,
+			errText:      "failed to dump data",
 		},
 		{
-			name: "add password failed when response status not 201",
-			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusForbidden},
-				},
-				err: nil,
-			},
-			addData: addData{
-				count: 0,
-				err:   nil,
-			},
+			name:    "add password failed when reserve id failed",
+			reserve: response{resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusInternalServerError}}},
 			wantErr: true,
 			errText: "response status",
 		},
 		{
-			name: "add password failed when request failed",
-			postResponse: postResponse{
-				resp: nil,
-				err:  errors.New("some error"),
-			},
-			addData: addData{
-				count: 0,
-				err:   nil,
-			},
-			wantErr: true,
-			errText: "failed request",
+			name:      "add password failed when response status not 201",
+			reserve:   response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:      response{resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusForbidden}}},
+			postCount: 1,
+			wantErr:   true,
+			errText:   "response status",
+		},
+		{
+			name:      "add password failed when request failed",
+			reserve:   response{resp: newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5})},
+			post:      response{err: errors.New("some error")},
+			postCount: 1,
+			wantErr:   true,
+			errText:   "failed request",
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cfg.EXPECT().GetToken().Times(1).Return("token")
-			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			cfg.EXPECT().GetToken().Times(1 + test.postCount).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(1 + test.postCount).Return(url)
 
-			r.EXPECT().Post(url+"/user/passwords", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-				Times(1).Return(test.postResponse.resp, test.postResponse.err)
+			r.EXPECT().Post(url+"/user/data/ids", gomock.Any(), gomock.Any()).
+				Times(1).Return(test.reserve.resp, test.reserve.err)
+			r.EXPECT().Post(url+"/user/passwords", gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(test.postCount).Return(test.post.resp, test.post.err)
+			r.EXPECT().Patch(gomock.Any(), gomock.Any()).Times(0)
 
-			cfg.EXPECT().AddData(gomock.Any()).Times(test.addData.count).Return(test.addData.err)
+			cfg.EXPECT().AddData(models.UserData{ID: 5, Type: "password", Mark: req.Mark, Version: 1}).
+				Times(test.addDataCount).Return(test.addDataErr)
 
 			err := s.AddPassword(req)
 
@@ -120,13 +109,46 @@ func TestAddPassword(t *testing.T) {
 	}
 }
 
+func TestAddPasswordBindsReservedID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var added models.AddSecretRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusCreated)
+
+		if r.URL.Path == "/user/passwords" {
+			_ = json.NewDecoder(r.Body).Decode(&added)
+		}
+		_ = json.NewEncoder(w).Encode(models.AddResponse{ID: 5})
+	}))
+	defer srv.Close()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	s := Init(cfg, requests.NewRequests(&config.Config{RequestTimeout: 5}), nil, nil)
+	s.vaultKey = testVaultKey
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(srv.URL)
+	cfg.EXPECT().AddData(models.UserData{ID: 5, Type: "password", Mark: "mail", Version: 1}).Times(1).Return(nil)
+
+	require.NoError(t, s.AddPassword(models.AddPasswordRequest{Login: "test", Mark: "mail"}))
+
+	assert.Equal(t, 5, added.ID)
+	password := models.EncryptPasswordData{}
+	require.NoError(t, s.openSecret("password", 5, added.Data, &password))
+	assert.Equal(t, "test", password.Login)
+}
+
 func TestGetPassword(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
 
 	url := "http://some/api"
 	passwordID := "1"
@@ -147,15 +169,14 @@ func TestGetPassword(t *testing.T) {
 			name: "get password success",
 			data: map[string]models.UserData{
 				"1": {
-					ID: 1,
+					ID:   1,
+					Type: "password",
 				},
 			},
 			getResponse: getResponse{
 				count: 1,
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusOK},
-				},
-				err: nil,
+				resp:  newSecretResponse(t, models.EncryptPasswordData{Login: "test", Password: "test"}),
+				err:   nil,
 			},
 			wantErr: false,
 			errText: "",
@@ -179,7 +200,8 @@ func TestGetPassword(t *testing.T) {
 			name: "get password failed when response status not 200",
 			data: map[string]models.UserData{
 				"1": {
-					ID: 1,
+					ID:   1,
+					Type: "password",
 				},
 			},
 			getResponse: getResponse{
@@ -196,7 +218,8 @@ func TestGetPassword(t *testing.T) {
 			name: "get password failed when request failed",
 			data: map[string]models.UserData{
 				"1": {
-					ID: 1,
+					ID:   1,
+					Type: "password",
 				},
 			},
 			getResponse: getResponse{
@@ -214,7 +237,7 @@ func TestGetPassword(t *testing.T) {
 			cfg.EXPECT().GetToken().Times(test.getResponse.count).Return("token")
 			cfg.EXPECT().GetServerAPI().Times(test.getResponse.count).Return(url)
 
-			r.EXPECT().Get(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			r.EXPECT().Get(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any()).
 				Times(test.getResponse.count).Return(test.getResponse.resp, test.getResponse.err)
 
 			_, err := s.GetPassword(passwordID)
@@ -228,3 +251,252 @@ func TestGetPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestEditPassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	mark := "new"
+	req := models.UpdatePasswordRequest{Mark: &mark}
+
+	type patchResponse struct {
+		count int
+		resp  *resty.Response
+		err   error
+	}
+	tests := []struct {
+		name          string
+		data          map[string]models.UserData
+		patchResponse patchResponse
+		addDataCount  int
+		wantErr       bool
+		errText       string
+	}{
+		{
+			name: "edit password success",
+			data: map[string]models.UserData{"1": {ID: 1, Mark: "old"}},
+			patchResponse: patchResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusOK},
+				},
+				err: nil,
+			},
+			addDataCount: 1,
+			wantErr:      false,
+			errText:      "",
+		},
+		{
+			name: "password not found",
+			data: map[string]models.UserData{"2": {ID: 2}},
+			patchResponse: patchResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
+			addDataCount: 0,
+			wantErr:      true,
+			errText:      "password id not found",
+		},
+		{
+			name: "edit password failed when response status not 200",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			patchResponse: patchResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				err: nil,
+			},
+			addDataCount: 0,
+			wantErr:      true,
+			errText:      "response status",
+		},
+		{
+			name: "edit password failed when request failed",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			patchResponse: patchResponse{
+				count: 1,
+				resp:  nil,
+				err:   errors.New("some error"),
+			},
+			addDataCount: 0,
+			wantErr:      true,
+			errText:      "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetData().Times(1).Return(test.data)
+			cfg.EXPECT().GetToken().Times(test.patchResponse.count).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(test.patchResponse.count).Return(url)
+
+			r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(test.patchResponse.count).Return(test.patchResponse.resp, test.patchResponse.err)
+
+			cfg.EXPECT().AddData(models.UserData{ID: 1, Mark: mark}).Times(test.addDataCount).Return(nil)
+
+			err := s.EditPassword("1", req)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEditPasswordSecretFields(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+	newPassword := "new"
+	data := map[string]models.UserData{"1": {ID: 1, Type: "password", Mark: "test"}}
+
+	cfg.EXPECT().GetData().AnyTimes().Return(data)
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("edit password keeps login", func(t *testing.T) {
+		r.EXPECT().Get(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(newSecretResponse(t, models.EncryptPasswordData{Login: "test", Password: "test"}), nil)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 1, Type: "password", Mark: "test"}).Times(1).Return(nil)
+
+		err := s.EditPassword("1", models.UpdatePasswordRequest{Password: &newPassword})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("when new password very big", func(t *testing.T) {
+		bigPassword := string(make([]rune, passwordPasswordMaxSize+1))
+
+		r.EXPECT().Get(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(newSecretResponse(t, models.EncryptPasswordData{Login: "test", Password: "test"}), nil)
+
+		err := s.EditPassword("1", models.UpdatePasswordRequest{Password: &bigPassword})
+
+		require.ErrorIs(t, err, ErrPasswordIsTooBig)
+	})
+}
+
+func TestDeletePassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+	s.vaultKey = testVaultKey
+
+	url := "http://some/api"
+
+	type deleteResponse struct {
+		count int
+		resp  *resty.Response
+		err   error
+	}
+	type deleteData struct {
+		count int
+		err   error
+	}
+	tests := []struct {
+		name           string
+		data           map[string]models.UserData
+		deleteResponse deleteResponse
+		deleteData     deleteData
+		wantErr        bool
+		errText        string
+	}{
+		{
+			name: "delete password success",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			deleteResponse: deleteResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNoContent},
+				},
+				err: nil,
+			},
+			deleteData: deleteData{count: 1, err: nil},
+			wantErr:    false,
+			errText:    "",
+		},
+		{
+			name: "delete password failed when dump data failed",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			deleteResponse: deleteResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNoContent},
+				},
+				err: nil,
+			},
+			deleteData: deleteData{count: 1, err: errors.New("some error")},
+			wantErr:    true,
+			errText:    "failed to dump data",
+		},
+		{
+			name: "password not found",
+			data: map[string]models.UserData{"2": {ID: 2}},
+			deleteResponse: deleteResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
+			deleteData: deleteData{count: 0, err: nil},
+			wantErr:    true,
+			errText:    "password id not found",
+		},
+		{
+			name: "delete password failed when response status not 204",
+			data: map[string]models.UserData{"1": {ID: 1}},
+			deleteResponse: deleteResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				err: nil,
+			},
+			deleteData: deleteData{count: 0, err: nil},
+			wantErr:    true,
+			errText:    "response status",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetData().Times(1).Return(test.data)
+			cfg.EXPECT().GetToken().Times(test.deleteResponse.count).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(test.deleteResponse.count).Return(url)
+
+			r.EXPECT().Delete(url+"/user/passwords/{id}", gomock.Any(), gomock.Any()).
+				Times(test.deleteResponse.count).Return(test.deleteResponse.resp, test.deleteResponse.err)
+
+			cfg.EXPECT().DeleteData("1").Times(test.deleteData.count).Return(test.deleteData.err)
+
+			err := s.DeletePassword("1")
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+// SearchData сервис поиска данных пользователя на сервере по фильтру.
+func (s *Services) SearchData(filter models.UserDataFilter) (models.UserDataPage, error) {
+	const path = "/user/data"
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithQueryParams(searchQueryParams(filter)),
+	)
+	if err != nil {
+		return models.UserDataPage{}, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return models.UserDataPage{}, failedResponseStatus(resp.Status())
+	}
+
+	page := models.UserDataPage{}
+	if err := json.Unmarshal(resp.Body(), &page); err != nil {
+		return models.UserDataPage{}, failedParseBody(err)
+	}
+
+	return page, nil
+}
+
+// searchQueryParams формирует параметры строки запроса из заданных полей фильтра.
+func searchQueryParams(filter models.UserDataFilter) map[string]string {
+	params := map[string]string{}
+
+	setParam := func(key, value string) {
+		if value != "" {
+			params[key] = value
+		}
+	}
+	setTime := func(key string, value time.Time) {
+		if !value.IsZero() {
+			params[key] = value.Format(time.RFC3339)
+		}
+	}
+
+	setParam("type", filter.Type)
+	setParam("mark", filter.MarkPrefix)
+	setParam("search", filter.Search)
+	setParam("sort", filter.Sort)
+	setParam("tag", filter.Tag)
+	setParam("folder", filter.Folder)
+	setTime("created_from", filter.CreatedFrom)
+	setTime("created_to", filter.CreatedTo)
+	setTime("updated_from", filter.UpdatedFrom)
+	setTime("updated_to", filter.UpdatedTo)
+
+	if filter.Limit > 0 {
+		params["limit"] = strconv.Itoa(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		params["offset"] = strconv.Itoa(filter.Offset)
+	}
+
+	return params
+}
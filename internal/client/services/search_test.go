@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	filter := models.UserDataFilter{Type: "card", Search: "aws", Limit: 20}
+
+	t.Run("search data success", func(t *testing.T) {
+		page := models.UserDataPage{Items: []models.UserDataItem{{ID: 1, Type: "card", Mark: "aws"}}, Total: 1}
+
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, page), nil)
+
+		res, err := s.SearchData(filter)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.Total)
+		require.Len(t, res.Items, 1)
+		assert.Equal(t, "aws", res.Items[0].Mark)
+	})
+
+	t.Run("search data failed when response status not 200", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusBadRequest}}, nil)
+
+		_, err := s.SearchData(filter)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("search data failed when request failed", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.SearchData(filter)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
+
+func TestSearchDataQuery(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		_, _ = w.Write([]byte(`{"items":[],"total":0}`))
+	}))
+	defer srv.Close()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	s := Init(cfg, requests.NewRequests(&config.Config{RequestTimeout: 5}), nil, nil)
+
+	cfg.EXPECT().GetToken().Times(1).Return("token")
+	cfg.EXPECT().GetServerAPI().Times(1).Return(srv.URL)
+
+	_, err := s.SearchData(models.UserDataFilter{Type: "card", Tag: "prod", Folder: "work/aws", Limit: 20})
+
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"type":   {"card"},
+		"tag":    {"prod"},
+		"folder": {"work/aws"},
+		"limit":  {"20"},
+	}, query)
+}
+
+func TestSearchQueryParams(t *testing.T) {
+	from := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter models.UserDataFilter
+		want   map[string]string
+	}{
+		{
+			name:   "empty filter",
+			filter: models.UserDataFilter{},
+			want:   map[string]string{},
+		},
+		{
+			name: "full filter",
+			filter: models.UserDataFilter{
+				Type:        "card",
+				MarkPrefix:  "ba",
+				Search:      "main",
+				Sort:        "-updated",
+				Tag:         "prod",
+				Folder:      "work/aws",
+				Limit:       20,
+				Offset:      40,
+				UpdatedFrom: from,
+			},
+			want: map[string]string{
+				"type":         "card",
+				"mark":         "ba",
+				"search":       "main",
+				"sort":         "-updated",
+				"tag":          "prod",
+				"folder":       "work/aws",
+				"limit":        "20",
+				"offset":       "40",
+				"updated_from": "2024-01-02T03:04:05Z",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, searchQueryParams(test.filter))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+// ErrUnboundSecret данные записи не привязаны к ее типу и ID: сохранены до привязки или подменены сервером.
+var ErrUnboundSecret = errors.New("data is not bound to the record, run migrate if it was saved by an older client")
+
+// addSecret сервис добавления данных пользователя, зашифрованных ключом хранилища.
+// Тип, метка, описание, папка и метки данных берутся из d.
+func (s *Services) addSecret(path string, data any, d models.UserData) error {
+	plain, err := json.Marshal(data)
+	if err != nil {
+		return failedCreateBody(err)
+	}
+
+	return s.addPlain(path, plain, d)
+}
+
+// addPlain сервис добавления данных пользователя, переданных в открытом виде.
+// ID записи резервируется на сервере до добавления, поэтому данные шифруются один раз
+// с привязкой к ID, под которым запись будет сохранена.
+func (s *Services) addPlain(path string, plain []byte, d models.UserData) error {
+	id, err := s.reserveDataID()
+	if err != nil {
+		return err
+	}
+
+	sealed, err := s.sealPlain(d.Type, id, plain)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(models.AddSecretRequest{
+		Data:        sealed,
+		Mark:        d.Mark,
+		Description: d.Description,
+		Folder:      d.Folder,
+		Tags:        d.Tags,
+		ID:          id,
+	})
+	if err != nil {
+		return failedCreateBody(err)
+	}
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithBody(body),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusCreated {
+		return failedResponseStatus(resp.Status())
+	}
+
+	d.ID = id
+	d.Version = 1
+
+	if err := s.cfg.AddData(d); err != nil {
+		return failedDumpData(err)
+	}
+
+	return nil
+}
+
+// getSecret сервис получения и расшифровки данных пользователя.
+func (s *Services) getSecret(path, id, notFoundText string, data any) (models.Secret, error) {
+	d, ok := s.cfg.GetData()[id]
+	if !ok {
+		return models.Secret{}, errors.New(notFoundText)
+	}
+
+	secret, err := s.fetchSecret(path, id)
+	if err != nil {
+		return secret, err
+	}
+
+	if err := s.openSecret(d.Type, d.ID, secret.Data, data); err != nil {
+		return secret, err
+	}
+
+	return secret, nil
+}
+
+// fetchSecret сервис получения данных пользователя с сервера.
+func (s *Services) fetchSecret(path, id string) (models.Secret, error) {
+	secret := models.Secret{}
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+	)
+	if err != nil {
+		return secret, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return secret, failedResponseStatus(resp.Status())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &secret); err != nil {
+		return secret, failedParseBody(err)
+	}
+
+	return secret, nil
+}
+
+// editSecret сервис частичного изменения данных пользователя.
+// Если данные не переданы, изменяются только метка и описание.
+func (s *Services) editSecret(path, id, notFoundText string, data any, mark, description *string) error {
+	req := models.UpdateSecretRequest{
+		Mark:        mark,
+		Description: description,
+	}
+
+	if data != nil {
+		d, ok := s.cfg.GetData()[id]
+		if !ok {
+			return errors.New(notFoundText)
+		}
+
+		sealed, err := s.sealSecret(d.Type, d.ID, data)
+		if err != nil {
+			return err
+		}
+
+		req.Data = sealed
+	}
+
+	return s.editData(path, id, notFoundText, req)
+}
+
+// sealSecret шифрует данные пользователя ключом хранилища с привязкой к типу и ID записи.
+func (s *Services) sealSecret(dataType string, id int, data any) ([]byte, error) {
+	plain, err := json.Marshal(data)
+	if err != nil {
+		return nil, failedCreateBody(err)
+	}
+
+	return s.sealPlain(dataType, id, plain)
+}
+
+// sealPlain шифрует открытые данные пользователя ключом хранилища с привязкой к типу и ID записи.
+func (s *Services) sealPlain(dataType string, id int, plain []byte) ([]byte, error) {
+	key, err := s.getVaultKey()
+	if err != nil {
+		return nil, failedVaultKey(err)
+	}
+
+	sealed, err := crypt.SealFor(key, plain, secretAAD(dataType, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	return sealed, nil
+}
+
+// openSecret расшифровывает данные пользователя ключом хранилища и разбирает их в data.
+func (s *Services) openSecret(dataType string, id int, sealed []byte, data any) error {
+	plain, err := s.openPlain(dataType, id, sealed)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(plain, data); err != nil {
+		return failedParseBody(err)
+	}
+
+	return nil
+}
+
+// openPlain расшифровывает данные пользователя ключом хранилища. Данные должны быть привязаны
+// к типу и ID записи: незашифрованные данные и данные другой записи не принимаются,
+// чтобы сервер не мог подменить их.
+func (s *Services) openPlain(dataType string, id int, sealed []byte) ([]byte, error) {
+	if !crypt.IsBound(sealed) {
+		return nil, ErrUnboundSecret
+	}
+
+	key, err := s.getVaultKey()
+	if err != nil {
+		return nil, failedVaultKey(err)
+	}
+
+	plain, err := crypt.OpenFor(key, sealed, secretAAD(dataType, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return plain, nil
+}
+
+// secretAAD возвращает дополнительные данные, к которым привязываются данные записи.
+func secretAAD(dataType string, id int) []byte {
+	return []byte("goph-keeper:" + dataType + ":" + strconv.Itoa(id))
+}
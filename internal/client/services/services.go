@@ -9,10 +9,15 @@ import (
 )
 
 const (
-	ContentTypeHeader   = "Content-Type"
-	AuthHeader          = "X-AUTH-TOKEN"
-	JSONContentType     = "application/json"
-	FormDataContentType = "multipart/form-data"
+	ContentTypeHeader      = "Content-Type"
+	AuthHeader             = "X-AUTH-TOKEN"
+	RangeHeader            = "Range"
+	IfRangeHeader          = "If-Range"
+	ContentRangeHeader     = "Content-Range"
+	ETagHeader             = "ETag"
+	JSONContentType        = "application/json"
+	FormDataContentType    = "multipart/form-data"
+	OctetStreamContentType = "application/octet-stream"
 )
 
 // Configurer интерфейс для конфигурации.
@@ -22,28 +27,55 @@ type Configurer interface {
 	GetRequestTimeout() int
 	GetToken() string
 	GetData() map[string]models.UserData
-	UpdateToken(token string) error
+	UpdateTokens(token, refreshToken string) error
+	GetSyncCursor() int64
 	UpdateData(data []models.UserData) error
+	ApplyDataChanges(changes models.UserDataChanges) error
 	AddData(data models.UserData) error
+	DeleteData(key string) error
 }
 
 // Requester интерфейс для HTTP запросов.
 type Requester interface {
 	Get(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error)
 	Post(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error)
+	Put(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error)
+	Patch(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error)
+	Delete(url string, opts ...requests.RequestOptionFunc) (*resty.Response, error)
 }
 
+// OfflineQueuer интерфейс для локальной очереди изменений, сделанных без связи с сервером.
+type OfflineQueuer interface {
+	KeyParams() (models.Vault, error)
+	SetKeyParams(params models.Vault) error
+	QueueChange(change models.PendingChange) error
+	PendingChanges() ([]models.PendingChange, error)
+	SetPendingChanges(changes []models.PendingChange) error
+	Clear() error
+}
+
+// PassphraseFunc тип функции для получения парольной фразы хранилища.
+type PassphraseFunc func() (string, error)
+
 // Services структура для работы с сервисами клиента.
 type Services struct {
 	cfg          Configurer
 	httpRequests Requester
+	queue        OfflineQueuer
+	passphrase   PassphraseFunc
+	uploadDir    string
+	vaultKey     []byte
 }
 
-// Init функция инициализации сервисов клиента.
-func Init(cfg Configurer, httpRequests Requester) *Services {
+// Init функция инициализации сервисов клиента. Если queue не задана, изменения без связи с сервером
+// не сохраняются.
+func Init(cfg Configurer, httpRequests Requester, queue OfflineQueuer, passphrase PassphraseFunc) *Services {
 	return &Services{
 		cfg:          cfg,
 		httpRequests: httpRequests,
+		queue:        queue,
+		passphrase:   passphrase,
+		uploadDir:    defaultUploadDir(),
 	}
 }
 
@@ -66,3 +98,18 @@ func failedCreateBody(err error) error {
 func failedDumpData(err error) error {
 	return fmt.Errorf("failed to dump data: %w", err)
 }
+
+// failedParseBody обертка ошибки разбора тела ответа.
+func failedParseBody(err error) error {
+	return fmt.Errorf("failed to parse body: %w", err)
+}
+
+// failedVaultKey обертка ошибки получения ключа хранилища.
+func failedVaultKey(err error) error {
+	return fmt.Errorf("failed to get vault key: %w", err)
+}
+
+// failedGenerateOTPCode обертка ошибки вычисления одноразового пароля.
+func failedGenerateOTPCode(err error) error {
+	return fmt.Errorf("failed to generate otp code: %w", err)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+// GetSessions сервис получения активных сессий пользователя.
+func (s *Services) GetSessions() ([]models.Session, error) {
+	const path = "/user/sessions"
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return nil, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, failedResponseStatus(resp.Status())
+	}
+
+	sessions := []models.Session{}
+	if err := json.Unmarshal(resp.Body(), &sessions); err != nil {
+		return nil, failedParseBody(err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession сервис отзыва сессии пользователя.
+func (s *Services) RevokeSession(id string) error {
+	const path = "/user/sessions/{id}"
+
+	resp, err := s.httpRequests.Delete(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return failedResponseStatus(resp.Status())
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSessions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+
+	t.Run("get sessions success", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/sessions", gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, []models.Session{{ID: "session", DeviceName: "laptop"}}), nil)
+
+		sessions, err := s.GetSessions()
+
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "laptop", sessions[0].DeviceName)
+	})
+
+	t.Run("get sessions failed when response status not 200", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/sessions", gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}, nil)
+
+		_, err := s.GetSessions()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("get sessions failed when request failed", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/sessions", gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.GetSessions()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+
+	tests := []struct {
+		name    string
+		resp    *resty.Response
+		err     error
+		wantErr bool
+		errText string
+	}{
+		{
+			name:    "revoke session success",
+			resp:    &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNoContent}},
+			err:     nil,
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:    "revoke session failed when session not found",
+			resp:    &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNotFound}},
+			err:     nil,
+			wantErr: true,
+			errText: "response status",
+		},
+		{
+			name:    "revoke session failed when request failed",
+			resp:    nil,
+			err:     errors.New("some error"),
+			wantErr: true,
+			errText: "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetToken().Times(1).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			r.EXPECT().Delete(url+"/user/sessions/{id}", gomock.Any(), gomock.Any()).Times(1).Return(test.resp, test.err)
+
+			err := s.RevokeSession("session")
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+// conflictSuffix суффикс метки копии записи, сохраненной при конфликте изменений.
+const conflictSuffix = " (конфликт)"
+
+var (
+	// ErrChangeQueued изменение не отправлено на сервер, а сохранено в локальную очередь изменений.
+	ErrChangeQueued = errors.New("no connection to server, change is saved locally and will be sent on next sync")
+	// ErrDataChanged запись уже изменил другой клиент.
+	ErrDataChanged = errors.New("data was changed on another device, run show --sync and repeat")
+)
+
+// secretPaths пути для изменения записей каждого типа, который можно изменять без связи с сервером.
+var secretPaths = map[string]string{
+	"password": passwordPath,
+	"card":     cardPath,
+	"text":     textPath,
+	"otp":      otpPath,
+}
+
+// secretAddPaths пути для добавления записей каждого типа, который можно изменять без связи с сервером.
+var secretAddPaths = map[string]string{
+	"password": passwordsPath,
+	"card":     cardsPath,
+	"text":     textsPath,
+	"otp":      otpsPath,
+}
+
+// SyncData сервис синхронизации данных с сервером. Сначала с сервера получаются изменения после курсора
+// синхронизации, затем отправляются изменения, сделанные без связи с сервером, и получается их результат.
+func (s *Services) SyncData() error {
+	if err := s.pullChanges(); err != nil {
+		return err
+	}
+
+	pushed, err := s.pushChanges()
+	if pushed {
+		if pullErr := s.pullChanges(); pullErr != nil && err == nil {
+			err = pullErr
+		}
+	}
+
+	return err
+}
+
+// pullChanges получает с сервера изменения данных пользователя после курсора синхронизации
+// и применяет их к кешу.
+func (s *Services) pullChanges() error {
+	const path = "/user/data"
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithQueryParams(map[string]string{"since": strconv.FormatInt(s.cfg.GetSyncCursor(), 10)}),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return failedResponseStatus(resp.Status())
+	}
+
+	changes := models.UserDataChanges{}
+	if err := json.Unmarshal(resp.Body(), &changes); err != nil {
+		return failedParseBody(err)
+	}
+
+	if err := s.cfg.ApplyDataChanges(changes); err != nil {
+		return fmt.Errorf("failed to update data: %w", err)
+	}
+
+	return nil
+}
+
+// pushChanges отправляет на сервер очередь изменений, сделанных без связи с сервером, в порядке их создания.
+// При ошибке неотправленные изменения остаются в очереди. Возвращает true, если данные на сервере изменились.
+func (s *Services) pushChanges() (bool, error) {
+	queue := s.pendingChanges()
+	if len(queue) == 0 {
+		return false, nil
+	}
+
+	data := maps.Clone(s.cfg.GetData())
+	pushed := map[int]bool{}
+
+	for i := range queue {
+		if err := s.pushChange(&queue[i], data, pushed); err != nil {
+			if err := s.queue.SetPendingChanges(queue[i:]); err != nil {
+				return i > 0, failedDumpData(err)
+			}
+
+			return i > 0, fmt.Errorf("failed to send offline changes: %w", err)
+		}
+	}
+
+	if err := s.queue.SetPendingChanges(nil); err != nil {
+		return true, failedDumpData(err)
+	}
+
+	return true, nil
+}
+
+// pendingChanges возвращает очередь изменений, сделанных без связи с сервером.
+func (s *Services) pendingChanges() []models.PendingChange {
+	if s.queue == nil {
+		return nil
+	}
+
+	queue, err := s.queue.PendingChanges()
+	if err != nil {
+		return nil
+	}
+
+	return queue
+}
+
+// pushChange отправляет на сервер одно изменение из очереди. data - данные пользователя с сервера,
+// pushed - записи, изменения которых уже отправлены при этой синхронизации.
+//
+// Если запись изменил другой клиент после того, как она была изменена без связи с сервером,
+// побеждает изменение, пришедшее на сервер последним: версия с сервера сохраняется копией
+// с пометкой о конфликте, а запись заменяется изменением из очереди. Если запись удалена на сервере,
+// измененные данные добавляются новой записью, а изменение только метки и описания отбрасывается.
+func (s *Services) pushChange(c *models.PendingChange, data map[string]models.UserData, pushed map[int]bool) error {
+	path, ok := secretPaths[c.Type]
+	if !ok {
+		return nil
+	}
+
+	id := strconv.Itoa(c.ID)
+	current, exists := data[id]
+
+	if c.Deleted {
+		if !exists {
+			return nil
+		}
+
+		return s.pushDelete(path, id)
+	}
+
+	if !exists {
+		return s.restoreChange(c)
+	}
+
+	if !pushed[c.ID] && c.Request.Version != 0 && c.Request.Version != current.Version {
+		if err := s.keepConflictCopy(path, c.ID, c.Type); err != nil {
+			return err
+		}
+
+		// Копия уже сохранена, при повторной отправке изменение не должно создать ее снова.
+		c.Request.Version = current.Version
+	}
+
+	req := c.Request
+	req.Version = current.Version
+
+	status, err := s.pushEdit(path, id, req)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case http.StatusOK:
+		pushed[c.ID] = true
+		if current.Version != 0 {
+			current.Version++
+		}
+		data[id] = current
+	case http.StatusNotFound:
+		delete(data, id)
+		return s.restoreChange(c)
+	case http.StatusConflict:
+		return ErrDataChanged
+	default:
+		return failedResponseStatus(strconv.Itoa(status))
+	}
+
+	return nil
+}
+
+// pushEdit отправляет изменение записи и возвращает статус ответа.
+func (s *Services) pushEdit(path, id string, req models.UpdateSecretRequest) (int, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, failedCreateBody(err)
+	}
+
+	resp, err := s.httpRequests.Patch(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+		requests.WithBody(body),
+	)
+	if err != nil {
+		return 0, failedRequest(err)
+	}
+
+	return resp.StatusCode(), nil
+}
+
+// pushDelete отправляет удаление записи. Запись, которой уже нет на сервере, считается удаленной.
+func (s *Services) pushDelete(path, id string) error {
+	resp, err := s.httpRequests.Delete(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusNoContent && resp.StatusCode() != http.StatusNotFound {
+		return failedResponseStatus(resp.Status())
+	}
+
+	return nil
+}
+
+// restoreChange добавляет новой записью данные изменения, запись которого удалена на сервере.
+func (s *Services) restoreChange(c *models.PendingChange) error {
+	if c.Request.Data == nil {
+		return nil
+	}
+
+	plain, err := s.openPlain(c.Type, c.ID, c.Request.Data)
+	if err != nil {
+		return err
+	}
+
+	return s.addPlain(secretAddPaths[c.Type], plain, models.UserData{
+		Type:        c.Type,
+		Mark:        c.Mark,
+		Description: c.Description,
+	})
+}
+
+// keepConflictCopy сохраняет текущую версию записи с сервера копией с пометкой о конфликте.
+func (s *Services) keepConflictCopy(path string, id int, dataType string) error {
+	secret, err := s.fetchSecret(path, strconv.Itoa(id))
+	if err != nil {
+		return err
+	}
+
+	plain, err := s.openPlain(dataType, id, secret.Data)
+	if err != nil {
+		return err
+	}
+
+	return s.addPlain(secretAddPaths[dataType], plain, models.UserData{
+		Type:        dataType,
+		Mark:        conflictMark(secret.Mark),
+		Description: secret.Description,
+	})
+}
+
+// conflictMark возвращает метку копии записи при конфликте, укладывающуюся в ограничение длины метки.
+func conflictMark(mark string) string {
+	runes := []rune(mark)
+	if limit := maxMarkSize - len([]rune(conflictSuffix)); len(runes) > limit {
+		runes = runes[:limit]
+	}
+
+	return string(runes) + conflictSuffix
+}
+
+// queueEdit сохраняет в очередь изменение записи, которое не удалось отправить на сервер,
+// и сразу применяет его к кешу. Без очереди изменений возвращается ошибка запроса.
+func (s *Services) queueEdit(d models.UserData, req models.UpdateSecretRequest, reqErr error) error {
+	if _, ok := secretPaths[d.Type]; !ok || s.queue == nil {
+		return failedRequest(reqErr)
+	}
+
+	d = patchUserData(d, req)
+
+	change := models.PendingChange{
+		Request:     req,
+		Type:        d.Type,
+		Mark:        d.Mark,
+		Description: d.Description,
+		ID:          d.ID,
+	}
+	if err := s.queue.QueueChange(change); err != nil {
+		return failedDumpData(err)
+	}
+
+	if err := s.cfg.AddData(d); err != nil {
+		return failedDumpData(err)
+	}
+
+	return ErrChangeQueued
+}
+
+// queueDelete сохраняет в очередь удаление записи, которое не удалось отправить на сервер,
+// и сразу удаляет запись из кеша. Без очереди изменений возвращается ошибка запроса.
+func (s *Services) queueDelete(d models.UserData, reqErr error) error {
+	if _, ok := secretPaths[d.Type]; !ok || s.queue == nil {
+		return failedRequest(reqErr)
+	}
+
+	change := models.PendingChange{
+		Type:        d.Type,
+		Mark:        d.Mark,
+		Description: d.Description,
+		ID:          d.ID,
+		Deleted:     true,
+	}
+	if err := s.queue.QueueChange(change); err != nil {
+		return failedDumpData(err)
+	}
+
+	if err := s.cfg.DeleteData(strconv.Itoa(d.ID)); err != nil {
+		return failedDumpData(err)
+	}
+
+	return ErrChangeQueued
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflictMark(t *testing.T) {
+	t.Run("short mark", func(t *testing.T) {
+		assert.Equal(t, "test (конфликт)", conflictMark("test"))
+	})
+
+	t.Run("long mark", func(t *testing.T) {
+		mark := conflictMark(strings.Repeat("м", maxMarkSize))
+
+		assert.Len(t, []rune(mark), maxMarkSize)
+		assert.True(t, strings.HasSuffix(mark, conflictSuffix))
+	})
+}
+
+func TestEditDataOffline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+
+	url := "http://some/api"
+	mark := "new"
+	data := map[string]models.UserData{"1": {ID: 1, Type: "password", Mark: "test", Version: 2}}
+	req := models.UpdatePasswordRequest{Mark: &mark}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("queue change without server", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+		queue.EXPECT().QueueChange(models.PendingChange{
+			Request: models.UpdateSecretRequest{Mark: &mark, Version: 2},
+			Type:    "password",
+			Mark:    "new",
+			ID:      1,
+		}).Times(1).Return(nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 1, Type: "password", Mark: "new", Version: 2}).Times(1).Return(nil)
+
+		err := s.EditPassword("1", req)
+
+		require.ErrorIs(t, err, ErrChangeQueued)
+	})
+
+	t.Run("request failed without offline queue", func(t *testing.T) {
+		s := Init(cfg, r, nil, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+
+		err := s.EditPassword("1", req)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+
+	t.Run("queue change failed", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+		queue.EXPECT().QueueChange(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		err := s.EditPassword("1", req)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to dump data")
+	})
+
+	t.Run("data changed by another client", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusConflict}}, nil)
+
+		err := s.EditPassword("1", req)
+
+		require.ErrorIs(t, err, ErrDataChanged)
+	})
+
+	t.Run("increase version after edit", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 1, Type: "password", Mark: "new", Version: 3}).Times(1).Return(nil)
+
+		require.NoError(t, s.EditPassword("1", req))
+	})
+}
+
+func TestDeleteDataOffline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+
+	url := "http://some/api"
+	data := map[string]models.UserData{
+		"1": {ID: 1, Type: "text", Mark: "test", Version: 2},
+		"2": {ID: 2, Type: "file", Mark: "test", Version: 1},
+	}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("queue delete without server", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Delete(url+"/user/texts/{id}", gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+		queue.EXPECT().QueueChange(models.PendingChange{Type: "text", Mark: "test", ID: 1, Deleted: true}).
+			Times(1).Return(nil)
+		cfg.EXPECT().DeleteData("1").Times(1).Return(nil)
+
+		err := s.DeleteText("1")
+
+		require.ErrorIs(t, err, ErrChangeQueued)
+	})
+
+	t.Run("files are not queued", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		cfg.EXPECT().GetData().Times(1).Return(data)
+		r.EXPECT().Delete(url+"/user/files/{id}", gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+
+		err := s.deleteData("/user/files/{id}", "2", "file not found")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
+
+func TestPushChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+
+	url := "http://some/api"
+	mark := "new"
+	okResp := &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}
+	edit := models.PendingChange{
+		Request: models.UpdateSecretRequest{Mark: &mark, Version: 2},
+		Type:    "password",
+		Mark:    mark,
+		ID:      1,
+	}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("empty queue", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		queue.EXPECT().PendingChanges().Times(1).Return(nil, nil)
+
+		pushed, err := s.pushChanges()
+
+		require.NoError(t, err)
+		assert.False(t, pushed)
+	})
+
+	t.Run("push edits of the same record", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		queue.EXPECT().PendingChanges().Times(1).Return([]models.PendingChange{edit, edit}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "password", Version: 2}})
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(2).Return(okResp, nil)
+		queue.EXPECT().SetPendingChanges(nil).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.NoError(t, err)
+		assert.True(t, pushed)
+	})
+
+	t.Run("keep server version on conflict", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+		s.vaultKey = testVaultKey
+
+		sealed := sealTestSecret(t, 1, models.EncryptPasswordData{Login: "test"})
+
+		queue.EXPECT().PendingChanges().Times(1).Return([]models.PendingChange{edit}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "password", Version: 3}})
+		r.EXPECT().Get(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.Secret{ID: 1, Data: sealed, Mark: "test"}), nil)
+		r.EXPECT().Post(url+"/user/data/ids", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5}), nil)
+		r.EXPECT().Post(url+"/user/passwords", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusCreated}}, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 5, Type: "password", Mark: "test (конфликт)", Version: 1}).
+			Times(1).Return(nil)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(okResp, nil)
+		queue.EXPECT().SetPendingChanges(nil).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.NoError(t, err)
+		assert.True(t, pushed)
+	})
+
+	t.Run("conflict copy failed when server data is not bound", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+		s.vaultKey = testVaultKey
+
+		queue.EXPECT().PendingChanges().Times(1).Return([]models.PendingChange{edit}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "password", Version: 3}})
+		r.EXPECT().Get(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.Secret{ID: 1, Data: []byte(`{"login":"test"}`)}), nil)
+		queue.EXPECT().SetPendingChanges([]models.PendingChange{edit}).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.ErrorIs(t, err, ErrUnboundSecret)
+		assert.False(t, pushed)
+	})
+
+	t.Run("restore data of deleted record", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+		s.vaultKey = testVaultKey
+
+		restored := edit
+		restored.Request.Data = sealTestSecret(t, 1, models.EncryptPasswordData{Login: "test"})
+
+		queue.EXPECT().PendingChanges().Times(1).Return([]models.PendingChange{restored, edit}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{})
+		r.EXPECT().Post(url+"/user/data/ids", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5}), nil)
+		r.EXPECT().Post(url+"/user/passwords", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusCreated}}, nil)
+		cfg.EXPECT().AddData(models.UserData{ID: 5, Type: "password", Mark: "new", Version: 1}).Times(1).Return(nil)
+		queue.EXPECT().SetPendingChanges(nil).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.NoError(t, err)
+		assert.True(t, pushed)
+	})
+
+	t.Run("push delete of deleted record", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		queue.EXPECT().PendingChanges().Times(1).
+			Return([]models.PendingChange{{ID: 1, Type: "text", Deleted: true}}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "text", Version: 1}})
+		r.EXPECT().Delete(url+"/user/texts/{id}", gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil)
+		queue.EXPECT().SetPendingChanges(nil).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.NoError(t, err)
+		assert.True(t, pushed)
+	})
+
+	t.Run("keep unsent changes", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		second := edit
+		second.ID = 2
+
+		queue.EXPECT().PendingChanges().Times(1).Return([]models.PendingChange{edit, second}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{
+			"1": {ID: 1, Type: "password", Version: 2},
+			"2": {ID: 2, Type: "password", Version: 2},
+		})
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(okResp, nil)
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(nil, errors.New("some error"))
+		queue.EXPECT().SetPendingChanges([]models.PendingChange{second}).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to send offline changes")
+		assert.True(t, pushed)
+	})
+
+	t.Run("keep change on version race", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		queue.EXPECT().PendingChanges().Times(1).Return([]models.PendingChange{edit}, nil)
+		cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "password", Version: 2}})
+		r.EXPECT().Patch(url+"/user/passwords/{id}", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusConflict}}, nil)
+		queue.EXPECT().SetPendingChanges([]models.PendingChange{edit}).Times(1).Return(nil)
+
+		pushed, err := s.pushChanges()
+
+		require.ErrorIs(t, err, ErrDataChanged)
+		assert.False(t, pushed)
+	})
+}
+
+func TestSyncDataWithPendingChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+	s := Init(cfg, r, queue, nil)
+
+	url := "http://some/api"
+	before := models.UserDataChanges{Changes: []models.UserData{{ID: 2, Type: "file", Version: 1}}, Cursor: 3}
+	after := models.UserDataChanges{Deleted: []int{1}, Cursor: 4}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("push queue and pull result", func(t *testing.T) {
+		gomock.InOrder(
+			cfg.EXPECT().GetSyncCursor().Times(1).Return(int64(2)),
+			r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+				Return(newJSONResponse(t, http.StatusOK, before), nil),
+			cfg.EXPECT().ApplyDataChanges(before).Times(1).Return(nil),
+			queue.EXPECT().PendingChanges().Times(1).
+				Return([]models.PendingChange{{ID: 1, Type: "text", Deleted: true}}, nil),
+			cfg.EXPECT().GetData().Times(1).Return(map[string]models.UserData{"1": {ID: 1, Type: "text"}}),
+			r.EXPECT().Delete(url+"/user/texts/{id}", gomock.Any(), gomock.Any()).Times(1).
+				Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil),
+			queue.EXPECT().SetPendingChanges(nil).Times(1).Return(nil),
+			cfg.EXPECT().GetSyncCursor().Times(1).Return(int64(3)),
+			r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+				Return(newJSONResponse(t, http.StatusOK, after), nil),
+			cfg.EXPECT().ApplyDataChanges(after).Times(1).Return(nil),
+		)
+
+		require.NoError(t, s.SyncData())
+	})
+}
+
+func TestLogoutUserWithPendingChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+	s := Init(cfg, r, queue, nil)
+
+	url := "http://some/api"
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("keep session when changes not sent", func(t *testing.T) {
+		queue.EXPECT().PendingChanges().Times(1).
+			Return([]models.PendingChange{{ID: 1, Type: "text", Deleted: true}}, nil)
+		cfg.EXPECT().GetSyncCursor().Times(1).Return(int64(0))
+		r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(nil, errors.New("some error"))
+		r.EXPECT().Post(gomock.Any(), gomock.Any()).Times(0)
+		queue.EXPECT().Clear().Times(0)
+
+		err := s.LogoutUser()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
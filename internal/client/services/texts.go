@@ -1,76 +1,74 @@
 package services
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
-
-	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 )
 
+const (
+	textsPath        = "/user/texts"
+	textPath         = "/user/texts/{id}"
+	textNotFoundText = "text id not found"
+)
+
 // AddText сервис добавления текста.
 func (s *Services) AddText(req models.AddTextRequest) error {
-	const path = "/user/texts"
+	data := models.EncryptTextData{Data: req.Data}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return failedCreateBody(err)
+	if err := validateTextData(data); err != nil {
+		return failedValidateFields(err)
 	}
-
-	addResp := models.AddResponse{}
-
-	resp, err := s.httpRequests.Post(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, JSONContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithBody(body),
-		requests.WithResult(&addResp),
-	)
-	if err != nil {
-		return failedRequest(err)
-	}
-	if resp.StatusCode() != http.StatusCreated {
-		return failedResponseStatus(resp.Status())
+	if err := validateMarkAndDescription(req.Mark, req.Description); err != nil {
+		return failedValidateFields(err)
 	}
 
-	d := models.UserData{
-		ID:          addResp.ID,
+	return s.addSecret(textsPath, data, models.UserData{
+		Type:        "text",
 		Mark:        req.Mark,
 		Description: req.Description,
-		Type:        "text",
-	}
-
-	if err := s.cfg.AddData(d); err != nil {
-		return failedDumpData(err)
-	}
-
-	return nil
+		Folder:      req.Folder,
+		Tags:        req.Tags,
+	})
 }
 
 // GetText сервис получения текста.
 func (s *Services) GetText(id string) (models.Text, error) {
-	const path = "/user/texts/{id}"
-
-	text := models.Text{}
+	data := models.EncryptTextData{}
 
-	if _, ok := s.cfg.GetData()[id]; !ok {
-		return text, errors.New("text id not found")
+	secret, err := s.getSecret(textPath, id, textNotFoundText, &data)
+	if err != nil {
+		return models.Text{}, err
 	}
 
-	resp, err := s.httpRequests.Get(
-		s.cfg.GetServerAPI()+path,
-		requests.WithHeader(ContentTypeHeader, JSONContentType),
-		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
-		requests.WithPathParams(map[string]string{"id": id}),
-		requests.WithResult(&text),
-	)
-	if err != nil {
-		return text, failedRequest(err)
+	return models.Text{
+		ID:          secret.ID,
+		Data:        data.Data,
+		Mark:        secret.Mark,
+		Description: secret.Description,
+	}, nil
+}
+
+// EditText сервис изменения текста.
+func (s *Services) EditText(id string, req models.UpdateTextRequest) error {
+	if err := validateMarkAndDescription(valueOrEmpty(req.Mark), valueOrEmpty(req.Description)); err != nil {
+		return failedValidateFields(err)
 	}
-	if resp.StatusCode() != http.StatusOK {
-		return text, failedResponseStatus(resp.Status())
+
+	var data any
+
+	if req.Data != nil {
+		newData := models.EncryptTextData{Data: *req.Data}
+
+		if err := validateTextData(newData); err != nil {
+			return failedValidateFields(err)
+		}
+
+		data = newData
 	}
 
-	return text, nil
+	return s.editSecret(textPath, id, textNotFoundText, data, req.Mark, req.Description)
+}
+
+// DeleteText сервис удаления текста.
+func (s *Services) DeleteText(id string) error {
+	return s.deleteData(textPath, id, textNotFoundText)
 }
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+const (
+	trashPath        = "/user/trash"
+	trashRestorePath = "/user/trash/{id}/restore"
+)
+
+// GetTrash сервис получения данных пользователя в корзине.
+func (s *Services) GetTrash() ([]models.TrashItem, error) {
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+trashPath,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return nil, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, failedResponseStatus(resp.Status())
+	}
+
+	items := []models.TrashItem{}
+	if err := json.Unmarshal(resp.Body(), &items); err != nil {
+		return nil, failedParseBody(err)
+	}
+
+	return items, nil
+}
+
+// RestoreTrash сервис восстановления данных пользователя из корзины,
+// после восстановления данные синхронизируются с сервером.
+func (s *Services) RestoreTrash(id string) error {
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+trashRestorePath,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"id": id}),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return failedResponseStatus(resp.Status())
+	}
+
+	return s.pullChanges()
+}
+
+// EmptyTrash сервис окончательного удаления всех данных пользователя из корзины.
+func (s *Services) EmptyTrash() error {
+	resp, err := s.httpRequests.Delete(
+		s.cfg.GetServerAPI()+trashPath,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return failedResponseStatus(resp.Status())
+	}
+
+	return nil
+}
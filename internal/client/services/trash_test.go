@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+
+	t.Run("get trash success", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/trash", gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, []models.TrashItem{{ID: 1, Type: "card", Mark: "bank"}}), nil)
+
+		items, err := s.GetTrash()
+
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "bank", items[0].Mark)
+	})
+
+	t.Run("get trash failed when response status not 200", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/trash", gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}, nil)
+
+		_, err := s.GetTrash()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("get trash failed when request failed", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/trash", gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.GetTrash()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
+
+func TestRestoreTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	changes := models.UserDataChanges{Changes: []models.UserData{{ID: 1, Type: "card", Version: 2}}, Cursor: 5}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	tests := []struct {
+		name      string
+		resp      *resty.Response
+		err       error
+		pullTimes int
+		wantErr   bool
+		errText   string
+	}{
+		{
+			name:      "restore success",
+			resp:      &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}},
+			pullTimes: 1,
+		},
+		{
+			name:    "restore failed when data not in trash",
+			resp:    &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNotFound}},
+			wantErr: true,
+			errText: "response status",
+		},
+		{
+			name:    "restore failed when request failed",
+			err:     errors.New("some error"),
+			wantErr: true,
+			errText: "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r.EXPECT().Post(url+"/user/trash/{id}/restore", gomock.Any(), gomock.Any()).Times(1).
+				Return(test.resp, test.err)
+			cfg.EXPECT().GetSyncCursor().Times(test.pullTimes).Return(int64(4))
+			r.EXPECT().Get(url+"/user/data", gomock.Any(), gomock.Any(), gomock.Any()).Times(test.pullTimes).
+				Return(newJSONResponse(t, http.StatusOK, changes), nil)
+			cfg.EXPECT().ApplyDataChanges(changes).Times(test.pullTimes).Return(nil)
+
+			err := s.RestoreTrash("1")
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+
+	tests := []struct {
+		name    string
+		resp    *resty.Response
+		err     error
+		wantErr bool
+		errText string
+	}{
+		{
+			name: "empty trash success",
+			resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusNoContent}},
+		},
+		{
+			name:    "empty trash failed when response status not 204",
+			resp:    &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusInternalServerError}},
+			wantErr: true,
+			errText: "response status",
+		},
+		{
+			name:    "empty trash failed when request failed",
+			err:     errors.New("some error"),
+			wantErr: true,
+			errText: "failed request",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetToken().Times(1).Return("token")
+			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			r.EXPECT().Delete(url+"/user/trash", gomock.Any()).Times(1).Return(test.resp, test.err)
+
+			err := s.EmptyTrash()
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
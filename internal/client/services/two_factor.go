@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+const twoFactorLoginPath = "/user/token/2fa"
+
+// ErrTwoFactorRequired ошибка отсутствия одноразового пароля при включенной двухфакторной аутентификации.
+var ErrTwoFactorRequired = errors.New("one-time code is required")
+
+// EnrollTwoFactor сервис получения секрета для приложения-аутентификатора.
+func (s *Services) EnrollTwoFactor() (models.EnrollTwoFactorResponse, error) {
+	const path = "/user/2fa/enroll"
+
+	enroll := models.EnrollTwoFactorResponse{}
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return enroll, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return enroll, failedResponseStatus(resp.Status())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &enroll); err != nil {
+		return enroll, failedParseBody(err)
+	}
+
+	return enroll, nil
+}
+
+// VerifyTwoFactor сервис подтверждения двухфакторной аутентификации, возвращает коды восстановления.
+func (s *Services) VerifyTwoFactor(code string) ([]string, error) {
+	const path = "/user/2fa/verify"
+
+	body, err := json.Marshal(models.VerifyTwoFactorRequest{Code: code})
+	if err != nil {
+		return nil, failedCreateBody(err)
+	}
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithBody(body),
+	)
+	if err != nil {
+		return nil, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, failedResponseStatus(resp.Status())
+	}
+
+	verify := models.VerifyTwoFactorResponse{}
+	if err := json.Unmarshal(resp.Body(), &verify); err != nil {
+		return nil, failedParseBody(err)
+	}
+
+	return verify.RecoveryCodes, nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginUserTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	req := models.CreateUserTokenRequest{Login: "user", Password: "pass", DeviceName: "laptop"}
+	challenge := newJSONResponse(t, http.StatusOK, models.CreateUserTokenResponse{TwoFactorToken: "2fa token"})
+	tokens := newJSONResponse(t, http.StatusOK, models.CreateUserTokenResponse{AuthToken: "token", RefreshToken: "refresh"})
+	code := func() (string, error) { return "123456", nil }
+
+	t.Run("login with one-time code", func(t *testing.T) {
+		cfg.EXPECT().GetServerAPI().Times(2).Return(url)
+		r.EXPECT().Post(url+"/user/token", gomock.Any(), gomock.Any()).Times(1).Return(challenge, nil)
+		r.EXPECT().Post(url+"/user/token/2fa", gomock.Any(), gomock.Any()).Times(1).Return(tokens, nil)
+		cfg.EXPECT().UpdateTokens("token", "refresh").Times(1).Return(nil)
+		cfg.EXPECT().UpdateData([]models.UserData{}).Times(1).Return(nil)
+
+		err := s.LoginUser(req, code)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("login failed when code not provided", func(t *testing.T) {
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Post(url+"/user/token", gomock.Any(), gomock.Any()).Times(1).Return(challenge, nil)
+		cfg.EXPECT().UpdateTokens(gomock.Any(), gomock.Any()).Times(0)
+
+		err := s.LoginUser(req, nil)
+
+		require.ErrorIs(t, err, ErrTwoFactorRequired)
+	})
+
+	t.Run("login failed when code rejected", func(t *testing.T) {
+		cfg.EXPECT().GetServerAPI().Times(2).Return(url)
+		r.EXPECT().Post(url+"/user/token", gomock.Any(), gomock.Any()).Times(1).Return(challenge, nil)
+		r.EXPECT().Post(url+"/user/token/2fa", gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}, nil)
+		cfg.EXPECT().UpdateTokens(gomock.Any(), gomock.Any()).Times(0)
+
+		err := s.LoginUser(req, code)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("login failed when reading code failed", func(t *testing.T) {
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Post(url+"/user/token", gomock.Any(), gomock.Any()).Times(1).Return(challenge, nil)
+
+		err := s.LoginUser(req, func() (string, error) { return "", errors.New("some error") })
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get one-time code")
+	})
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	enroll := models.EnrollTwoFactorResponse{Secret: "SECRET", URI: "otpauth://totp/GophKeeper:user"}
+
+	type postResponse struct {
+		resp *resty.Response
+		err  error
+	}
+
+	tests := []struct {
+		name         string
+		postResponse postResponse
+		want         models.EnrollTwoFactorResponse
+		wantErr      string
+	}{
+		{
+			name:         "enroll success",
+			postResponse: postResponse{resp: newJSONResponse(t, http.StatusOK, enroll)},
+			want:         enroll,
+		},
+		{
+			name:         "enroll failed when request failed",
+			postResponse: postResponse{err: errors.New("some error")},
+			wantErr:      "failed request",
+		},
+		{
+			name: "enroll failed when already enabled",
+			postResponse: postResponse{
+				resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusConflict}},
+			},
+			wantErr: "response status",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			cfg.EXPECT().GetToken().Times(1).Return("token")
+			r.EXPECT().Post(url+"/user/2fa/enroll", gomock.Any()).Times(1).
+				Return(test.postResponse.resp, test.postResponse.err)
+
+			got, err := s.EnrollTwoFactor()
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+	codes := []string{"AAAA-BBBB-CCCC-DDDD"}
+
+	type postResponse struct {
+		resp *resty.Response
+		err  error
+	}
+
+	tests := []struct {
+		name         string
+		postResponse postResponse
+		want         []string
+		wantErr      string
+	}{
+		{
+			name:         "verify success",
+			postResponse: postResponse{resp: newJSONResponse(t, http.StatusOK, models.VerifyTwoFactorResponse{RecoveryCodes: codes})},
+			want:         codes,
+		},
+		{
+			name:         "verify failed when request failed",
+			postResponse: postResponse{err: errors.New("some error")},
+			wantErr:      "failed request",
+		},
+		{
+			name: "verify failed when code invalid",
+			postResponse: postResponse{
+				resp: &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusBadRequest}},
+			},
+			wantErr: "response status",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+			cfg.EXPECT().GetToken().Times(1).Return("token")
+			r.EXPECT().Post(url+"/user/2fa/verify", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+				Return(test.postResponse.resp, test.postResponse.err)
+
+			got, err := s.VerifyTwoFactor("123456")
+
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
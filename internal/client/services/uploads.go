@@ -0,0 +1,325 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+const (
+	uploadsPath = "/user/files/uploads"
+	uploadPath  = "/user/files/uploads/{uploadID}"
+)
+
+// uploadState локальное состояние загрузки файла по частям, по которому загрузка продолжается
+// после обрыва связи. Зашифрованная копия файла хранится рядом, чтобы части совпадали с уже загруженными.
+type uploadState struct {
+	ModTime     time.Time `json:"mod_time"`
+	UploadID    string    `json:"upload_id"`
+	Description string    `json:"description"`
+	Folder      string    `json:"folder,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	SealedPath  string    `json:"sealed_path"`
+	Size        int64     `json:"size"`
+	PartSize    int64     `json:"part_size"`
+}
+
+// defaultUploadDir возвращает каталог для состояния незавершенных загрузок.
+func defaultUploadDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "goph-keeper", "uploads")
+}
+
+// uploadFile загружает файл на сервер по частям с меткой, описанием, папкой и метками из req
+// и возвращает идентификатор сохраненного файла.
+func (s *Services) uploadFile(filePath string, req models.AddFileRequest) (int, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if err := os.MkdirAll(s.uploadDir, 0o700); err != nil {
+		return 0, fmt.Errorf("failed to create upload dir: %w", err)
+	}
+	statePath := s.uploadStatePath(filePath, req.Mark)
+
+	state, uploaded, err := s.resumeUpload(statePath, info, req)
+	if err != nil {
+		return 0, err
+	}
+	if state == nil {
+		state, err = s.startUpload(filePath, statePath, info, req)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.uploadParts(state, uploaded); err != nil {
+		return 0, fmt.Errorf("%w (run the command again to resume the upload)", err)
+	}
+
+	id, err := s.completeUpload(state.UploadID)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = os.Remove(state.SealedPath)
+	_ = os.Remove(statePath)
+
+	return id, nil
+}
+
+// uploadStatePath возвращает путь к файлу состояния загрузки файла filePath с меткой mark.
+func (s *Services) uploadStatePath(filePath, mark string) string {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		filePath = abs
+	}
+	sum := sha256.Sum256([]byte(filePath + "\n" + mark))
+
+	return filepath.Join(s.uploadDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// resumeUpload читает состояние прерванной загрузки и запрашивает у сервера уже загруженные части.
+// Возвращает nil, если продолжать нечего: состояния нет, файл, описание, папка или метки изменились
+// или сервер не знает загрузку.
+func (s *Services) resumeUpload(
+	statePath string,
+	info os.FileInfo,
+	req models.AddFileRequest,
+) (*uploadState, map[int]int64, error) {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return nil, nil, nil //nolint:nilerr // состояния нет, загрузка начинается заново
+	}
+
+	var state uploadState
+	if err := json.Unmarshal(data, &state); err != nil ||
+		state.Size != info.Size() || !state.ModTime.Equal(info.ModTime()) || state.Description != req.Description ||
+		state.Folder != req.Folder || !slices.Equal(state.Tags, req.Tags) {
+		s.discardUpload(statePath, &state)
+		return nil, nil, nil
+	}
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+uploadPath,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"uploadID": state.UploadID}),
+	)
+	if err != nil {
+		return nil, nil, failedRequest(err)
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		s.discardUpload(statePath, &state)
+		return nil, nil, nil
+	default:
+		return nil, nil, failedResponseStatus(resp.Status())
+	}
+
+	status := models.FileUploadStatus{}
+	if err := json.Unmarshal(resp.Body(), &status); err != nil {
+		return nil, nil, failedParseBody(err)
+	}
+
+	uploaded := make(map[int]int64, len(status.Parts))
+	for _, p := range status.Parts {
+		uploaded[p.Number] = p.Size
+	}
+
+	return &state, uploaded, nil
+}
+
+// startUpload резервирует ID файла, шифрует файл с привязкой к нему и начинает новую загрузку на сервере.
+func (s *Services) startUpload(
+	filePath, statePath string,
+	info os.FileInfo,
+	req models.AddFileRequest,
+) (*uploadState, error) {
+	id, err := s.reserveDataID()
+	if err != nil {
+		return nil, err
+	}
+
+	sealedPath := statePath[:len(statePath)-len(filepath.Ext(statePath))] + ".sealed"
+	if err := s.sealFileTo(filePath, sealedPath, id); err != nil {
+		return nil, err
+	}
+
+	sealedInfo, err := os.Stat(sealedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted file: %w", err)
+	}
+
+	body, err := json.Marshal(models.InitFileUploadRequest{
+		ID:          id,
+		FileName:    filepath.Base(filePath),
+		Mark:        req.Mark,
+		Description: req.Description,
+		Folder:      req.Folder,
+		Tags:        req.Tags,
+		FileSize:    sealedInfo.Size(),
+	})
+	if err != nil {
+		return nil, failedCreateBody(err)
+	}
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+uploadsPath,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithBody(body),
+	)
+	if err != nil {
+		return nil, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusCreated {
+		return nil, failedResponseStatus(resp.Status())
+	}
+
+	status := models.FileUploadStatus{}
+	if err := json.Unmarshal(resp.Body(), &status); err != nil {
+		return nil, failedParseBody(err)
+	}
+
+	state := &uploadState{
+		ModTime:     info.ModTime(),
+		UploadID:    status.UploadID,
+		Description: req.Description,
+		Folder:      req.Folder,
+		Tags:        req.Tags,
+		SealedPath:  sealedPath,
+		Size:        info.Size(),
+		PartSize:    status.PartSize,
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode upload state: %w", err)
+	}
+	if err := os.WriteFile(statePath, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write upload state: %w", err)
+	}
+
+	return state, nil
+}
+
+// uploadParts загружает части зашифрованного файла, которых еще нет на сервере.
+// Каждая часть повторяется при ошибке до количества попыток из конфигурации.
+func (s *Services) uploadParts(state *uploadState, uploaded map[int]int64) error {
+	f, err := os.Open(state.SealedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted file: %w", err)
+	}
+
+	if state.PartSize <= 0 {
+		return errors.New("invalid upload part size")
+	}
+
+	buf := make([]byte, state.PartSize)
+	for offset, number := int64(0), 1; offset < info.Size(); offset, number = offset+state.PartSize, number+1 {
+		size := min(state.PartSize, info.Size()-offset)
+		if uploaded[number] == size {
+			continue
+		}
+
+		part := buf[:size]
+		if _, err := f.ReadAt(part, offset); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read encrypted file: %w", err)
+		}
+
+		if err := s.uploadPart(state.UploadID, number, part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uploadPart загружает одну часть файла с повторными попытками.
+func (s *Services) uploadPart(uploadID string, number int, part []byte) error {
+	const path = "/user/files/uploads/{uploadID}/parts/{partNumber}"
+
+	retries := max(s.cfg.GetRequestRetry(), 0)
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		resp, rErr := s.httpRequests.Put(
+			s.cfg.GetServerAPI()+path,
+			requests.WithHeader(ContentTypeHeader, OctetStreamContentType),
+			requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+			requests.WithPathParams(map[string]string{"uploadID": uploadID, "partNumber": strconv.Itoa(number)}),
+			requests.WithBody(part),
+		)
+
+		switch {
+		case rErr != nil:
+			err = failedRequest(rErr)
+		case resp.StatusCode() == http.StatusOK:
+			return nil
+		case resp.StatusCode() < http.StatusInternalServerError:
+			return fmt.Errorf("failed to upload part %d: %w", number, failedResponseStatus(resp.Status()))
+		default:
+			err = failedResponseStatus(resp.Status())
+		}
+	}
+
+	return fmt.Errorf("failed to upload part %d: %w", number, err)
+}
+
+// completeUpload завершает загрузку файла и возвращает идентификатор сохраненного файла.
+func (s *Services) completeUpload(uploadID string) (int, error) {
+	const path = "/user/files/uploads/{uploadID}/complete"
+
+	resp, err := s.httpRequests.Post(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithPathParams(map[string]string{"uploadID": uploadID}),
+	)
+	if err != nil {
+		return 0, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusCreated {
+		return 0, failedResponseStatus(resp.Status())
+	}
+
+	addResp := models.AddResponse{}
+	if err := json.Unmarshal(resp.Body(), &addResp); err != nil {
+		return 0, failedParseBody(err)
+	}
+
+	return addResp.ID, nil
+}
+
+// discardUpload удаляет локальное состояние загрузки, которую нельзя продолжить.
+func (s *Services) discardUpload(statePath string, state *uploadState) {
+	if state.SealedPath != "" {
+		_ = os.Remove(state.SealedPath)
+	}
+	_ = os.Remove(statePath)
+}
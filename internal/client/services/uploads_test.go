@@ -0,0 +1,294 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPartSize = 32
+
+type uploadMocks struct {
+	cfg *mocks.MockConfigurer
+	r   *mocks.MockRequester
+}
+
+func newUploadTest(t *testing.T, retries int) (*Services, uploadMocks) {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	m := uploadMocks{cfg: mocks.NewMockConfigurer(mockCtrl), r: mocks.NewMockRequester(mockCtrl)}
+	m.cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	m.cfg.EXPECT().GetServerAPI().AnyTimes().Return("http://some/api")
+	m.cfg.EXPECT().GetRequestRetry().AnyTimes().Return(retries)
+
+	s := Init(m.cfg, m.r, nil, nil)
+	s.vaultKey = testVaultKey
+	s.uploadDir = t.TempDir()
+
+	return s, m
+}
+
+func (m uploadMocks) expectInit(t *testing.T, times int) {
+	t.Helper()
+
+	m.expectReserve(t, times)
+	m.r.EXPECT().Post("http://some/api/user/files/uploads", gomock.Any()).Times(times).
+		Return(newJSONResponse(t, http.StatusCreated, models.FileUploadStatus{
+			UploadID: "upload", PartSize: testPartSize, Parts: []models.FileUploadPart{},
+		}), nil)
+}
+
+func (m uploadMocks) expectReserve(t *testing.T, times int) {
+	t.Helper()
+
+	m.r.EXPECT().Post("http://some/api/user/data/ids", gomock.Any(), gomock.Any()).Times(times).
+		Return(newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5}), nil)
+}
+
+func (m uploadMocks) expectComplete(t *testing.T) {
+	t.Helper()
+
+	m.r.EXPECT().Post("http://some/api/user/files/uploads/{uploadID}/complete", gomock.Any()).Times(1).
+		Return(newJSONResponse(t, http.StatusCreated, models.AddResponse{ID: 5}), nil)
+	m.cfg.EXPECT().AddData(models.UserData{ID: 5, Mark: "test", Description: "desc", Type: "file"}).
+		Times(1).Return(nil)
+}
+
+func (m uploadMocks) expectParts(times int, responses ...*resty.Response) {
+	call := 0
+	m.r.EXPECT().Put("http://some/api/user/files/uploads/{uploadID}/parts/{partNumber}", gomock.Any()).
+		Times(times).
+		DoAndReturn(func(string, ...requests.RequestOptionFunc) (*resty.Response, error) {
+			call++
+			if call <= len(responses) {
+				if responses[call-1] == nil {
+					return nil, errors.New("connection reset")
+				}
+				return responses[call-1], nil
+			}
+			return newStatusResponse(http.StatusOK), nil
+		})
+}
+
+func newStatusResponse(status int) *resty.Response {
+	return &resty.Response{RawResponse: &http.Response{StatusCode: status}}
+}
+
+// newUploadFile создает файл размером size байт и возвращает количество частей его зашифрованной копии.
+func newUploadFile(t *testing.T, size int) (string, int) {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	require.NoError(t, os.WriteFile(filePath, make([]byte, size), 0o600))
+
+	return filePath, sealedParts(t, filePath)
+}
+
+// sealedParts возвращает количество частей зашифрованной копии файла.
+func sealedParts(t *testing.T, filePath string) int {
+	t.Helper()
+
+	sealedPath := filepath.Join(t.TempDir(), "sealed")
+	s := Init(nil, nil, nil, nil)
+	s.vaultKey = testVaultKey
+	require.NoError(t, s.sealFileTo(filePath, sealedPath, 1))
+	info, err := os.Stat(sealedPath)
+	require.NoError(t, err)
+
+	return int((info.Size() + testPartSize - 1) / testPartSize)
+}
+
+func uploadDirEntries(t *testing.T, s *Services) []os.DirEntry {
+	t.Helper()
+
+	entries, err := os.ReadDir(s.uploadDir)
+	require.NoError(t, err)
+
+	return entries
+}
+
+func TestAddFile(t *testing.T) {
+	filePath, parts := newUploadFile(t, 100)
+
+	t.Run("upload file by parts", func(t *testing.T) {
+		s, m := newUploadTest(t, 0)
+		m.expectInit(t, 1)
+		m.expectParts(parts)
+		m.expectComplete(t)
+
+		require.NoError(t, s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+		assert.Empty(t, uploadDirEntries(t, s))
+	})
+
+	t.Run("retry failed part", func(t *testing.T) {
+		s, m := newUploadTest(t, 2)
+		m.expectInit(t, 1)
+		m.expectParts(parts+2, nil, newStatusResponse(http.StatusBadGateway))
+		m.expectComplete(t)
+
+		require.NoError(t, s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+	})
+
+	t.Run("do not retry rejected part", func(t *testing.T) {
+		s, m := newUploadTest(t, 2)
+		m.expectInit(t, 1)
+		m.expectParts(1, newStatusResponse(http.StatusBadRequest))
+
+		err := s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to upload part 1")
+	})
+
+	t.Run("init upload failed", func(t *testing.T) {
+		s, m := newUploadTest(t, 0)
+		m.expectReserve(t, 1)
+		m.r.EXPECT().Post("http://some/api/user/files/uploads", gomock.Any()).Times(1).
+			Return(newStatusResponse(http.StatusBadRequest), nil)
+
+		err := s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("complete upload failed", func(t *testing.T) {
+		s, m := newUploadTest(t, 0)
+		m.expectInit(t, 1)
+		m.expectParts(parts)
+		m.r.EXPECT().Post("http://some/api/user/files/uploads/{uploadID}/complete", gomock.Any()).Times(1).
+			Return(newStatusResponse(http.StatusConflict), nil)
+
+		err := s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+}
+
+func TestAddFileLabels(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var initReq models.InitFileUploadRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /user/data/ids", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":5}`))
+	})
+	mux.HandleFunc("POST /user/files/uploads", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&initReq))
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"upload_id":"upload","part_size":%d,"parts":[]}`, testPartSize)
+	})
+	mux.HandleFunc("PUT /user/files/uploads/upload/parts/{partNumber}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /user/files/uploads/upload/complete", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":5}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	s := Init(cfg, requests.NewRequests(&config.Config{RequestTimeout: 5}), nil, nil)
+	s.vaultKey = testVaultKey
+	s.uploadDir = t.TempDir()
+	filePath, _ := newUploadFile(t, 100)
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(srv.URL)
+	cfg.EXPECT().GetRequestRetry().AnyTimes().Return(0)
+	cfg.EXPECT().AddData(models.UserData{
+		ID: 5, Mark: "test", Description: "desc", Folder: "work/docs", Tags: []string{"scan"}, Type: "file",
+	}).Times(1).Return(nil)
+
+	req := models.AddFileRequest{Mark: "test", Description: "desc", Folder: "work/docs", Tags: []string{"scan"}}
+	require.NoError(t, s.AddFile(filePath, req))
+
+	assert.Equal(t, 5, initReq.ID)
+	assert.Equal(t, "work/docs", initReq.Folder)
+	assert.Equal(t, []string{"scan"}, initReq.Tags)
+}
+func TestAddFileResume(t *testing.T) {
+	filePath, parts := newUploadFile(t, 100)
+
+	t.Run("resume interrupted upload", func(t *testing.T) {
+		s, m := newUploadTest(t, 0)
+
+		// Первый запуск: вторая часть не загружается.
+		m.expectInit(t, 1)
+		m.expectParts(2, newStatusResponse(http.StatusOK), nil)
+
+		err := s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "run the command again to resume the upload")
+		assert.Len(t, uploadDirEntries(t, s), 2)
+
+		// Второй запуск: сервер уже хранит первую часть, загружаются только остальные.
+		m.r.EXPECT().Get("http://some/api/user/files/uploads/{uploadID}", gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.FileUploadStatus{
+				UploadID: "upload",
+				PartSize: testPartSize,
+				Parts:    []models.FileUploadPart{{Number: 1, Size: testPartSize, ETag: "etag"}},
+			}), nil)
+		m.expectParts(parts - 1)
+		m.expectComplete(t)
+
+		require.NoError(t, s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+		assert.Empty(t, uploadDirEntries(t, s))
+	})
+
+	t.Run("restart upload unknown to server", func(t *testing.T) {
+		s, m := newUploadTest(t, 0)
+
+		m.expectInit(t, 1)
+		m.expectParts(1, nil)
+		require.Error(t, s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+
+		m.r.EXPECT().Get("http://some/api/user/files/uploads/{uploadID}", gomock.Any()).Times(1).
+			Return(newStatusResponse(http.StatusNotFound), nil)
+		m.expectInit(t, 1)
+		m.expectParts(parts)
+		m.expectComplete(t)
+
+		require.NoError(t, s.AddFile(filePath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+	})
+
+	t.Run("restart upload when file changed", func(t *testing.T) {
+		s, m := newUploadTest(t, 0)
+		changedPath, _ := newUploadFile(t, 100)
+
+		m.expectInit(t, 1)
+		m.expectParts(1, nil)
+		require.Error(t, s.AddFile(changedPath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+
+		require.NoError(t, os.WriteFile(changedPath, make([]byte, 10), 0o600))
+		m.expectInit(t, 1)
+		m.expectParts(sealedParts(t, changedPath))
+		m.expectComplete(t)
+
+		require.NoError(t, s.AddFile(changedPath, models.AddFileRequest{Mark: "test", Description: "desc"}))
+	})
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+// GetUsage сервис получения использования хранилища пользователем и его ограничений.
+func (s *Services) GetUsage() (models.UserUsage, error) {
+	const path = "/user/usage"
+
+	var usage models.UserUsage
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+path,
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return usage, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return usage, failedResponseStatus(resp.Status())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &usage); err != nil {
+		return usage, failedParseBody(err)
+	}
+
+	return usage, nil
+}
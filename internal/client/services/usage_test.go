@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
+
+	t.Run("get usage success", func(t *testing.T) {
+		usage := models.UserUsage{UsedBytes: 100, MaxBytes: 1000, Items: 2, MaxItems: 10}
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/usage", gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, usage), nil)
+
+		resp, err := s.GetUsage()
+
+		require.NoError(t, err)
+		assert.Equal(t, usage, resp)
+	})
+
+	t.Run("get usage failed when response status not 200", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/usage", gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}, nil)
+
+		_, err := s.GetUsage()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+
+	t.Run("get usage failed when request failed", func(t *testing.T) {
+		cfg.EXPECT().GetToken().Times(1).Return("token")
+		cfg.EXPECT().GetServerAPI().Times(1).Return(url)
+		r.EXPECT().Get(url+"/user/usage", gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.GetUsage()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
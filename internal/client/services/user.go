@@ -34,38 +34,102 @@ func (s *Services) RegisterUser(req models.RegisterUserRequest) error {
 }
 
 // LoginUser сервис аутентификации пользователя.
-func (s *Services) LoginUser(req models.CreateUserTokenRequest) error {
+// Если у пользователя включена двухфакторная аутентификация, одноразовый пароль запрашивается через code.
+func (s *Services) LoginUser(req models.CreateUserTokenRequest, code func() (string, error)) error {
 	const path = "/user/token"
 
-	body, err := json.Marshal(req)
+	userToken, err := s.requestToken(path, req)
 	if err != nil {
-		return failedCreateBody(err)
+		return err
+	}
+
+	if userToken.TwoFactorToken != "" {
+		if code == nil {
+			return ErrTwoFactorRequired
+		}
+
+		otp, err := code()
+		if err != nil {
+			return fmt.Errorf("failed to get one-time code: %w", err)
+		}
+
+		userToken, err = s.requestToken(twoFactorLoginPath, models.LoginTwoFactorRequest{
+			TwoFactorToken: userToken.TwoFactorToken,
+			Code:           otp,
+			DeviceName:     req.DeviceName,
+			ClientVersion:  req.ClientVersion,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.cfg.UpdateTokens(userToken.AuthToken, userToken.RefreshToken); err != nil {
+		return fmt.Errorf("failed to update auth token: %w", err)
+	}
+
+	// Кеш мог остаться от другого пользователя, поэтому первая синхронизация получает все данные.
+	if err := s.cfg.UpdateData([]models.UserData{}); err != nil {
+		return fmt.Errorf("failed to update data: %w", err)
 	}
 
+	return nil
+}
+
+// requestToken запрос токенов доступа.
+func (s *Services) requestToken(path string, req any) (models.CreateUserTokenResponse, error) {
 	userToken := models.CreateUserTokenResponse{}
 
+	body, err := json.Marshal(req)
+	if err != nil {
+		return userToken, failedCreateBody(err)
+	}
+
 	resp, err := s.httpRequests.Post(
 		s.cfg.GetServerAPI()+path,
 		requests.WithHeader(ContentTypeHeader, JSONContentType),
 		requests.WithBody(body),
-		requests.WithResult(&userToken),
 	)
 	if err != nil {
-		return failedRequest(err)
+		return userToken, failedRequest(err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return failedResponseStatus(resp.Status())
+		return userToken, failedResponseStatus(resp.Status())
 	}
 
-	if err := s.cfg.UpdateToken(userToken.AuthToken); err != nil {
-		return fmt.Errorf("failed to update auth token: %w", err)
+	if err := json.Unmarshal(resp.Body(), &userToken); err != nil {
+		return userToken, failedParseBody(err)
 	}
-	return nil
+
+	return userToken, nil
 }
 
-// LogoutUser сервис удаления данных пользователя.
+// LogoutUser сервис завершения сессии и удаления данных пользователя.
 func (s *Services) LogoutUser() error {
-	if err := s.cfg.UpdateToken(""); err != nil {
+	const path = "/user/logout"
+
+	if token := s.cfg.GetToken(); token != "" {
+		// Изменения, сделанные без связи с сервером, удалятся вместе с очередью изменений.
+		if len(s.pendingChanges()) > 0 {
+			if err := s.SyncData(); err != nil {
+				return err
+			}
+		}
+
+		resp, err := s.httpRequests.Post(
+			s.cfg.GetServerAPI()+path,
+			requests.WithHeader(AuthHeader, token),
+		)
+		if err != nil {
+			return failedRequest(err)
+		}
+		// 401 означает, что сессия уже завершена на сервере.
+		if resp.StatusCode() != http.StatusNoContent && resp.StatusCode() != http.StatusUnauthorized {
+			return failedResponseStatus(resp.Status())
+		}
+	}
+
+	if err := s.cfg.UpdateTokens("", ""); err != nil {
 		return fmt.Errorf("failed to update auth token: %w", err)
 	}
 
@@ -73,5 +137,12 @@ func (s *Services) LogoutUser() error {
 		return fmt.Errorf("failed to update data: %w", err)
 	}
 
+	if s.queue != nil {
+		if err := s.queue.Clear(); err != nil {
+			return failedDumpData(err)
+		}
+	}
+	s.vaultKey = nil
+
 	return nil
 }
@@ -19,7 +19,7 @@ func TestRegisterUser(t *testing.T) {
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
 
 	url := "http://some/api"
 	req := models.RegisterUserRequest{}
@@ -91,10 +91,11 @@ func TestLoginUser(t *testing.T) {
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
 
 	url := "http://some/api"
 	req := models.CreateUserTokenRequest{}
+	tokens := models.CreateUserTokenResponse{AuthToken: "token", RefreshToken: "refresh"}
 
 	type postResponse struct {
 		resp *resty.Response
@@ -114,10 +115,8 @@ func TestLoginUser(t *testing.T) {
 		{
 			name: "login user success",
 			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusOK},
-				},
-				err: nil,
+				resp: newJSONResponse(t, http.StatusOK, tokens),
+				err:  nil,
 			},
 			updateToken: updateToken{
 				count: 1,
@@ -129,10 +128,8 @@ func TestLoginUser(t *testing.T) {
 		{
 			name: "login user failed",
 			postResponse: postResponse{
-				resp: &resty.Response{
-					RawResponse: &http.Response{StatusCode: http.StatusOK},
-				},
-				err: nil,
+				resp: newJSONResponse(t, http.StatusOK, tokens),
+				err:  nil,
 			},
 			updateToken: updateToken{
 				count: 1,
@@ -174,12 +171,15 @@ func TestLoginUser(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			cfg.EXPECT().GetServerAPI().Times(1).Return(url)
 
-			r.EXPECT().Post(url+"/user/token", gomock.Any(), gomock.Any(), gomock.Any()).
+			r.EXPECT().Post(url+"/user/token", gomock.Any(), gomock.Any()).
 				Times(1).Return(test.postResponse.resp, test.postResponse.err)
 
-			cfg.EXPECT().UpdateToken(gomock.Any()).Times(test.updateToken.count).Return(test.updateToken.err)
+			cfg.EXPECT().UpdateTokens("token", "refresh").Times(test.updateToken.count).Return(test.updateToken.err)
+			if test.updateToken.count > 0 && test.updateToken.err == nil {
+				cfg.EXPECT().UpdateData([]models.UserData{}).Times(1).Return(nil)
+			}
 
-			err := s.LoginUser(req)
+			err := s.LoginUser(req, nil)
 
 			if test.wantErr {
 				require.Error(t, err)
@@ -197,27 +197,67 @@ func TestLogoutUser(t *testing.T) {
 
 	cfg := mocks.NewMockConfigurer(mockCtrl)
 	r := mocks.NewMockRequester(mockCtrl)
-	s := Init(cfg, r)
+	s := Init(cfg, r, nil, nil)
+
+	url := "http://some/api"
 
+	type postResponse struct {
+		count int
+		resp  *resty.Response
+		err   error
+	}
 	type updateToken struct {
-		err error
+		count int
+		err   error
 	}
 	type updateData struct {
 		count int
 		err   error
 	}
 	tests := []struct {
-		name        string
-		updateToken updateToken
-		updateData  updateData
-		wantErr     bool
-		errText     string
+		name         string
+		token        string
+		postResponse postResponse
+		updateToken  updateToken
+		updateData   updateData
+		wantErr      bool
+		errText      string
 	}{
 		{
-			name: "loguot user success",
+			name:  "loguot user success",
+			token: "token",
+			postResponse: postResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusNoContent},
+				},
+				err: nil,
+			},
 			updateToken: updateToken{
+				count: 1,
+				err:   nil,
+			},
+			updateData: updateData{
+				count: 1,
+				err:   nil,
+			},
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:  "logout user success when session already revoked",
+			token: "token",
+			postResponse: postResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusUnauthorized},
+				},
 				err: nil,
 			},
+			updateToken: updateToken{
+				count: 1,
+				err:   nil,
+			},
 			updateData: updateData{
 				count: 1,
 				err:   nil,
@@ -226,9 +266,75 @@ func TestLogoutUser(t *testing.T) {
 			errText: "",
 		},
 		{
-			name: "logout user failed when failed update token",
+			name:  "logout user success without token",
+			token: "",
+			postResponse: postResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
 			updateToken: updateToken{
-				err: errors.New("some error"),
+				count: 1,
+				err:   nil,
+			},
+			updateData: updateData{
+				count: 1,
+				err:   nil,
+			},
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:  "logout user failed when request failed",
+			token: "token",
+			postResponse: postResponse{
+				count: 1,
+				resp:  nil,
+				err:   errors.New("some error"),
+			},
+			updateToken: updateToken{
+				count: 0,
+				err:   nil,
+			},
+			updateData: updateData{
+				count: 0,
+				err:   nil,
+			},
+			wantErr: true,
+			errText: "failed request",
+		},
+		{
+			name:  "logout user failed when response status not 204",
+			token: "token",
+			postResponse: postResponse{
+				count: 1,
+				resp: &resty.Response{
+					RawResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+				},
+				err: nil,
+			},
+			updateToken: updateToken{
+				count: 0,
+				err:   nil,
+			},
+			updateData: updateData{
+				count: 0,
+				err:   nil,
+			},
+			wantErr: true,
+			errText: "response status",
+		},
+		{
+			name:  "logout user failed when failed update token",
+			token: "",
+			postResponse: postResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
+			updateToken: updateToken{
+				count: 1,
+				err:   errors.New("some error"),
 			},
 			updateData: updateData{
 				count: 0,
@@ -238,9 +344,16 @@ func TestLogoutUser(t *testing.T) {
 			errText: "failed to update auth token",
 		},
 		{
-			name: "logout user failed when failed update data",
+			name:  "logout user failed when failed update data",
+			token: "",
+			postResponse: postResponse{
+				count: 0,
+				resp:  nil,
+				err:   nil,
+			},
 			updateToken: updateToken{
-				err: nil,
+				count: 1,
+				err:   nil,
 			},
 			updateData: updateData{
 				count: 1,
@@ -252,7 +365,11 @@ func TestLogoutUser(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cfg.EXPECT().UpdateToken("").Times(1).Return(test.updateToken.err)
+			cfg.EXPECT().GetToken().Times(1).Return(test.token)
+			cfg.EXPECT().GetServerAPI().Times(test.postResponse.count).Return(url)
+			r.EXPECT().Post(url+"/user/logout", gomock.Any()).
+				Times(test.postResponse.count).Return(test.postResponse.resp, test.postResponse.err)
+			cfg.EXPECT().UpdateTokens("", "").Times(test.updateToken.count).Return(test.updateToken.err)
 			cfg.EXPECT().UpdateData([]models.UserData{}).Times(test.updateData.count).Return(test.updateData.err)
 
 			err := s.LogoutUser()
@@ -266,3 +383,37 @@ func TestLogoutUser(t *testing.T) {
 		})
 	}
 }
+
+func TestLogoutUserClearsQueue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("")
+	cfg.EXPECT().UpdateTokens("", "").AnyTimes().Return(nil)
+	cfg.EXPECT().UpdateData(gomock.Any()).AnyTimes().Return(nil)
+
+	t.Run("clear offline queue", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+		s.vaultKey = testVaultKey
+
+		queue.EXPECT().Clear().Times(1).Return(nil)
+
+		require.NoError(t, s.LogoutUser())
+		assert.Nil(t, s.vaultKey)
+	})
+
+	t.Run("clear offline queue failed", func(t *testing.T) {
+		s := Init(cfg, r, queue, nil)
+
+		queue.EXPECT().Clear().Times(1).Return(errors.New("some error"))
+
+		err := s.LogoutUser()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to dump data")
+	})
+}
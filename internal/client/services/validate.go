@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+var (
+	ErrLoginIsTooBig         = errors.New("login is too big")
+	ErrPasswordIsTooBig      = errors.New("password is too big")
+	ErrCardNumberInvalid     = errors.New("card number invalid")
+	ErrCardOwnerIsTooBig     = errors.New("card owner is too big")
+	ErrCardExpiryDateInvalid = errors.New("card expiry date invalid")
+	ErrCardCVV2Invalid       = errors.New("card cvv2 invalid")
+	ErrTextIsTooBig          = errors.New("text is too big")
+	ErrOTPLabelIsTooBig      = errors.New("otp issuer or account is too big")
+	ErrMarkIsTooBig          = errors.New("mark is too big")
+	ErrDescriptionIsTooBig   = errors.New("description is too big")
+)
+
+const (
+	maxMarkSize        = 100
+	maxDescriptionSize = 3000
+
+	passwordLoginMaxSize    = 100
+	passwordPasswordMaxSize = 100
+
+	cardNumberSize     = 16
+	cardOwnerMaxSize   = 100
+	cardExpiryDateSize = 7
+	cardCVV2Size       = 3
+
+	textDataMaxSize = 1000
+
+	otpLabelMaxSize = 100
+)
+
+// failedValidateFields обертка ошибки проверки полей.
+func failedValidateFields(err error) error {
+	return fmt.Errorf("failed to validate fields: %w", err)
+}
+
+func validatePasswordData(data models.EncryptPasswordData) error {
+	if len([]rune(data.Login)) > passwordLoginMaxSize {
+		return ErrLoginIsTooBig
+	}
+	if len([]rune(data.Password)) > passwordPasswordMaxSize {
+		return ErrPasswordIsTooBig
+	}
+
+	return nil
+}
+
+func validateCardData(data models.EncryptCardData) error {
+	if len(data.Number) != cardNumberSize {
+		return ErrCardNumberInvalid
+	}
+	if len(data.Owner) > cardOwnerMaxSize {
+		return ErrCardOwnerIsTooBig
+	}
+	if len(data.ExpiryDate) != cardExpiryDateSize {
+		return ErrCardExpiryDateInvalid
+	}
+	if len(data.CVV2) != cardCVV2Size {
+		return ErrCardCVV2Invalid
+	}
+
+	return nil
+}
+
+func validateTextData(data models.EncryptTextData) error {
+	if len([]rune(data.Data)) > textDataMaxSize {
+		return ErrTextIsTooBig
+	}
+
+	return nil
+}
+
+func validateOTPData(data models.EncryptOTPData) error {
+	if len([]rune(data.Issuer)) > otpLabelMaxSize || len([]rune(data.Account)) > otpLabelMaxSize {
+		return ErrOTPLabelIsTooBig
+	}
+
+	return nil
+}
+
+func validateMarkAndDescription(mark, description string) error {
+	if len([]rune(mark)) > maxMarkSize {
+		return ErrMarkIsTooBig
+	}
+	if len([]rune(description)) > maxDescriptionSize {
+		return ErrDescriptionIsTooBig
+	}
+
+	return nil
+}
+
+// patchValue заменяет значение, если передано новое.
+func patchValue(value *string, newValue *string) {
+	if newValue != nil {
+		*value = *newValue
+	}
+}
+
+// valueOrEmpty возвращает значение или пустую строку, если оно не передано.
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/requests"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+const vaultPath = "/user/vault"
+
+// getVaultKey сервис получения ключа хранилища пользователя.
+// При первом обращении ключ выводится из парольной фразы и проверяется по проверочному блоку на сервере,
+// а если проверочного блока еще нет, он создается. Без связи с сервером ключ проверяется
+// по параметрам, сохраненным вместе с очередью изменений.
+func (s *Services) getVaultKey() ([]byte, error) {
+	if s.vaultKey != nil {
+		return s.vaultKey, nil
+	}
+
+	if s.passphrase == nil {
+		return nil, errors.New("passphrase is not configured")
+	}
+
+	vault, err := s.fetchVault()
+	if err != nil {
+		local, ok := s.localKeyParams()
+		if !ok {
+			return nil, err
+		}
+		vault = local
+	}
+
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	key := crypt.DeriveKey(passphrase, vault.Salt)
+
+	if len(vault.Check) == 0 {
+		created, err := s.createVaultCheck(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !created {
+			// Проверочный блок успел создать другой клиент, поэтому ключ сверяется с ним.
+			if vault, err = s.fetchVault(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(vault.Check) != 0 {
+		if err := crypt.VerifyCheck(key, vault.Check); err != nil {
+			return nil, err //nolint:wrapcheck // Ошибка уже понятна пользователю
+		}
+	}
+
+	s.vaultKey = key
+	s.saveLocalKeyParams(vault)
+
+	return key, nil
+}
+
+// localKeyParams возвращает параметры ключа хранилища, сохраненные вместе с очередью изменений,
+// если по ним можно проверить парольную фразу.
+func (s *Services) localKeyParams() (models.Vault, bool) {
+	if s.queue == nil {
+		return models.Vault{}, false
+	}
+
+	params, err := s.queue.KeyParams()
+	if err != nil || len(params.Salt) == 0 || len(params.Check) == 0 {
+		return models.Vault{}, false
+	}
+
+	return params, true
+}
+
+// saveLocalKeyParams сохраняет параметры ключа хранилища, чтобы изменять записи без связи с сервером.
+func (s *Services) saveLocalKeyParams(vault models.Vault) {
+	if s.queue == nil || len(vault.Check) == 0 {
+		return
+	}
+
+	// Без сохраненных параметров изменения без связи с сервером недоступны, остальное работает через сервер.
+	_ = s.queue.SetKeyParams(vault)
+}
+
+// fetchVault сервис получения параметров ключа хранилища с сервера.
+func (s *Services) fetchVault() (models.Vault, error) {
+	vault := models.Vault{}
+
+	resp, err := s.httpRequests.Get(
+		s.cfg.GetServerAPI()+vaultPath,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+	)
+	if err != nil {
+		return vault, failedRequest(err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return vault, failedResponseStatus(resp.Status())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &vault); err != nil {
+		return vault, failedParseBody(err)
+	}
+
+	return vault, nil
+}
+
+// createVaultCheck сервис сохранения проверочного блока ключа хранилища на сервере.
+func (s *Services) createVaultCheck(key []byte) (bool, error) {
+	check, err := crypt.NewCheck(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to create vault check: %w", err)
+	}
+
+	body, err := json.Marshal(models.UpdateVaultRequest{Check: check})
+	if err != nil {
+		return false, failedCreateBody(err)
+	}
+
+	resp, err := s.httpRequests.Put(
+		s.cfg.GetServerAPI()+vaultPath,
+		requests.WithHeader(ContentTypeHeader, JSONContentType),
+		requests.WithHeader(AuthHeader, s.cfg.GetToken()),
+		requests.WithBody(body),
+	)
+	if err != nil {
+		return false, failedRequest(err)
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, failedResponseStatus(resp.Status())
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/crypt"
+	"github.com/MihailSergeenkov/GophKeeper/internal/client/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSalt       = []byte("0123456789abcdef")
+	testPassphrase = "passphrase"
+	testVaultKey   = crypt.DeriveKey(testPassphrase, testSalt)
+)
+
+// newSecretResponse возвращает ответ сервера с данными записи 1, зашифрованными тестовым ключом хранилища.
+func newSecretResponse(t *testing.T, data any) *resty.Response {
+	t.Helper()
+
+	secret := models.Secret{ID: 1, Data: sealTestSecret(t, 1, data), Mark: "test", Description: "test"}
+
+	return newJSONResponse(t, http.StatusOK, secret)
+}
+
+// sealTestSecret шифрует данные тестовым ключом хранилища с привязкой к записи с указанным ID.
+// Тип записи определяется по типу данных.
+func sealTestSecret(t *testing.T, id int, data any) []byte {
+	t.Helper()
+
+	var dataType string
+	switch data.(type) {
+	case models.EncryptPasswordData:
+		dataType = "password"
+	case models.EncryptCardData:
+		dataType = "card"
+	case models.EncryptTextData:
+		dataType = "text"
+	case models.EncryptOTPData:
+		dataType = "otp"
+	}
+
+	plain, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	sealed, err := crypt.SealFor(testVaultKey, plain, secretAAD(dataType, id))
+	require.NoError(t, err)
+
+	return sealed
+}
+
+func newJSONResponse(t *testing.T, status int, body any) *resty.Response {
+	t.Helper()
+
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	resp := &resty.Response{RawResponse: &http.Response{StatusCode: status}}
+
+	return resp.SetBody(b)
+}
+
+func TestGetVaultKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+
+	url := "http://some/api"
+	passphrase := func() (string, error) { return testPassphrase, nil }
+
+	check, err := crypt.NewCheck(testVaultKey)
+	require.NoError(t, err)
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("when vault check exists", func(t *testing.T) {
+		s := Init(cfg, r, nil, passphrase)
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.Vault{Salt: testSalt, Check: check}), nil)
+
+		key, err := s.getVaultKey()
+		require.NoError(t, err)
+		assert.Equal(t, testVaultKey, key)
+
+		key, err = s.getVaultKey()
+		require.NoError(t, err)
+		assert.Equal(t, testVaultKey, key)
+	})
+
+	t.Run("when vault check created", func(t *testing.T) {
+		s := Init(cfg, r, nil, passphrase)
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.Vault{Salt: testSalt}), nil)
+		r.EXPECT().Put(url+"/user/vault", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}, nil)
+
+		key, err := s.getVaultKey()
+		require.NoError(t, err)
+		assert.Equal(t, testVaultKey, key)
+	})
+
+	t.Run("when vault check created by another client", func(t *testing.T) {
+		s := Init(cfg, r, nil, passphrase)
+
+		gomock.InOrder(
+			r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+				Return(newJSONResponse(t, http.StatusOK, models.Vault{Salt: testSalt}), nil),
+			r.EXPECT().Put(url+"/user/vault", gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+				Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusConflict}}, nil),
+			r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+				Return(newJSONResponse(t, http.StatusOK, models.Vault{Salt: testSalt, Check: check}), nil),
+		)
+
+		key, err := s.getVaultKey()
+		require.NoError(t, err)
+		assert.Equal(t, testVaultKey, key)
+	})
+
+	t.Run("when passphrase wrong", func(t *testing.T) {
+		s := Init(cfg, r, nil, func() (string, error) { return "wrong", nil })
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.Vault{Salt: testSalt, Check: check}), nil)
+
+		_, err := s.getVaultKey()
+		require.ErrorIs(t, err, crypt.ErrWrongPassphrase)
+		assert.Nil(t, s.vaultKey)
+	})
+
+	t.Run("when read passphrase failed", func(t *testing.T) {
+		s := Init(cfg, r, nil, func() (string, error) { return "", errors.New("some error") })
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, models.Vault{Salt: testSalt, Check: check}), nil)
+
+		_, err := s.getVaultKey()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read passphrase")
+	})
+
+	t.Run("when get vault failed", func(t *testing.T) {
+		s := Init(cfg, r, nil, passphrase)
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}, nil)
+
+		_, err := s.getVaultKey()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "response status")
+	})
+}
+
+func TestOpenSecret(t *testing.T) {
+	s := Init(nil, nil, nil, nil)
+	s.vaultKey = testVaultKey
+
+	t.Run("open sealed secret", func(t *testing.T) {
+		sealed, err := s.sealSecret("text", 1, models.EncryptTextData{Data: "test"})
+		require.NoError(t, err)
+		assert.NotContains(t, string(sealed), "test")
+
+		data := models.EncryptTextData{}
+		require.NoError(t, s.openSecret("text", 1, sealed, &data))
+		assert.Equal(t, "test", data.Data)
+	})
+
+	t.Run("reject secret of another record", func(t *testing.T) {
+		sealed, err := s.sealSecret("text", 1, models.EncryptTextData{Data: "test"})
+		require.NoError(t, err)
+
+		data := models.EncryptTextData{}
+		require.ErrorContains(t, s.openSecret("text", 2, sealed, &data), "failed to decrypt data")
+		require.ErrorContains(t, s.openSecret("password", 1, sealed, &data), "failed to decrypt data")
+	})
+
+	t.Run("reject legacy secret", func(t *testing.T) {
+		data := models.EncryptTextData{}
+		require.ErrorIs(t, s.openSecret("text", 1, []byte(`{"data":"test"}`), &data), ErrUnboundSecret)
+
+		sealed, err := crypt.Seal(testVaultKey, []byte(`{"data":"test"}`))
+		require.NoError(t, err)
+		require.ErrorIs(t, s.openSecret("text", 1, sealed, &data), ErrUnboundSecret)
+	})
+}
+
+func TestGetVaultKeyOffline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cfg := mocks.NewMockConfigurer(mockCtrl)
+	r := mocks.NewMockRequester(mockCtrl)
+	queue := mocks.NewMockOfflineQueuer(mockCtrl)
+
+	url := "http://some/api"
+	passphrase := func() (string, error) { return testPassphrase, nil }
+
+	check, err := crypt.NewCheck(testVaultKey)
+	require.NoError(t, err)
+	params := models.Vault{Salt: testSalt, Check: check}
+
+	cfg.EXPECT().GetToken().AnyTimes().Return("token")
+	cfg.EXPECT().GetServerAPI().AnyTimes().Return(url)
+
+	t.Run("save key params after check", func(t *testing.T) {
+		s := Init(cfg, r, queue, passphrase)
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(newJSONResponse(t, http.StatusOK, params), nil)
+		queue.EXPECT().SetKeyParams(params).Times(1).Return(nil)
+
+		key, err := s.getVaultKey()
+
+		require.NoError(t, err)
+		assert.Equal(t, testVaultKey, key)
+	})
+
+	t.Run("check key by saved params without server", func(t *testing.T) {
+		s := Init(cfg, r, queue, passphrase)
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(nil, errors.New("some error"))
+		queue.EXPECT().KeyParams().Times(1).Return(params, nil)
+		queue.EXPECT().SetKeyParams(params).Times(1).Return(nil)
+
+		key, err := s.getVaultKey()
+
+		require.NoError(t, err)
+		assert.Equal(t, testVaultKey, key)
+	})
+
+	t.Run("wrong passphrase without server", func(t *testing.T) {
+		s := Init(cfg, r, queue, func() (string, error) { return "wrong", nil })
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(nil, errors.New("some error"))
+		queue.EXPECT().KeyParams().Times(1).Return(params, nil)
+
+		_, err := s.getVaultKey()
+
+		require.ErrorIs(t, err, crypt.ErrWrongPassphrase)
+	})
+
+	t.Run("server error without saved params", func(t *testing.T) {
+		s := Init(cfg, r, queue, passphrase)
+
+		r.EXPECT().Get(url+"/user/vault", gomock.Any(), gomock.Any()).Times(1).
+			Return(nil, errors.New("some error"))
+		queue.EXPECT().KeyParams().Times(1).Return(models.Vault{}, nil)
+
+		_, err := s.getVaultKey()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed request")
+	})
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"io"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -14,26 +15,85 @@ type RegisterUserRequest struct {
 
 // CreateUserTokenRequest тип для получения токена доступа пользователя.
 type CreateUserTokenRequest struct {
-	Login    string `json:"login"`
-	Password string `json:"password"`
+	Login         string `json:"login"`
+	Password      string `json:"password"`
+	DeviceName    string `json:"device_name,omitempty"`
+	ClientVersion string `json:"client_version,omitempty"`
 }
 
 // CreateUserTokenResponse тип ответа с токеном доступа пользователя.
+// Если у пользователя включена двухфакторная аутентификация, вместо токенов выдается TwoFactorToken.
 type CreateUserTokenResponse struct {
-	AuthToken string `json:"auth_token"`
+	AuthToken      string `json:"auth_token,omitempty"`
+	RefreshToken   string `json:"refresh_token,omitempty"`
+	ExpiresIn      int    `json:"expires_in,omitempty"`
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
+}
+
+// LoginTwoFactorRequest тип для второго шага получения токена доступа.
+// Code может содержать одноразовый пароль или код восстановления.
+type LoginTwoFactorRequest struct {
+	TwoFactorToken string `json:"two_factor_token"`
+	Code           string `json:"code"`
+	DeviceName     string `json:"device_name,omitempty"`
+	ClientVersion  string `json:"client_version,omitempty"`
+}
+
+// EnrollTwoFactorResponse тип ответа с секретом для приложения-аутентификатора.
+type EnrollTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// VerifyTwoFactorRequest тип для подтверждения подключения двухфакторной аутентификации.
+type VerifyTwoFactorRequest struct {
+	Code string `json:"code"`
+}
+
+// VerifyTwoFactorResponse тип ответа с кодами восстановления.
+type VerifyTwoFactorResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactor тип состояния двухфакторной аутентификации пользователя.
+type TwoFactor struct {
+	Secret   []byte
+	LastStep int64
+	Enabled  bool
+}
+
+// RefreshUserTokenRequest тип для обновления токена доступа пользователя.
+type RefreshUserTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // User тип пользователя.
 type User struct {
-	Login    string
-	Password []byte
-	ID       int
+	Login       string
+	Password    []byte
+	ID          int
+	TOTPEnabled bool
 }
 
 // Claims тип для данных токена доступа.
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID int
+	SessionID string
+	Purpose   string `json:",omitempty"`
+	UserID    int
+}
+
+// Session тип сессии пользователя.
+type Session struct {
+	CreatedAt     time.Time `json:"created_at"`
+	LastUsedAt    time.Time `json:"last_used_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ID            string    `json:"id"`
+	DeviceName    string    `json:"device_name"`
+	ClientVersion string    `json:"client_version"`
+	IP            string    `json:"ip"`
+	UserID        int       `json:"-"`
+	Current       bool      `json:"current"`
 }
 
 // AddResponse тип для ответа добавленния данных.
@@ -42,45 +102,270 @@ type AddResponse struct {
 }
 
 // UserData тип для данных пользователя.
+// Version - номер версии записи, увеличивается при каждом изменении.
+// Folder - путь папки вида "work/aws", пустой у данных вне папок, Tags - метки в алфавитном порядке.
 type UserData struct {
-	Mark        string `json:"mark"`
-	Description string `json:"description"`
-	Type        string `json:"type"`
-	ID          int    `json:"id"`
+	Tags        []string `json:"tags,omitempty"`
+	Folder      string   `json:"folder,omitempty"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	ID          int      `json:"id"`
+	Version     int      `json:"version"`
+}
+
+// UserDataChanges тип для изменений данных пользователя после курсора синхронизации.
+// Changes - добавленные и измененные записи, Secrets - содержимое измененных записей, кроме файлов,
+// зашифрованное на клиенте, Deleted - ID удаленных записей,
+// Cursor - курсор, с которого нужно запрашивать следующие изменения,
+// Full - в Changes все записи пользователя, и кеш клиента нужно заменить целиком.
+type UserDataChanges struct {
+	Changes []UserData `json:"changes"`
+	Secrets []Secret   `json:"secrets,omitempty"`
+	Deleted []int      `json:"deleted"`
+	Cursor  int64      `json:"cursor"`
+	Full    bool       `json:"full"`
+}
+
+// UserDataFilter тип для поиска данных пользователя. Пустые поля не ограничивают поиск.
+// MarkPrefix - начало метки без учета регистра, Search - слова для полнотекстового поиска по метке и описанию,
+// Sort - поле сортировки (id, type, mark, created, updated), с префиксом "-" - по убыванию.
+// Tag - метка данных, Folder - папка данных вместе с вложенными папками.
+// Limit - количество данных на странице, Offset - сколько найденных данных пропустить.
+type UserDataFilter struct {
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Type        string
+	MarkPrefix  string
+	Search      string
+	Sort        string
+	Tag         string
+	Folder      string
+	Limit       int
+	Offset      int
+}
+
+// UserDataItem тип для найденных данных пользователя.
+type UserDataItem struct {
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Mark        string    `json:"mark"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	ID          int       `json:"id"`
+	Version     int       `json:"version"`
+}
+
+// UserDataPage тип для страницы найденных данных пользователя. Total - количество всех найденных данных.
+type UserDataPage struct {
+	Items []UserDataItem `json:"items"`
+	Total int            `json:"total"`
+}
+
+// UserDataRevision тип для сохраненной прежней версии данных пользователя.
+// CreatedAt - время, когда версия была заменена.
+type UserDataRevision struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Mark        string    `json:"mark"`
+	Description string    `json:"description"`
+	Version     int       `json:"version"`
+}
+
+// TrashItem тип для данных пользователя в корзине. DeletedAt - время удаления.
+type TrashItem struct {
+	DeletedAt   time.Time `json:"deleted_at"`
+	Mark        string    `json:"mark"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	ID          int       `json:"id"`
 }
 
 // AddPasswordRequest тип для добавления пароля пользователя.
 type AddPasswordRequest struct {
-	Login       string `json:"login"`
-	Password    string `json:"password"`
-	Mark        string `json:"mark"`
-	Description string `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Login       string   `json:"login"`
+	Password    string   `json:"password"`
+	Folder      string   `json:"folder,omitempty"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
 }
 
 // AddCardRequest тип для добавления карты пользователя.
 type AddCardRequest struct {
-	Number      string `json:"number"`
-	Owner       string `json:"owner"`
-	ExpiryDate  string `json:"expiry_date"`
-	CVV2        string `json:"cvv2"`
-	Mark        string `json:"mark"`
-	Description string `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Number      string   `json:"number"`
+	Owner       string   `json:"owner"`
+	ExpiryDate  string   `json:"expiry_date"`
+	CVV2        string   `json:"cvv2"`
+	Folder      string   `json:"folder,omitempty"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
 }
 
 // AddTextRequest тип для добавления текста пользователя.
 type AddTextRequest struct {
-	Data        string `json:"data"`
-	Mark        string `json:"mark"`
-	Description string `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Data        string   `json:"data"`
+	Folder      string   `json:"folder,omitempty"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
+}
+
+// AddOTPRequest тип для добавления секрета TOTP пользователя.
+// Если указана ссылка otpauth://, остальные параметры берутся из нее.
+type AddOTPRequest struct {
+	Tags        []string `json:"tags,omitempty"`
+	URI         string   `json:"uri"`
+	Issuer      string   `json:"issuer"`
+	Account     string   `json:"account"`
+	Secret      string   `json:"secret"`
+	Algorithm   string   `json:"algorithm"`
+	Folder      string   `json:"folder,omitempty"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
+	Digits      int      `json:"digits"`
+	Period      int      `json:"period"`
+}
+
+// AddSecretRequest тип для добавления данных пользователя, зашифрованных на клиенте.
+// Version - при полной замене данных версия, которую изменяет клиент (0 - без проверки версии).
+// При полной замене данных папка и метки тоже заменяются.
+// ID - при добавлении ID, заранее зарезервированный клиентом, к которому привязаны данные
+// (0 - ID назначает сервер).
+type AddSecretRequest struct {
+	Data        []byte   `json:"data"`
+	Tags        []string `json:"tags,omitempty"`
+	Folder      string   `json:"folder,omitempty"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
+	ID          int      `json:"id,omitempty"`
+	Version     int      `json:"version,omitempty"`
 }
 
 // AddFileRequest тип для добавления файла пользователя.
+// Папка и метки задаются только при добавлении, при замене файла они не меняются.
 type AddFileRequest struct {
 	File        io.Reader
+	Tags        []string
+	Folder      string
+	FileName    string
+	Mark        string
+	Description string
+	FileSize    int64
+}
+
+// InitFileUploadRequest тип для начала загрузки файла пользователя по частям.
+// Папка и метки задаются файлу при завершении загрузки. ID - заранее зарезервированный ID файла,
+// к которому привязан ключ файла (0 - ID назначает сервер).
+type InitFileUploadRequest struct {
+	Tags        []string `json:"tags,omitempty"`
+	Folder      string   `json:"folder,omitempty"`
+	FileName    string   `json:"file_name"`
+	Mark        string   `json:"mark"`
+	Description string   `json:"description"`
+	FileSize    int64    `json:"file_size"`
+	ID          int      `json:"id,omitempty"`
+}
+
+// FileUpload тип для сессии загрузки файла пользователя по частям.
+// DataID - зарезервированный ID будущего файла (0 - ID назначается при завершении загрузки).
+type FileUpload struct {
+	CreatedAt   time.Time
+	ID          string
+	UploadID    string
+	ObjectName  string
 	FileName    string
 	Mark        string
 	Description string
+	Folder      string
+	Tags        []string
 	FileSize    int64
+	UserID      int
+	DataID      int
+}
+
+// FileUploadPart тип для загруженной части файла.
+type FileUploadPart struct {
+	ETag   string `json:"etag"`
+	Number int    `json:"number"`
+	Size   int64  `json:"size"`
+}
+
+// FileUploadStatus тип для ответа о состоянии загрузки файла по частям.
+type FileUploadStatus struct {
+	UploadID string           `json:"upload_id"`
+	Parts    []FileUploadPart `json:"parts"`
+	PartSize int64            `json:"part_size"`
+	FileSize int64            `json:"file_size"`
+}
+
+// UpdatePasswordRequest тип для частичного обновления пароля пользователя.
+type UpdatePasswordRequest struct {
+	Login       *string `json:"login,omitempty"`
+	Password    *string `json:"password,omitempty"`
+	Mark        *string `json:"mark,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateCardRequest тип для частичного обновления карты пользователя.
+type UpdateCardRequest struct {
+	Number      *string `json:"number,omitempty"`
+	Owner       *string `json:"owner,omitempty"`
+	ExpiryDate  *string `json:"expiry_date,omitempty"`
+	CVV2        *string `json:"cvv2,omitempty"`
+	Mark        *string `json:"mark,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateTextRequest тип для частичного обновления текста пользователя.
+type UpdateTextRequest struct {
+	Data        *string `json:"data,omitempty"`
+	Mark        *string `json:"mark,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateOTPRequest тип для частичного обновления секрета TOTP пользователя.
+type UpdateOTPRequest struct {
+	URI         *string `json:"uri,omitempty"`
+	Mark        *string `json:"mark,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateSecretRequest тип для частичного обновления данных пользователя, зашифрованных на клиенте.
+// Version - версия, которую изменяет клиент (0 - без проверки версии).
+// Tags заменяет все метки данных, пустой список удаляет их.
+type UpdateSecretRequest struct {
+	Data        []byte    `json:"data,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	Folder      *string   `json:"folder,omitempty"`
+	Mark        *string   `json:"mark,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Version     int       `json:"version,omitempty"`
+}
+
+// PendingChange тип для изменения данных пользователя, сделанного клиентом без связи с сервером.
+// Request.Version - версия записи, которую изменял клиент. Mark и Description - метка и описание
+// записи после изменения, Deleted - запись удалена.
+type PendingChange struct {
+	Request     UpdateSecretRequest `json:"request"`
+	Type        string              `json:"type"`
+	Mark        string              `json:"mark"`
+	Description string              `json:"description"`
+	ID          int                 `json:"id"`
+	Deleted     bool                `json:"deleted"`
+}
+
+// UpdateFileRequest тип для частичного обновления описания файла пользователя.
+// Version - версия, которую изменяет клиент (0 - без проверки версии).
+// Tags заменяет все метки файла, пустой список удаляет их.
+type UpdateFileRequest struct {
+	Tags        *[]string `json:"tags,omitempty"`
+	Folder      *string   `json:"folder,omitempty"`
+	Mark        *string   `json:"mark,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Version     int       `json:"version,omitempty"`
 }
 
 // Password тип для пароля пользователя.
@@ -111,9 +396,119 @@ type Text struct {
 	ID          int    `json:"id"`
 }
 
-// File тип для файла пользователя.
+// OTP тип для секрета TOTP пользователя.
+type OTP struct {
+	Issuer      string `json:"issuer"`
+	Account     string `json:"account"`
+	Secret      string `json:"secret"`
+	Algorithm   string `json:"algorithm"`
+	Mark        string `json:"mark"`
+	Description string `json:"description"`
+	Digits      int    `json:"digits"`
+	Period      int    `json:"period"`
+	ID          int    `json:"id"`
+}
+
+// OTPCode тип для текущего одноразового пароля пользователя.
+type OTPCode struct {
+	Code      string `json:"code"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Remaining int    `json:"remaining"`
+	Period    int    `json:"period"`
+}
+
+// Secret тип для данных пользователя, зашифрованных на клиенте.
+type Secret struct {
+	Data        []byte `json:"data"`
+	Mark        string `json:"mark"`
+	Description string `json:"description"`
+	ID          int    `json:"id"`
+}
+
+// Vault тип для параметров ключа хранилища пользователя.
+type Vault struct {
+	Salt  []byte `json:"salt"`
+	Check []byte `json:"check,omitempty"`
+}
+
+// UpdateVaultRequest тип для сохранения проверочного блока ключа хранилища пользователя.
+type UpdateVaultRequest struct {
+	Check []byte `json:"check"`
+}
+
+// File тип для файла пользователя. Size - полный размер файла,
+// Range - отдаваемый диапазон байт (nil, если отдается весь файл).
 type File struct {
-	File io.ReadCloser
+	File     io.ReadCloser
+	Range    *FileRange
+	FileName string
+	ETag     string
+	Size     int64
+}
+
+// FileRange тип для диапазона байт файла, границы включаются.
+// В запросе отрицательный Start означает последние End байт, отрицательный End - до конца файла.
+type FileRange struct {
+	Start int64
+	End   int64
+}
+
+// UserUsage тип для использования хранилища пользователем и его ограничений.
+// Нулевое ограничение означает, что оно не задано.
+type UserUsage struct {
+	UsedBytes   int64 `json:"used_bytes"`
+	MaxBytes    int64 `json:"max_bytes"`
+	MaxFileSize int64 `json:"max_file_size"`
+	Items       int   `json:"items"`
+	MaxItems    int   `json:"max_items"`
+}
+
+// FileInfo тип для объекта в файловом хранилище.
+type FileInfo struct {
+	ModTime time.Time
+	Name    string
+	Size    int64
+}
+
+// PendingFileObject тип для объекта, запись которого в файловое хранилище начата,
+// но еще не подтверждена сохранением данных пользователя.
+type PendingFileObject struct {
+	CreatedAt  time.Time
+	ObjectName string
+}
+
+// FileUserData тип для зашифрованных данных файла пользователя при проверке файлов.
+// ObjectMissing - объект файла не был найден в файловом хранилище при прошлой проверке.
+type FileUserData struct {
+	Data          []byte
+	ID            int
+	ObjectMissing bool
+}
+
+// FileIssue тип для несогласованности данных пользователя и файлового хранилища.
+// DataID - идентификатор данных пользователя (0, если у объекта нет данных).
+type FileIssue struct {
+	ObjectName string
+	UserID     int
+	DataID     int
+}
+
+// FileCheckReport тип для отчета о проверке согласованности файлов.
+// OrphanedObjects - объекты без данных пользователя, MissingObjects - данные пользователя без объектов.
+type FileCheckReport struct {
+	OrphanedObjects []FileIssue
+	MissingObjects  []FileIssue
+	Users           int
+	DeletedObjects  int
+	StalePending    int
+}
+
+// GetFileRequest тип для запроса получения файла пользователя.
+// Диапазон Range применяется, только если IfRange пуст или совпадает с ETag файла.
+type GetFileRequest struct {
+	Range   *FileRange
+	IfRange string
 }
 
 // EncryptPasswordData тип для шифрованных данных пароля пользователя.
@@ -135,7 +530,40 @@ type EncryptTextData struct {
 	Data string `json:"data"`
 }
 
+// EncryptOTPData тип для шифрованных данных секрета TOTP пользователя.
+type EncryptOTPData struct {
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Secret    string `json:"secret"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period"`
+}
+
 // EncryptFileData тип для шифрованных данных файла пользователя.
+// ObjectName - имя объекта в файловом хранилище (пусто у файлов, сохраненных под именем файла).
 type EncryptFileData struct {
-	FileName string `json:"file_name"`
+	FileName   string `json:"file_name"`
+	ObjectName string `json:"object_name,omitempty"`
+}
+
+// EncryptedUserData тип для зашифрованных данных пользователя.
+type EncryptedUserData struct {
+	Data   []byte
+	ID     int
+	UserID int
+}
+
+// UserDataLabels тип для папки и меток данных пользователя. Nil означает, что их не нужно менять,
+// пустая папка убирает данные из папки, пустой список меток удаляет метки.
+type UserDataLabels struct {
+	Folder *string
+	Tags   *[]string
+}
+
+// TrashedUserData тип для зашифрованных данных пользователя в корзине, которые нужно удалить окончательно.
+type TrashedUserData struct {
+	Type string
+	Data []byte
+	ID   int
 }
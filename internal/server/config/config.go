@@ -2,9 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/caarlos0/env/v11"
@@ -13,12 +15,22 @@ import (
 
 // Settings структура для конфигурирования сервиса.
 type Settings struct {
-	RunAddr     string        `json:"server_address" env:"SERVER_ADDRESS" envDefault:"localhost:8080"`
-	DatabaseURI string        `json:"db_uri" env:"DATABASE_URI" envDefault:"postgresql://localhost:5432/test"`
-	SecretKey   string        `json:"secret_key" env:"SECRET_KEY" envDefault:"1234567890"`
-	S3          S3Settings    `json:"s3"`
-	LogLevel    zapcore.Level `json:"log_level" env:"LOG_LEVEL" envDefault:"ERROR"`
-	EnableHTTPS bool          `json:"enable_https" env:"ENABLE_HTTPS" envDefault:"false"`
+	RunAddr     string              `json:"server_address" env:"SERVER_ADDRESS" envDefault:"localhost:8080"`
+	DatabaseURI string              `json:"db_uri" env:"DATABASE_URI" envDefault:"postgresql://localhost:5432/test"`
+	SecretKey   string              `json:"secret_key" env:"SECRET_KEY" envDefault:"1234567890"`
+	JWTKey      string              `json:"jwt_key" env:"JWT_KEY"`
+	MasterKey   string              `json:"master_key" env:"MASTER_KEY"`
+	S3          S3Settings          `json:"s3"`
+	FileStorage FileStorageSettings `json:"file_storage"`
+	FileGC      FileGCSettings      `json:"file_gc"`
+	Rotation    RotationSettings    `json:"rotation"`
+	Tokens      TokenSettings       `json:"tokens"`
+	Limits      LimitSettings       `json:"limits"`
+	Quotas      QuotaSettings       `json:"quotas"`
+	History     HistorySettings     `json:"history"`
+	Trash       TrashSettings       `json:"trash"`
+	LogLevel    zapcore.Level       `json:"log_level" env:"LOG_LEVEL" envDefault:"ERROR"`
+	EnableHTTPS bool                `json:"enable_https" env:"ENABLE_HTTPS" envDefault:"false"`
 }
 
 type S3Settings struct {
@@ -26,9 +38,81 @@ type S3Settings struct {
 	AccessKeyID     string `json:"access_key_id" env:"S3_ACCESS_KEY_ID" envDefault:"test_id"`
 	SecretAccessKey string `json:"secret_access_key" env:"S3_SECRET_ACCESS_KEY" envDefault:"test_secret"`
 	Region          string `json:"region" env:"S3_REGION" envDefault:"us-east-1"`
+	Bucket          string `json:"bucket" env:"S3_BUCKET" envDefault:"goph-keeper"`
 	SecretPassword  string `json:"secret_password" env:"S3_SECRET_PASSWORD" envDefault:"12345678"`
 	UseSSL          bool   `json:"use_ssl" env:"S3_USE_SSL" envDefault:"false"`
 	SecureFiles     bool   `json:"secure_files" env:"S3_SECURE_FILES" envDefault:"false"`
+	MigrateBuckets  bool   `json:"migrate_buckets" env:"S3_MIGRATE_BUCKETS" envDefault:"false"`
+}
+
+// Хранилища файлов, которые можно выбрать в FileStorageSettings.
+const (
+	FileStorageS3     = "s3"
+	FileStorageLocal  = "local"
+	FileStorageMemory = "memory"
+)
+
+// FileStorageSettings структура для выбора хранилища файлов: S3, каталог на локальном диске или память.
+type FileStorageSettings struct {
+	Backend string `json:"backend" env:"FILE_STORAGE" envDefault:"s3"`
+	Dir     string `json:"dir" env:"FILE_STORAGE_DIR" envDefault:"files"`
+}
+
+// FileGCSettings структура для настройки проверки согласованности файлов и сборки мусора.
+// Нулевой интервал отключает фоновую сборку мусора. Объекты моложе PendingTTL не удаляются:
+// их запись могла еще не завершиться.
+type FileGCSettings struct {
+	Interval   time.Duration `json:"interval" env:"FILE_GC_INTERVAL" envDefault:"1h"`
+	PendingTTL time.Duration `json:"pending_ttl" env:"FILE_GC_PENDING_TTL" envDefault:"24h"`
+	Report     bool          `json:"report" env:"FILE_GC_REPORT" envDefault:"false"`
+}
+
+// TrashSettings структура для настройки корзины: сколько удаленные данные хранятся в корзине
+// и как часто они окончательно удаляются. Нулевой интервал отключает фоновую очистку корзины.
+type TrashSettings struct {
+	Retention time.Duration `json:"retention" env:"TRASH_RETENTION" envDefault:"720h"`
+	Interval  time.Duration `json:"interval" env:"TRASH_PURGE_INTERVAL" envDefault:"1h"`
+}
+
+// RotationSettings структура для настройки ротации мастер-ключа и перешифровки данных в устаревших форматах.
+type RotationSettings struct {
+	OldMasterKey string `json:"old_master_key" env:"OLD_MASTER_KEY"`
+	BatchSize    int    `json:"batch_size" env:"ROTATION_BATCH_SIZE" envDefault:"100"`
+	Enabled      bool   `json:"enabled" env:"ROTATE_MASTER_KEY" envDefault:"false"`
+	Reencrypt    bool   `json:"reencrypt" env:"REENCRYPT_LEGACY_DATA" envDefault:"false"`
+}
+
+// TokenSettings структура для настройки времени жизни токенов.
+type TokenSettings struct {
+	AccessTTL  time.Duration `json:"access_ttl" env:"ACCESS_TOKEN_TTL" envDefault:"15m"`
+	RefreshTTL time.Duration `json:"refresh_ttl" env:"REFRESH_TOKEN_TTL" envDefault:"720h"`
+}
+
+// LimitSettings структура для настройки ограничения частоты запросов и защиты от перебора паролей.
+// Нулевая частота запросов или количество попыток отключает соответствующее ограничение.
+type LimitSettings struct {
+	RPS           float64       `json:"rps" env:"RATE_LIMIT_RPS" envDefault:"20"`
+	Burst         int           `json:"burst" env:"RATE_LIMIT_BURST" envDefault:"40"`
+	AuthRPS       float64       `json:"auth_rps" env:"AUTH_RATE_LIMIT_RPS" envDefault:"1"`
+	AuthBurst     int           `json:"auth_burst" env:"AUTH_RATE_LIMIT_BURST" envDefault:"10"`
+	LoginAttempts int           `json:"login_attempts" env:"LOGIN_ATTEMPTS" envDefault:"5"`
+	IPAttempts    int           `json:"ip_attempts" env:"IP_ATTEMPTS" envDefault:"20"`
+	LockoutBase   time.Duration `json:"lockout_base" env:"LOCKOUT_BASE" envDefault:"1s"`
+	LockoutMax    time.Duration `json:"lockout_max" env:"LOCKOUT_MAX" envDefault:"15m"`
+}
+
+// QuotaSettings структура для настройки ограничений хранилища каждого пользователя:
+// общего объема данных и файлов, количества записей и размера одного файла. Нулевое значение отключает ограничение.
+type QuotaSettings struct {
+	MaxBytes    int64 `json:"max_bytes" env:"QUOTA_MAX_BYTES" envDefault:"1073741824"`
+	MaxItems    int   `json:"max_items" env:"QUOTA_MAX_ITEMS" envDefault:"10000"`
+	MaxFileSize int64 `json:"max_file_size" env:"QUOTA_MAX_FILE_SIZE" envDefault:"268435456"`
+}
+
+// HistorySettings структура для настройки истории версий данных пользователя:
+// сколько последних прежних версий хранится для каждой записи. Нулевое значение отключает ограничение.
+type HistorySettings struct {
+	Retention int `json:"retention" env:"HISTORY_RETENTION" envDefault:"10"`
 }
 
 // Setup функция считывания и применения пользовательских настроек сервиса.
@@ -53,9 +137,31 @@ func Setup(withFlags bool) (*Settings, error) {
 		s.parseFlags()
 	}
 
+	if err := s.validateKeys(); err != nil {
+		return nil, fmt.Errorf("invalid keys: %w", err)
+	}
+
 	return &s, nil
 }
 
+// validateKeys проверяет, что мастер-ключ и ключ подписи токенов заданы и ключ подписи
+// не совпадает с ключами шифрования данных.
+func (s *Settings) validateKeys() error {
+	if s.MasterKey == "" {
+		return errors.New("master key is not set")
+	}
+
+	if s.JWTKey == "" {
+		return errors.New("jwt key is not set")
+	}
+
+	if s.JWTKey == s.SecretKey || s.JWTKey == s.MasterKey {
+		return errors.New("jwt key must differ from secret key and master key")
+	}
+
+	return nil
+}
+
 func getConfigData() ([]byte, bool, error) {
 	configFile := os.Getenv("CONFIG")
 
@@ -115,7 +221,9 @@ func (s *Settings) parseFlags() {
 	})
 
 	flag.StringVar(&s.DatabaseURI, "d", s.DatabaseURI, "database URI")
-	flag.StringVar(&s.SecretKey, "sk", s.SecretKey, "secret key for generate cookie token")
+	flag.StringVar(&s.SecretKey, "sk", s.SecretKey, "legacy secret key for decrypt data encrypted before user data keys")
+	flag.StringVar(&s.JWTKey, "jk", s.JWTKey, "secret key for sign auth tokens (required, differs from other keys)")
+	flag.StringVar(&s.MasterKey, "mk", s.MasterKey, "master key for encrypt user data keys (required)")
 	flag.BoolVar(&s.EnableHTTPS, "s", s.EnableHTTPS, "enable HTTPS")
 
 	flag.StringVar(&s.S3.Endpoint, "se", s.S3.Endpoint, "address and port for s3")
@@ -123,8 +231,47 @@ func (s *Settings) parseFlags() {
 	flag.StringVar(&s.S3.SecretAccessKey, "ss", s.S3.SecretAccessKey, "secret access key for s3")
 	flag.BoolVar(&s.S3.UseSSL, "su", s.S3.UseSSL, "enable SSL for S3")
 	flag.StringVar(&s.S3.Region, "sr", s.S3.Region, "region for s3")
+	flag.StringVar(&s.S3.Bucket, "sb", s.S3.Bucket, "bucket for files in s3")
 	flag.StringVar(&s.S3.SecretPassword, "sp", s.S3.SecretPassword, "secret password for s3")
 	flag.BoolVar(&s.S3.SecureFiles, "sf", s.S3.SecureFiles, "secure files in S3")
+	flag.BoolVar(&s.S3.MigrateBuckets, "s3migrate", s.S3.MigrateBuckets,
+		"move files from legacy per-user buckets to the s3 bucket and exit")
+
+	flag.StringVar(&s.FileStorage.Backend, "fs", s.FileStorage.Backend, "file storage: s3, local or memory")
+	flag.StringVar(&s.FileStorage.Dir, "fsd", s.FileStorage.Dir, "directory for local file storage")
+
+	flag.DurationVar(&s.FileGC.Interval, "gci", s.FileGC.Interval, "interval of file garbage collection (0 disables)")
+	flag.DurationVar(&s.FileGC.PendingTTL, "gcttl", s.FileGC.PendingTTL, "min age of files removed by garbage collection")
+	flag.BoolVar(&s.FileGC.Report, "gcreport", s.FileGC.Report,
+		"print report of inconsistencies between files and user data without changes and exit")
+
+	flag.BoolVar(&s.Rotation.Enabled, "rotate", s.Rotation.Enabled, "rotate master key and exit")
+	flag.StringVar(&s.Rotation.OldMasterKey, "omk", s.Rotation.OldMasterKey, "old master key for rotation")
+	flag.IntVar(&s.Rotation.BatchSize, "rbs", s.Rotation.BatchSize, "batch size for master key rotation")
+	flag.BoolVar(&s.Rotation.Reencrypt, "reencrypt", s.Rotation.Reencrypt,
+		"reencrypt user data stored in legacy formats with user data keys and exit")
+
+	flag.DurationVar(&s.Tokens.AccessTTL, "att", s.Tokens.AccessTTL, "access token lifetime")
+	flag.DurationVar(&s.Tokens.RefreshTTL, "rtt", s.Tokens.RefreshTTL, "refresh token lifetime")
+
+	flag.Float64Var(&s.Limits.RPS, "rl", s.Limits.RPS, "requests per second for each client address (0 disables)")
+	flag.IntVar(&s.Limits.Burst, "rlb", s.Limits.Burst, "burst of requests for each client address")
+	flag.Float64Var(&s.Limits.AuthRPS, "arl", s.Limits.AuthRPS, "login and register requests per second for each client address (0 disables)")
+	flag.IntVar(&s.Limits.AuthBurst, "arlb", s.Limits.AuthBurst, "burst of login and register requests for each client address")
+	flag.IntVar(&s.Limits.LoginAttempts, "la", s.Limits.LoginAttempts, "failed login attempts before lockout of login (0 disables)")
+	flag.IntVar(&s.Limits.IPAttempts, "ia", s.Limits.IPAttempts, "failed login attempts before lockout of client address (0 disables)")
+	flag.DurationVar(&s.Limits.LockoutBase, "lb", s.Limits.LockoutBase, "first lockout duration, doubled on every next failure")
+	flag.DurationVar(&s.Limits.LockoutMax, "lm", s.Limits.LockoutMax, "max lockout duration")
+
+	flag.Int64Var(&s.Quotas.MaxBytes, "qb", s.Quotas.MaxBytes, "max bytes of data and files for each user (0 disables)")
+	flag.IntVar(&s.Quotas.MaxItems, "qi", s.Quotas.MaxItems, "max number of data items for each user (0 disables)")
+	flag.Int64Var(&s.Quotas.MaxFileSize, "qfs", s.Quotas.MaxFileSize, "max size of one file in bytes (0 disables)")
+
+	flag.IntVar(&s.History.Retention, "hr", s.History.Retention,
+		"number of previous versions kept in history of each data item (0 disables limit)")
+
+	flag.DurationVar(&s.Trash.Retention, "tr", s.Trash.Retention, "time deleted data is kept in trash before purge")
+	flag.DurationVar(&s.Trash.Interval, "tpi", s.Trash.Interval, "interval of trash purge (0 disables)")
 
 	flag.String("c", "", "config file path (shorthand)")
 	flag.String("config", "", "config file path")
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +23,7 @@ func TestSetup(t *testing.T) {
 			name: "success setup",
 			setEnv: func() {
 				require.NoError(t, os.Setenv("SERVER_ADDRESS", runAddr))
+				setKeys(t, "jwt key", "master key")
 			},
 			wantErr: false,
 			errText: "",
@@ -51,6 +53,38 @@ func TestSetup(t *testing.T) {
 			wantErr: true,
 			errText: "failed to parse envs",
 		},
+		{
+			name: "master key is not set",
+			setEnv: func() {
+				setKeys(t, "jwt key", "")
+			},
+			wantErr: true,
+			errText: "master key is not set",
+		},
+		{
+			name: "jwt key is not set",
+			setEnv: func() {
+				setKeys(t, "", "master key")
+			},
+			wantErr: true,
+			errText: "jwt key is not set",
+		},
+		{
+			name: "jwt key equals secret key",
+			setEnv: func() {
+				setKeys(t, "1234567890", "master key")
+			},
+			wantErr: true,
+			errText: "jwt key must differ",
+		},
+		{
+			name: "jwt key equals master key",
+			setEnv: func() {
+				setKeys(t, "master key", "master key")
+			},
+			wantErr: true,
+			errText: "jwt key must differ",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -65,11 +99,27 @@ func TestSetup(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, runAddr, config.RunAddr)
+				assert.Equal(t, "jwt key", config.JWTKey)
+				assert.Equal(t, "master key", config.MasterKey)
+				assert.Equal(t, 5, config.Limits.LoginAttempts)
+				assert.Equal(t, FileStorageS3, config.FileStorage.Backend)
+				assert.Equal(t, time.Hour, config.FileGC.Interval)
+				assert.Equal(t, int64(1<<30), config.Quotas.MaxBytes)
+				assert.Equal(t, 10, config.History.Retention)
+				assert.Equal(t, 30*24*time.Hour, config.Trash.Retention)
+				assert.Equal(t, time.Hour, config.Trash.Interval)
 			}
 		})
 	}
 }
 
+func setKeys(t *testing.T, jwtKey, masterKey string) {
+	t.Helper()
+
+	require.NoError(t, os.Setenv("JWT_KEY", jwtKey))
+	require.NoError(t, os.Setenv("MASTER_KEY", masterKey))
+}
+
 func TestGetConfigData(t *testing.T) {
 	tests := []struct {
 		setEnv      func()
@@ -154,6 +204,7 @@ func TestParseConfigData(t *testing.T) {
 				assert.Equal(t, "localhost:8081", config.RunAddr)
 				assert.Equal(t, "postgresql://localhost:5432/goph_keeper", config.DatabaseURI)
 				assert.Equal(t, "12345", config.SecretKey)
+				assert.Equal(t, "54321", config.JWTKey)
 				assert.Equal(t, zapcore.InfoLevel, config.LogLevel)
 				assert.Equal(t, true, config.EnableHTTPS)
 			}
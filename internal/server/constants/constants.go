@@ -2,4 +2,8 @@ package constants
 
 type ContextValueKey int
 
-const KeyUserID ContextValueKey = iota
+const (
+	KeyUserID ContextValueKey = iota
+	KeySessionID
+	KeyClientIP
+)
@@ -3,53 +3,186 @@ package crypt
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 )
 
+// Версии формата зашифрованных данных: версия | nonce | шифротекст.
+const (
+	// envelopeV1 данные зашифрованы общим ключом приложения.
+	envelopeV1 byte = 0x01
+	// envelopeV2 данные зашифрованы ключом данных пользователя.
+	envelopeV2 byte = 0x02
+	// wrapV1 ключ данных пользователя зашифрован мастер-ключом.
+	wrapV1 byte = 0x01
+
+	dataKeySize = 32
+	keyIDSize   = 8
+)
+
+var errInvalidEnvelope = errors.New("invalid envelope")
+
 // Crypt структура для работы с функциями криптографии приложения.
 type Crypt struct {
-	settings *config.Settings
-	aesgcm   cipher.AEAD
-	nonce    []byte
+	settings    *config.Settings
+	masterKey   cipher.AEAD
+	legacyKey   cipher.AEAD
+	masterKeyID string
+	legacyNonce []byte
 }
 
 // NewCrypt функция инициализации криптографии приложения.
 func NewCrypt(settings *config.Settings) (*Crypt, error) {
-	key := sha256.Sum256([]byte(settings.SecretKey))
-
-	aesblock, err := aes.NewCipher(key[:])
+	masterKey := sha256.Sum256([]byte(settings.MasterKey))
+	masterAEAD, err := newAEAD(masterKey[:])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cipher block %w", err)
+		return nil, err
 	}
-	aesgcm, err := cipher.NewGCM(aesblock)
+
+	legacyKey := sha256.Sum256([]byte(settings.SecretKey))
+	legacyAEAD, err := newAEAD(legacyKey[:])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cipher aead %w", err)
+		return nil, err
 	}
 
-	nonce := key[len(key)-aesgcm.NonceSize():]
+	// Nonce, которым зашифрованы данные в самом первом формате, нужен только для их расшифровки.
+	legacyNonce := legacyKey[len(legacyKey)-legacyAEAD.NonceSize():]
+
+	// Идентификатор мастер-ключа позволяет понять, каким ключом зашифрован ключ данных, не раскрывая его.
+	masterKeyHash := sha256.Sum256(masterKey[:])
 
 	return &Crypt{
-		settings: settings,
-		aesgcm:   aesgcm,
-		nonce:    nonce,
+		settings:    settings,
+		masterKey:   masterAEAD,
+		legacyKey:   legacyAEAD,
+		masterKeyID: hex.EncodeToString(masterKeyHash[:keyIDSize]),
+		legacyNonce: legacyNonce,
 	}, nil
 }
 
-// EncryptData функция зашифровки данных.
-func (c Crypt) EncryptData(data []byte) []byte {
-	encrypted := c.aesgcm.Seal(nil, c.nonce, data, nil)
-	return encrypted
+// MasterKeyID функция получения идентификатора мастер-ключа.
+func (c Crypt) MasterKeyID() string {
+	return c.masterKeyID
+}
+
+// GenerateDataKey функция генерации ключа данных пользователя, возвращает ключ и его зашифрованную копию.
+func (c Crypt) GenerateDataKey() ([]byte, []byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key %w", err)
+	}
+
+	wrappedKey, err := c.WrapDataKey(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dataKey, wrappedKey, nil
+}
+
+// WrapDataKey функция шифрования ключа данных пользователя мастер-ключом.
+func (c Crypt) WrapDataKey(dataKey []byte) ([]byte, error) {
+	return seal(c.masterKey, wrapV1, dataKey)
+}
+
+// UnwrapDataKey функция расшифровки ключа данных пользователя мастер-ключом.
+func (c Crypt) UnwrapDataKey(wrappedKey []byte) ([]byte, error) {
+	dataKey, err := open(c.masterKey, wrapV1, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %w", err)
+	}
+
+	return dataKey, nil
+}
+
+// EncryptData функция зашифровки данных ключом пользователя, для каждого вызова генерируется случайный nonce.
+func (c Crypt) EncryptData(dataKey []byte, data []byte) ([]byte, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return seal(aead, envelopeV2, data)
 }
 
-// DecryptData функция расшифровки данных.
-func (c Crypt) DecryptData(encrypted []byte) ([]byte, error) {
-	decrypted, err := c.aesgcm.Open(nil, c.nonce, encrypted, nil)
+// DecryptData функция расшифровки данных, поддерживает устаревшие форматы с общим ключом приложения.
+func (c Crypt) DecryptData(dataKey []byte, encrypted []byte) ([]byte, error) {
+	if len(encrypted) > 0 && encrypted[0] == envelopeV2 {
+		aead, err := newAEAD(dataKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if decrypted, err := open(aead, envelopeV2, encrypted); err == nil {
+			return decrypted, nil
+		}
+	}
+
+	if decrypted, err := open(c.legacyKey, envelopeV1, encrypted); err == nil {
+		return decrypted, nil
+	}
+
+	decrypted, err := c.legacyKey.Open(nil, c.legacyNonce, encrypted, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt data %w", err)
 	}
 
 	return decrypted, nil
 }
+
+// IsLegacyData функция проверки, что данные зашифрованы не ключом пользователя.
+func (c Crypt) IsLegacyData(dataKey []byte, encrypted []byte) bool {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return true
+	}
+
+	_, err = open(aead, envelopeV2, encrypted)
+	return err != nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	aesblock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher block %w", err)
+	}
+	aesgcm, err := cipher.NewGCM(aesblock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher aead %w", err)
+	}
+
+	return aesgcm, nil
+}
+
+func seal(aead cipher.AEAD, version byte, data []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+
+	envelope := make([]byte, 1+nonceSize, 1+nonceSize+len(data)+aead.Overhead())
+	envelope[0] = version
+
+	if _, err := rand.Read(envelope[1:]); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce %w", err)
+	}
+
+	return aead.Seal(envelope, envelope[1:], data, nil), nil
+}
+
+func open(aead cipher.AEAD, version byte, encrypted []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+
+	if len(encrypted) < 1+nonceSize+aead.Overhead() || encrypted[0] != version {
+		return nil, errInvalidEnvelope
+	}
+
+	decrypted, err := aead.Open(nil, encrypted[1:1+nonceSize], encrypted[1+nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open envelope %w", err)
+	}
+
+	return decrypted, nil
+}
@@ -10,6 +10,8 @@ import (
 
 func TestNewCrypt(t *testing.T) {
 	t.Run("init crypt", func(t *testing.T) {
+		t.Setenv("JWT_KEY", "jwt key")
+		t.Setenv("MASTER_KEY", "master key")
 		settings, err := config.Setup(false)
 		require.NoError(t, err)
 
@@ -20,40 +22,132 @@ func TestNewCrypt(t *testing.T) {
 	})
 }
 
+func TestDataKey(t *testing.T) {
+	t.Setenv("JWT_KEY", "jwt key")
+	t.Setenv("MASTER_KEY", "master key")
+	settings, err := config.Setup(false)
+	require.NoError(t, err)
+	c, err := NewCrypt(settings)
+	require.NoError(t, err)
+
+	t.Run("generate and unwrap data key", func(t *testing.T) {
+		dataKey, wrappedKey, err := c.GenerateDataKey()
+		require.NoError(t, err)
+
+		assert.Len(t, dataKey, dataKeySize)
+		assert.NotEqual(t, dataKey, wrappedKey)
+
+		unwrapped, err := c.UnwrapDataKey(wrappedKey)
+
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, unwrapped)
+	})
+
+	t.Run("unwrap with another master key", func(t *testing.T) {
+		_, wrappedKey, err := c.GenerateDataKey()
+		require.NoError(t, err)
+
+		other, err := NewCrypt(&config.Settings{MasterKey: "other"})
+		require.NoError(t, err)
+
+		_, err = other.UnwrapDataKey(wrappedKey)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to unwrap data key")
+	})
+
+	t.Run("rewrap with another master key", func(t *testing.T) {
+		dataKey, wrappedKey, err := c.GenerateDataKey()
+		require.NoError(t, err)
+
+		other, err := NewCrypt(&config.Settings{MasterKey: "other"})
+		require.NoError(t, err)
+
+		unwrapped, err := c.UnwrapDataKey(wrappedKey)
+		require.NoError(t, err)
+		rewrapped, err := other.WrapDataKey(unwrapped)
+		require.NoError(t, err)
+		result, err := other.UnwrapDataKey(rewrapped)
+
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, result)
+		assert.NotEqual(t, c.MasterKeyID(), other.MasterKeyID())
+	})
+}
+
 func TestEncryptData(t *testing.T) {
 	t.Run("encrypt data", func(t *testing.T) {
+		t.Setenv("JWT_KEY", "jwt key")
+		t.Setenv("MASTER_KEY", "master key")
 		settings, err := config.Setup(false)
 		require.NoError(t, err)
 		c, err := NewCrypt(settings)
 		require.NoError(t, err)
+		dataKey, _, err := c.GenerateDataKey()
+		require.NoError(t, err)
 
 		someData := []byte("some data")
-		result := c.EncryptData(someData)
+		first, err := c.EncryptData(dataKey, someData)
+		require.NoError(t, err)
+		second, err := c.EncryptData(dataKey, someData)
+		require.NoError(t, err)
 
-		assert.NotEmpty(t, result)
+		assert.Equal(t, envelopeV2, first[0])
+		assert.NotEqual(t, first, second)
 	})
 }
 
 func TestDecryptData(t *testing.T) {
+	t.Setenv("JWT_KEY", "jwt key")
+	t.Setenv("MASTER_KEY", "master key")
 	settings, err := config.Setup(false)
 	require.NoError(t, err)
 	c, err := NewCrypt(settings)
 	require.NoError(t, err)
+	dataKey, _, err := c.GenerateDataKey()
+	require.NoError(t, err)
+	otherKey, _, err := c.GenerateDataKey()
+	require.NoError(t, err)
 
 	someData := []byte("some data")
+	encrypted, err := c.EncryptData(dataKey, someData)
+	require.NoError(t, err)
+	sharedEncrypted, err := seal(c.legacyKey, envelopeV1, someData)
+	require.NoError(t, err)
 
 	tests := []struct {
 		name     string
+		dataKey  []byte
 		someData []byte
 		wantErr  bool
 	}{
 		{
 			name:     "decrypt success",
-			someData: c.EncryptData(someData),
+			dataKey:  dataKey,
+			someData: encrypted,
 			wantErr:  false,
 		},
+		{
+			name:     "decrypt shared key format success",
+			dataKey:  dataKey,
+			someData: sharedEncrypted,
+			wantErr:  false,
+		},
+		{
+			name:     "decrypt legacy success",
+			dataKey:  dataKey,
+			someData: c.legacyKey.Seal(nil, c.legacyNonce, someData, nil),
+			wantErr:  false,
+		},
+		{
+			name:     "decrypt with another user key failed",
+			dataKey:  otherKey,
+			someData: encrypted,
+			wantErr:  true,
+		},
 		{
 			name:     "decrypt failed",
+			dataKey:  dataKey,
 			someData: []byte("some encrypt data"),
 			wantErr:  true,
 		},
@@ -61,7 +155,7 @@ func TestDecryptData(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, err := c.DecryptData(test.someData)
+			result, err := c.DecryptData(test.dataKey, test.someData)
 
 			if test.wantErr {
 				require.Error(t, err)
@@ -73,3 +167,24 @@ func TestDecryptData(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLegacyData(t *testing.T) {
+	t.Setenv("JWT_KEY", "jwt key")
+	t.Setenv("MASTER_KEY", "master key")
+	settings, err := config.Setup(false)
+	require.NoError(t, err)
+	c, err := NewCrypt(settings)
+	require.NoError(t, err)
+	dataKey, _, err := c.GenerateDataKey()
+	require.NoError(t, err)
+
+	someData := []byte("some data")
+	encrypted, err := c.EncryptData(dataKey, someData)
+	require.NoError(t, err)
+	sharedEncrypted, err := seal(c.legacyKey, envelopeV1, someData)
+	require.NoError(t, err)
+
+	assert.False(t, c.IsLegacyData(dataKey, encrypted))
+	assert.True(t, c.IsLegacyData(dataKey, sharedEncrypted))
+	assert.True(t, c.IsLegacyData(dataKey, c.legacyKey.Seal(nil, c.legacyNonce, someData, nil)))
+}
@@ -0,0 +1,74 @@
+// Package filestore содержит файловые хранилища, которые не требуют S3:
+// хранилище на локальном диске и хранилище в памяти.
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+var (
+	ErrFileNotFound      = errors.New("file not found")
+	ErrUploadNotFound    = errors.New("upload not found")
+	ErrInvalidObjectName = errors.New("invalid object name")
+	ErrInvalidUploadPart = errors.New("invalid upload part")
+	ErrInvalidFileSize   = errors.New("file size does not match")
+)
+
+// userID возвращает идентификатор пользователя из контекста.
+func userID(ctx context.Context) (int, error) {
+	id, ok := ctx.Value(constants.KeyUserID).(int)
+	if !ok {
+		return 0, errors.New("failed to fetch user id from context")
+	}
+
+	return id, nil
+}
+
+// validateObjectName проверяет, что имя объекта не выходит за каталог пользователя.
+func validateObjectName(objectName string) error {
+	if objectName == "" || objectName == "." || objectName == ".." || strings.ContainsAny(objectName, `/\`+"\x00") {
+		return fmt.Errorf("%w: %q", ErrInvalidObjectName, objectName)
+	}
+
+	return nil
+}
+
+// sortedParts возвращает части загрузки, упорядоченные по номеру.
+func sortedParts(parts []models.FileUploadPart) []models.FileUploadPart {
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Number < parts[j].Number
+	})
+
+	return parts
+}
+
+// checkParts проверяет, что части для завершения загрузки совпадают с загруженными.
+func checkParts(parts, uploaded []models.FileUploadPart) error {
+	byNumber := make(map[int]models.FileUploadPart, len(uploaded))
+	for _, p := range uploaded {
+		byNumber[p.Number] = p
+	}
+
+	for _, p := range parts {
+		u, ok := byNumber[p.Number]
+		if !ok || strings.Trim(u.ETag, `"`) != strings.Trim(p.ETag, `"`) {
+			return fmt.Errorf("%w: part %d", ErrInvalidUploadPart, p.Number)
+		}
+	}
+
+	return nil
+}
+
+// readCloser объединяет чтение части файла с закрытием самого файла.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
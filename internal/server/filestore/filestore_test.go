@@ -0,0 +1,176 @@
+package filestore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fileStorager методы хранилища файлов, общие для всех реализаций.
+type fileStorager interface {
+	AddFile(ctx context.Context, file io.Reader, objectName string, objectSize int64) error
+	GetFile(ctx context.Context, objectName string, rng *models.FileRange) (models.File, error)
+	StatFile(ctx context.Context, objectName string) (models.File, error)
+	InitUpload(ctx context.Context, objectName string) (string, error)
+	UploadPart(
+		ctx context.Context, objectName, uploadID string, number int, part io.Reader, size int64,
+	) (models.FileUploadPart, error)
+	ListUploadParts(ctx context.Context, objectName, uploadID string) ([]models.FileUploadPart, error)
+	CompleteUpload(ctx context.Context, objectName, uploadID string, parts []models.FileUploadPart) error
+	AbortUpload(ctx context.Context, objectName, uploadID string) error
+	DeleteFile(ctx context.Context, objectName string) error
+	ListFiles(ctx context.Context) ([]models.FileInfo, error)
+}
+
+func userContext(id int) context.Context {
+	return context.WithValue(context.Background(), constants.KeyUserID, id)
+}
+
+func readFile(t *testing.T, ctx context.Context, fs fileStorager, objectName string, rng *models.FileRange) string {
+	t.Helper()
+
+	file, err := fs.GetFile(ctx, objectName, rng)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, file.File.Close())
+	}()
+
+	data, err := io.ReadAll(file.File)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// testFileStorage проверяет поведение хранилища файлов, общее для всех реализаций.
+func testFileStorage(t *testing.T, fs fileStorager) {
+	t.Helper()
+
+	ctx := userContext(1)
+	otherCtx := userContext(2)
+
+	t.Run("add and get file", func(t *testing.T) {
+		require.NoError(t, fs.AddFile(ctx, strings.NewReader("file content"), "test.txt", 12))
+
+		assert.Equal(t, "file content", readFile(t, ctx, fs, "test.txt", nil))
+		assert.Equal(t, "content", readFile(t, ctx, fs, "test.txt", &models.FileRange{Start: 5, End: 11}))
+
+		info, err := fs.StatFile(ctx, "test.txt")
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), info.Size)
+		assert.NotEmpty(t, info.ETag)
+	})
+
+	t.Run("replace file changes etag", func(t *testing.T) {
+		before, err := fs.StatFile(ctx, "test.txt")
+		require.NoError(t, err)
+
+		require.NoError(t, fs.AddFile(ctx, strings.NewReader("new content"), "test.txt", -1))
+
+		after, err := fs.StatFile(ctx, "test.txt")
+		require.NoError(t, err)
+		assert.NotEqual(t, before.ETag, after.ETag)
+		assert.Equal(t, "new content", readFile(t, ctx, fs, "test.txt", nil))
+	})
+
+	t.Run("size mismatch keeps old file", func(t *testing.T) {
+		err := fs.AddFile(ctx, strings.NewReader("short"), "test.txt", 100)
+		require.ErrorIs(t, err, ErrInvalidFileSize)
+		assert.Equal(t, "new content", readFile(t, ctx, fs, "test.txt", nil))
+	})
+
+	t.Run("files of other users are separated", func(t *testing.T) {
+		_, err := fs.StatFile(otherCtx, "test.txt")
+		require.ErrorIs(t, err, ErrFileNotFound)
+
+		require.NoError(t, fs.AddFile(otherCtx, strings.NewReader("other"), "test.txt", 5))
+		assert.Equal(t, "other", readFile(t, otherCtx, fs, "test.txt", nil))
+		assert.Equal(t, "new content", readFile(t, ctx, fs, "test.txt", nil))
+	})
+
+	t.Run("list files", func(t *testing.T) {
+		files, err := fs.ListFiles(ctx)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "test.txt", files[0].Name)
+		assert.Equal(t, int64(11), files[0].Size)
+		assert.False(t, files[0].ModTime.IsZero())
+
+		files, err = fs.ListFiles(userContext(3))
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("invalid object name", func(t *testing.T) {
+		for _, name := range []string{"", "..", "../test.txt", "dir/test.txt", `dir\test.txt`} {
+			err := fs.AddFile(ctx, strings.NewReader("data"), name, 4)
+			require.ErrorIs(t, err, ErrInvalidObjectName, name)
+		}
+	})
+
+	t.Run("missing user id", func(t *testing.T) {
+		err := fs.AddFile(context.Background(), strings.NewReader("data"), "test.txt", 4)
+		require.ErrorContains(t, err, "failed to fetch user id")
+	})
+
+	t.Run("delete file", func(t *testing.T) {
+		require.NoError(t, fs.DeleteFile(ctx, "test.txt"))
+		require.NoError(t, fs.DeleteFile(ctx, "test.txt"))
+
+		_, err := fs.GetFile(ctx, "test.txt", nil)
+		require.ErrorIs(t, err, ErrFileNotFound)
+	})
+
+	t.Run("multipart upload", func(t *testing.T) {
+		uploadID, err := fs.InitUpload(ctx, "upload.txt")
+		require.NoError(t, err)
+
+		second, err := fs.UploadPart(ctx, "upload.txt", uploadID, 2, strings.NewReader("world"), 5)
+		require.NoError(t, err)
+		_, err = fs.UploadPart(ctx, "upload.txt", uploadID, 1, strings.NewReader("hallo "), 6)
+		require.NoError(t, err)
+		first, err := fs.UploadPart(ctx, "upload.txt", uploadID, 1, strings.NewReader("hello "), 6)
+		require.NoError(t, err)
+
+		_, err = fs.UploadPart(ctx, "upload.txt", uploadID, 3, strings.NewReader("!"), 2)
+		require.ErrorIs(t, err, ErrInvalidFileSize)
+
+		parts, err := fs.ListUploadParts(ctx, "upload.txt", uploadID)
+		require.NoError(t, err)
+		assert.Equal(t, []models.FileUploadPart{first, second}, parts)
+
+		_, err = fs.ListUploadParts(otherCtx, "upload.txt", uploadID)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+
+		stale := models.FileUploadPart{Number: 1, ETag: "stale"}
+		err = fs.CompleteUpload(ctx, "upload.txt", uploadID, []models.FileUploadPart{stale, second})
+		require.ErrorIs(t, err, ErrInvalidUploadPart)
+
+		require.NoError(t, fs.CompleteUpload(ctx, "upload.txt", uploadID, parts))
+		assert.Equal(t, "hello world", readFile(t, ctx, fs, "upload.txt", nil))
+
+		_, err = fs.ListUploadParts(ctx, "upload.txt", uploadID)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+	})
+
+	t.Run("abort upload", func(t *testing.T) {
+		uploadID, err := fs.InitUpload(ctx, "aborted.txt")
+		require.NoError(t, err)
+		_, err = fs.UploadPart(ctx, "aborted.txt", uploadID, 1, strings.NewReader("data"), 4)
+		require.NoError(t, err)
+
+		require.NoError(t, fs.AbortUpload(ctx, "aborted.txt", uploadID))
+
+		_, err = fs.UploadPart(ctx, "aborted.txt", uploadID, 2, strings.NewReader("data"), 4)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+		require.ErrorIs(t, fs.AbortUpload(ctx, "aborted.txt", uploadID), ErrUploadNotFound)
+
+		_, err = fs.StatFile(ctx, "aborted.txt")
+		require.ErrorIs(t, err, ErrFileNotFound)
+	})
+}
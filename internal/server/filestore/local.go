@@ -0,0 +1,424 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	filesDir   = "files"
+	uploadsDir = "uploads"
+	tmpDir     = "tmp"
+)
+
+// Local структура для хранения файлов на локальном диске.
+// Файлы пользователя лежат в <root>/<id пользователя>/files, части незавершенных загрузок -
+// в <root>/<id пользователя>/uploads/<id загрузки>. Запись выполняется во временный файл
+// с последующим переименованием, поэтому недописанный файл не заменяет сохраненный.
+type Local struct {
+	root string
+}
+
+// NewLocal функция инициализации хранилища файлов в каталоге root.
+func NewLocal(root string) (*Local, error) {
+	if root == "" {
+		return nil, errors.New("file storage dir is not set")
+	}
+
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create file storage dir %w", err)
+	}
+
+	return &Local{root: root}, nil
+}
+
+// AddFile функция добавления файла в локальное хранилище. Отрицательный objectSize означает неизвестный размер.
+func (l *Local) AddFile(ctx context.Context, file io.Reader, objectName string, objectSize int64) error {
+	path, err := l.filePath(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := l.createTemp(ctx)
+	if err != nil {
+		return err
+	}
+
+	written, err := io.Copy(tmp, file)
+	if err == nil && objectSize >= 0 && written != objectSize {
+		err = fmt.Errorf("%w: written %d of %d bytes", ErrInvalidFileSize, written, objectSize)
+	}
+
+	return commitTemp(tmp, path, err)
+}
+
+// GetFile функция получения файла из локального хранилища. Если задан rng, читается только этот диапазон байт.
+func (l *Local) GetFile(ctx context.Context, objectName string, rng *models.FileRange) (models.File, error) {
+	var resp models.File
+
+	path, err := l.filePath(ctx, objectName)
+	if err != nil {
+		return resp, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return resp, openError(err, ErrFileNotFound)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return resp, fmt.Errorf("failed to stat file %w", err)
+	}
+
+	resp.File = f
+	resp.ETag = fileETag(info)
+	resp.Size = info.Size()
+
+	if rng != nil {
+		if _, err := f.Seek(rng.Start, io.SeekStart); err != nil {
+			_ = f.Close()
+			return resp, fmt.Errorf("failed to seek file %w", err)
+		}
+
+		resp.Size = rng.End - rng.Start + 1
+		resp.File = readCloser{Reader: io.LimitReader(f, resp.Size), Closer: f}
+	}
+
+	return resp, nil
+}
+
+// StatFile функция получения размера и ETag файла из локального хранилища.
+func (l *Local) StatFile(ctx context.Context, objectName string) (models.File, error) {
+	var resp models.File
+
+	path, err := l.filePath(ctx, objectName)
+	if err != nil {
+		return resp, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return resp, openError(err, ErrFileNotFound)
+	}
+
+	resp.ETag = fileETag(info)
+	resp.Size = info.Size()
+
+	return resp, nil
+}
+
+// DeleteFile функция удаления файла из локального хранилища. Удаление отсутствующего файла не считается ошибкой.
+func (l *Local) DeleteFile(ctx context.Context, objectName string) error {
+	path, err := l.filePath(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove file %w", err)
+	}
+
+	return nil
+}
+
+// ListFiles функция получения списка файлов пользователя из локального хранилища.
+func (l *Local) ListFiles(ctx context.Context) ([]models.FileInfo, error) {
+	dir, err := l.userDir(ctx, filesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list files %w", err)
+	}
+
+	files := make([]models.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file %w", err)
+		}
+
+		files = append(files, models.FileInfo{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	return files, nil
+}
+
+// InitUpload функция начала загрузки файла по частям в локальное хранилище. Возвращает идентификатор загрузки.
+func (l *Local) InitUpload(ctx context.Context, objectName string) (string, error) {
+	if err := validateObjectName(objectName); err != nil {
+		return "", err
+	}
+
+	uploadID := uuid.NewString()
+
+	dir, err := l.userDir(ctx, uploadsDir, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create upload dir %w", err)
+	}
+
+	return uploadID, nil
+}
+
+// UploadPart функция загрузки части файла в локальное хранилище.
+func (l *Local) UploadPart(
+	ctx context.Context,
+	objectName, uploadID string,
+	number int,
+	part io.Reader,
+	size int64,
+) (models.FileUploadPart, error) {
+	dir, err := l.uploadDir(ctx, objectName, uploadID)
+	if err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	tmp, err := l.createTemp(ctx)
+	if err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	written, err := io.Copy(tmp, part)
+	if err == nil && written != size {
+		err = fmt.Errorf("%w: written %d of %d bytes", ErrInvalidFileSize, written, size)
+	}
+
+	path := filepath.Join(dir, strconv.Itoa(number))
+	if err := commitTemp(tmp, path, err); err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.FileUploadPart{}, fmt.Errorf("failed to stat part %w", err)
+	}
+
+	return models.FileUploadPart{Number: number, Size: info.Size(), ETag: fileETag(info)}, nil
+}
+
+// ListUploadParts функция получения загруженных частей файла из локального хранилища.
+func (l *Local) ListUploadParts(ctx context.Context, objectName, uploadID string) ([]models.FileUploadPart, error) {
+	dir, err := l.uploadDir(ctx, objectName, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upload parts %w", err)
+	}
+
+	parts := make([]models.FileUploadPart, 0, len(entries))
+	for _, e := range entries {
+		number, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat part %w", err)
+		}
+
+		parts = append(parts, models.FileUploadPart{Number: number, Size: info.Size(), ETag: fileETag(info)})
+	}
+
+	return sortedParts(parts), nil
+}
+
+// CompleteUpload функция завершения загрузки файла по частям в локальное хранилище.
+// Части склеиваются в указанном порядке, после чего загрузка удаляется.
+func (l *Local) CompleteUpload(ctx context.Context, objectName, uploadID string, parts []models.FileUploadPart) error {
+	uploaded, err := l.ListUploadParts(ctx, objectName, uploadID)
+	if err != nil {
+		return err
+	}
+	if err := checkParts(parts, uploaded); err != nil {
+		return err
+	}
+
+	dir, err := l.uploadDir(ctx, objectName, uploadID)
+	if err != nil {
+		return err
+	}
+	path, err := l.filePath(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := l.createTemp(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range parts {
+		if err = appendFile(tmp, filepath.Join(dir, strconv.Itoa(p.Number))); err != nil {
+			break
+		}
+	}
+
+	if err := commitTemp(tmp, path, err); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove upload dir %w", err)
+	}
+
+	return nil
+}
+
+// AbortUpload функция отмены загрузки файла по частям в локальном хранилище.
+func (l *Local) AbortUpload(ctx context.Context, objectName, uploadID string) error {
+	dir, err := l.uploadDir(ctx, objectName, uploadID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove upload dir %w", err)
+	}
+
+	return nil
+}
+
+// userDir возвращает путь внутри каталога пользователя из контекста.
+func (l *Local) userDir(ctx context.Context, elem ...string) (string, error) {
+	id, err := userID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(append([]string{l.root, strconv.Itoa(id)}, elem...)...), nil
+}
+
+// filePath возвращает путь к файлу пользователя и создает каталог файлов пользователя.
+func (l *Local) filePath(ctx context.Context, objectName string) (string, error) {
+	if err := validateObjectName(objectName); err != nil {
+		return "", err
+	}
+
+	dir, err := l.userDir(ctx, filesDir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create user dir %w", err)
+	}
+
+	return filepath.Join(dir, objectName), nil
+}
+
+// uploadDir возвращает каталог существующей загрузки.
+func (l *Local) uploadDir(ctx context.Context, objectName, uploadID string) (string, error) {
+	if err := validateObjectName(objectName); err != nil {
+		return "", err
+	}
+	if err := uuid.Validate(uploadID); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUploadNotFound, uploadID)
+	}
+
+	dir, err := l.userDir(ctx, uploadsDir, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return "", openError(err, ErrUploadNotFound)
+	}
+
+	return dir, nil
+}
+
+// createTemp создает временный файл в каталоге пользователя, с которого файлы переименовываются на место.
+func (l *Local) createTemp(ctx context.Context) (*os.File, error) {
+	dir, err := l.userDir(ctx, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create tmp dir %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, "file-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tmp file %w", err)
+	}
+
+	return f, nil
+}
+
+// commitTemp сбрасывает временный файл на диск и переименовывает его в path.
+// Если при записи произошла ошибка writeErr, временный файл удаляется.
+func commitTemp(tmp *os.File, path string, writeErr error) error {
+	err := writeErr
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write file %w", err)
+	}
+
+	return nil
+}
+
+// appendFile дописывает содержимое файла path в dst.
+func appendFile(dst io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open part %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(dst, f); err != nil {
+		return fmt.Errorf("failed to copy part %w", err)
+	}
+
+	return nil
+}
+
+// fileETag возвращает ETag файла по времени изменения и размеру.
+func fileETag(info fs.FileInfo) string {
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())
+}
+
+// openError приводит ошибку отсутствия файла к notFound.
+func openError(err, notFound error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return notFound
+	}
+
+	return fmt.Errorf("failed to open file %w", err)
+}
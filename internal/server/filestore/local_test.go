@@ -0,0 +1,48 @@
+package filestore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	fs, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	testFileStorage(t, fs)
+}
+
+func TestNewLocal(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "files")
+
+	_, err := NewLocal(root)
+	require.NoError(t, err)
+	assert.DirExists(t, root)
+
+	_, err = NewLocal("")
+	require.Error(t, err)
+}
+
+func TestLocalLayout(t *testing.T) {
+	root := t.TempDir()
+	fs, err := NewLocal(root)
+	require.NoError(t, err)
+	ctx := userContext(7)
+
+	require.NoError(t, fs.AddFile(ctx, strings.NewReader("data"), "test.txt", 4))
+	assert.FileExists(t, filepath.Join(root, "7", "files", "test.txt"))
+
+	err = fs.AddFile(ctx, iotest.ErrReader(os.ErrClosed), "broken.txt", 4)
+	require.ErrorIs(t, err, os.ErrClosed)
+	assert.NoFileExists(t, filepath.Join(root, "7", "files", "broken.txt"))
+
+	entries, err := os.ReadDir(filepath.Join(root, "7", "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,304 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // md5 используется для ETag, как в S3
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/google/uuid"
+)
+
+// memoryFile файл или часть загрузки, хранящиеся в памяти.
+type memoryFile struct {
+	modTime time.Time
+	etag    string
+	data    []byte
+}
+
+// memoryUpload незавершенная загрузка файла по частям.
+type memoryUpload struct {
+	parts      map[int]memoryFile
+	objectName string
+	userID     int
+}
+
+// Memory структура для хранения файлов в памяти. Подходит для тестов и запуска без S3,
+// файлы теряются при перезапуске сервера.
+type Memory struct {
+	files   map[int]map[string]memoryFile
+	uploads map[string]*memoryUpload
+	mu      sync.RWMutex
+}
+
+// NewMemory функция инициализации хранилища файлов в памяти.
+func NewMemory() *Memory {
+	return &Memory{
+		files:   make(map[int]map[string]memoryFile),
+		uploads: make(map[string]*memoryUpload),
+	}
+}
+
+// AddFile функция добавления файла в хранилище в памяти. Отрицательный objectSize означает неизвестный размер.
+func (m *Memory) AddFile(ctx context.Context, file io.Reader, objectName string, objectSize int64) error {
+	id, err := m.check(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	f, err := readMemoryFile(file, objectSize)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.files[id] == nil {
+		m.files[id] = make(map[string]memoryFile)
+	}
+	m.files[id][objectName] = f
+
+	return nil
+}
+
+// GetFile функция получения файла из хранилища в памяти. Если задан rng, читается только этот диапазон байт.
+func (m *Memory) GetFile(ctx context.Context, objectName string, rng *models.FileRange) (models.File, error) {
+	f, err := m.get(ctx, objectName)
+	if err != nil {
+		return models.File{}, err
+	}
+
+	data := f.data
+	if rng != nil {
+		data = data[rng.Start : rng.End+1]
+	}
+
+	return models.File{
+		File: io.NopCloser(bytes.NewReader(data)),
+		ETag: f.etag,
+		Size: int64(len(data)),
+	}, nil
+}
+
+// StatFile функция получения размера и ETag файла из хранилища в памяти.
+func (m *Memory) StatFile(ctx context.Context, objectName string) (models.File, error) {
+	f, err := m.get(ctx, objectName)
+	if err != nil {
+		return models.File{}, err
+	}
+
+	return models.File{ETag: f.etag, Size: int64(len(f.data))}, nil
+}
+
+// DeleteFile функция удаления файла из хранилища в памяти.
+func (m *Memory) DeleteFile(ctx context.Context, objectName string) error {
+	id, err := m.check(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.files[id], objectName)
+
+	return nil
+}
+
+// ListFiles функция получения списка файлов пользователя из хранилища в памяти.
+func (m *Memory) ListFiles(ctx context.Context) ([]models.FileInfo, error) {
+	id, err := userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	files := make([]models.FileInfo, 0, len(m.files[id]))
+	for name, f := range m.files[id] {
+		files = append(files, models.FileInfo{Name: name, Size: int64(len(f.data)), ModTime: f.modTime})
+	}
+
+	return files, nil
+}
+
+// InitUpload функция начала загрузки файла по частям в хранилище в памяти. Возвращает идентификатор загрузки.
+func (m *Memory) InitUpload(ctx context.Context, objectName string) (string, error) {
+	id, err := m.check(ctx, objectName)
+	if err != nil {
+		return "", err
+	}
+
+	uploadID := uuid.NewString()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.uploads[uploadID] = &memoryUpload{userID: id, objectName: objectName, parts: make(map[int]memoryFile)}
+
+	return uploadID, nil
+}
+
+// UploadPart функция загрузки части файла в хранилище в памяти.
+func (m *Memory) UploadPart(
+	ctx context.Context,
+	objectName, uploadID string,
+	number int,
+	part io.Reader,
+	size int64,
+) (models.FileUploadPart, error) {
+	if _, err := m.upload(ctx, objectName, uploadID); err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	f, err := readMemoryFile(part, size)
+	if err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Загрузку могли отменить, пока читалась часть.
+	u, ok := m.uploads[uploadID]
+	if !ok {
+		return models.FileUploadPart{}, ErrUploadNotFound
+	}
+	u.parts[number] = f
+
+	return models.FileUploadPart{Number: number, Size: int64(len(f.data)), ETag: f.etag}, nil
+}
+
+// ListUploadParts функция получения загруженных частей файла из хранилища в памяти.
+func (m *Memory) ListUploadParts(ctx context.Context, objectName, uploadID string) ([]models.FileUploadPart, error) {
+	u, err := m.upload(ctx, objectName, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	parts := make([]models.FileUploadPart, 0, len(u.parts))
+	for number, p := range u.parts {
+		parts = append(parts, models.FileUploadPart{Number: number, Size: int64(len(p.data)), ETag: p.etag})
+	}
+
+	return sortedParts(parts), nil
+}
+
+// CompleteUpload функция завершения загрузки файла по частям в хранилище в памяти.
+func (m *Memory) CompleteUpload(ctx context.Context, objectName, uploadID string, parts []models.FileUploadPart) error {
+	uploaded, err := m.ListUploadParts(ctx, objectName, uploadID)
+	if err != nil {
+		return err
+	}
+	if err := checkParts(parts, uploaded); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.uploads[uploadID]
+	if !ok {
+		return ErrUploadNotFound
+	}
+
+	var buf bytes.Buffer
+	for _, p := range parts {
+		buf.Write(u.parts[p.Number].data)
+	}
+
+	if m.files[u.userID] == nil {
+		m.files[u.userID] = make(map[string]memoryFile)
+	}
+	m.files[u.userID][objectName] = newMemoryFile(buf.Bytes())
+	delete(m.uploads, uploadID)
+
+	return nil
+}
+
+// AbortUpload функция отмены загрузки файла по частям в хранилище в памяти.
+func (m *Memory) AbortUpload(ctx context.Context, objectName, uploadID string) error {
+	if _, err := m.upload(ctx, objectName, uploadID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.uploads, uploadID)
+
+	return nil
+}
+
+// check проверяет имя объекта и возвращает идентификатор пользователя из контекста.
+func (m *Memory) check(ctx context.Context, objectName string) (int, error) {
+	if err := validateObjectName(objectName); err != nil {
+		return 0, err
+	}
+
+	return userID(ctx)
+}
+
+func (m *Memory) get(ctx context.Context, objectName string) (memoryFile, error) {
+	id, err := m.check(ctx, objectName)
+	if err != nil {
+		return memoryFile{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, ok := m.files[id][objectName]
+	if !ok {
+		return memoryFile{}, ErrFileNotFound
+	}
+
+	return f, nil
+}
+
+// upload возвращает загрузку пользователя из контекста.
+func (m *Memory) upload(ctx context.Context, objectName, uploadID string) (*memoryUpload, error) {
+	id, err := m.check(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.uploads[uploadID]
+	if !ok || u.userID != id || u.objectName != objectName {
+		return nil, ErrUploadNotFound
+	}
+
+	return u, nil
+}
+
+// readMemoryFile читает данные и проверяет их размер, отрицательный size означает неизвестный размер.
+func readMemoryFile(r io.Reader, size int64) (memoryFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return memoryFile{}, fmt.Errorf("failed to read file %w", err)
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return memoryFile{}, fmt.Errorf("%w: read %d of %d bytes", ErrInvalidFileSize, len(data), size)
+	}
+
+	return newMemoryFile(data), nil
+}
+
+func newMemoryFile(data []byte) memoryFile {
+	sum := md5.Sum(data) //nolint:gosec // md5 используется для ETag, как в S3
+
+	return memoryFile{data: data, etag: hex.EncodeToString(sum[:]), modTime: time.Now()}
+}
@@ -0,0 +1,9 @@
+package filestore
+
+import (
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	testFileStorage(t, NewMemory())
+}
@@ -15,7 +15,7 @@ import (
 // AddCard обработчик для добавления данных карты пользователя.
 func (h *Handlers) AddCard() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req models.AddCardRequest
+		var req models.AddSecretRequest
 
 		dec := json.NewDecoder(r.Body)
 		if err := dec.Decode(&req); err != nil {
@@ -24,8 +24,16 @@ func (h *Handlers) AddCard() http.HandlerFunc {
 			return
 		}
 
-		id, err := h.services.AddCard(r.Context(), &req)
+		id, err := h.services.AddCard(r.Context(), req)
 		if err != nil {
+			if writeQuotaError(w, err) {
+				return
+			}
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 			h.logger.Error("failed to add card", zap.Error(err))
 			return
@@ -77,3 +85,69 @@ func (h *Handlers) GetCard() http.HandlerFunc {
 		}
 	}
 }
+
+// UpdateCard обработчик для полной замены данных карты пользователя.
+func (h *Handlers) UpdateCard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "cardID")
+		cardID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed card ID param", zap.Error(err))
+			return
+		}
+
+		var req models.AddSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.UpdateCard(r.Context(), cardID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to update card")
+	}
+}
+
+// PatchCard обработчик для частичного обновления данных карты пользователя.
+func (h *Handlers) PatchCard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "cardID")
+		cardID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed card ID param", zap.Error(err))
+			return
+		}
+
+		var req models.UpdateSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.PatchCard(r.Context(), cardID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to patch card")
+	}
+}
+
+// DeleteCard обработчик для удаления данных карты пользователя.
+func (h *Handlers) DeleteCard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "cardID")
+		cardID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed card ID param", zap.Error(err))
+			return
+		}
+
+		err = h.services.DeleteCard(r.Context(), cardID)
+		h.writeChangeResult(w, err, http.StatusNoContent, "failed to delete card")
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
-	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
 	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
@@ -28,14 +27,9 @@ func TestAddCard(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	requestBody := `
-	{"number":"1234123412341234","owner":"test","expiry_date":"11/2300","cvv2":"777","mark":"test","description":"test"}
-	`
-	requestObject := models.AddCardRequest{
-		Number:      "1234123412341234",
-		Owner:       "test",
-		ExpiryDate:  "11/2300",
-		CVV2:        "777",
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
 		Mark:        "test",
 		Description: "test",
 	}
@@ -88,7 +82,7 @@ func TestAddCard(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			s.EXPECT().
-				AddCard(gomock.Any(), &requestObject).
+				AddCard(gomock.Any(), requestObject).
 				Times(1).
 				Return(test.serviceResponse.id, test.serviceResponse.err)
 
@@ -120,7 +114,7 @@ func TestAddCardFailedReadBody(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	requestBody := `{"number":"1234123412341234","mark":"test","description":"test",adasd}`
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test",adasd}`
 
 	t.Run("failed to read request body", func(t *testing.T) {
 		s.EXPECT().AddCard(gomock.Any(), gomock.Any()).Times(0)
@@ -145,8 +139,7 @@ func TestGetCard(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
 	cardID := 1
@@ -156,7 +149,7 @@ func TestGetCard(t *testing.T) {
 	defer ts.Close()
 
 	type serviceResponse struct {
-		res models.Card
+		res models.Secret
 		err error
 	}
 
@@ -175,12 +168,9 @@ func TestGetCard(t *testing.T) {
 		{
 			name: "get card success",
 			serviceResponse: serviceResponse{
-				res: models.Card{
+				res: models.Secret{
 					ID:          1,
-					Number:      "1234123412341234",
-					Owner:       "test",
-					ExpiryDate:  "11/2300",
-					CVV2:        "777",
+					Data:        []byte("test"),
 					Mark:        "test",
 					Description: "test",
 				},
@@ -188,7 +178,7 @@ func TestGetCard(t *testing.T) {
 			},
 			want: want{
 				code:          http.StatusOK,
-				body:          `{"number":"1234123412341234","owner":"test","expiry_date":"11/2300","cvv2":"777","mark":"test","description":"test","id":1}` + "\n", //nolint:lll // Исключение
+				body:          `{"data":"dGVzdA==","mark":"test","description":"test","id":1}` + "\n",
 				errorLogTimes: 0,
 				log:           "",
 			},
@@ -196,7 +186,7 @@ func TestGetCard(t *testing.T) {
 		{
 			name: "card no found",
 			serviceResponse: serviceResponse{
-				res: models.Card{},
+				res: models.Secret{},
 				err: services.ErrNotFound,
 			},
 			want: want{
@@ -209,7 +199,7 @@ func TestGetCard(t *testing.T) {
 		{
 			name: "get card failed with some error",
 			serviceResponse: serviceResponse{
-				res: models.Card{},
+				res: models.Secret{},
 				err: errors.New("some error"),
 			},
 			want: want{
@@ -227,7 +217,7 @@ func TestGetCard(t *testing.T) {
 				Return(test.serviceResponse.res, test.serviceResponse.err)
 
 			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
-			storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
 			res, resBody := testGetRequest(t, ts, "/api/user/cards/1")
 			closeBody(t, res)
@@ -246,8 +236,7 @@ func TestGetCardFailedReadParam(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
 	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
@@ -257,7 +246,7 @@ func TestGetCardFailedReadParam(t *testing.T) {
 	t.Run("failed to read request param", func(t *testing.T) {
 		s.EXPECT().GetCard(gomock.Any(), gomock.Any()).Times(0)
 		l.EXPECT().Error("failed card ID param", gomock.Any()).Times(1)
-		storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+		storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
 		res, _ := testGetRequest(t, ts, "/api/user/cards/adasd")
 		closeBody(t, res)
@@ -265,3 +254,240 @@ func TestGetCardFailedReadParam(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
 }
+
+func TestUpdateCard(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	cardID := 1
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "update card success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "card no found",
+			serviceErr: services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "update card failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to update card",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().UpdateCard(gomock.Any(), cardID, requestObject).Times(1).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPut, "/api/user/cards/1", requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestPatchCard(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	mark := "new"
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		requestBody  string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "patch card success",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "patch card failed with some error",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to patch card",
+			},
+		},
+		{
+			name:         "failed to read request body",
+			requestBody:  `{"mark":"new",adasd}`,
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed to read request body",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().PatchCard(gomock.Any(), 1, models.UpdateSecretRequest{Mark: &mark}).
+				Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPatch, "/api/user/cards/1", test.requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestDeleteCard(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "delete card success",
+			path:         "/api/user/cards/1",
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusNoContent,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "card no found",
+			path:         "/api/user/cards/1",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "failed to read request param",
+			path:         "/api/user/cards/adasd",
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed card ID param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteCard(gomock.Any(), 1).Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodDelete, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
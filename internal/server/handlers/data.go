@@ -2,14 +2,38 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
 	"go.uber.org/zap"
 )
 
+// searchParams параметры поиска данных пользователя.
+var searchParams = []string{
+	"type", "mark", "search", "sort", "tag", "folder", "limit", "offset",
+	"created_from", "created_to", "updated_from", "updated_to",
+}
+
 // FetchUserData обработчик для получения базовой информации о данных пользователя.
+// С параметром since возвращаются только изменения после курсора синхронизации,
+// с параметрами поиска - страница найденных данных.
 func (h *Handlers) FetchUserData() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("since") {
+			h.fetchUserDataChanges(w, r)
+			return
+		}
+		if hasSearchParams(r.URL.Query()) {
+			h.searchUserData(w, r)
+			return
+		}
+
 		data, err := h.services.FetchUserData(r.Context())
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -33,3 +57,116 @@ func (h *Handlers) FetchUserData() http.HandlerFunc {
 		}
 	}
 }
+
+// fetchUserDataChanges отвечает изменениями данных пользователя после курсора из параметра since.
+func (h *Handlers) fetchUserDataChanges(w http.ResponseWriter, r *http.Request) {
+	since, err := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+	if err != nil || since < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		h.logger.Error("failed since param", zap.Error(err))
+		return
+	}
+
+	changes, err := h.services.FetchUserDataChanges(r.Context(), since)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.Error("failed to fetch user data changes from DB", zap.Error(err))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, changes)
+}
+
+// searchUserData отвечает страницей данных пользователя, найденных по параметрам поиска.
+func (h *Handlers) searchUserData(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseUserDataFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.logger.Error("failed search params", zap.Error(err))
+		return
+	}
+
+	page, err := h.services.SearchUserData(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFilter) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.Error("failed to search user data", zap.Error(err))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, page)
+}
+
+// ReserveDataID обработчик для резервирования ID данных пользователя до их добавления.
+func (h *Handlers) ReserveDataID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := h.services.ReserveDataID(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to reserve data id", zap.Error(err))
+			return
+		}
+
+		h.writeJSON(w, http.StatusCreated, models.AddResponse{ID: id})
+	}
+}
+
+// hasSearchParams проверяет, передан ли хотя бы один параметр поиска.
+func hasSearchParams(q url.Values) bool {
+	for _, p := range searchParams {
+		if q.Has(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseUserDataFilter разбирает параметры поиска данных пользователя. Время передается в формате RFC 3339.
+func parseUserDataFilter(q url.Values) (models.UserDataFilter, error) {
+	filter := models.UserDataFilter{
+		Type:       q.Get("type"),
+		MarkPrefix: q.Get("mark"),
+		Search:     q.Get("search"),
+		Sort:       q.Get("sort"),
+		Tag:        q.Get("tag"),
+		Folder:     q.Get("folder"),
+	}
+
+	ints := map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset}
+	for name, dst := range ints {
+		if !q.Has(name) {
+			continue
+		}
+
+		v, err := strconv.Atoi(q.Get(name))
+		if err != nil {
+			return filter, fmt.Errorf("failed %s param: %w", name, err)
+		}
+		*dst = v
+	}
+
+	times := map[string]*time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+		"updated_from": &filter.UpdatedFrom,
+		"updated_to":   &filter.UpdatedTo,
+	}
+	for name, dst := range times {
+		if !q.Has(name) {
+			continue
+		}
+
+		v, err := time.Parse(time.RFC3339, q.Get(name))
+		if err != nil {
+			return filter, fmt.Errorf("failed %s param: %w", name, err)
+		}
+		*dst = v
+	}
+
+	return filter, nil
+}
@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +54,7 @@ func TestFetchUserData(t *testing.T) {
 						Type:        "card",
 						Mark:        "Mark",
 						Description: "Description",
+						Version:     2,
 					},
 				},
 				err: nil,
@@ -59,7 +62,7 @@ func TestFetchUserData(t *testing.T) {
 			want: want{
 				code:          http.StatusOK,
 				contentType:   JSONContentType,
-				body:          "[{\"mark\":\"Mark\",\"description\":\"Description\",\"type\":\"card\",\"id\":1}]\n",
+				body:          "[{\"mark\":\"Mark\",\"description\":\"Description\",\"type\":\"card\",\"id\":1,\"version\":2}]\n",
 				errorLogTimes: 0,
 				log:           "",
 			},
@@ -115,3 +118,219 @@ func TestFetchUserData(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchUserDataChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	errSome := errors.New("some error")
+	changes := models.UserDataChanges{
+		Changes: []models.UserData{{ID: 1, Type: "card", Mark: "Mark", Version: 2}},
+		Deleted: []int{3},
+		Cursor:  10,
+	}
+
+	t.Run("fetch user data changes success", func(t *testing.T) {
+		s.EXPECT().FetchUserDataChanges(gomock.Any(), int64(5)).Times(1).Return(changes, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/data?since=5", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.FetchUserData()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, JSONContentType, res.Header.Get(ContentTypeHeader))
+
+		resBody, err := io.ReadAll(res.Body)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"changes": [{"mark":"Mark","description":"","type":"card","id":1,"version":2}],
+			"deleted": [3],
+			"cursor": 10,
+			"full": false
+		}`, string(resBody))
+	})
+
+	t.Run("fetch user data changes failed", func(t *testing.T) {
+		s.EXPECT().FetchUserDataChanges(gomock.Any(), int64(0)).Times(1).Return(models.UserDataChanges{}, errSome)
+		l.EXPECT().Error("failed to fetch user data changes from DB", zap.Error(errSome)).Times(1)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/data?since=0", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.FetchUserData()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("invalid since param", func(t *testing.T) {
+		s.EXPECT().FetchUserDataChanges(gomock.Any(), gomock.Any()).Times(0)
+		l.EXPECT().Error("failed since param", gomock.Any()).Times(1)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/data?since=abc", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.FetchUserData()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestSearchUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	errSome := errors.New("some error")
+	changedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	page := models.UserDataPage{
+		Items: []models.UserDataItem{
+			{ID: 1, Type: "card", Mark: "aws", Version: 2, CreatedAt: changedAt, UpdatedAt: changedAt},
+		},
+		Total: 3,
+	}
+
+	t.Run("search user data success", func(t *testing.T) {
+		filter := models.UserDataFilter{
+			Type:        "card",
+			MarkPrefix:  "aw",
+			Search:      "root",
+			Sort:        "-updated",
+			Tag:         "prod",
+			Folder:      "work/aws",
+			Limit:       20,
+			Offset:      10,
+			CreatedFrom: changedAt,
+			UpdatedTo:   changedAt,
+		}
+		s.EXPECT().SearchUserData(gomock.Any(), filter).Times(1).Return(page, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/data?type=card&mark=aw&search=root&sort=-updated"+
+			"&tag=prod&folder=work/aws"+
+			"&limit=20&offset=10&created_from=2024-01-02T03:04:05Z&updated_to=2024-01-02T03:04:05Z", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.FetchUserData()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		resBody, err := io.ReadAll(res.Body)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"items": [{
+				"created_at": "2024-01-02T03:04:05Z",
+				"updated_at": "2024-01-02T03:04:05Z",
+				"mark": "aws",
+				"description": "",
+				"type": "card",
+				"id": 1,
+				"version": 2
+			}],
+			"total": 3
+		}`, string(resBody))
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		s.EXPECT().SearchUserData(gomock.Any(), models.UserDataFilter{Type: "note"}).Times(1).
+			Return(models.UserDataPage{}, services.ErrInvalidFilter)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/data?type=note", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.FetchUserData()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("search user data failed", func(t *testing.T) {
+		s.EXPECT().SearchUserData(gomock.Any(), models.UserDataFilter{Limit: 5}).Times(1).
+			Return(models.UserDataPage{}, errSome)
+		l.EXPECT().Error("failed to search user data", zap.Error(errSome)).Times(1)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/data?limit=5", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.FetchUserData()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	for _, query := range []string{"limit=abc", "offset=1.5", "created_to=yesterday"} {
+		t.Run("invalid param "+query, func(t *testing.T) {
+			s.EXPECT().SearchUserData(gomock.Any(), gomock.Any()).Times(0)
+			l.EXPECT().Error("failed search params", gomock.Any()).Times(1)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/user/data?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			handlers.FetchUserData()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		})
+	}
+}
+
+func TestReserveDataID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	t.Run("reserve data id success", func(t *testing.T) {
+		s.EXPECT().ReserveDataID(gomock.Any()).Times(1).Return(7, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/user/data/ids", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.ReserveDataID()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+		resBody, err := io.ReadAll(res.Body)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":7}`, string(resBody))
+	})
+
+	t.Run("reserve data id failed", func(t *testing.T) {
+		errSome := errors.New("some error")
+		s.EXPECT().ReserveDataID(gomock.Any()).Times(1).Return(0, errSome)
+		l.EXPECT().Error("failed to reserve data id", zap.Error(errSome)).Times(1)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/user/data/ids", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.ReserveDataID()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+}
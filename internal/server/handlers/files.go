@@ -3,8 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
@@ -36,6 +40,8 @@ func (h *Handlers) AddFile() http.HandlerFunc {
 		req := models.AddFileRequest{
 			Mark:        r.PostFormValue("mark"),
 			Description: r.PostFormValue("description"),
+			Folder:      r.PostFormValue("folder"),
+			Tags:        r.PostForm["tag"],
 			File:        file,
 			FileName:    header.Filename,
 			FileSize:    header.Size,
@@ -43,6 +49,10 @@ func (h *Handlers) AddFile() http.HandlerFunc {
 
 		id, err := h.services.AddFile(r.Context(), req)
 		if err != nil {
+			if writeQuotaError(w, err) {
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 			h.logger.Error("failed to add file", zap.Error(err))
 			return
@@ -61,19 +71,35 @@ func (h *Handlers) AddFile() http.HandlerFunc {
 }
 
 // GetFile обработчик для получения конкретного файла пользователя.
+// Поддерживает запрос одного диапазона байт через заголовки Range и If-Range.
 func (h *Handlers) GetFile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		fileMark := chi.URLParam(r, "fileMark")
+		id := chi.URLParam(r, "fileID")
+		fileID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed file ID param", zap.Error(err))
+			return
+		}
+
+		req := models.GetFileRequest{
+			Range:   parseRange(r.Header.Get(RangeHeader)),
+			IfRange: r.Header.Get(IfRangeHeader),
+		}
 
-		file, err := h.services.GetFile(r.Context(), fileMark)
+		file, err := h.services.GetFile(r.Context(), fileID, req)
 		if err != nil {
-			if errors.Is(err, services.ErrNotFound) {
+			var rangeErr *services.RangeNotSatisfiableError
+			switch {
+			case errors.Is(err, services.ErrNotFound):
 				w.WriteHeader(http.StatusNotFound)
-				return
+			case errors.As(err, &rangeErr):
+				w.Header().Set(ContentRangeHeader, fmt.Sprintf("bytes */%d", rangeErr.Size))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				h.logger.Error("failed to get file", zap.Error(err))
 			}
-
-			w.WriteHeader(http.StatusInternalServerError)
-			h.logger.Error("failed to get file", zap.Error(err))
 			return
 		}
 		defer func() {
@@ -82,20 +108,155 @@ func (h *Handlers) GetFile() http.HandlerFunc {
 			}
 		}()
 
-		fileBytes, err := io.ReadAll(file.File)
+		status := http.StatusOK
+		length := file.Size
+		if file.Range != nil {
+			status = http.StatusPartialContent
+			length = file.Range.End - file.Range.Start + 1
+			w.Header().Set(ContentRangeHeader,
+				fmt.Sprintf("bytes %d-%d/%d", file.Range.Start, file.Range.End, file.Size))
+		}
+
+		if length > 0 {
+			w.Header().Set(ContentLengthHeader, strconv.FormatInt(length, 10))
+		}
+		if file.FileName != "" {
+			w.Header().Set(ContentDispositionHeader,
+				mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}))
+		}
+		if file.ETag != "" {
+			w.Header().Set(ETagHeader, strconv.Quote(strings.Trim(file.ETag, `"`)))
+		}
+		w.Header().Set(AcceptRangesHeader, "bytes")
+		w.Header().Set(ContentTypeHeader, OctetStreamContentType)
+		w.WriteHeader(status)
+
+		// Заголовки уже отправлены, поэтому ошибку копирования можно только залогировать.
+		if _, err := io.Copy(w, file.File); err != nil {
+			h.logger.Error("failed to write file data", zap.Error(err))
+			return
+		}
+	}
+}
+
+// parseRange разбирает заголовок Range с одним диапазоном байт.
+// Для пустого, некорректного или составного заголовка возвращается nil и файл отдается целиком.
+func parseRange(header string) *models.FileRange {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return nil
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil
+	}
+
+	if first == "" {
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix <= 0 {
+			return nil
+		}
+		return &models.FileRange{Start: -1, End: suffix}
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return nil
+	}
+
+	if last == "" {
+		return &models.FileRange{Start: start, End: -1}
+	}
+
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return nil
+	}
+
+	return &models.FileRange{Start: start, End: end}
+}
+
+// UpdateFile обработчик для замены файла пользователя. Папка и метки файла при замене не меняются,
+// для их изменения используется PatchFile.
+func (h *Handlers) UpdateFile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "fileID")
+		fileID, err := strconv.Atoi(id)
 		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed file ID param", zap.Error(err))
+			return
+		}
+
+		if err := r.ParseMultipartForm(10 << 20); err != nil { //nolint:gomnd // так нагляднее
 			w.WriteHeader(http.StatusInternalServerError)
-			h.logger.Error("failed to prepare file", zap.Error(err))
+			h.logger.Error("failed to parse file", zap.Error(err))
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/octet-stream")
-		w.WriteHeader(http.StatusOK)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed to retrieving the file", zap.Error(err))
+			return
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				h.logger.Error("failed to close file", zap.Error(err))
+			}
+		}()
 
-		if _, err := w.Write(fileBytes); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			h.logger.Error("failed to write file data", zap.Error(err))
+		req := models.AddFileRequest{
+			Mark:        r.PostFormValue("mark"),
+			Description: r.PostFormValue("description"),
+			File:        file,
+			FileName:    header.Filename,
+			FileSize:    header.Size,
+		}
+
+		err = h.services.UpdateFile(r.Context(), fileID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to update file")
+	}
+}
+
+// PatchFile обработчик для обновления пометки и описания файла пользователя.
+func (h *Handlers) PatchFile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "fileID")
+		fileID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed file ID param", zap.Error(err))
+			return
+		}
+
+		var req models.UpdateFileRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
 			return
 		}
+
+		err = h.services.PatchFile(r.Context(), fileID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to patch file")
+	}
+}
+
+// DeleteFile обработчик для удаления файла пользователя.
+func (h *Handlers) DeleteFile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "fileID")
+		fileID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed file ID param", zap.Error(err))
+			return
+		}
+
+		err = h.services.DeleteFile(r.Context(), fileID)
+		h.writeChangeResult(w, err, http.StatusNoContent, "failed to delete file")
 	}
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"mime/multipart"
@@ -13,7 +14,6 @@ import (
 	"testing"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
-	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
 	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
@@ -89,7 +89,11 @@ func TestAddFile(t *testing.T) {
 			s.EXPECT().
 				AddFile(gomock.Any(), gomock.Any()).
 				Times(1).
-				Return(test.serviceResponse.id, test.serviceResponse.err)
+				DoAndReturn(func(_ context.Context, req models.AddFileRequest) (int, error) {
+					assert.Equal(t, "work/aws", req.Folder)
+					assert.Equal(t, []string{"prod", "team"}, req.Tags)
+					return test.serviceResponse.id, test.serviceResponse.err
+				})
 
 			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
 
@@ -110,6 +114,12 @@ func TestAddFile(t *testing.T) {
 			require.NoError(t, err)
 			err = writer.WriteField("description", "test")
 			require.NoError(t, err)
+			err = writer.WriteField("folder", "work/aws")
+			require.NoError(t, err)
+			for _, tag := range []string{"prod", "team"} {
+				err = writer.WriteField("tag", tag)
+				require.NoError(t, err)
+			}
 
 			err = writer.Close()
 			require.NoError(t, err)
@@ -234,11 +244,10 @@ func TestGetFile(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
-	fileMark := "test"
+	fileID := 1
 
 	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
 	ts := httptest.NewServer(r)
@@ -252,9 +261,13 @@ func TestGetFile(t *testing.T) {
 		code          int
 		errorLogTimes int
 		log           string
+		headers       map[string]string
+		body          string
 	}
 	tests := []struct {
 		name            string
+		headers         map[string]string
+		req             models.GetFileRequest
 		serviceResponse serviceResponse
 		want            want
 	}{
@@ -262,7 +275,10 @@ func TestGetFile(t *testing.T) {
 			name: "get file success",
 			serviceResponse: serviceResponse{
 				res: models.File{
-					File: io.NopCloser(strings.NewReader("some data")),
+					File:     io.NopCloser(strings.NewReader("some data")),
+					FileName: "отчёт 2024.pdf",
+					ETag:     "d41d8cd98f00b204e9800998ecf8427e",
+					Size:     9,
 				},
 				err: nil,
 			},
@@ -270,6 +286,56 @@ func TestGetFile(t *testing.T) {
 				code:          http.StatusOK,
 				errorLogTimes: 0,
 				log:           "",
+				headers: map[string]string{
+					"Content-Type":        "application/octet-stream",
+					"Content-Length":      "9",
+					"Content-Disposition": "attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D1%91%D1%82%202024.pdf",
+					"ETag":                `"d41d8cd98f00b204e9800998ecf8427e"`,
+				},
+				body: "some data",
+			},
+		},
+		{
+			name:    "get file range success",
+			headers: map[string]string{"Range": "bytes=5-", "If-Range": `"etag"`},
+			req: models.GetFileRequest{
+				Range:   &models.FileRange{Start: 5, End: -1},
+				IfRange: `"etag"`,
+			},
+			serviceResponse: serviceResponse{
+				res: models.File{
+					File:  io.NopCloser(strings.NewReader("data")),
+					Range: &models.FileRange{Start: 5, End: 8},
+					ETag:  "etag",
+					Size:  9,
+				},
+				err: nil,
+			},
+			want: want{
+				code:          http.StatusPartialContent,
+				errorLogTimes: 0,
+				log:           "",
+				headers: map[string]string{
+					"Content-Length": "4",
+					"Content-Range":  "bytes 5-8/9",
+					"Accept-Ranges":  "bytes",
+				},
+				body: "data",
+			},
+		},
+		{
+			name:    "range not satisfiable",
+			headers: map[string]string{"Range": "bytes=20-"},
+			req:     models.GetFileRequest{Range: &models.FileRange{Start: 20, End: -1}},
+			serviceResponse: serviceResponse{
+				res: models.File{},
+				err: &services.RangeNotSatisfiableError{Size: 9},
+			},
+			want: want{
+				code:          http.StatusRequestedRangeNotSatisfiable,
+				errorLogTimes: 0,
+				log:           "",
+				headers:       map[string]string{"Content-Range": "bytes */9"},
 			},
 		},
 		{
@@ -299,16 +365,233 @@ func TestGetFile(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			s.EXPECT().GetFile(gomock.Any(), fileMark).Times(1).
+			s.EXPECT().GetFile(gomock.Any(), fileID, test.req).Times(1).
 				Return(test.serviceResponse.res, test.serviceResponse.err)
 
 			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
-			storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
-			res, _ := testGetRequest(t, ts, "/api/user/files/test")
+			res, body := testRequestWithHeaders(t, ts, "/api/user/files/1", test.headers)
 			closeBody(t, res)
 
 			assert.Equal(t, test.want.code, res.StatusCode)
+			for k, v := range test.want.headers {
+				assert.Equal(t, v, res.Header.Get(k))
+			}
+			if test.want.body != "" {
+				assert.Equal(t, test.want.body, body)
+			}
+		})
+	}
+
+	t.Run("invalid file id", func(t *testing.T) {
+		s.EXPECT().GetFile(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		l.EXPECT().Error("failed file ID param", gomock.Any()).Times(1)
+		storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+		res, _ := testGetRequest(t, ts, "/api/user/files/test")
+		closeBody(t, res)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   *models.FileRange
+	}{
+		{name: "empty header", header: "", want: nil},
+		{name: "closed range", header: "bytes=0-499", want: &models.FileRange{Start: 0, End: 499}},
+		{name: "open range", header: "bytes=500-", want: &models.FileRange{Start: 500, End: -1}},
+		{name: "suffix range", header: "bytes=-500", want: &models.FileRange{Start: -1, End: 500}},
+		{name: "multiple ranges", header: "bytes=0-1,5-6", want: nil},
+		{name: "wrong unit", header: "items=0-1", want: nil},
+		{name: "end before start", header: "bytes=5-1", want: nil},
+		{name: "not a number", header: "bytes=a-b", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, parseRange(test.header))
+		})
+	}
+}
+
+func TestUpdateFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "update file success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "file no found",
+			serviceErr: services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "update file failed",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to update file",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().UpdateFile(gomock.Any(), 1, gomock.Any()).Times(1).Return(test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			part, err := writer.CreateFormFile("file", "test.txt")
+			require.NoError(t, err)
+			_, err = part.Write([]byte("some data"))
+			require.NoError(t, err)
+
+			err = writer.WriteField("mark", "test")
+			require.NoError(t, err)
+			err = writer.Close()
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPut, "/api/user/files/1", body)
+			request.Header.Add("Content-Type", writer.FormDataContentType())
+			request = withURLParam(request, "fileID", "1")
+
+			w := httptest.NewRecorder()
+			handlers.UpdateFile()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestPatchFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	description := "new"
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		code       int
+		logTimes   int
+	}{
+		{
+			name:       "patch file success",
+			serviceErr: nil,
+			code:       http.StatusOK,
+			logTimes:   0,
+		},
+		{
+			name:       "patch file failed",
+			serviceErr: errors.New("some error"),
+			code:       http.StatusInternalServerError,
+			logTimes:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().PatchFile(gomock.Any(), 1, models.UpdateFileRequest{Description: &description}).
+				Times(1).Return(test.serviceErr)
+			l.EXPECT().Error("failed to patch file", zap.Error(test.serviceErr)).Times(test.logTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPatch, "/api/user/files/1", `{"description":"new"}`)
+			closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
+
+func TestDeleteFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		code       int
+	}{
+		{
+			name:       "delete file success",
+			serviceErr: nil,
+			code:       http.StatusNoContent,
+		},
+		{
+			name:       "file no found",
+			serviceErr: services.ErrNotFound,
+			code:       http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteFile(gomock.Any(), 1).Times(1).Return(test.serviceErr)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodDelete, "/api/user/files/1", "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
 		})
 	}
 }
@@ -2,17 +2,32 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
-
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/ratelimit"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 var (
-	encRespErrStr     = "error encoding response"
-	readReqErrStr     = "failed to read request body"
-	ContentTypeHeader = "Content-Type"
-	JSONContentType   = "application/json"
+	encRespErrStr            = "error encoding response"
+	readReqErrStr            = "failed to read request body"
+	ContentTypeHeader        = "Content-Type"
+	ContentLengthHeader      = "Content-Length"
+	ContentDispositionHeader = "Content-Disposition"
+	ETagHeader               = "ETag"
+	AcceptRangesHeader       = "Accept-Ranges"
+	ContentRangeHeader       = "Content-Range"
+	RangeHeader              = "Range"
+	IfRangeHeader            = "If-Range"
+	JSONContentType          = "application/json"
+	OctetStreamContentType   = "application/octet-stream"
+	RetryAfterHeader         = "Retry-After"
 )
 
 // Handlers структура для работы с обработчиками HTTP запросов приложения.
@@ -26,15 +41,57 @@ type Servicer interface {
 	Ping(ctx context.Context) error
 	RegisterUser(ctx context.Context, req models.RegisterUserRequest) error
 	CreateUserToken(ctx context.Context, req models.CreateUserTokenRequest) (models.CreateUserTokenResponse, error)
+	RefreshUserToken(ctx context.Context, req models.RefreshUserTokenRequest) (models.CreateUserTokenResponse, error)
+	LoginTwoFactor(ctx context.Context, req models.LoginTwoFactorRequest) (models.CreateUserTokenResponse, error)
+	EnrollTwoFactor(ctx context.Context) (models.EnrollTwoFactorResponse, error)
+	VerifyTwoFactor(ctx context.Context, req models.VerifyTwoFactorRequest) (models.VerifyTwoFactorResponse, error)
+	LogoutUser(ctx context.Context) error
+	GetSessions(ctx context.Context) ([]models.Session, error)
+	GetUsage(ctx context.Context) (models.UserUsage, error)
+	RevokeSession(ctx context.Context, sessionID string) error
 	FetchUserData(ctx context.Context) ([]models.UserData, error)
-	AddPassword(ctx context.Context, req models.AddPasswordRequest) (int, error)
-	GetPassword(ctx context.Context, id int) (models.Password, error)
-	AddCard(ctx context.Context, req *models.AddCardRequest) (int, error)
-	GetCard(ctx context.Context, id int) (models.Card, error)
-	AddText(ctx context.Context, req models.AddTextRequest) (int, error)
-	GetText(ctx context.Context, id int) (models.Text, error)
+	FetchUserDataChanges(ctx context.Context, since int64) (models.UserDataChanges, error)
+	ReserveDataID(ctx context.Context) (int, error)
+	SearchUserData(ctx context.Context, filter models.UserDataFilter) (models.UserDataPage, error)
+	FetchHistory(ctx context.Context, id int, dataType string) ([]models.UserDataRevision, error)
+	RestoreRevision(ctx context.Context, id int, version int, dataType string) error
+	FetchTrash(ctx context.Context) ([]models.TrashItem, error)
+	RestoreTrash(ctx context.Context, id int) error
+	EmptyTrash(ctx context.Context) (int, error)
+	AddPassword(ctx context.Context, req models.AddSecretRequest) (int, error)
+	GetPassword(ctx context.Context, id int) (models.Secret, error)
+	UpdatePassword(ctx context.Context, id int, req models.AddSecretRequest) error
+	PatchPassword(ctx context.Context, id int, req models.UpdateSecretRequest) error
+	DeletePassword(ctx context.Context, id int) error
+	AddCard(ctx context.Context, req models.AddSecretRequest) (int, error)
+	GetCard(ctx context.Context, id int) (models.Secret, error)
+	UpdateCard(ctx context.Context, id int, req models.AddSecretRequest) error
+	PatchCard(ctx context.Context, id int, req models.UpdateSecretRequest) error
+	DeleteCard(ctx context.Context, id int) error
+	AddText(ctx context.Context, req models.AddSecretRequest) (int, error)
+	GetText(ctx context.Context, id int) (models.Secret, error)
+	UpdateText(ctx context.Context, id int, req models.AddSecretRequest) error
+	PatchText(ctx context.Context, id int, req models.UpdateSecretRequest) error
+	DeleteText(ctx context.Context, id int) error
+	AddOTP(ctx context.Context, req models.AddSecretRequest) (int, error)
+	GetOTP(ctx context.Context, id int) (models.Secret, error)
+	UpdateOTP(ctx context.Context, id int, req models.AddSecretRequest) error
+	PatchOTP(ctx context.Context, id int, req models.UpdateSecretRequest) error
+	DeleteOTP(ctx context.Context, id int) error
+	GetVault(ctx context.Context) (models.Vault, error)
+	SetVaultCheck(ctx context.Context, req models.UpdateVaultRequest) error
 	AddFile(ctx context.Context, req models.AddFileRequest) (int, error)
-	GetFile(ctx context.Context, fileMark string) (models.File, error)
+	GetFile(ctx context.Context, id int, req models.GetFileRequest) (models.File, error)
+	UpdateFile(ctx context.Context, id int, req models.AddFileRequest) error
+	PatchFile(ctx context.Context, id int, req models.UpdateFileRequest) error
+	DeleteFile(ctx context.Context, id int) error
+	InitFileUpload(ctx context.Context, req models.InitFileUploadRequest) (models.FileUploadStatus, error)
+	GetFileUpload(ctx context.Context, id string) (models.FileUploadStatus, error)
+	UploadFilePart(
+		ctx context.Context, id string, number int, part io.Reader, size int64,
+	) (models.FileUploadPart, error)
+	CompleteFileUpload(ctx context.Context, id string) (int, error)
+	AbortFileUpload(ctx context.Context, id string) error
 }
 
 // Logger интерфейс для логгера приложения.
@@ -49,3 +106,66 @@ func NewHandlers(services Servicer, logger Logger) *Handlers {
 		logger:   logger,
 	}
 }
+
+// writeChangeResult записывает статус ответа на запрос изменения или удаления данных.
+func (h *Handlers) writeChangeResult(w http.ResponseWriter, err error, successStatus int, errMsg string) {
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrVersionConflict) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if writeQuotaError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.Error(errMsg, zap.Error(err))
+		return
+	}
+
+	w.WriteHeader(successStatus)
+}
+
+// writeQuotaError записывает статус ответа, если данные отклонены из-за ограничений хранилища пользователя.
+// Возвращает false, если ошибка не связана с ограничениями.
+func writeQuotaError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrFileTooBig):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrQuotaExceeded):
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// writeJSON записывает ответ со статусом status и телом resp в формате JSON.
+func (h *Handlers) writeJSON(w http.ResponseWriter, status int, resp any) {
+	w.Header().Set(ContentTypeHeader, JSONContentType)
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(resp); err != nil {
+		h.logger.Error(encRespErrStr, zap.Error(err))
+	}
+}
+
+// writeTooManyAttempts отвечает 429 с заголовком Retry-After, если вход заблокирован
+// после неудачных попыток. Возвращает false для остальных ошибок.
+func writeTooManyAttempts(w http.ResponseWriter, err error) bool {
+	var retryErr *services.RetryAfterError
+	if !errors.As(err, &retryErr) {
+		return false
+	}
+
+	w.Header().Set(RetryAfterHeader, ratelimit.RetryAfter(retryErr.RetryAfter))
+	w.WriteHeader(http.StatusTooManyRequests)
+
+	return true
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GetHistory обработчик для получения списка прежних версий данных пользователя типа dataType.
+// ID данных берется из параметра пути idParam.
+func (h *Handlers) GetHistory(dataType, idParam string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, idParam))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed "+dataType+" ID param", zap.Error(err))
+			return
+		}
+
+		revisions, err := h.services.FetchHistory(r.Context(), id, dataType)
+		if err != nil {
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to fetch "+dataType+" history", zap.Error(err))
+			return
+		}
+
+		h.writeJSON(w, http.StatusOK, revisions)
+	}
+}
+
+// RestoreRevision обработчик для восстановления прежней версии данных пользователя типа dataType.
+// ID данных берется из параметра пути idParam, номер версии - из параметра version.
+func (h *Handlers) RestoreRevision(dataType, idParam string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, idParam))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed "+dataType+" ID param", zap.Error(err))
+			return
+		}
+
+		version, err := strconv.Atoi(chi.URLParam(r, "version"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed version param", zap.Error(err))
+			return
+		}
+
+		err = h.services.RestoreRevision(r.Context(), id, version, dataType)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to restore "+dataType+" revision")
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
+	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGetHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	revisions := []models.UserDataRevision{
+		{Version: 1, Mark: "mark", Description: "description", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		dataType     string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "get password history success",
+			path:         "/api/user/passwords/1/history",
+			dataType:     "password",
+			serviceTimes: 1,
+			want: want{
+				code: http.StatusOK,
+				body: `[{"created_at":"2024-01-02T03:04:05Z","mark":"mark","description":"description","version":1}]` +
+					"\n",
+			},
+		},
+		{
+			name:         "get otp history success",
+			path:         "/api/user/otps/1/history",
+			dataType:     "otp",
+			serviceTimes: 1,
+			want: want{
+				code: http.StatusOK,
+				body: `[{"created_at":"2024-01-02T03:04:05Z","mark":"mark","description":"description","version":1}]` +
+					"\n",
+			},
+		},
+		{
+			name:         "get file history success",
+			path:         "/api/user/files/1/history",
+			dataType:     "file",
+			serviceTimes: 1,
+			want: want{
+				code: http.StatusOK,
+				body: `[{"created_at":"2024-01-02T03:04:05Z","mark":"mark","description":"description","version":1}]` +
+					"\n",
+			},
+		},
+		{
+			name:         "card not found",
+			path:         "/api/user/cards/1/history",
+			dataType:     "card",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code: http.StatusNotFound,
+			},
+		},
+		{
+			name:         "failed to fetch history",
+			path:         "/api/user/texts/1/history",
+			dataType:     "text",
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to fetch text history",
+			},
+		},
+		{
+			name:     "failed to read request param",
+			path:     "/api/user/passwords/abc/history",
+			dataType: "password",
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed password ID param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().FetchHistory(gomock.Any(), 1, test.dataType).Times(test.serviceTimes).
+				Return(revisions, test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, body := testRequest(t, ts, http.MethodGet, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+			if test.want.body != "" {
+				assert.Equal(t, test.want.body, body)
+			}
+		})
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		dataType     string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "restore password revision success",
+			path:         "/api/user/passwords/1/history/2/restore",
+			dataType:     "password",
+			serviceTimes: 1,
+			want: want{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name:         "restore file revision success",
+			path:         "/api/user/files/1/history/2/restore",
+			dataType:     "file",
+			serviceTimes: 1,
+			want: want{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name:         "revision not found",
+			path:         "/api/user/cards/1/history/2/restore",
+			dataType:     "card",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code: http.StatusNotFound,
+			},
+		},
+		{
+			name:         "failed to restore revision",
+			path:         "/api/user/texts/1/history/2/restore",
+			dataType:     "text",
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to restore text revision",
+			},
+		},
+		{
+			name:     "failed to read version param",
+			path:     "/api/user/otps/1/history/abc/restore",
+			dataType: "otp",
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed version param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().RestoreRevision(gomock.Any(), 1, 2, test.dataType).Times(test.serviceTimes).
+				Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPost, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
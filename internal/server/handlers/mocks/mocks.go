@@ -6,6 +6,7 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	models "github.com/MihailSergeenkov/GophKeeper/internal/models"
@@ -36,8 +37,22 @@ func (m *MockServicer) EXPECT() *MockServicerMockRecorder {
 	return m.recorder
 }
 
+// AbortFileUpload mocks base method.
+func (m *MockServicer) AbortFileUpload(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortFileUpload", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortFileUpload indicates an expected call of AbortFileUpload.
+func (mr *MockServicerMockRecorder) AbortFileUpload(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortFileUpload", reflect.TypeOf((*MockServicer)(nil).AbortFileUpload), ctx, id)
+}
+
 // AddCard mocks base method.
-func (m *MockServicer) AddCard(ctx context.Context, req *models.AddCardRequest) (int, error) {
+func (m *MockServicer) AddCard(ctx context.Context, req models.AddSecretRequest) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddCard", ctx, req)
 	ret0, _ := ret[0].(int)
@@ -66,8 +81,23 @@ func (mr *MockServicerMockRecorder) AddFile(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFile", reflect.TypeOf((*MockServicer)(nil).AddFile), ctx, req)
 }
 
+// AddOTP mocks base method.
+func (m *MockServicer) AddOTP(ctx context.Context, req models.AddSecretRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOTP", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOTP indicates an expected call of AddOTP.
+func (mr *MockServicerMockRecorder) AddOTP(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOTP", reflect.TypeOf((*MockServicer)(nil).AddOTP), ctx, req)
+}
+
 // AddPassword mocks base method.
-func (m *MockServicer) AddPassword(ctx context.Context, req models.AddPasswordRequest) (int, error) {
+func (m *MockServicer) AddPassword(ctx context.Context, req models.AddSecretRequest) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddPassword", ctx, req)
 	ret0, _ := ret[0].(int)
@@ -82,7 +112,7 @@ func (mr *MockServicerMockRecorder) AddPassword(ctx, req interface{}) *gomock.Ca
 }
 
 // AddText mocks base method.
-func (m *MockServicer) AddText(ctx context.Context, req models.AddTextRequest) (int, error) {
+func (m *MockServicer) AddText(ctx context.Context, req models.AddSecretRequest) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddText", ctx, req)
 	ret0, _ := ret[0].(int)
@@ -96,6 +126,21 @@ func (mr *MockServicerMockRecorder) AddText(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddText", reflect.TypeOf((*MockServicer)(nil).AddText), ctx, req)
 }
 
+// CompleteFileUpload mocks base method.
+func (m *MockServicer) CompleteFileUpload(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteFileUpload", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteFileUpload indicates an expected call of CompleteFileUpload.
+func (mr *MockServicerMockRecorder) CompleteFileUpload(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteFileUpload", reflect.TypeOf((*MockServicer)(nil).CompleteFileUpload), ctx, id)
+}
+
 // CreateUserToken mocks base method.
 func (m *MockServicer) CreateUserToken(ctx context.Context, req models.CreateUserTokenRequest) (models.CreateUserTokenResponse, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +156,136 @@ func (mr *MockServicerMockRecorder) CreateUserToken(ctx, req interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockServicer)(nil).CreateUserToken), ctx, req)
 }
 
+// DeleteCard mocks base method.
+func (m *MockServicer) DeleteCard(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCard", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCard indicates an expected call of DeleteCard.
+func (mr *MockServicerMockRecorder) DeleteCard(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCard", reflect.TypeOf((*MockServicer)(nil).DeleteCard), ctx, id)
+}
+
+// DeleteFile mocks base method.
+func (m *MockServicer) DeleteFile(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockServicerMockRecorder) DeleteFile(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockServicer)(nil).DeleteFile), ctx, id)
+}
+
+// DeleteOTP mocks base method.
+func (m *MockServicer) DeleteOTP(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOTP", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOTP indicates an expected call of DeleteOTP.
+func (mr *MockServicerMockRecorder) DeleteOTP(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOTP", reflect.TypeOf((*MockServicer)(nil).DeleteOTP), ctx, id)
+}
+
+// DeletePassword mocks base method.
+func (m *MockServicer) DeletePassword(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePassword", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePassword indicates an expected call of DeletePassword.
+func (mr *MockServicerMockRecorder) DeletePassword(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePassword", reflect.TypeOf((*MockServicer)(nil).DeletePassword), ctx, id)
+}
+
+// DeleteText mocks base method.
+func (m *MockServicer) DeleteText(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteText", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteText indicates an expected call of DeleteText.
+func (mr *MockServicerMockRecorder) DeleteText(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteText", reflect.TypeOf((*MockServicer)(nil).DeleteText), ctx, id)
+}
+
+// EmptyTrash mocks base method.
+func (m *MockServicer) EmptyTrash(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmptyTrash", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmptyTrash indicates an expected call of EmptyTrash.
+func (mr *MockServicerMockRecorder) EmptyTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmptyTrash", reflect.TypeOf((*MockServicer)(nil).EmptyTrash), ctx)
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockServicer) EnrollTwoFactor(ctx context.Context) (models.EnrollTwoFactorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor", ctx)
+	ret0, _ := ret[0].(models.EnrollTwoFactorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockServicerMockRecorder) EnrollTwoFactor(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockServicer)(nil).EnrollTwoFactor), ctx)
+}
+
+// FetchHistory mocks base method.
+func (m *MockServicer) FetchHistory(ctx context.Context, id int, dataType string) ([]models.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchHistory", ctx, id, dataType)
+	ret0, _ := ret[0].([]models.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchHistory indicates an expected call of FetchHistory.
+func (mr *MockServicerMockRecorder) FetchHistory(ctx, id, dataType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchHistory", reflect.TypeOf((*MockServicer)(nil).FetchHistory), ctx, id, dataType)
+}
+
+// FetchTrash mocks base method.
+func (m *MockServicer) FetchTrash(ctx context.Context) ([]models.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTrash", ctx)
+	ret0, _ := ret[0].([]models.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTrash indicates an expected call of FetchTrash.
+func (mr *MockServicerMockRecorder) FetchTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTrash", reflect.TypeOf((*MockServicer)(nil).FetchTrash), ctx)
+}
+
 // FetchUserData mocks base method.
 func (m *MockServicer) FetchUserData(ctx context.Context) ([]models.UserData, error) {
 	m.ctrl.T.Helper()
@@ -126,11 +301,26 @@ func (mr *MockServicerMockRecorder) FetchUserData(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserData", reflect.TypeOf((*MockServicer)(nil).FetchUserData), ctx)
 }
 
+// FetchUserDataChanges mocks base method.
+func (m *MockServicer) FetchUserDataChanges(ctx context.Context, since int64) (models.UserDataChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchUserDataChanges", ctx, since)
+	ret0, _ := ret[0].(models.UserDataChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchUserDataChanges indicates an expected call of FetchUserDataChanges.
+func (mr *MockServicerMockRecorder) FetchUserDataChanges(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserDataChanges", reflect.TypeOf((*MockServicer)(nil).FetchUserDataChanges), ctx, since)
+}
+
 // GetCard mocks base method.
-func (m *MockServicer) GetCard(ctx context.Context, id int) (models.Card, error) {
+func (m *MockServicer) GetCard(ctx context.Context, id int) (models.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCard", ctx, id)
-	ret0, _ := ret[0].(models.Card)
+	ret0, _ := ret[0].(models.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -142,25 +332,55 @@ func (mr *MockServicerMockRecorder) GetCard(ctx, id interface{}) *gomock.Call {
 }
 
 // GetFile mocks base method.
-func (m *MockServicer) GetFile(ctx context.Context, fileMark string) (models.File, error) {
+func (m *MockServicer) GetFile(ctx context.Context, id int, req models.GetFileRequest) (models.File, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFile", ctx, fileMark)
+	ret := m.ctrl.Call(m, "GetFile", ctx, id, req)
 	ret0, _ := ret[0].(models.File)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFile indicates an expected call of GetFile.
-func (mr *MockServicerMockRecorder) GetFile(ctx, fileMark interface{}) *gomock.Call {
+func (mr *MockServicerMockRecorder) GetFile(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockServicer)(nil).GetFile), ctx, id, req)
+}
+
+// GetFileUpload mocks base method.
+func (m *MockServicer) GetFileUpload(ctx context.Context, id string) (models.FileUploadStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileUpload", ctx, id)
+	ret0, _ := ret[0].(models.FileUploadStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileUpload indicates an expected call of GetFileUpload.
+func (mr *MockServicerMockRecorder) GetFileUpload(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUpload", reflect.TypeOf((*MockServicer)(nil).GetFileUpload), ctx, id)
+}
+
+// GetOTP mocks base method.
+func (m *MockServicer) GetOTP(ctx context.Context, id int) (models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOTP", ctx, id)
+	ret0, _ := ret[0].(models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOTP indicates an expected call of GetOTP.
+func (mr *MockServicerMockRecorder) GetOTP(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockServicer)(nil).GetFile), ctx, fileMark)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOTP", reflect.TypeOf((*MockServicer)(nil).GetOTP), ctx, id)
 }
 
 // GetPassword mocks base method.
-func (m *MockServicer) GetPassword(ctx context.Context, id int) (models.Password, error) {
+func (m *MockServicer) GetPassword(ctx context.Context, id int) (models.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPassword", ctx, id)
-	ret0, _ := ret[0].(models.Password)
+	ret0, _ := ret[0].(models.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -171,11 +391,26 @@ func (mr *MockServicerMockRecorder) GetPassword(ctx, id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassword", reflect.TypeOf((*MockServicer)(nil).GetPassword), ctx, id)
 }
 
+// GetSessions mocks base method.
+func (m *MockServicer) GetSessions(ctx context.Context) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", ctx)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockServicerMockRecorder) GetSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockServicer)(nil).GetSessions), ctx)
+}
+
 // GetText mocks base method.
-func (m *MockServicer) GetText(ctx context.Context, id int) (models.Text, error) {
+func (m *MockServicer) GetText(ctx context.Context, id int) (models.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetText", ctx, id)
-	ret0, _ := ret[0].(models.Text)
+	ret0, _ := ret[0].(models.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -186,6 +421,150 @@ func (mr *MockServicerMockRecorder) GetText(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetText", reflect.TypeOf((*MockServicer)(nil).GetText), ctx, id)
 }
 
+// GetUsage mocks base method.
+func (m *MockServicer) GetUsage(ctx context.Context) (models.UserUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx)
+	ret0, _ := ret[0].(models.UserUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockServicerMockRecorder) GetUsage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockServicer)(nil).GetUsage), ctx)
+}
+
+// GetVault mocks base method.
+func (m *MockServicer) GetVault(ctx context.Context) (models.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVault", ctx)
+	ret0, _ := ret[0].(models.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVault indicates an expected call of GetVault.
+func (mr *MockServicerMockRecorder) GetVault(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVault", reflect.TypeOf((*MockServicer)(nil).GetVault), ctx)
+}
+
+// InitFileUpload mocks base method.
+func (m *MockServicer) InitFileUpload(ctx context.Context, req models.InitFileUploadRequest) (models.FileUploadStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitFileUpload", ctx, req)
+	ret0, _ := ret[0].(models.FileUploadStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitFileUpload indicates an expected call of InitFileUpload.
+func (mr *MockServicerMockRecorder) InitFileUpload(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitFileUpload", reflect.TypeOf((*MockServicer)(nil).InitFileUpload), ctx, req)
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockServicer) LoginTwoFactor(ctx context.Context, req models.LoginTwoFactorRequest) (models.CreateUserTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", ctx, req)
+	ret0, _ := ret[0].(models.CreateUserTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockServicerMockRecorder) LoginTwoFactor(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockServicer)(nil).LoginTwoFactor), ctx, req)
+}
+
+// LogoutUser mocks base method.
+func (m *MockServicer) LogoutUser(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutUser", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutUser indicates an expected call of LogoutUser.
+func (mr *MockServicerMockRecorder) LogoutUser(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockServicer)(nil).LogoutUser), ctx)
+}
+
+// PatchCard mocks base method.
+func (m *MockServicer) PatchCard(ctx context.Context, id int, req models.UpdateSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCard", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchCard indicates an expected call of PatchCard.
+func (mr *MockServicerMockRecorder) PatchCard(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCard", reflect.TypeOf((*MockServicer)(nil).PatchCard), ctx, id, req)
+}
+
+// PatchFile mocks base method.
+func (m *MockServicer) PatchFile(ctx context.Context, id int, req models.UpdateFileRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchFile", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchFile indicates an expected call of PatchFile.
+func (mr *MockServicerMockRecorder) PatchFile(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchFile", reflect.TypeOf((*MockServicer)(nil).PatchFile), ctx, id, req)
+}
+
+// PatchOTP mocks base method.
+func (m *MockServicer) PatchOTP(ctx context.Context, id int, req models.UpdateSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchOTP", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchOTP indicates an expected call of PatchOTP.
+func (mr *MockServicerMockRecorder) PatchOTP(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchOTP", reflect.TypeOf((*MockServicer)(nil).PatchOTP), ctx, id, req)
+}
+
+// PatchPassword mocks base method.
+func (m *MockServicer) PatchPassword(ctx context.Context, id int, req models.UpdateSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPassword", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchPassword indicates an expected call of PatchPassword.
+func (mr *MockServicerMockRecorder) PatchPassword(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPassword", reflect.TypeOf((*MockServicer)(nil).PatchPassword), ctx, id, req)
+}
+
+// PatchText mocks base method.
+func (m *MockServicer) PatchText(ctx context.Context, id int, req models.UpdateSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchText", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchText indicates an expected call of PatchText.
+func (mr *MockServicerMockRecorder) PatchText(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchText", reflect.TypeOf((*MockServicer)(nil).PatchText), ctx, id, req)
+}
+
 // Ping mocks base method.
 func (m *MockServicer) Ping(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -200,6 +579,21 @@ func (mr *MockServicerMockRecorder) Ping(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockServicer)(nil).Ping), ctx)
 }
 
+// RefreshUserToken mocks base method.
+func (m *MockServicer) RefreshUserToken(ctx context.Context, req models.RefreshUserTokenRequest) (models.CreateUserTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshUserToken", ctx, req)
+	ret0, _ := ret[0].(models.CreateUserTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshUserToken indicates an expected call of RefreshUserToken.
+func (mr *MockServicerMockRecorder) RefreshUserToken(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshUserToken", reflect.TypeOf((*MockServicer)(nil).RefreshUserToken), ctx, req)
+}
+
 // RegisterUser mocks base method.
 func (m *MockServicer) RegisterUser(ctx context.Context, req models.RegisterUserRequest) error {
 	m.ctrl.T.Helper()
@@ -214,6 +608,192 @@ func (mr *MockServicerMockRecorder) RegisterUser(ctx, req interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockServicer)(nil).RegisterUser), ctx, req)
 }
 
+// ReserveDataID mocks base method.
+func (m *MockServicer) ReserveDataID(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveDataID", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveDataID indicates an expected call of ReserveDataID.
+func (mr *MockServicerMockRecorder) ReserveDataID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveDataID", reflect.TypeOf((*MockServicer)(nil).ReserveDataID), ctx)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockServicer) RestoreRevision(ctx context.Context, id, version int, dataType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, id, version, dataType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockServicerMockRecorder) RestoreRevision(ctx, id, version, dataType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockServicer)(nil).RestoreRevision), ctx, id, version, dataType)
+}
+
+// RestoreTrash mocks base method.
+func (m *MockServicer) RestoreTrash(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTrash", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTrash indicates an expected call of RestoreTrash.
+func (mr *MockServicerMockRecorder) RestoreTrash(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTrash", reflect.TypeOf((*MockServicer)(nil).RestoreTrash), ctx, id)
+}
+
+// RevokeSession mocks base method.
+func (m *MockServicer) RevokeSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockServicerMockRecorder) RevokeSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockServicer)(nil).RevokeSession), ctx, sessionID)
+}
+
+// SearchUserData mocks base method.
+func (m *MockServicer) SearchUserData(ctx context.Context, filter models.UserDataFilter) (models.UserDataPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUserData", ctx, filter)
+	ret0, _ := ret[0].(models.UserDataPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUserData indicates an expected call of SearchUserData.
+func (mr *MockServicerMockRecorder) SearchUserData(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUserData", reflect.TypeOf((*MockServicer)(nil).SearchUserData), ctx, filter)
+}
+
+// SetVaultCheck mocks base method.
+func (m *MockServicer) SetVaultCheck(ctx context.Context, req models.UpdateVaultRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVaultCheck", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVaultCheck indicates an expected call of SetVaultCheck.
+func (mr *MockServicerMockRecorder) SetVaultCheck(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVaultCheck", reflect.TypeOf((*MockServicer)(nil).SetVaultCheck), ctx, req)
+}
+
+// UpdateCard mocks base method.
+func (m *MockServicer) UpdateCard(ctx context.Context, id int, req models.AddSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCard", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCard indicates an expected call of UpdateCard.
+func (mr *MockServicerMockRecorder) UpdateCard(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCard", reflect.TypeOf((*MockServicer)(nil).UpdateCard), ctx, id, req)
+}
+
+// UpdateFile mocks base method.
+func (m *MockServicer) UpdateFile(ctx context.Context, id int, req models.AddFileRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFile", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFile indicates an expected call of UpdateFile.
+func (mr *MockServicerMockRecorder) UpdateFile(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFile", reflect.TypeOf((*MockServicer)(nil).UpdateFile), ctx, id, req)
+}
+
+// UpdateOTP mocks base method.
+func (m *MockServicer) UpdateOTP(ctx context.Context, id int, req models.AddSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOTP", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOTP indicates an expected call of UpdateOTP.
+func (mr *MockServicerMockRecorder) UpdateOTP(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOTP", reflect.TypeOf((*MockServicer)(nil).UpdateOTP), ctx, id, req)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockServicer) UpdatePassword(ctx context.Context, id int, req models.AddSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockServicerMockRecorder) UpdatePassword(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockServicer)(nil).UpdatePassword), ctx, id, req)
+}
+
+// UpdateText mocks base method.
+func (m *MockServicer) UpdateText(ctx context.Context, id int, req models.AddSecretRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateText", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateText indicates an expected call of UpdateText.
+func (mr *MockServicerMockRecorder) UpdateText(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateText", reflect.TypeOf((*MockServicer)(nil).UpdateText), ctx, id, req)
+}
+
+// UploadFilePart mocks base method.
+func (m *MockServicer) UploadFilePart(ctx context.Context, id string, number int, part io.Reader, size int64) (models.FileUploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFilePart", ctx, id, number, part, size)
+	ret0, _ := ret[0].(models.FileUploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadFilePart indicates an expected call of UploadFilePart.
+func (mr *MockServicerMockRecorder) UploadFilePart(ctx, id, number, part, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFilePart", reflect.TypeOf((*MockServicer)(nil).UploadFilePart), ctx, id, number, part, size)
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockServicer) VerifyTwoFactor(ctx context.Context, req models.VerifyTwoFactorRequest) (models.VerifyTwoFactorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", ctx, req)
+	ret0, _ := ret[0].(models.VerifyTwoFactorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockServicerMockRecorder) VerifyTwoFactor(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockServicer)(nil).VerifyTwoFactor), ctx, req)
+}
+
 // MockLogger is a mock of Logger interface.
 type MockLogger struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// AddOTP обработчик для добавления секрета TOTP пользователя.
+func (h *Handlers) AddOTP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.AddSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		id, err := h.services.AddOTP(r.Context(), req)
+		if err != nil {
+			if writeQuotaError(w, err) {
+				return
+			}
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to add otp", zap.Error(err))
+			return
+		}
+
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusCreated)
+
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(models.AddResponse{ID: id}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error(encRespErrStr, zap.Error(err))
+			return
+		}
+	}
+}
+
+// GetOTP обработчик для получения конкретного секрета TOTP пользователя.
+func (h *Handlers) GetOTP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "otpID")
+		otpID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed otp ID param", zap.Error(err))
+			return
+		}
+
+		otp, err := h.services.GetOTP(r.Context(), otpID)
+		if err != nil {
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to get otp", zap.Error(err))
+			return
+		}
+
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(otp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error(encRespErrStr, zap.Error(err))
+			return
+		}
+	}
+}
+
+// UpdateOTP обработчик для полной замены секрета TOTP пользователя.
+func (h *Handlers) UpdateOTP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "otpID")
+		otpID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed otp ID param", zap.Error(err))
+			return
+		}
+
+		var req models.AddSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.UpdateOTP(r.Context(), otpID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to update otp")
+	}
+}
+
+// PatchOTP обработчик для частичного обновления секрета TOTP пользователя.
+func (h *Handlers) PatchOTP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "otpID")
+		otpID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed otp ID param", zap.Error(err))
+			return
+		}
+
+		var req models.UpdateSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.PatchOTP(r.Context(), otpID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to patch otp")
+	}
+}
+
+// DeleteOTP обработчик для удаления секрета TOTP пользователя.
+func (h *Handlers) DeleteOTP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "otpID")
+		otpID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed otp ID param", zap.Error(err))
+			return
+		}
+
+		err = h.services.DeleteOTP(r.Context(), otpID)
+		h.writeChangeResult(w, err, http.StatusNoContent, "failed to delete otp")
+	}
+}
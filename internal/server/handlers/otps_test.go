@@ -0,0 +1,493 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
+	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAddOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	type serviceResponse struct {
+		id  int
+		err error
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name            string
+		serviceResponse serviceResponse
+		want            want
+	}{
+		{
+			name: "add otp success",
+			serviceResponse: serviceResponse{
+				id:  1,
+				err: nil,
+			},
+			want: want{
+				code:          http.StatusCreated,
+				body:          "{\"id\":1}\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "add otp failed",
+			serviceResponse: serviceResponse{
+				id:  0,
+				err: errors.New("some error"),
+			},
+			want: want{
+				code:          http.StatusInternalServerError,
+				body:          "",
+				errorLogTimes: 1,
+				log:           "failed to add otp",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().
+				AddOTP(gomock.Any(), requestObject).
+				Times(1).
+				Return(test.serviceResponse.id, test.serviceResponse.err)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/otps", strings.NewReader(requestBody))
+			w := httptest.NewRecorder()
+			handlers.AddOTP()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+
+			if http.StatusCreated == res.StatusCode {
+				resBody, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.want.body, string(resBody))
+			}
+		})
+	}
+}
+
+func TestAddOTPFailedReadBody(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test",adasd}`
+
+	t.Run("failed to read request body", func(t *testing.T) {
+		s.EXPECT().AddOTP(gomock.Any(), gomock.Any()).Times(0)
+		l.EXPECT().Error("failed to read request body", gomock.Any()).Times(1)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/user/otps", strings.NewReader(requestBody))
+		w := httptest.NewRecorder()
+		handlers.AddOTP()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestGetOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	otpID := 1
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type serviceResponse struct {
+		res models.Secret
+		err error
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name            string
+		serviceResponse serviceResponse
+		want            want
+	}{
+		{
+			name: "get otp success",
+			serviceResponse: serviceResponse{
+				res: models.Secret{
+					ID:          1,
+					Data:        []byte("test"),
+					Mark:        "test",
+					Description: "test",
+				},
+				err: nil,
+			},
+			want: want{
+				code:          http.StatusOK,
+				body:          `{"data":"dGVzdA==","mark":"test","description":"test","id":1}` + "\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "otp no found",
+			serviceResponse: serviceResponse{
+				res: models.Secret{},
+				err: services.ErrNotFound,
+			},
+			want: want{
+				code:          http.StatusNotFound,
+				body:          "",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "get otp failed with some error",
+			serviceResponse: serviceResponse{
+				res: models.Secret{},
+				err: errors.New("some error"),
+			},
+			want: want{
+				code:          http.StatusInternalServerError,
+				body:          "",
+				errorLogTimes: 1,
+				log:           "failed to get otp",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetOTP(gomock.Any(), otpID).Times(1).
+				Return(test.serviceResponse.res, test.serviceResponse.err)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, resBody := testGetRequest(t, ts, "/api/user/otps/1")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+			assert.Equal(t, test.want.body, resBody)
+		})
+	}
+}
+
+func TestGetOTPFailedReadParam(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	t.Run("failed to read request param", func(t *testing.T) {
+		s.EXPECT().GetOTP(gomock.Any(), gomock.Any()).Times(0)
+		l.EXPECT().Error("failed otp ID param", gomock.Any()).Times(1)
+		storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+		res, _ := testGetRequest(t, ts, "/api/user/otps/adasd")
+		closeBody(t, res)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestUpdateOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	otpID := 1
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "update otp success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "otp no found",
+			serviceErr: services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "update otp failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to update otp",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().UpdateOTP(gomock.Any(), otpID, requestObject).Times(1).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPut, "/api/user/otps/1", requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestPatchOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	mark := "new"
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		requestBody  string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "patch otp success",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "patch otp failed with some error",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to patch otp",
+			},
+		},
+		{
+			name:         "failed to read request body",
+			requestBody:  `{"mark":"new",adasd}`,
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed to read request body",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().PatchOTP(gomock.Any(), 1, models.UpdateSecretRequest{Mark: &mark}).
+				Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPatch, "/api/user/otps/1", test.requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestDeleteOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "delete otp success",
+			path:         "/api/user/otps/1",
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusNoContent,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "otp no found",
+			path:         "/api/user/otps/1",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "failed to read request param",
+			path:         "/api/user/otps/adasd",
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed otp ID param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteOTP(gomock.Any(), 1).Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodDelete, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
@@ -15,7 +15,7 @@ import (
 // AddPassword обработчик для добавления данных пароля пользователя.
 func (h *Handlers) AddPassword() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req models.AddPasswordRequest
+		var req models.AddSecretRequest
 
 		dec := json.NewDecoder(r.Body)
 		if err := dec.Decode(&req); err != nil {
@@ -26,6 +26,14 @@ func (h *Handlers) AddPassword() http.HandlerFunc {
 
 		id, err := h.services.AddPassword(r.Context(), req)
 		if err != nil {
+			if writeQuotaError(w, err) {
+				return
+			}
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 			h.logger.Error("failed to add password", zap.Error(err))
 			return
@@ -77,3 +85,69 @@ func (h *Handlers) GetPassword() http.HandlerFunc {
 		}
 	}
 }
+
+// UpdatePassword обработчик для полной замены данных пароля пользователя.
+func (h *Handlers) UpdatePassword() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "passwordID")
+		passwordID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed password ID param", zap.Error(err))
+			return
+		}
+
+		var req models.AddSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.UpdatePassword(r.Context(), passwordID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to update password")
+	}
+}
+
+// PatchPassword обработчик для частичного обновления данных пароля пользователя.
+func (h *Handlers) PatchPassword() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "passwordID")
+		passwordID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed password ID param", zap.Error(err))
+			return
+		}
+
+		var req models.UpdateSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.PatchPassword(r.Context(), passwordID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to patch password")
+	}
+}
+
+// DeletePassword обработчик для удаления данных пароля пользователя.
+func (h *Handlers) DeletePassword() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "passwordID")
+		passwordID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed password ID param", zap.Error(err))
+			return
+		}
+
+		err = h.services.DeletePassword(r.Context(), passwordID)
+		h.writeChangeResult(w, err, http.StatusNoContent, "failed to delete password")
+	}
+}
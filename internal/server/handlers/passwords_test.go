@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -9,7 +10,6 @@ import (
 	"testing"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
-	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
 	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
@@ -28,10 +28,9 @@ func TestAddPassword(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	requestBody := `{"login":"test","password":"test","mark":"test","description":"test"}`
-	requestObject := models.AddPasswordRequest{
-		Login:       "test",
-		Password:    "test",
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
 		Mark:        "test",
 		Description: "test",
 	}
@@ -79,6 +78,32 @@ func TestAddPassword(t *testing.T) {
 				log:           "failed to add password",
 			},
 		},
+		{
+			name: "add password with unknown reserved id",
+			serviceResponse: serviceResponse{
+				id:  0,
+				err: fmt.Errorf("wrapped: %w", services.ErrNotFound),
+			},
+			want: want{
+				code:          http.StatusNotFound,
+				body:          "",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "add password over quota",
+			serviceResponse: serviceResponse{
+				id:  0,
+				err: fmt.Errorf("wrapped: %w", services.ErrQuotaExceeded),
+			},
+			want: want{
+				code:          http.StatusForbidden,
+				body:          "",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -116,7 +141,7 @@ func TestAddPasswordFailedReadBody(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	requestBody := `{"login":"test","password":"test","mark":"test","description":"test",adasd}`
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test",adasd}`
 
 	t.Run("failed to read request body", func(t *testing.T) {
 		s.EXPECT().AddPassword(gomock.Any(), gomock.Any()).Times(0)
@@ -141,8 +166,7 @@ func TestGetPassword(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
 	passwordID := 1
@@ -152,7 +176,7 @@ func TestGetPassword(t *testing.T) {
 	defer ts.Close()
 
 	type serviceResponse struct {
-		res models.Password
+		res models.Secret
 		err error
 	}
 
@@ -171,10 +195,9 @@ func TestGetPassword(t *testing.T) {
 		{
 			name: "get password success",
 			serviceResponse: serviceResponse{
-				res: models.Password{
+				res: models.Secret{
 					ID:          1,
-					Login:       "test",
-					Password:    "test",
+					Data:        []byte("test"),
 					Mark:        "test",
 					Description: "test",
 				},
@@ -182,7 +205,7 @@ func TestGetPassword(t *testing.T) {
 			},
 			want: want{
 				code:          http.StatusOK,
-				body:          `{"login":"test","password":"test","mark":"test","description":"test","id":1}` + "\n",
+				body:          `{"data":"dGVzdA==","mark":"test","description":"test","id":1}` + "\n",
 				errorLogTimes: 0,
 				log:           "",
 			},
@@ -190,7 +213,7 @@ func TestGetPassword(t *testing.T) {
 		{
 			name: "password no found",
 			serviceResponse: serviceResponse{
-				res: models.Password{},
+				res: models.Secret{},
 				err: services.ErrNotFound,
 			},
 			want: want{
@@ -203,7 +226,7 @@ func TestGetPassword(t *testing.T) {
 		{
 			name: "get password failed with some error",
 			serviceResponse: serviceResponse{
-				res: models.Password{},
+				res: models.Secret{},
 				err: errors.New("some error"),
 			},
 			want: want{
@@ -221,7 +244,7 @@ func TestGetPassword(t *testing.T) {
 				Return(test.serviceResponse.res, test.serviceResponse.err)
 
 			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
-			storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
 			res, resBody := testGetRequest(t, ts, "/api/user/passwords/1")
 			closeBody(t, res)
@@ -240,8 +263,7 @@ func TestGetPasswordFailedReadParam(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
 	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
@@ -251,7 +273,7 @@ func TestGetPasswordFailedReadParam(t *testing.T) {
 	t.Run("failed to read request param", func(t *testing.T) {
 		s.EXPECT().GetPassword(gomock.Any(), gomock.Any()).Times(0)
 		l.EXPECT().Error("failed password ID param", gomock.Any()).Times(1)
-		storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+		storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
 		res, _ := testGetRequest(t, ts, "/api/user/passwords/adasd")
 		closeBody(t, res)
@@ -259,3 +281,249 @@ func TestGetPasswordFailedReadParam(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
 }
+
+func TestUpdatePassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	passwordID := 1
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "update password success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "password no found",
+			serviceErr: services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "password changed by another client",
+			serviceErr: services.ErrVersionConflict,
+			want: want{
+				code:          http.StatusConflict,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "update password failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to update password",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().UpdatePassword(gomock.Any(), passwordID, requestObject).Times(1).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPut, "/api/user/passwords/1", requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestPatchPassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	mark := "new"
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		requestBody  string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "patch password success",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "patch password failed with some error",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to patch password",
+			},
+		},
+		{
+			name:         "failed to read request body",
+			requestBody:  `{"mark":"new",adasd}`,
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed to read request body",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().PatchPassword(gomock.Any(), 1, models.UpdateSecretRequest{Mark: &mark}).
+				Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPatch, "/api/user/passwords/1", test.requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestDeletePassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "delete password success",
+			path:         "/api/user/passwords/1",
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusNoContent,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "password no found",
+			path:         "/api/user/passwords/1",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "failed to read request param",
+			path:         "/api/user/passwords/adasd",
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed password ID param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeletePassword(gomock.Any(), 1).Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodDelete, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
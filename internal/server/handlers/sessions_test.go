@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetSessions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	lastUsedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	type serviceResponse struct {
+		res []models.Session
+		err error
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name            string
+		serviceResponse serviceResponse
+		want            want
+	}{
+		{
+			name: "get sessions success",
+			serviceResponse: serviceResponse{
+				res: []models.Session{
+					{
+						ID:            "session",
+						DeviceName:    "laptop",
+						ClientVersion: "0.0.1",
+						IP:            "192.0.2.1",
+						CreatedAt:     lastUsedAt,
+						LastUsedAt:    lastUsedAt,
+						ExpiresAt:     lastUsedAt,
+						Current:       true,
+					},
+				},
+				err: nil,
+			},
+			want: want{
+				code: http.StatusOK,
+				body: `[{"created_at":"2024-01-02T03:04:05Z","last_used_at":"2024-01-02T03:04:05Z",` +
+					`"expires_at":"2024-01-02T03:04:05Z","id":"session","device_name":"laptop",` +
+					`"client_version":"0.0.1","ip":"192.0.2.1","current":true}]` + "\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "get sessions failed",
+			serviceResponse: serviceResponse{
+				res: nil,
+				err: errors.New("some error"),
+			},
+			want: want{
+				code:          http.StatusInternalServerError,
+				body:          "",
+				errorLogTimes: 1,
+				log:           "failed to get sessions",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetSessions(gomock.Any()).Times(1).Return(test.serviceResponse.res, test.serviceResponse.err)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/user/sessions", http.NoBody)
+			w := httptest.NewRecorder()
+			handlers.GetSessions()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+
+			if http.StatusOK == res.StatusCode {
+				resBody, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.want.body, string(resBody))
+			}
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "revoke session success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusNoContent,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "revoke session failed with ErrSessionNotFound",
+			serviceErr: services.ErrSessionNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "revoke session failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to revoke session",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().RevokeSession(gomock.Any(), "session").Times(1).Return(test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodDelete, "/api/user/sessions/session", http.NoBody)
+			request = withURLParam(request, "sessionID", "session")
+			w := httptest.NewRecorder()
+			handlers.RevokeSession()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
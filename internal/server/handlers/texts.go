@@ -15,7 +15,7 @@ import (
 // AddText обработчик для добавления текстовых данных пользователя.
 func (h *Handlers) AddText() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req models.AddTextRequest
+		var req models.AddSecretRequest
 
 		dec := json.NewDecoder(r.Body)
 		if err := dec.Decode(&req); err != nil {
@@ -26,6 +26,14 @@ func (h *Handlers) AddText() http.HandlerFunc {
 
 		id, err := h.services.AddText(r.Context(), req)
 		if err != nil {
+			if writeQuotaError(w, err) {
+				return
+			}
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
 			w.WriteHeader(http.StatusInternalServerError)
 			h.logger.Error("failed to add text", zap.Error(err))
 			return
@@ -77,3 +85,69 @@ func (h *Handlers) GetText() http.HandlerFunc {
 		}
 	}
 }
+
+// UpdateText обработчик для полной замены данных текста пользователя.
+func (h *Handlers) UpdateText() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "textID")
+		textID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed text ID param", zap.Error(err))
+			return
+		}
+
+		var req models.AddSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.UpdateText(r.Context(), textID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to update text")
+	}
+}
+
+// PatchText обработчик для частичного обновления данных текста пользователя.
+func (h *Handlers) PatchText() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "textID")
+		textID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed text ID param", zap.Error(err))
+			return
+		}
+
+		var req models.UpdateSecretRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		err = h.services.PatchText(r.Context(), textID, req)
+		h.writeChangeResult(w, err, http.StatusOK, "failed to patch text")
+	}
+}
+
+// DeleteText обработчик для удаления данных текста пользователя.
+func (h *Handlers) DeleteText() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "textID")
+		textID, err := strconv.Atoi(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed text ID param", zap.Error(err))
+			return
+		}
+
+		err = h.services.DeleteText(r.Context(), textID)
+		h.writeChangeResult(w, err, http.StatusNoContent, "failed to delete text")
+	}
+}
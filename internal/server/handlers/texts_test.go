@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
-	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
 	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
@@ -28,9 +27,9 @@ func TestAddText(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	requestBody := `{"data":"test","mark":"test","description":"test"}`
-	requestObject := models.AddTextRequest{
-		Data:        "test",
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
 		Mark:        "test",
 		Description: "test",
 	}
@@ -115,7 +114,7 @@ func TestAddTextFailedReadBody(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	requestBody := `{"data":"test","mark":"test","description":"test",adasd}`
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test",adasd}`
 
 	t.Run("failed to read request body", func(t *testing.T) {
 		s.EXPECT().AddText(gomock.Any(), gomock.Any()).Times(0)
@@ -140,8 +139,7 @@ func TestGetText(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
 	textID := 1
@@ -151,7 +149,7 @@ func TestGetText(t *testing.T) {
 	defer ts.Close()
 
 	type serviceResponse struct {
-		res models.Text
+		res models.Secret
 		err error
 	}
 
@@ -170,9 +168,9 @@ func TestGetText(t *testing.T) {
 		{
 			name: "get text success",
 			serviceResponse: serviceResponse{
-				res: models.Text{
+				res: models.Secret{
 					ID:          1,
-					Data:        "test",
+					Data:        []byte("test"),
 					Mark:        "test",
 					Description: "test",
 				},
@@ -180,7 +178,7 @@ func TestGetText(t *testing.T) {
 			},
 			want: want{
 				code:          http.StatusOK,
-				body:          "{\"data\":\"test\",\"mark\":\"test\",\"description\":\"test\",\"id\":1}\n",
+				body:          `{"data":"dGVzdA==","mark":"test","description":"test","id":1}` + "\n",
 				errorLogTimes: 0,
 				log:           "",
 			},
@@ -188,7 +186,7 @@ func TestGetText(t *testing.T) {
 		{
 			name: "text no found",
 			serviceResponse: serviceResponse{
-				res: models.Text{},
+				res: models.Secret{},
 				err: services.ErrNotFound,
 			},
 			want: want{
@@ -201,7 +199,7 @@ func TestGetText(t *testing.T) {
 		{
 			name: "get text failed with some error",
 			serviceResponse: serviceResponse{
-				res: models.Text{},
+				res: models.Secret{},
 				err: errors.New("some error"),
 			},
 			want: want{
@@ -219,7 +217,7 @@ func TestGetText(t *testing.T) {
 				Return(test.serviceResponse.res, test.serviceResponse.err)
 
 			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
-			storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
 			res, resBody := testGetRequest(t, ts, "/api/user/texts/1")
 			closeBody(t, res)
@@ -238,8 +236,7 @@ func TestGetTextFailedReadParam(t *testing.T) {
 	l := mocks.NewMockLogger(mockCtrl)
 	handlers := NewHandlers(s, l)
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 	storage := rMocks.NewMockStorager(mockCtrl)
 
 	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
@@ -249,7 +246,7 @@ func TestGetTextFailedReadParam(t *testing.T) {
 	t.Run("failed to read request param", func(t *testing.T) {
 		s.EXPECT().GetText(gomock.Any(), gomock.Any()).Times(0)
 		l.EXPECT().Error("failed text ID param", gomock.Any()).Times(1)
-		storage.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(1)
+		storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
 
 		res, _ := testGetRequest(t, ts, "/api/user/texts/adasd")
 		closeBody(t, res)
@@ -257,3 +254,240 @@ func TestGetTextFailedReadParam(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
 }
+
+func TestUpdateText(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	textID := 1
+	requestBody := `{"data":"dGVzdA==","mark":"test","description":"test"}`
+	requestObject := models.AddSecretRequest{
+		Data:        []byte("test"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "update text success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "text no found",
+			serviceErr: services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "update text failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to update text",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().UpdateText(gomock.Any(), textID, requestObject).Times(1).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPut, "/api/user/texts/1", requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestPatchText(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	mark := "new"
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		requestBody  string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "patch text success",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "patch text failed with some error",
+			requestBody:  `{"mark":"new"}`,
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to patch text",
+			},
+		},
+		{
+			name:         "failed to read request body",
+			requestBody:  `{"mark":"new",adasd}`,
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed to read request body",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().PatchText(gomock.Any(), 1, models.UpdateSecretRequest{Mark: &mark}).
+				Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPatch, "/api/user/texts/1", test.requestBody)
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestDeleteText(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "delete text success",
+			path:         "/api/user/texts/1",
+			serviceTimes: 1,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusNoContent,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "text no found",
+			path:         "/api/user/texts/1",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code:          http.StatusNotFound,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:         "failed to read request param",
+			path:         "/api/user/texts/adasd",
+			serviceTimes: 0,
+			serviceErr:   nil,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed text ID param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().DeleteText(gomock.Any(), 1).Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodDelete, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GetTrash обработчик для получения данных пользователя в корзине.
+func (h *Handlers) GetTrash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := h.services.FetchTrash(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to fetch trash", zap.Error(err))
+			return
+		}
+
+		h.writeJSON(w, http.StatusOK, items)
+	}
+}
+
+// RestoreTrash обработчик для восстановления данных пользователя из корзины.
+func (h *Handlers) RestoreTrash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "dataID"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed data ID param", zap.Error(err))
+			return
+		}
+
+		if err := h.services.RestoreTrash(r.Context(), id); err != nil {
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to restore data from trash", zap.Error(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// EmptyTrash обработчик для окончательного удаления всех данных пользователя из корзины.
+func (h *Handlers) EmptyTrash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := h.services.EmptyTrash(r.Context()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to empty trash", zap.Error(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/routes"
+	rMocks "github.com/MihailSergeenkov/GophKeeper/internal/server/routes/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGetTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	items := []models.TrashItem{
+		{ID: 1, Type: "card", Mark: "mark", DeletedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name: "get trash success",
+			want: want{
+				code: http.StatusOK,
+				body: `[{"deleted_at":"2024-01-02T03:04:05Z","mark":"mark","description":"","type":"card","id":1}]` +
+					"\n",
+			},
+		},
+		{
+			name:       "failed to fetch trash",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().FetchTrash(gomock.Any()).Times(1).Return(items, test.serviceErr)
+
+			l.EXPECT().Error("failed to fetch trash", gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, body := testRequest(t, ts, http.MethodGet, "/api/user/trash", "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+			if test.want.body != "" {
+				assert.Equal(t, test.want.body, body)
+			}
+		})
+	}
+}
+
+func TestRestoreTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		serviceTimes int
+		serviceErr   error
+		want         want
+	}{
+		{
+			name:         "restore success",
+			path:         "/api/user/trash/1/restore",
+			serviceTimes: 1,
+			want: want{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name:         "data not in trash",
+			path:         "/api/user/trash/1/restore",
+			serviceTimes: 1,
+			serviceErr:   services.ErrNotFound,
+			want: want{
+				code: http.StatusNotFound,
+			},
+		},
+		{
+			name:         "failed to restore",
+			path:         "/api/user/trash/1/restore",
+			serviceTimes: 1,
+			serviceErr:   errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to restore data from trash",
+			},
+		},
+		{
+			name: "failed to read request param",
+			path: "/api/user/trash/abc/restore",
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 1,
+				log:           "failed data ID param",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().RestoreTrash(gomock.Any(), 1).Times(test.serviceTimes).Return(test.serviceErr)
+
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodPost, test.path, "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	settings := testSettings(t)
+	storage := rMocks.NewMockStorager(mockCtrl)
+
+	r := routes.NewRouter(handlers, settings, zap.NewNop(), storage)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		name          string
+		serviceErr    error
+		code          int
+		errorLogTimes int
+	}{
+		{
+			name: "empty trash success",
+			code: http.StatusNoContent,
+		},
+		{
+			name:          "failed to empty trash",
+			serviceErr:    errors.New("some error"),
+			code:          http.StatusInternalServerError,
+			errorLogTimes: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EmptyTrash(gomock.Any()).Times(1).Return(2, test.serviceErr)
+
+			l.EXPECT().Error("failed to empty trash", gomock.Any()).Times(test.errorLogTimes)
+			storage.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(testSession, nil)
+
+			res, _ := testRequest(t, ts, http.MethodDelete, "/api/user/trash", "")
+			closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
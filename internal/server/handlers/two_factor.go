@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"go.uber.org/zap"
+)
+
+// EnrollTwoFactor обработчик для получения секрета приложения-аутентификатора.
+func (h *Handlers) EnrollTwoFactor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := h.services.EnrollTwoFactor(r.Context())
+		if err != nil {
+			if errors.Is(err, services.ErrTwoFactorEnabled) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to enroll two factor", zap.Error(err))
+			return
+		}
+
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error(encRespErrStr, zap.Error(err))
+			return
+		}
+	}
+}
+
+// VerifyTwoFactor обработчик для подтверждения подключения двухфакторной аутентификации.
+func (h *Handlers) VerifyTwoFactor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.VerifyTwoFactorRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		resp, err := h.services.VerifyTwoFactor(r.Context(), req)
+		if err != nil {
+			if errors.Is(err, services.ErrTwoFactorCode) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			if errors.Is(err, services.ErrTwoFactorEnabled) || errors.Is(err, services.ErrTwoFactorNotEnrolled) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to verify two factor", zap.Error(err))
+			return
+		}
+
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error(encRespErrStr, zap.Error(err))
+			return
+		}
+	}
+}
+
+// LoginTwoFactor обработчик для второго шага получения ключа доступа пользователя.
+func (h *Handlers) LoginTwoFactor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.LoginTwoFactorRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		resp, err := h.services.LoginTwoFactor(r.Context(), req)
+		if err != nil {
+			if writeTooManyAttempts(w, err) {
+				return
+			}
+			if errors.Is(err, services.ErrUserLoginCreds) || errors.Is(err, services.ErrTwoFactorCode) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to login with two factor", zap.Error(err))
+			return
+		}
+
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error(encRespErrStr, zap.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEnrollTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		resp       models.EnrollTwoFactorResponse
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "enroll two factor success",
+			resp:       models.EnrollTwoFactorResponse{Secret: "SECRET", URI: "otpauth://totp/x"},
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				body:          `{"secret":"SECRET","uri":"otpauth://totp/x"}` + "\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "enroll two factor failed with ErrTwoFactorEnabled",
+			resp:       models.EnrollTwoFactorResponse{},
+			serviceErr: services.ErrTwoFactorEnabled,
+			want: want{
+				code:          http.StatusConflict,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "enroll two factor failed with some error",
+			resp:       models.EnrollTwoFactorResponse{},
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to enroll two factor",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().EnrollTwoFactor(gomock.Any()).Times(1).Return(test.resp, test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/2fa/enroll", http.NoBody)
+			w := httptest.NewRecorder()
+			handlers.EnrollTwoFactor()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+
+			if http.StatusOK == res.StatusCode {
+				resBody, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.want.body, string(resBody))
+			}
+		})
+	}
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	requestBody := `{"code":"123456"}`
+	requestObject := models.VerifyTwoFactorRequest{Code: "123456"}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		resp       models.VerifyTwoFactorResponse
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "verify two factor success",
+			resp:       models.VerifyTwoFactorResponse{RecoveryCodes: []string{"AAAA-BBBB"}},
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				body:          `{"recovery_codes":["AAAA-BBBB"]}` + "\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "verify two factor failed with ErrTwoFactorCode",
+			serviceErr: services.ErrTwoFactorCode,
+			want: want{
+				code:          http.StatusBadRequest,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "verify two factor failed with ErrTwoFactorNotEnrolled",
+			serviceErr: services.ErrTwoFactorNotEnrolled,
+			want: want{
+				code:          http.StatusConflict,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "verify two factor failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to verify two factor",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().VerifyTwoFactor(gomock.Any(), requestObject).Times(1).Return(test.resp, test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/2fa/verify", strings.NewReader(requestBody))
+			w := httptest.NewRecorder()
+			handlers.VerifyTwoFactor()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+
+			if http.StatusOK == res.StatusCode {
+				resBody, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.want.body, string(resBody))
+			}
+		})
+	}
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	requestBody := `{"two_factor_token":"token","code":"123456"}`
+	requestObject := models.LoginTwoFactorRequest{TwoFactorToken: "token", Code: "123456"}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "login two factor success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "login two factor failed with ErrTwoFactorCode",
+			serviceErr: services.ErrTwoFactorCode,
+			want: want{
+				code:          http.StatusUnauthorized,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "login two factor failed with ErrUserLoginCreds",
+			serviceErr: services.ErrUserLoginCreds,
+			want: want{
+				code:          http.StatusUnauthorized,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "login two factor failed with too many attempts",
+			serviceErr: &services.RetryAfterError{RetryAfter: time.Minute},
+			want: want{
+				code:          http.StatusTooManyRequests,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "login two factor failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to login with two factor",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().LoginTwoFactor(gomock.Any(), requestObject).Times(1).
+				Return(models.CreateUserTokenResponse{AuthToken: "qwerty"}, test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/token/2fa", strings.NewReader(requestBody))
+			w := httptest.NewRecorder()
+			handlers.LoginTwoFactor()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// InitFileUpload обработчик для начала загрузки файла пользователя по частям.
+func (h *Handlers) InitFileUpload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.InitFileUploadRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		resp, err := h.services.InitFileUpload(r.Context(), req)
+		if err != nil {
+			if writeQuotaError(w, err) {
+				return
+			}
+
+			if isUploadValidationError(err) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to init file upload", zap.Error(err))
+			return
+		}
+
+		h.writeJSON(w, http.StatusCreated, resp)
+	}
+}
+
+// GetFileUpload обработчик для получения состояния загрузки файла по частям.
+func (h *Handlers) GetFileUpload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := h.services.GetFileUpload(r.Context(), chi.URLParam(r, "uploadID"))
+		if err != nil {
+			if errors.Is(err, services.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to get file upload", zap.Error(err))
+			return
+		}
+
+		h.writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// UploadFilePart обработчик для загрузки части файла. Тело запроса читается потоком,
+// поэтому размер части должен быть передан в заголовке Content-Length.
+func (h *Handlers) UploadFilePart() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		number, err := strconv.Atoi(chi.URLParam(r, "partNumber"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error("failed part number param", zap.Error(err))
+			return
+		}
+
+		if r.ContentLength <= 0 {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		if r.ContentLength > services.UploadPartSize {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, r.ContentLength)
+
+		resp, err := h.services.UploadFilePart(r.Context(), chi.URLParam(r, "uploadID"), number, body, r.ContentLength)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, services.ErrInvalidUploadPart):
+				w.WriteHeader(http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				h.logger.Error("failed to upload file part", zap.Error(err))
+			}
+			return
+		}
+
+		h.writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// CompleteFileUpload обработчик для завершения загрузки файла по частям.
+func (h *Handlers) CompleteFileUpload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := h.services.CompleteFileUpload(r.Context(), chi.URLParam(r, "uploadID"))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, services.ErrUploadIncomplete):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				h.logger.Error("failed to complete file upload", zap.Error(err))
+			}
+			return
+		}
+
+		h.writeJSON(w, http.StatusCreated, models.AddResponse{ID: id})
+	}
+}
+
+// AbortFileUpload обработчик для отмены загрузки файла по частям.
+func (h *Handlers) AbortFileUpload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.services.AbortFileUpload(r.Context(), chi.URLParam(r, "uploadID"))
+		h.writeChangeResult(w, err, http.StatusNoContent, "failed to abort file upload")
+	}
+}
+
+// isUploadValidationError проверяет, что загрузка отклонена из-за некорректных параметров.
+func isUploadValidationError(err error) bool {
+	return errors.Is(err, services.ErrUserMarkIsTooBig) ||
+		errors.Is(err, services.ErrUserDescriptionIsTooBig) ||
+		errors.Is(err, services.ErrInvalidFileSize)
+}
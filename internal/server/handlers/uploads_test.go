@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestInitFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	req := models.InitFileUploadRequest{FileName: "file.bin", Mark: "mark", FileSize: 100}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+		body          string
+	}
+	tests := []struct {
+		name        string
+		body        string
+		serviceErr  error
+		serviceCall int
+		want        want
+	}{
+		{
+			name:        "init upload success",
+			body:        `{"file_name":"file.bin","mark":"mark","file_size":100}`,
+			serviceCall: 1,
+			want: want{
+				code: http.StatusCreated,
+				body: `{"upload_id":"upload","parts":[],"part_size":8388608,"file_size":100}` + "\n",
+			},
+		},
+		{
+			name: "bad request body",
+			body: `{`,
+			want: want{code: http.StatusBadRequest, errorLogTimes: 1, log: readReqErrStr},
+		},
+		{
+			name:        "invalid file size",
+			body:        `{"file_name":"file.bin","mark":"mark","file_size":100}`,
+			serviceErr:  services.ErrInvalidFileSize,
+			serviceCall: 1,
+			want:        want{code: http.StatusBadRequest},
+		},
+		{
+			name:        "data id is not reserved",
+			body:        `{"file_name":"file.bin","mark":"mark","file_size":100}`,
+			serviceErr:  services.ErrNotFound,
+			serviceCall: 1,
+			want:        want{code: http.StatusNotFound},
+		},
+		{
+			name:        "init upload failed",
+			body:        `{"file_name":"file.bin","mark":"mark","file_size":100}`,
+			serviceErr:  errors.New("some error"),
+			serviceCall: 1,
+			want:        want{code: http.StatusInternalServerError, errorLogTimes: 1, log: "failed to init file upload"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().InitFileUpload(gomock.Any(), req).Times(test.serviceCall).Return(models.FileUploadStatus{
+				UploadID: "upload", Parts: []models.FileUploadPart{}, PartSize: services.UploadPartSize, FileSize: 100,
+			}, test.serviceErr)
+			l.EXPECT().Error(test.want.log, gomock.Any()).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/files/uploads", strings.NewReader(test.body))
+			w := httptest.NewRecorder()
+			handlers.InitFileUpload()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+			if test.want.body != "" {
+				body, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.want.body, string(body))
+			}
+		})
+	}
+}
+
+func TestGetFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		code       int
+		logTimes   int
+	}{
+		{name: "get upload success", code: http.StatusOK},
+		{name: "upload not found", serviceErr: services.ErrNotFound, code: http.StatusNotFound},
+		{name: "get upload failed", serviceErr: errors.New("some error"), code: http.StatusInternalServerError, logTimes: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetFileUpload(gomock.Any(), "upload").Times(1).
+				Return(models.FileUploadStatus{UploadID: "upload"}, test.serviceErr)
+			l.EXPECT().Error("failed to get file upload", zap.Error(test.serviceErr)).Times(test.logTimes)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/user/files/uploads/upload", http.NoBody)
+			request = withURLParam(request, "uploadID", "upload")
+			w := httptest.NewRecorder()
+			handlers.GetFileUpload()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
+
+func TestUploadFilePart(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	tests := []struct {
+		name          string
+		partNumber    string
+		body          string
+		contentLength int64
+		serviceCall   int
+		serviceErr    error
+		code          int
+		logTimes      int
+		log           string
+	}{
+		{
+			name:          "upload part success",
+			partNumber:    "1",
+			body:          "data",
+			contentLength: 4,
+			serviceCall:   1,
+			code:          http.StatusOK,
+		},
+		{
+			name:          "wrong part number",
+			partNumber:    "first",
+			body:          "data",
+			contentLength: 4,
+			code:          http.StatusBadRequest,
+			logTimes:      1,
+			log:           "failed part number param",
+		},
+		{
+			name:          "content length required",
+			partNumber:    "1",
+			body:          "",
+			contentLength: -1,
+			code:          http.StatusLengthRequired,
+		},
+		{
+			name:          "part is too big",
+			partNumber:    "1",
+			body:          "data",
+			contentLength: services.UploadPartSize + 1,
+			code:          http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "upload not found",
+			partNumber:    "1",
+			body:          "data",
+			contentLength: 4,
+			serviceCall:   1,
+			serviceErr:    services.ErrNotFound,
+			code:          http.StatusNotFound,
+		},
+		{
+			name:          "upload part failed",
+			partNumber:    "1",
+			body:          "data",
+			contentLength: 4,
+			serviceCall:   1,
+			serviceErr:    errors.New("some error"),
+			code:          http.StatusInternalServerError,
+			logTimes:      1,
+			log:           "failed to upload file part",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().UploadFilePart(gomock.Any(), "upload", 1, gomock.Any(), int64(4)).Times(test.serviceCall).
+				DoAndReturn(func(_ context.Context, _ string, _ int, part io.Reader, _ int64) (models.FileUploadPart, error) {
+					data, err := io.ReadAll(part)
+					require.NoError(t, err)
+					assert.Equal(t, test.body, string(data))
+					return models.FileUploadPart{Number: 1, Size: 4, ETag: "etag"}, test.serviceErr
+				})
+			l.EXPECT().Error(test.log, gomock.Any()).Times(test.logTimes)
+
+			request := httptest.NewRequest(
+				http.MethodPut, "/api/user/files/uploads/upload/parts/1", strings.NewReader(test.body),
+			)
+			request.ContentLength = test.contentLength
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("uploadID", "upload")
+			rctx.URLParams.Add("partNumber", test.partNumber)
+			request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+			handlers.UploadFilePart()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
+
+func TestCompleteFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		code       int
+		logTimes   int
+	}{
+		{name: "complete upload success", code: http.StatusCreated},
+		{name: "upload not found", serviceErr: services.ErrNotFound, code: http.StatusNotFound},
+		{name: "upload incomplete", serviceErr: services.ErrUploadIncomplete, code: http.StatusConflict},
+		{
+			name:       "complete upload failed",
+			serviceErr: errors.New("some error"),
+			code:       http.StatusInternalServerError,
+			logTimes:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().CompleteFileUpload(gomock.Any(), "upload").Times(1).Return(1, test.serviceErr)
+			l.EXPECT().Error("failed to complete file upload", zap.Error(test.serviceErr)).Times(test.logTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/files/uploads/upload/complete", http.NoBody)
+			request = withURLParam(request, "uploadID", "upload")
+			w := httptest.NewRecorder()
+			handlers.CompleteFileUpload()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
+
+func TestAbortFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		code       int
+		logTimes   int
+	}{
+		{name: "abort upload success", code: http.StatusNoContent},
+		{name: "upload not found", serviceErr: services.ErrNotFound, code: http.StatusNotFound},
+		{
+			name:       "abort upload failed",
+			serviceErr: errors.New("some error"),
+			code:       http.StatusInternalServerError,
+			logTimes:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().AbortFileUpload(gomock.Any(), "upload").Times(1).Return(test.serviceErr)
+			l.EXPECT().Error("failed to abort file upload", zap.Error(test.serviceErr)).Times(test.logTimes)
+
+			request := httptest.NewRequest(http.MethodDelete, "/api/user/files/uploads/upload", http.NoBody)
+			request = withURLParam(request, "uploadID", "upload")
+			w := httptest.NewRecorder()
+			handlers.AbortFileUpload()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
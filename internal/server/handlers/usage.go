@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// GetUsage обработчик для получения использования хранилища пользователем и его ограничений.
+func (h *Handlers) GetUsage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		usage, err := h.services.GetUsage(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to get usage", zap.Error(err))
+			return
+		}
+
+		h.writeJSON(w, http.StatusOK, usage)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	t.Run("get usage success", func(t *testing.T) {
+		usage := models.UserUsage{UsedBytes: 100, MaxBytes: 1000, Items: 2, MaxItems: 10, MaxFileSize: 500}
+		s.EXPECT().GetUsage(gomock.Any()).Times(1).Return(usage, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/usage", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.GetUsage()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t,
+			`{"used_bytes":100,"max_bytes":1000,"max_file_size":500,"items":2,"max_items":10}`, string(resBody))
+	})
+
+	t.Run("get usage failed", func(t *testing.T) {
+		someErr := errors.New("some error")
+		s.EXPECT().GetUsage(gomock.Any()).Times(1).Return(models.UserUsage{}, someErr)
+		l.EXPECT().Error("failed to get usage", zap.Error(someErr)).Times(1)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/user/usage", http.NoBody)
+		w := httptest.NewRecorder()
+		handlers.GetUsage()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+}
+
+func TestWriteQuotaError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		written bool
+	}{
+		{
+			name:    "quota exceeded",
+			err:     fmt.Errorf("wrapped: %w", services.ErrQuotaExceeded),
+			code:    http.StatusForbidden,
+			written: true,
+		},
+		{
+			name:    "file too big",
+			err:     fmt.Errorf("wrapped: %w", services.ErrFileTooBig),
+			code:    http.StatusRequestEntityTooLarge,
+			written: true,
+		},
+		{
+			name: "other error",
+			err:  errors.New("some error"),
+			code: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			assert.Equal(t, test.written, writeQuotaError(w, test.err))
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
@@ -59,6 +59,9 @@ func (h *Handlers) CreateUserToken() http.HandlerFunc {
 		resp, err := h.services.CreateUserToken(r.Context(), req)
 
 		if err != nil {
+			if writeTooManyAttempts(w, err) {
+				return
+			}
 			if errors.Is(err, services.ErrUserLoginCreds) {
 				w.WriteHeader(http.StatusUnauthorized)
 				return
@@ -80,3 +83,60 @@ func (h *Handlers) CreateUserToken() http.HandlerFunc {
 		}
 	}
 }
+
+// RefreshUserToken обработчик для обновления ключа доступа пользователя по refresh токену.
+func (h *Handlers) RefreshUserToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.RefreshUserTokenRequest
+
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.logger.Error(readReqErrStr, zap.Error(err))
+			return
+		}
+
+		resp, err := h.services.RefreshUserToken(r.Context(), req)
+
+		if err != nil {
+			if errors.Is(err, services.ErrSessionNotFound) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to refresh user token", zap.Error(err))
+			return
+		}
+
+		w.Header().Set(ContentTypeHeader, JSONContentType)
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error(encRespErrStr, zap.Error(err))
+			return
+		}
+	}
+}
+
+// LogoutUser обработчик для завершения текущей сессии пользователя.
+func (h *Handlers) LogoutUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.services.LogoutUser(r.Context())
+
+		if err != nil {
+			if errors.Is(err, services.ErrSessionNotFound) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			h.logger.Error("failed to logout user", zap.Error(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
@@ -169,13 +170,15 @@ func TestCreateUserToken(t *testing.T) {
 			name: "create user token success",
 			serviceResponse: serviceResponse{
 				res: models.CreateUserTokenResponse{
-					AuthToken: "qwerty",
+					AuthToken:    "qwerty",
+					RefreshToken: "refresh",
+					ExpiresIn:    900,
 				},
 				err: nil,
 			},
 			want: want{
 				code:          http.StatusOK,
-				body:          "{\"auth_token\":\"qwerty\"}\n",
+				body:          `{"auth_token":"qwerty","refresh_token":"refresh","expires_in":900}` + "\n",
 				errorLogTimes: 0,
 				log:           "",
 			},
@@ -233,6 +236,23 @@ func TestCreateUserToken(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("create user token failed with too many attempts", func(t *testing.T) {
+		s.EXPECT().
+			CreateUserToken(gomock.Any(), requestObject).
+			Times(1).
+			Return(models.CreateUserTokenResponse{}, &services.RetryAfterError{RetryAfter: 90 * time.Second})
+
+		request := httptest.NewRequest(http.MethodPost, "/api/user/token", strings.NewReader(requestBody))
+		w := httptest.NewRecorder()
+		handlers.CreateUserToken()(w, request)
+
+		res := w.Result()
+		defer closeBody(t, res)
+
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "90", res.Header.Get(RetryAfterHeader))
+	})
 }
 
 func TestFailedReadBodyLoginUser(t *testing.T) {
@@ -259,3 +279,168 @@ func TestFailedReadBodyLoginUser(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 	})
 }
+
+func TestRefreshUserToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	requestBody := `{"refresh_token":"refresh"}`
+	requestObject := models.RefreshUserTokenRequest{RefreshToken: "refresh"}
+
+	type serviceResponse struct {
+		res models.CreateUserTokenResponse
+		err error
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name            string
+		serviceResponse serviceResponse
+		want            want
+	}{
+		{
+			name: "refresh user token success",
+			serviceResponse: serviceResponse{
+				res: models.CreateUserTokenResponse{
+					AuthToken:    "qwerty",
+					RefreshToken: "new refresh",
+					ExpiresIn:    900,
+				},
+				err: nil,
+			},
+			want: want{
+				code:          http.StatusOK,
+				body:          `{"auth_token":"qwerty","refresh_token":"new refresh","expires_in":900}` + "\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "refresh user token failed with ErrSessionNotFound",
+			serviceResponse: serviceResponse{
+				res: models.CreateUserTokenResponse{},
+				err: services.ErrSessionNotFound,
+			},
+			want: want{
+				code:          http.StatusUnauthorized,
+				body:          "",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "refresh user token failed with some error",
+			serviceResponse: serviceResponse{
+				res: models.CreateUserTokenResponse{},
+				err: errors.New("some error"),
+			},
+			want: want{
+				code:          http.StatusInternalServerError,
+				body:          "",
+				errorLogTimes: 1,
+				log:           "failed to refresh user token",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().
+				RefreshUserToken(gomock.Any(), requestObject).
+				Times(1).
+				Return(test.serviceResponse.res, test.serviceResponse.err)
+
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceResponse.err)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", strings.NewReader(requestBody))
+			w := httptest.NewRecorder()
+			handlers.RefreshUserToken()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+
+			if http.StatusOK == res.StatusCode {
+				resBody, err := io.ReadAll(res.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.want.body, string(resBody))
+			}
+		})
+	}
+}
+
+func TestLogoutUser(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "logout user success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusNoContent,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "logout user failed with ErrSessionNotFound",
+			serviceErr: services.ErrSessionNotFound,
+			want: want{
+				code:          http.StatusUnauthorized,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "logout user failed with some error",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to logout user",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().LogoutUser(gomock.Any()).Times(1).Return(test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPost, "/api/user/logout", http.NoBody)
+			w := httptest.NewRecorder()
+			handlers.LogoutUser()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/handlers/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetVault(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	errSome := errors.New("some error")
+
+	type serviceResponse struct {
+		res models.Vault
+		err error
+	}
+
+	type want struct {
+		code          int
+		body          string
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name            string
+		serviceResponse serviceResponse
+		want            want
+	}{
+		{
+			name: "get vault success",
+			serviceResponse: serviceResponse{
+				res: models.Vault{Salt: []byte("salt"), Check: []byte("test")},
+				err: nil,
+			},
+			want: want{
+				code:          http.StatusOK,
+				body:          `{"salt":"c2FsdA==","check":"dGVzdA=="}` + "\n",
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name: "get vault failed",
+			serviceResponse: serviceResponse{
+				res: models.Vault{},
+				err: errSome,
+			},
+			want: want{
+				code:          http.StatusInternalServerError,
+				body:          "",
+				errorLogTimes: 1,
+				log:           "failed to get vault",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().GetVault(gomock.Any()).Times(1).Return(test.serviceResponse.res, test.serviceResponse.err)
+			l.EXPECT().Error(test.want.log, zap.Error(errSome)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/user/vault", http.NoBody)
+			w := httptest.NewRecorder()
+			handlers.GetVault()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+
+			resBody, err := io.ReadAll(res.Body)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want.body, string(resBody))
+		})
+	}
+}
+
+func TestSetVaultCheck(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s := mocks.NewMockServicer(mockCtrl)
+	l := mocks.NewMockLogger(mockCtrl)
+	handlers := NewHandlers(s, l)
+
+	requestBody := `{"check":"dGVzdA=="}`
+	requestObject := models.UpdateVaultRequest{Check: []byte("test")}
+
+	type want struct {
+		code          int
+		errorLogTimes int
+		log           string
+	}
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		want       want
+	}{
+		{
+			name:       "set vault check success",
+			serviceErr: nil,
+			want: want{
+				code:          http.StatusOK,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "when vault check exist",
+			serviceErr: services.ErrVaultCheckExist,
+			want: want{
+				code:          http.StatusConflict,
+				errorLogTimes: 0,
+				log:           "",
+			},
+		},
+		{
+			name:       "set vault check failed",
+			serviceErr: errors.New("some error"),
+			want: want{
+				code:          http.StatusInternalServerError,
+				errorLogTimes: 1,
+				log:           "failed to set vault check",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.EXPECT().SetVaultCheck(gomock.Any(), requestObject).Times(1).Return(test.serviceErr)
+			l.EXPECT().Error(test.want.log, zap.Error(test.serviceErr)).Times(test.want.errorLogTimes)
+
+			request := httptest.NewRequest(http.MethodPut, "/api/user/vault", strings.NewReader(requestBody))
+			w := httptest.NewRecorder()
+			handlers.SetVaultCheck()(w, request)
+
+			res := w.Result()
+			defer closeBody(t, res)
+
+			assert.Equal(t, test.want.code, res.StatusCode)
+		})
+	}
+}
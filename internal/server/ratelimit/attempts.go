@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Attempts считает неудачные попытки входа для каждого ключа (логина или адреса клиента).
+// После free бесплатных попыток ключ блокируется, время блокировки удваивается
+// с каждой следующей неудачей, но не превышает maxLockout.
+// Счетчик сбрасывается после успешного входа или через maxLockout без неудач.
+type Attempts struct {
+	now         func() time.Time
+	entries     map[string]*attempt
+	lastCleanup time.Time
+	free        int
+	baseLockout time.Duration
+	maxLockout  time.Duration
+	mu          sync.Mutex
+}
+
+type attempt struct {
+	lockedUntil time.Time
+	lastFailure time.Time
+	failures    int
+}
+
+// NewAttempts функция инициализации счетчика неудачных попыток.
+// При free равном нулю блокировка отключена.
+func NewAttempts(free int, baseLockout, maxLockout time.Duration) *Attempts {
+	return &Attempts{
+		now:         time.Now,
+		entries:     make(map[string]*attempt),
+		free:        free,
+		baseLockout: baseLockout,
+		maxLockout:  maxLockout,
+	}
+}
+
+// Check функция для получения оставшегося времени блокировки ключа.
+func (a *Attempts) Check(key string) time.Duration {
+	if a.free <= 0 {
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e, ok := a.entries[key]
+	if !ok {
+		return 0
+	}
+
+	return max(e.lockedUntil.Sub(a.now()), 0)
+}
+
+// Fail функция для учета неудачной попытки, возвращает время блокировки ключа.
+func (a *Attempts) Fail(key string) time.Duration {
+	if a.free <= 0 {
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.cleanup(now)
+
+	e, ok := a.entries[key]
+	if !ok || a.expired(e, now) {
+		e = &attempt{}
+		a.entries[key] = e
+	}
+
+	e.failures++
+	e.lastFailure = now
+
+	if e.failures <= a.free {
+		return 0
+	}
+
+	// Удваиваем, пока не достигнут предел: сдвиг на число неудач переполняет time.Duration
+	// уже при базовом времени в несколько минут.
+	lockout := min(a.baseLockout, a.maxLockout)
+	for i := e.failures - a.free - 1; i > 0 && lockout < a.maxLockout; i-- {
+		if lockout > a.maxLockout/2 {
+			lockout = a.maxLockout
+		} else {
+			lockout *= 2
+		}
+	}
+	e.lockedUntil = now.Add(lockout)
+
+	return lockout
+}
+
+// Reset функция для сброса счетчика после успешного входа.
+func (a *Attempts) Reset(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.entries, key)
+}
+
+// expired проверяет, что с последней неудачи прошло достаточно времени для сброса счетчика.
+func (a *Attempts) expired(e *attempt, now time.Time) bool {
+	return now.After(e.lockedUntil) && now.Sub(e.lastFailure) > a.maxLockout
+}
+
+// cleanup удаляет счетчики, которые уже можно сбросить.
+func (a *Attempts) cleanup(now time.Time) {
+	if now.Sub(a.lastCleanup) < a.maxLockout {
+		return
+	}
+	a.lastCleanup = now
+
+	for key, e := range a.entries {
+		if a.expired(e, now) {
+			delete(a.entries, key)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttempts(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	a := NewAttempts(3, time.Second, time.Minute)
+	a.now = func() time.Time { return now }
+
+	t.Run("free attempts", func(t *testing.T) {
+		for range 3 {
+			assert.Zero(t, a.Fail("user"))
+		}
+		assert.Zero(t, a.Check("user"))
+	})
+
+	t.Run("exponential lockout", func(t *testing.T) {
+		assert.Equal(t, time.Second, a.Fail("user"))
+		assert.Equal(t, 2*time.Second, a.Fail("user"))
+		assert.Equal(t, 4*time.Second, a.Fail("user"))
+		assert.Equal(t, 4*time.Second, a.Check("user"))
+
+		now = now.Add(3 * time.Second)
+		assert.Equal(t, time.Second, a.Check("user"))
+	})
+
+	t.Run("lockout capped", func(t *testing.T) {
+		for range 40 {
+			a.Fail("user")
+		}
+
+		assert.Equal(t, time.Minute, a.Check("user"))
+	})
+
+	t.Run("counter expires", func(t *testing.T) {
+		now = now.Add(2*time.Minute + time.Second)
+
+		assert.Zero(t, a.Check("user"))
+		assert.Zero(t, a.Fail("user"))
+	})
+
+	t.Run("reset after success", func(t *testing.T) {
+		for range 4 {
+			a.Fail("other")
+		}
+		assert.Positive(t, a.Check("other"))
+
+		a.Reset("other")
+
+		assert.Zero(t, a.Check("other"))
+	})
+
+	t.Run("large base lockout", func(t *testing.T) {
+		long := NewAttempts(1, 10*time.Minute, 24*time.Hour)
+		long.now = func() time.Time { return now }
+
+		assert.Zero(t, long.Fail("user"))
+		for range 40 {
+			assert.Positive(t, long.Fail("user"))
+		}
+		assert.Equal(t, 24*time.Hour, long.Check("user"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := NewAttempts(0, time.Second, time.Minute)
+
+		for range 10 {
+			assert.Zero(t, disabled.Fail("user"))
+		}
+		assert.Zero(t, disabled.Check("user"))
+	})
+}
@@ -0,0 +1,88 @@
+// Package ratelimit реализует ограничение частоты запросов и защиту от перебора паролей.
+package ratelimit
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTTL время, после которого неактивные ключи удаляются из памяти.
+const idleTTL = 10 * time.Minute
+
+// Limiter ограничивает частоту запросов для каждого ключа (например, адреса клиента)
+// алгоритмом token bucket.
+type Limiter struct {
+	now         func() time.Time
+	limiters    map[string]*limiterEntry
+	lastCleanup time.Time
+	limit       rate.Limit
+	burst       int
+	mu          sync.Mutex
+}
+
+type limiterEntry struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// NewLimiter функция инициализации ограничителя: rps запросов в секунду с запасом burst.
+func NewLimiter(rps float64, burst int) *Limiter {
+	return &Limiter{
+		now:      time.Now,
+		limiters: make(map[string]*limiterEntry),
+		limit:    rate.Limit(rps),
+		burst:    burst,
+	}
+}
+
+// Allow функция для проверки, можно ли выполнить запрос для ключа.
+// Если нельзя, возвращает время, через которое стоит повторить запрос.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	e, ok := l.limiters[key]
+	if !ok {
+		e = &limiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = e
+	}
+	e.seen = now
+
+	r := e.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return false, time.Second
+	}
+
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+// cleanup удаляет ключи, по которым давно не было запросов.
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < idleTTL {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, e := range l.limiters {
+		if now.Sub(e.seen) > idleTTL {
+			delete(l.limiters, key)
+		}
+	}
+}
+
+// RetryAfter функция для получения значения заголовка Retry-After в секундах (не меньше одной).
+func RetryAfter(d time.Duration) string {
+	return strconv.Itoa(max(int(math.Ceil(d.Seconds())), 1))
+}
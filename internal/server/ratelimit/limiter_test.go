@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewLimiter(1, 2)
+	l.now = func() time.Time { return now }
+
+	t.Run("burst allowed", func(t *testing.T) {
+		for range 2 {
+			ok, _ := l.Allow("10.0.0.1")
+			assert.True(t, ok)
+		}
+	})
+
+	t.Run("limited after burst", func(t *testing.T) {
+		ok, retryAfter := l.Allow("10.0.0.1")
+
+		assert.False(t, ok)
+		assert.Equal(t, time.Second, retryAfter)
+	})
+
+	t.Run("other key not limited", func(t *testing.T) {
+		ok, _ := l.Allow("10.0.0.2")
+
+		assert.True(t, ok)
+	})
+
+	t.Run("allowed after refill", func(t *testing.T) {
+		now = now.Add(time.Second)
+
+		ok, _ := l.Allow("10.0.0.1")
+
+		assert.True(t, ok)
+	})
+
+	t.Run("idle keys removed", func(t *testing.T) {
+		now = now.Add(2 * idleTTL)
+
+		_, _ = l.Allow("10.0.0.3")
+
+		assert.Len(t, l.limiters, 1)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, "1", RetryAfter(0))
+	assert.Equal(t, "1", RetryAfter(300*time.Millisecond))
+	assert.Equal(t, "2", RetryAfter(1100*time.Millisecond))
+	assert.Equal(t, "900", RetryAfter(15*time.Minute))
+}
@@ -24,45 +24,58 @@ func authMiddleware(settings *config.Settings, l *zap.Logger, s Storager) func(n
 				return
 			}
 
-			userID := getUserID(settings, authToken)
-			if userID == -1 {
+			claims, err := getClaims(settings, authToken)
+			if err != nil {
 				w.WriteHeader(http.StatusUnauthorized)
-				l.Error("failed to parse auth token")
+				l.Error("failed to parse auth token", zap.Error(err))
 				return
 			}
 
-			_, err := s.GetUserByID(r.Context(), userID)
+			ip, _ := r.Context().Value(constants.KeyClientIP).(string)
+
+			session, err := s.TouchSession(r.Context(), claims.SessionID, ip)
 			if err != nil {
-				if errors.Is(err, storage.ErrUserNotFound) {
+				if errors.Is(err, storage.ErrSessionNotFound) {
 					w.WriteHeader(http.StatusUnauthorized)
 					return
 				}
 
 				w.WriteHeader(http.StatusUnauthorized)
-				l.Error("failed to get user from DB", zap.Error(err))
+				l.Error("failed to get session from DB", zap.Error(err))
 				return
 			}
 
-			newContext := context.WithValue(r.Context(), constants.KeyUserID, userID)
+			if session.UserID != claims.UserID {
+				w.WriteHeader(http.StatusUnauthorized)
+				l.Error("auth token does not match session")
+				return
+			}
+
+			newContext := context.WithValue(r.Context(), constants.KeyUserID, claims.UserID)
+			newContext = context.WithValue(newContext, constants.KeySessionID, claims.SessionID)
 			newRequest := r.WithContext(newContext)
 			next.ServeHTTP(w, newRequest)
 		})
 	}
 }
 
-func getUserID(settings *config.Settings, tokenString string) int {
+// getClaims разбирает токен доступа. Токены без срока действия и сессии не принимаются.
+func getClaims(settings *config.Settings, tokenString string) (*models.Claims, error) {
 	claims := &models.Claims{}
 
 	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return []byte(settings.SecretKey), nil
-	})
-
+		return []byte(settings.JWTKey), nil
+	}, jwt.WithExpirationRequired())
 	if err != nil {
-		return -1
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if claims.SessionID == "" {
+		return nil, errors.New("token without session")
 	}
 
-	return claims.UserID
+	return claims, nil
 }
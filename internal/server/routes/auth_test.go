@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
@@ -22,15 +23,17 @@ func TestAuthMiddleware(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
-	settings, err := config.Setup(false)
-	require.NoError(t, err)
+	settings := testSettings(t)
 
 	logger := zap.NewNop()
 	store := mocks.NewMockStorager(mockCtrl)
 
 	ctx := context.Background()
 	userID := 1
-	userToken := buildJWTString(t, settings, userID)
+	sessionID := "session"
+	userToken := buildJWTString(t, settings, userID, sessionID, time.Hour)
+	expiredToken := buildJWTString(t, settings, userID, sessionID, -time.Hour)
+	legacyToken := buildJWTString(t, settings, userID, "", 0)
 
 	type want struct {
 		code int
@@ -48,12 +51,43 @@ func TestAuthMiddleware(t *testing.T) {
 			withAuthHeader: true,
 			userToken:      userToken,
 			mockStorage: func() {
-				store.EXPECT().GetUserByID(ctx, userID).Times(1).Return(models.User{}, nil)
+				store.EXPECT().TouchSession(ctx, sessionID, gomock.Any()).Times(1).
+					Return(models.Session{ID: sessionID, UserID: userID}, nil)
 			},
 			want: want{
 				code: http.StatusOK,
 			},
 		},
+		{
+			name:           "when auth token expired",
+			withAuthHeader: true,
+			userToken:      expiredToken,
+			mockStorage:    func() {},
+			want: want{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name:           "when auth token without expiry and session",
+			withAuthHeader: true,
+			userToken:      legacyToken,
+			mockStorage:    func() {},
+			want: want{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name:           "when session belongs to another user",
+			withAuthHeader: true,
+			userToken:      userToken,
+			mockStorage: func() {
+				store.EXPECT().TouchSession(ctx, sessionID, gomock.Any()).Times(1).
+					Return(models.Session{ID: sessionID, UserID: 2}, nil)
+			},
+			want: want{
+				code: http.StatusUnauthorized,
+			},
+		},
 		{
 			name:           "without auth token",
 			withAuthHeader: false,
@@ -73,22 +107,24 @@ func TestAuthMiddleware(t *testing.T) {
 			},
 		},
 		{
-			name:           "when user not found",
+			name:           "when session revoked",
 			withAuthHeader: true,
 			userToken:      userToken,
 			mockStorage: func() {
-				store.EXPECT().GetUserByID(ctx, userID).Times(1).Return(models.User{}, storage.ErrUserNotFound)
+				store.EXPECT().TouchSession(ctx, sessionID, gomock.Any()).Times(1).
+					Return(models.Session{}, storage.ErrSessionNotFound)
 			},
 			want: want{
 				code: http.StatusUnauthorized,
 			},
 		},
 		{
-			name:           "failed get user from storage",
+			name:           "failed get session from storage",
 			withAuthHeader: true,
 			userToken:      userToken,
 			mockStorage: func() {
-				store.EXPECT().GetUserByID(ctx, userID).Times(1).Return(models.User{}, errors.New("some error"))
+				store.EXPECT().TouchSession(ctx, sessionID, gomock.Any()).Times(1).
+					Return(models.Session{}, errors.New("some error"))
 			},
 			want: want{
 				code: http.StatusUnauthorized,
@@ -120,14 +156,20 @@ func TestAuthMiddleware(t *testing.T) {
 	}
 }
 
-func buildJWTString(t *testing.T, settings *config.Settings, userID int) string {
+func buildJWTString(t *testing.T, settings *config.Settings, userID int, sessionID string, ttl time.Duration) string {
 	t.Helper()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.Claims{
-		UserID: userID,
-	})
+	claims := models.Claims{
+		SessionID: sessionID,
+		UserID:    userID,
+	}
+	if ttl != 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(ttl))
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	tokenString, err := token.SignedString([]byte(settings.SecretKey))
+	tokenString, err := token.SignedString([]byte(settings.JWTKey))
 	require.NoError(t, err)
 
 	return tokenString
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+// withClientIP сохраняет адрес клиента в контексте запроса.
+func withClientIP() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+
+			newContext := context.WithValue(r.Context(), constants.KeyClientIP, ip)
+			next.ServeHTTP(w, r.WithContext(newContext))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{
+			name:       "address with port",
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "ipv6 address with port",
+			remoteAddr: "[2001:db8::1]:1234",
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "address without port",
+			remoteAddr: "192.0.2.1",
+			want:       "192.0.2.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got any
+
+			handler := withClientIP()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = r.Context().Value(constants.KeyClientIP)
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			request.RemoteAddr = test.remoteAddr
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
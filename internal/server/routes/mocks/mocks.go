@@ -36,6 +36,20 @@ func (m *MockHandlerer) EXPECT() *MockHandlererMockRecorder {
 	return m.recorder
 }
 
+// AbortFileUpload mocks base method.
+func (m *MockHandlerer) AbortFileUpload() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortFileUpload")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// AbortFileUpload indicates an expected call of AbortFileUpload.
+func (mr *MockHandlererMockRecorder) AbortFileUpload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortFileUpload", reflect.TypeOf((*MockHandlerer)(nil).AbortFileUpload))
+}
+
 // AddCard mocks base method.
 func (m *MockHandlerer) AddCard() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -64,6 +78,20 @@ func (mr *MockHandlererMockRecorder) AddFile() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFile", reflect.TypeOf((*MockHandlerer)(nil).AddFile))
 }
 
+// AddOTP mocks base method.
+func (m *MockHandlerer) AddOTP() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOTP")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// AddOTP indicates an expected call of AddOTP.
+func (mr *MockHandlererMockRecorder) AddOTP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOTP", reflect.TypeOf((*MockHandlerer)(nil).AddOTP))
+}
+
 // AddPassword mocks base method.
 func (m *MockHandlerer) AddPassword() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -92,6 +120,20 @@ func (mr *MockHandlererMockRecorder) AddText() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddText", reflect.TypeOf((*MockHandlerer)(nil).AddText))
 }
 
+// CompleteFileUpload mocks base method.
+func (m *MockHandlerer) CompleteFileUpload() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteFileUpload")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// CompleteFileUpload indicates an expected call of CompleteFileUpload.
+func (mr *MockHandlererMockRecorder) CompleteFileUpload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteFileUpload", reflect.TypeOf((*MockHandlerer)(nil).CompleteFileUpload))
+}
+
 // CreateUserToken mocks base method.
 func (m *MockHandlerer) CreateUserToken() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -106,6 +148,104 @@ func (mr *MockHandlererMockRecorder) CreateUserToken() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockHandlerer)(nil).CreateUserToken))
 }
 
+// DeleteCard mocks base method.
+func (m *MockHandlerer) DeleteCard() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCard")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// DeleteCard indicates an expected call of DeleteCard.
+func (mr *MockHandlererMockRecorder) DeleteCard() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCard", reflect.TypeOf((*MockHandlerer)(nil).DeleteCard))
+}
+
+// DeleteFile mocks base method.
+func (m *MockHandlerer) DeleteFile() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockHandlererMockRecorder) DeleteFile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockHandlerer)(nil).DeleteFile))
+}
+
+// DeleteOTP mocks base method.
+func (m *MockHandlerer) DeleteOTP() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOTP")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// DeleteOTP indicates an expected call of DeleteOTP.
+func (mr *MockHandlererMockRecorder) DeleteOTP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOTP", reflect.TypeOf((*MockHandlerer)(nil).DeleteOTP))
+}
+
+// DeletePassword mocks base method.
+func (m *MockHandlerer) DeletePassword() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePassword")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// DeletePassword indicates an expected call of DeletePassword.
+func (mr *MockHandlererMockRecorder) DeletePassword() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePassword", reflect.TypeOf((*MockHandlerer)(nil).DeletePassword))
+}
+
+// DeleteText mocks base method.
+func (m *MockHandlerer) DeleteText() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteText")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// DeleteText indicates an expected call of DeleteText.
+func (mr *MockHandlererMockRecorder) DeleteText() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteText", reflect.TypeOf((*MockHandlerer)(nil).DeleteText))
+}
+
+// EmptyTrash mocks base method.
+func (m *MockHandlerer) EmptyTrash() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmptyTrash")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// EmptyTrash indicates an expected call of EmptyTrash.
+func (mr *MockHandlererMockRecorder) EmptyTrash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmptyTrash", reflect.TypeOf((*MockHandlerer)(nil).EmptyTrash))
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockHandlerer) EnrollTwoFactor() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockHandlererMockRecorder) EnrollTwoFactor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockHandlerer)(nil).EnrollTwoFactor))
+}
+
 // FetchUserData mocks base method.
 func (m *MockHandlerer) FetchUserData() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -148,6 +288,48 @@ func (mr *MockHandlererMockRecorder) GetFile() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockHandlerer)(nil).GetFile))
 }
 
+// GetFileUpload mocks base method.
+func (m *MockHandlerer) GetFileUpload() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileUpload")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetFileUpload indicates an expected call of GetFileUpload.
+func (mr *MockHandlererMockRecorder) GetFileUpload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUpload", reflect.TypeOf((*MockHandlerer)(nil).GetFileUpload))
+}
+
+// GetHistory mocks base method.
+func (m *MockHandlerer) GetHistory(dataType, idParam string) http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", dataType, idParam)
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockHandlererMockRecorder) GetHistory(dataType, idParam interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockHandlerer)(nil).GetHistory), dataType, idParam)
+}
+
+// GetOTP mocks base method.
+func (m *MockHandlerer) GetOTP() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOTP")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetOTP indicates an expected call of GetOTP.
+func (mr *MockHandlererMockRecorder) GetOTP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOTP", reflect.TypeOf((*MockHandlerer)(nil).GetOTP))
+}
+
 // GetPassword mocks base method.
 func (m *MockHandlerer) GetPassword() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -162,6 +344,20 @@ func (mr *MockHandlererMockRecorder) GetPassword() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassword", reflect.TypeOf((*MockHandlerer)(nil).GetPassword))
 }
 
+// GetSessions mocks base method.
+func (m *MockHandlerer) GetSessions() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockHandlererMockRecorder) GetSessions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockHandlerer)(nil).GetSessions))
+}
+
 // GetText mocks base method.
 func (m *MockHandlerer) GetText() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -176,6 +372,160 @@ func (mr *MockHandlererMockRecorder) GetText() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetText", reflect.TypeOf((*MockHandlerer)(nil).GetText))
 }
 
+// GetTrash mocks base method.
+func (m *MockHandlerer) GetTrash() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockHandlererMockRecorder) GetTrash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockHandlerer)(nil).GetTrash))
+}
+
+// GetUsage mocks base method.
+func (m *MockHandlerer) GetUsage() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockHandlererMockRecorder) GetUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockHandlerer)(nil).GetUsage))
+}
+
+// GetVault mocks base method.
+func (m *MockHandlerer) GetVault() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVault")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// GetVault indicates an expected call of GetVault.
+func (mr *MockHandlererMockRecorder) GetVault() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVault", reflect.TypeOf((*MockHandlerer)(nil).GetVault))
+}
+
+// InitFileUpload mocks base method.
+func (m *MockHandlerer) InitFileUpload() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitFileUpload")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// InitFileUpload indicates an expected call of InitFileUpload.
+func (mr *MockHandlererMockRecorder) InitFileUpload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitFileUpload", reflect.TypeOf((*MockHandlerer)(nil).InitFileUpload))
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockHandlerer) LoginTwoFactor() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockHandlererMockRecorder) LoginTwoFactor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockHandlerer)(nil).LoginTwoFactor))
+}
+
+// LogoutUser mocks base method.
+func (m *MockHandlerer) LogoutUser() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutUser")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// LogoutUser indicates an expected call of LogoutUser.
+func (mr *MockHandlererMockRecorder) LogoutUser() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockHandlerer)(nil).LogoutUser))
+}
+
+// PatchCard mocks base method.
+func (m *MockHandlerer) PatchCard() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCard")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// PatchCard indicates an expected call of PatchCard.
+func (mr *MockHandlererMockRecorder) PatchCard() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCard", reflect.TypeOf((*MockHandlerer)(nil).PatchCard))
+}
+
+// PatchFile mocks base method.
+func (m *MockHandlerer) PatchFile() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchFile")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// PatchFile indicates an expected call of PatchFile.
+func (mr *MockHandlererMockRecorder) PatchFile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchFile", reflect.TypeOf((*MockHandlerer)(nil).PatchFile))
+}
+
+// PatchOTP mocks base method.
+func (m *MockHandlerer) PatchOTP() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchOTP")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// PatchOTP indicates an expected call of PatchOTP.
+func (mr *MockHandlererMockRecorder) PatchOTP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchOTP", reflect.TypeOf((*MockHandlerer)(nil).PatchOTP))
+}
+
+// PatchPassword mocks base method.
+func (m *MockHandlerer) PatchPassword() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPassword")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// PatchPassword indicates an expected call of PatchPassword.
+func (mr *MockHandlererMockRecorder) PatchPassword() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPassword", reflect.TypeOf((*MockHandlerer)(nil).PatchPassword))
+}
+
+// PatchText mocks base method.
+func (m *MockHandlerer) PatchText() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchText")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// PatchText indicates an expected call of PatchText.
+func (mr *MockHandlererMockRecorder) PatchText() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchText", reflect.TypeOf((*MockHandlerer)(nil).PatchText))
+}
+
 // Ping mocks base method.
 func (m *MockHandlerer) Ping() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -190,6 +540,20 @@ func (mr *MockHandlererMockRecorder) Ping() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockHandlerer)(nil).Ping))
 }
 
+// RefreshUserToken mocks base method.
+func (m *MockHandlerer) RefreshUserToken() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshUserToken")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// RefreshUserToken indicates an expected call of RefreshUserToken.
+func (mr *MockHandlererMockRecorder) RefreshUserToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshUserToken", reflect.TypeOf((*MockHandlerer)(nil).RefreshUserToken))
+}
+
 // RegisterUser mocks base method.
 func (m *MockHandlerer) RegisterUser() http.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -204,6 +568,174 @@ func (mr *MockHandlererMockRecorder) RegisterUser() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockHandlerer)(nil).RegisterUser))
 }
 
+// ReserveDataID mocks base method.
+func (m *MockHandlerer) ReserveDataID() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveDataID")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// ReserveDataID indicates an expected call of ReserveDataID.
+func (mr *MockHandlererMockRecorder) ReserveDataID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveDataID", reflect.TypeOf((*MockHandlerer)(nil).ReserveDataID))
+}
+
+// RestoreRevision mocks base method.
+func (m *MockHandlerer) RestoreRevision(dataType, idParam string) http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", dataType, idParam)
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockHandlererMockRecorder) RestoreRevision(dataType, idParam interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockHandlerer)(nil).RestoreRevision), dataType, idParam)
+}
+
+// RestoreTrash mocks base method.
+func (m *MockHandlerer) RestoreTrash() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTrash")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// RestoreTrash indicates an expected call of RestoreTrash.
+func (mr *MockHandlererMockRecorder) RestoreTrash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTrash", reflect.TypeOf((*MockHandlerer)(nil).RestoreTrash))
+}
+
+// RevokeSession mocks base method.
+func (m *MockHandlerer) RevokeSession() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockHandlererMockRecorder) RevokeSession() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockHandlerer)(nil).RevokeSession))
+}
+
+// SetVaultCheck mocks base method.
+func (m *MockHandlerer) SetVaultCheck() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVaultCheck")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// SetVaultCheck indicates an expected call of SetVaultCheck.
+func (mr *MockHandlererMockRecorder) SetVaultCheck() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVaultCheck", reflect.TypeOf((*MockHandlerer)(nil).SetVaultCheck))
+}
+
+// UpdateCard mocks base method.
+func (m *MockHandlerer) UpdateCard() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCard")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// UpdateCard indicates an expected call of UpdateCard.
+func (mr *MockHandlererMockRecorder) UpdateCard() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCard", reflect.TypeOf((*MockHandlerer)(nil).UpdateCard))
+}
+
+// UpdateFile mocks base method.
+func (m *MockHandlerer) UpdateFile() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFile")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// UpdateFile indicates an expected call of UpdateFile.
+func (mr *MockHandlererMockRecorder) UpdateFile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFile", reflect.TypeOf((*MockHandlerer)(nil).UpdateFile))
+}
+
+// UpdateOTP mocks base method.
+func (m *MockHandlerer) UpdateOTP() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOTP")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// UpdateOTP indicates an expected call of UpdateOTP.
+func (mr *MockHandlererMockRecorder) UpdateOTP() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOTP", reflect.TypeOf((*MockHandlerer)(nil).UpdateOTP))
+}
+
+// UpdatePassword mocks base method.
+func (m *MockHandlerer) UpdatePassword() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockHandlererMockRecorder) UpdatePassword() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockHandlerer)(nil).UpdatePassword))
+}
+
+// UpdateText mocks base method.
+func (m *MockHandlerer) UpdateText() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateText")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// UpdateText indicates an expected call of UpdateText.
+func (mr *MockHandlererMockRecorder) UpdateText() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateText", reflect.TypeOf((*MockHandlerer)(nil).UpdateText))
+}
+
+// UploadFilePart mocks base method.
+func (m *MockHandlerer) UploadFilePart() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFilePart")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// UploadFilePart indicates an expected call of UploadFilePart.
+func (mr *MockHandlererMockRecorder) UploadFilePart() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFilePart", reflect.TypeOf((*MockHandlerer)(nil).UploadFilePart))
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockHandlerer) VerifyTwoFactor() http.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor")
+	ret0, _ := ret[0].(http.HandlerFunc)
+	return ret0
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockHandlererMockRecorder) VerifyTwoFactor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockHandlerer)(nil).VerifyTwoFactor))
+}
+
 // MockStorager is a mock of Storager interface.
 type MockStorager struct {
 	ctrl     *gomock.Controller
@@ -227,17 +759,17 @@ func (m *MockStorager) EXPECT() *MockStoragerMockRecorder {
 	return m.recorder
 }
 
-// GetUserByID mocks base method.
-func (m *MockStorager) GetUserByID(ctx context.Context, userID int) (models.User, error) {
+// TouchSession mocks base method.
+func (m *MockStorager) TouchSession(ctx context.Context, sessionID, ip string) (models.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
-	ret0, _ := ret[0].(models.User)
+	ret := m.ctrl.Call(m, "TouchSession", ctx, sessionID, ip)
+	ret0, _ := ret[0].(models.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockStoragerMockRecorder) GetUserByID(ctx, userID interface{}) *gomock.Call {
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockStoragerMockRecorder) TouchSession(ctx, sessionID, ip interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStorager)(nil).GetUserByID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockStorager)(nil).TouchSession), ctx, sessionID, ip)
 }
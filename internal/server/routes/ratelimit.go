@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/ratelimit"
+)
+
+// withRateLimit ограничивает частоту запросов с одного адреса клиента.
+// При превышении отвечает 429 с заголовком Retry-After. При нулевой частоте ограничение отключено.
+func withRateLimit(rps float64, burst int) func(next http.Handler) http.Handler {
+	if rps <= 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	limiter := ratelimit.NewLimiter(rps, burst)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, _ := r.Context().Value(constants.KeyClientIP).(string)
+
+			if ok, retryAfter := limiter.Allow(ip); !ok {
+				w.Header().Set("Retry-After", ratelimit.RetryAfter(retryAfter))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(handler http.Handler, ip string) *http.Response {
+		r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		r = r.WithContext(context.WithValue(r.Context(), constants.KeyClientIP, ip))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w.Result()
+	}
+
+	t.Run("requests limited per client address", func(t *testing.T) {
+		handler := withRateLimit(0.001, 2)(next)
+
+		for range 2 {
+			res := request(handler, "192.0.2.1")
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		}
+
+		res := request(handler, "192.0.2.1")
+		defer closeBody(t, res)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.NotEmpty(t, res.Header.Get("Retry-After"))
+
+		other := request(handler, "192.0.2.2")
+		defer closeBody(t, other)
+		assert.Equal(t, http.StatusOK, other.StatusCode)
+	})
+
+	t.Run("rate limit disabled", func(t *testing.T) {
+		handler := withRateLimit(0, 0)(next)
+
+		for range 10 {
+			res := request(handler, "192.0.2.1")
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		}
+	})
+}
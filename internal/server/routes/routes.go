@@ -16,25 +16,64 @@ type Handlerer interface {
 	Ping() http.HandlerFunc
 	RegisterUser() http.HandlerFunc
 	CreateUserToken() http.HandlerFunc
+	RefreshUserToken() http.HandlerFunc
+	LoginTwoFactor() http.HandlerFunc
+	EnrollTwoFactor() http.HandlerFunc
+	VerifyTwoFactor() http.HandlerFunc
+	LogoutUser() http.HandlerFunc
+	GetSessions() http.HandlerFunc
+	RevokeSession() http.HandlerFunc
+	GetUsage() http.HandlerFunc
 	FetchUserData() http.HandlerFunc
+	ReserveDataID() http.HandlerFunc
+	GetHistory(dataType, idParam string) http.HandlerFunc
+	RestoreRevision(dataType, idParam string) http.HandlerFunc
+	GetTrash() http.HandlerFunc
+	RestoreTrash() http.HandlerFunc
+	EmptyTrash() http.HandlerFunc
+	GetVault() http.HandlerFunc
+	SetVaultCheck() http.HandlerFunc
 	GetPassword() http.HandlerFunc
 	AddPassword() http.HandlerFunc
+	UpdatePassword() http.HandlerFunc
+	PatchPassword() http.HandlerFunc
+	DeletePassword() http.HandlerFunc
 	GetCard() http.HandlerFunc
 	AddCard() http.HandlerFunc
+	UpdateCard() http.HandlerFunc
+	PatchCard() http.HandlerFunc
+	DeleteCard() http.HandlerFunc
 	GetText() http.HandlerFunc
 	AddText() http.HandlerFunc
+	UpdateText() http.HandlerFunc
+	PatchText() http.HandlerFunc
+	DeleteText() http.HandlerFunc
+	GetOTP() http.HandlerFunc
+	AddOTP() http.HandlerFunc
+	UpdateOTP() http.HandlerFunc
+	PatchOTP() http.HandlerFunc
+	DeleteOTP() http.HandlerFunc
 	GetFile() http.HandlerFunc
+	InitFileUpload() http.HandlerFunc
+	GetFileUpload() http.HandlerFunc
+	UploadFilePart() http.HandlerFunc
+	CompleteFileUpload() http.HandlerFunc
+	AbortFileUpload() http.HandlerFunc
 	AddFile() http.HandlerFunc
+	UpdateFile() http.HandlerFunc
+	PatchFile() http.HandlerFunc
+	DeleteFile() http.HandlerFunc
 }
 
 // Storager интерфейс для хранилища данных.
 type Storager interface {
-	GetUserByID(ctx context.Context, userID int) (models.User, error)
+	TouchSession(ctx context.Context, sessionID, ip string) (models.Session, error)
 }
 
 var (
-	JSONContentType     = "application/json"
-	FormDataContentType = "multipart/form-data"
+	JSONContentType        = "application/json"
+	FormDataContentType    = "multipart/form-data"
+	OctetStreamContentType = "application/octet-stream"
 )
 
 // NewRouter функция инициализации роутинга.
@@ -45,41 +84,105 @@ func NewRouter(h Handlerer, settings *config.Settings, l *zap.Logger, s Storager
 
 	r.Route("/api/user", func(r chi.Router) {
 		r.Use(withRequestLogging(l))
+		r.Use(withClientIP())
+		r.Use(withRateLimit(settings.Limits.RPS, settings.Limits.Burst))
 
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.AllowContentType(FormDataContentType))
 			r.Use(authMiddleware(settings, l, s))
 
 			r.Route("/files", func(r chi.Router) {
-				r.Get("/{fileMark}", h.GetFile())
-				r.Post("/", h.AddFile())
+				r.Get("/{fileID}", h.GetFile())
+				r.Delete("/{fileID}", h.DeleteFile())
+				r.Get("/{fileID}/history", h.GetHistory("file", "fileID"))
+				r.Post("/{fileID}/history/{version}/restore", h.RestoreRevision("file", "fileID"))
+
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.AllowContentType(FormDataContentType))
+
+					r.Post("/", h.AddFile())
+					r.Put("/{fileID}", h.UpdateFile())
+				})
+
+				r.With(middleware.AllowContentType(JSONContentType)).Patch("/{fileID}", h.PatchFile())
+
+				r.Route("/uploads", func(r chi.Router) {
+					r.With(middleware.AllowContentType(JSONContentType)).Post("/", h.InitFileUpload())
+					r.Get("/{uploadID}", h.GetFileUpload())
+					r.Delete("/{uploadID}", h.AbortFileUpload())
+					r.Post("/{uploadID}/complete", h.CompleteFileUpload())
+					r.With(middleware.AllowContentType(OctetStreamContentType)).
+						Put("/{uploadID}/parts/{partNumber}", h.UploadFilePart())
+				})
 			})
 		})
 
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.AllowContentType(JSONContentType))
 
-			r.Post("/register", h.RegisterUser())
-			r.Post("/token", h.CreateUserToken())
+			r.Group(func(r chi.Router) {
+				r.Use(withRateLimit(settings.Limits.AuthRPS, settings.Limits.AuthBurst))
+
+				r.Post("/register", h.RegisterUser())
+				r.Post("/token", h.CreateUserToken())
+				r.Post("/token/refresh", h.RefreshUserToken())
+				r.Post("/token/2fa", h.LoginTwoFactor())
+			})
 
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware(settings, l, s))
 
+				r.Post("/logout", h.LogoutUser())
+				r.Post("/2fa/enroll", h.EnrollTwoFactor())
+				r.Post("/2fa/verify", h.VerifyTwoFactor())
+				r.Get("/sessions", h.GetSessions())
+				r.Delete("/sessions/{sessionID}", h.RevokeSession())
 				r.Get("/data", h.FetchUserData())
+				r.Post("/data/ids", h.ReserveDataID())
+				r.Get("/usage", h.GetUsage())
+				r.Get("/vault", h.GetVault())
+				r.Put("/vault", h.SetVaultCheck())
+				r.Get("/trash", h.GetTrash())
+				r.Delete("/trash", h.EmptyTrash())
+				r.Post("/trash/{dataID}/restore", h.RestoreTrash())
 
 				r.Route("/passwords", func(r chi.Router) {
 					r.Get("/{passwordID}", h.GetPassword())
+					r.Put("/{passwordID}", h.UpdatePassword())
+					r.Patch("/{passwordID}", h.PatchPassword())
+					r.Delete("/{passwordID}", h.DeletePassword())
 					r.Post("/", h.AddPassword())
+					r.Get("/{passwordID}/history", h.GetHistory("password", "passwordID"))
+					r.Post("/{passwordID}/history/{version}/restore", h.RestoreRevision("password", "passwordID"))
 				})
 
 				r.Route("/cards", func(r chi.Router) {
 					r.Get("/{cardID}", h.GetCard())
+					r.Put("/{cardID}", h.UpdateCard())
+					r.Patch("/{cardID}", h.PatchCard())
+					r.Delete("/{cardID}", h.DeleteCard())
 					r.Post("/", h.AddCard())
+					r.Get("/{cardID}/history", h.GetHistory("card", "cardID"))
+					r.Post("/{cardID}/history/{version}/restore", h.RestoreRevision("card", "cardID"))
 				})
 
 				r.Route("/texts", func(r chi.Router) {
 					r.Get("/{textID}", h.GetText())
+					r.Put("/{textID}", h.UpdateText())
+					r.Patch("/{textID}", h.PatchText())
+					r.Delete("/{textID}", h.DeleteText())
 					r.Post("/", h.AddText())
+					r.Get("/{textID}/history", h.GetHistory("text", "textID"))
+					r.Post("/{textID}/history/{version}/restore", h.RestoreRevision("text", "textID"))
+				})
+
+				r.Route("/otps", func(r chi.Router) {
+					r.Get("/{otpID}", h.GetOTP())
+					r.Put("/{otpID}", h.UpdateOTP())
+					r.Patch("/{otpID}", h.PatchOTP())
+					r.Delete("/{otpID}", h.DeleteOTP())
+					r.Post("/", h.AddOTP())
+					r.Get("/{otpID}/history", h.GetHistory("otp", "otpID"))
+					r.Post("/{otpID}/history/{version}/restore", h.RestoreRevision("otp", "otpID"))
 				})
 			})
 		})
@@ -19,8 +19,7 @@ func TestNewRouter(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		handlers := mocks.NewMockHandlerer(mockCtrl)
-		settings, err := config.Setup(false)
-		require.NoError(t, err)
+		settings := testSettings(t)
 
 		logger := zap.NewNop()
 		storage := mocks.NewMockStorager(mockCtrl)
@@ -28,15 +27,53 @@ func TestNewRouter(t *testing.T) {
 		handlers.EXPECT().Ping().Times(1)
 		handlers.EXPECT().RegisterUser().Times(1)
 		handlers.EXPECT().CreateUserToken().Times(1)
+		handlers.EXPECT().RefreshUserToken().Times(1)
+		handlers.EXPECT().LoginTwoFactor().Times(1)
+		handlers.EXPECT().EnrollTwoFactor().Times(1)
+		handlers.EXPECT().VerifyTwoFactor().Times(1)
+		handlers.EXPECT().LogoutUser().Times(1)
+		handlers.EXPECT().GetSessions().Times(1)
+		handlers.EXPECT().RevokeSession().Times(1)
+		handlers.EXPECT().GetUsage().Times(1)
 		handlers.EXPECT().FetchUserData().Times(1)
+		handlers.EXPECT().ReserveDataID().Times(1)
+		handlers.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Times(5)
+		handlers.EXPECT().RestoreRevision(gomock.Any(), gomock.Any()).Times(5)
+		handlers.EXPECT().GetTrash().Times(1)
+		handlers.EXPECT().RestoreTrash().Times(1)
+		handlers.EXPECT().EmptyTrash().Times(1)
+		handlers.EXPECT().GetVault().Times(1)
+		handlers.EXPECT().SetVaultCheck().Times(1)
 		handlers.EXPECT().GetPassword().Times(1)
 		handlers.EXPECT().AddPassword().Times(1)
+		handlers.EXPECT().UpdatePassword().Times(1)
+		handlers.EXPECT().PatchPassword().Times(1)
+		handlers.EXPECT().DeletePassword().Times(1)
 		handlers.EXPECT().GetCard().Times(1)
 		handlers.EXPECT().AddCard().Times(1)
+		handlers.EXPECT().UpdateCard().Times(1)
+		handlers.EXPECT().PatchCard().Times(1)
+		handlers.EXPECT().DeleteCard().Times(1)
 		handlers.EXPECT().GetText().Times(1)
 		handlers.EXPECT().AddText().Times(1)
+		handlers.EXPECT().UpdateText().Times(1)
+		handlers.EXPECT().PatchText().Times(1)
+		handlers.EXPECT().DeleteText().Times(1)
+		handlers.EXPECT().GetOTP().Times(1)
+		handlers.EXPECT().AddOTP().Times(1)
+		handlers.EXPECT().UpdateOTP().Times(1)
+		handlers.EXPECT().PatchOTP().Times(1)
+		handlers.EXPECT().DeleteOTP().Times(1)
 		handlers.EXPECT().GetFile().Times(1)
+		handlers.EXPECT().InitFileUpload().Times(1)
+		handlers.EXPECT().GetFileUpload().Times(1)
+		handlers.EXPECT().UploadFilePart().Times(1)
+		handlers.EXPECT().CompleteFileUpload().Times(1)
+		handlers.EXPECT().AbortFileUpload().Times(1)
 		handlers.EXPECT().AddFile().Times(1)
+		handlers.EXPECT().UpdateFile().Times(1)
+		handlers.EXPECT().PatchFile().Times(1)
+		handlers.EXPECT().DeleteFile().Times(1)
 
 		r := NewRouter(handlers, settings, logger, storage)
 		assert.Implements(t, (*chi.Router)(nil), r)
@@ -51,3 +88,16 @@ func closeBody(t *testing.T, r *http.Response) {
 		t.Log(err)
 	}
 }
+
+// testSettings возвращает настройки сервиса с тестовыми ключами.
+func testSettings(t *testing.T) *config.Settings {
+	t.Helper()
+
+	t.Setenv("JWT_KEY", "jwt key")
+	t.Setenv("MASTER_KEY", "master key")
+
+	settings, err := config.Setup(false)
+	require.NoError(t, err)
+
+	return settings
+}
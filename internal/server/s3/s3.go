@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
 	"github.com/minio/minio-go/v7"
@@ -16,17 +18,25 @@ import (
 
 var (
 	contentType = "application/octet-stream"
+	// pBacketName префикс бакетов пользователей, в которых файлы хранились до перехода на общий бакет.
 	pBacketName = "backetforuserid"
+	usersPrefix = "users/"
 )
 
-// S3 структура для работы с S3 хранилищем приложения.
+// S3 структура для работы с S3 хранилищем приложения. Файлы всех пользователей хранятся в одном бакете
+// с ключами вида users/<id пользователя>/<имя файла>.
 type S3 struct {
 	client   *minio.Client
+	core     *minio.Core
 	settings *config.S3Settings
 }
 
 // NewClient функция инициализации S3 хранилища приложения.
 func NewClient(ctx context.Context, settings *config.S3Settings) (*S3, error) {
+	if settings.Bucket == "" {
+		return nil, errors.New("s3 bucket is not set")
+	}
+
 	minioClient, err := minio.New(settings.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(settings.AccessKeyID, settings.SecretAccessKey, ""),
 		Secure: settings.UseSSL,
@@ -36,24 +46,21 @@ func NewClient(ctx context.Context, settings *config.S3Settings) (*S3, error) {
 		return nil, fmt.Errorf("failed to initialize minio client object %w", err)
 	}
 
-	return &S3{client: minioClient, settings: settings}, nil
+	return &S3{client: minioClient, core: &minio.Core{Client: minioClient}, settings: settings}, nil
 }
 
 // AddFile функция добавления файла в S3 хранилище.
 func (fs S3) AddFile(ctx context.Context, file io.Reader, objectName string, objectSize int64) error {
-	backetName, err := fs.CheckOrCreateBacket(ctx)
+	key, err := objectKey(ctx, objectName)
 	if err != nil {
-		return fmt.Errorf("failed to work with backet: %w", err)
+		return err
 	}
 
 	options := minio.PutObjectOptions{ContentType: contentType}
 
-	if fs.settings.SecureFiles {
-		encryption := encrypt.DefaultPBKDF([]byte(fs.settings.SecretPassword), []byte(backetName+objectName))
-		options.ServerSideEncryption = encryption
-	}
+	options.ServerSideEncryption = fs.encryption(fs.settings.Bucket, key)
 
-	_, err = fs.client.PutObject(ctx, backetName, objectName, file, objectSize, options)
+	_, err = fs.client.PutObject(ctx, fs.settings.Bucket, key, file, objectSize, options)
 	if err != nil {
 		return fmt.Errorf("failed to put file %w", err)
 	}
@@ -61,48 +68,342 @@ func (fs S3) AddFile(ctx context.Context, file io.Reader, objectName string, obj
 	return nil
 }
 
-// GetFile функция получения файла из S3 хранилища.
-func (fs S3) GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
-	backetName, err := fs.CheckOrCreateBacket(ctx)
+// GetFile функция получения файла из S3 хранилища. Если задан rng, читается только этот диапазон байт.
+func (fs S3) GetFile(ctx context.Context, objectName string, rng *models.FileRange) (models.File, error) {
+	var resp models.File
+	key, err := objectKey(ctx, objectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to work with backet: %w", err)
+		return resp, err
 	}
 
 	options := minio.GetObjectOptions{}
 
-	if fs.settings.SecureFiles {
-		encryption := encrypt.DefaultPBKDF([]byte(fs.settings.SecretPassword), []byte(backetName+objectName))
-		options.ServerSideEncryption = encryption
+	options.ServerSideEncryption = fs.encryption(fs.settings.Bucket, key)
+
+	if rng != nil {
+		if err := options.SetRange(rng.Start, rng.End); err != nil {
+			return resp, fmt.Errorf("failed to set range %w", err)
+		}
 	}
 
-	file, err := fs.client.GetObject(ctx, backetName, objectName, options)
+	file, err := fs.client.GetObject(ctx, fs.settings.Bucket, key, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file %w", err)
+		return resp, fmt.Errorf("failed to get file %w", err)
 	}
 
-	return file, nil
+	info, err := file.Stat()
+	if err != nil {
+		if closeErr := file.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+		return resp, fmt.Errorf("failed to stat file %w", err)
+	}
+
+	resp.File = file
+	resp.ETag = info.ETag
+	resp.Size = info.Size
+
+	return resp, nil
 }
 
-// CheckOrCreateBacket функция проверки существования и создания бакета в S3 хранилище.
-func (fs S3) CheckOrCreateBacket(ctx context.Context) (string, error) {
-	userID, ok := ctx.Value(constants.KeyUserID).(int)
-	if !ok {
-		return "", errors.New("failed to fetch user id from context")
+// StatFile функция получения размера и ETag файла из S3 хранилища.
+func (fs S3) StatFile(ctx context.Context, objectName string) (models.File, error) {
+	var resp models.File
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return resp, err
+	}
+
+	options := minio.StatObjectOptions{}
+
+	options.ServerSideEncryption = fs.encryption(fs.settings.Bucket, key)
+
+	info, err := fs.client.StatObject(ctx, fs.settings.Bucket, key, options)
+	if err != nil {
+		return resp, fmt.Errorf("failed to stat file %w", err)
+	}
+
+	resp.ETag = info.ETag
+	resp.Size = info.Size
+
+	return resp, nil
+}
+
+// DeleteFile функция удаления файла из S3 хранилища.
+func (fs S3) DeleteFile(ctx context.Context, objectName string) error {
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	err = fs.client.RemoveObject(ctx, fs.settings.Bucket, key, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to remove file %w", err)
+	}
+
+	return nil
+}
+
+// ListFiles функция получения списка файлов пользователя из S3 хранилища.
+func (fs S3) ListFiles(ctx context.Context) ([]models.FileInfo, error) {
+	prefix, err := objectKey(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []models.FileInfo{}
+
+	options := minio.ListObjectsOptions{Prefix: prefix, Recursive: true}
+	for obj := range fs.client.ListObjects(ctx, fs.settings.Bucket, options) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list files %w", obj.Err)
+		}
+
+		files = append(files, models.FileInfo{
+			Name:    strings.TrimPrefix(obj.Key, prefix),
+			Size:    obj.Size,
+			ModTime: obj.LastModified,
+		})
+	}
+
+	return files, nil
+}
+
+// InitUpload функция начала загрузки файла по частям в S3 хранилище. Возвращает идентификатор загрузки S3.
+func (fs S3) InitUpload(ctx context.Context, objectName string) (string, error) {
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return "", err
+	}
+
+	options := minio.PutObjectOptions{
+		ContentType:          contentType,
+		ServerSideEncryption: fs.encryption(fs.settings.Bucket, key),
+	}
+
+	uploadID, err := fs.core.NewMultipartUpload(ctx, fs.settings.Bucket, key, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to init multipart upload %w", err)
+	}
+
+	return uploadID, nil
+}
+
+// UploadPart функция загрузки части файла в S3 хранилище.
+func (fs S3) UploadPart(
+	ctx context.Context,
+	objectName, uploadID string,
+	number int,
+	part io.Reader,
+	size int64,
+) (models.FileUploadPart, error) {
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	options := minio.PutObjectPartOptions{SSE: fs.encryption(fs.settings.Bucket, key)}
+
+	objectPart, err := fs.core.PutObjectPart(ctx, fs.settings.Bucket, key, uploadID, number, part, size, options)
+	if err != nil {
+		return models.FileUploadPart{}, fmt.Errorf("failed to put object part %w", err)
+	}
+
+	return models.FileUploadPart{Number: objectPart.PartNumber, Size: objectPart.Size, ETag: objectPart.ETag}, nil
+}
+
+// ListUploadParts функция получения загруженных частей файла из S3 хранилища.
+func (fs S3) ListUploadParts(ctx context.Context, objectName, uploadID string) ([]models.FileUploadPart, error) {
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []models.FileUploadPart{}
+	marker := 0
+
+	for {
+		result, err := fs.core.ListObjectParts(ctx, fs.settings.Bucket, key, uploadID, marker, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list object parts %w", err)
+		}
+
+		for _, p := range result.ObjectParts {
+			parts = append(parts, models.FileUploadPart{Number: p.PartNumber, Size: p.Size, ETag: p.ETag})
+		}
+
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// CompleteUpload функция завершения загрузки файла по частям в S3 хранилище.
+func (fs S3) CompleteUpload(ctx context.Context, objectName, uploadID string, parts []models.FileUploadPart) error {
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		completeParts = append(completeParts, minio.CompletePart{PartNumber: p.Number, ETag: p.ETag})
+	}
+
+	options := minio.PutObjectOptions{ServerSideEncryption: fs.encryption(fs.settings.Bucket, key)}
+
+	_, err = fs.core.CompleteMultipartUpload(ctx, fs.settings.Bucket, key, uploadID, completeParts, options)
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload %w", err)
+	}
+
+	return nil
+}
+
+// AbortUpload функция отмены загрузки файла по частям в S3 хранилище.
+func (fs S3) AbortUpload(ctx context.Context, objectName, uploadID string) error {
+	key, err := objectKey(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.core.AbortMultipartUpload(ctx, fs.settings.Bucket, key, uploadID); err != nil {
+		return fmt.Errorf("failed to abort multipart upload %w", err)
+	}
+
+	return nil
+}
+
+// encryption возвращает параметры шифрования объекта на стороне S3 или nil, если шифрование выключено.
+func (fs S3) encryption(backetName, objectName string) encrypt.ServerSide {
+	if !fs.settings.SecureFiles {
+		return nil
 	}
-	backetName := pBacketName + strconv.Itoa(userID)
 
-	exists, err := fs.client.BucketExists(ctx, backetName)
+	return encrypt.DefaultPBKDF([]byte(fs.settings.SecretPassword), []byte(backetName+objectName))
+}
+
+// CheckBucket функция проверки существования и создания бакета приложения. Вызывается один раз при запуске.
+func (fs S3) CheckBucket(ctx context.Context) error {
+	exists, err := fs.client.BucketExists(ctx, fs.settings.Bucket)
 	if err != nil {
-		return "", fmt.Errorf("failed to check backet exist %w", err)
+		return fmt.Errorf("failed to check backet exist %w", err)
 	}
 	if exists {
-		return backetName, nil
+		return nil
 	}
 
-	err = fs.client.MakeBucket(ctx, backetName, minio.MakeBucketOptions{Region: fs.settings.Region})
+	err = fs.client.MakeBucket(ctx, fs.settings.Bucket, minio.MakeBucketOptions{Region: fs.settings.Region})
 	if err != nil {
-		return "", fmt.Errorf("failed to create backet %w", err)
+		return fmt.Errorf("failed to create backet %w", err)
+	}
+
+	return nil
+}
+
+// MigrateLegacyBuckets функция переноса файлов из бакетов пользователей старого формата в общий бакет.
+// Перенесенные файлы удаляются из старых бакетов, поэтому прерванный перенос можно запустить повторно.
+// Незавершенные загрузки по частям в старых бакетах отменяются. После каждого файла вызывается progress.
+// Возвращает количество перенесенных файлов.
+func (fs S3) MigrateLegacyBuckets(ctx context.Context, progress func(backetName, objectName string)) (int, error) {
+	buckets, err := fs.client.ListBuckets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list backets %w", err)
+	}
+
+	count := 0
+	for _, b := range buckets {
+		userID, ok := legacyBucketUserID(b.Name)
+		if !ok {
+			continue
+		}
+
+		moved, err := fs.migrateLegacyBucket(ctx, b.Name, userID, progress)
+		count += moved
+		if err != nil {
+			return count, fmt.Errorf("failed to migrate backet %s: %w", b.Name, err)
+		}
+	}
+
+	return count, nil
+}
+
+// migrateLegacyBucket переносит файлы одного бакета пользователя старого формата и удаляет бакет.
+func (fs S3) migrateLegacyBucket(
+	ctx context.Context,
+	backetName string,
+	userID int,
+	progress func(backetName, objectName string),
+) (int, error) {
+	count := 0
+	for obj := range fs.client.ListObjects(ctx, backetName, minio.ListObjectsOptions{Recursive: true}) {
+		if obj.Err != nil {
+			return count, fmt.Errorf("failed to list files %w", obj.Err)
+		}
+
+		key := userObjectKey(userID, obj.Key)
+		src := minio.CopySrcOptions{Bucket: backetName, Object: obj.Key, Encryption: fs.encryption(backetName, obj.Key)}
+		dst := minio.CopyDestOptions{
+			Bucket:     fs.settings.Bucket,
+			Object:     key,
+			Encryption: fs.encryption(fs.settings.Bucket, key),
+		}
+
+		if _, err := fs.client.ComposeObject(ctx, dst, src); err != nil {
+			return count, fmt.Errorf("failed to copy file %s %w", obj.Key, err)
+		}
+		if err := fs.client.RemoveObject(ctx, backetName, obj.Key, minio.RemoveObjectOptions{}); err != nil {
+			return count, fmt.Errorf("failed to remove file %s %w", obj.Key, err)
+		}
+
+		count++
+		if progress != nil {
+			progress(backetName, obj.Key)
+		}
+	}
+
+	for upload := range fs.client.ListIncompleteUploads(ctx, backetName, "", true) {
+		if upload.Err != nil {
+			return count, fmt.Errorf("failed to list uploads %w", upload.Err)
+		}
+		if err := fs.client.RemoveIncompleteUpload(ctx, backetName, upload.Key); err != nil {
+			return count, fmt.Errorf("failed to abort upload %s %w", upload.Key, err)
+		}
+	}
+
+	if err := fs.client.RemoveBucket(ctx, backetName); err != nil {
+		return count, fmt.Errorf("failed to remove backet %w", err)
+	}
+
+	return count, nil
+}
+
+// objectKey возвращает ключ объекта пользователя из контекста в общем бакете.
+func objectKey(ctx context.Context, objectName string) (string, error) {
+	userID, ok := ctx.Value(constants.KeyUserID).(int)
+	if !ok {
+		return "", errors.New("failed to fetch user id from context")
+	}
+
+	return userObjectKey(userID, objectName), nil
+}
+
+func userObjectKey(userID int, objectName string) string {
+	return usersPrefix + strconv.Itoa(userID) + "/" + objectName
+}
+
+// legacyBucketUserID возвращает идентификатор пользователя по имени бакета старого формата.
+func legacyBucketUserID(backetName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(backetName, pBacketName)
+	if !ok {
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(suffix)
+	if err != nil || strconv.Itoa(userID) != suffix {
+		return 0, false
 	}
 
-	return backetName, nil
+	return userID, true
 }
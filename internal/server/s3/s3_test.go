@@ -0,0 +1,53 @@
+package s3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(context.Background(), &config.S3Settings{Endpoint: "localhost:9000", Bucket: "goph-keeper"})
+	require.NoError(t, err)
+
+	_, err = NewClient(context.Background(), &config.S3Settings{Endpoint: "localhost:9000"})
+	require.Error(t, err)
+}
+
+func TestObjectKey(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 42)
+
+	key, err := objectKey(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "users/42/file.txt", key)
+
+	_, err = objectKey(context.Background(), "file.txt")
+	require.Error(t, err)
+}
+
+func TestLegacyBucketUserID(t *testing.T) {
+	tests := []struct {
+		name       string
+		backetName string
+		userID     int
+		ok         bool
+	}{
+		{name: "legacy bucket", backetName: "backetforuserid7", userID: 7, ok: true},
+		{name: "shared bucket", backetName: "goph-keeper", ok: false},
+		{name: "no user id", backetName: "backetforuserid", ok: false},
+		{name: "not a number", backetName: "backetforuseridx", ok: false},
+		{name: "leading zero", backetName: "backetforuserid07", ok: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userID, ok := legacyBucketUserID(test.backetName)
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.userID, userID)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed attempts")
+
+// RetryAfterError ошибка блокировки после неудачных попыток входа.
+type RetryAfterError struct {
+	RetryAfter time.Duration
+}
+
+// Error возвращает текст ошибки.
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrTooManyAttempts.
+func (e *RetryAfterError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// checkAttempts проверить, не заблокированы ли учетная запись и адрес клиента после неудачных попыток входа.
+func (s *Services) checkAttempts(ctx context.Context, key string) error {
+	retryAfter := max(s.loginAttempts.Check(key), s.ipAttempts.Check(clientIP(ctx)))
+	if retryAfter > 0 {
+		return &RetryAfterError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// failAttempt учесть неудачную попытку входа для учетной записи и адреса клиента.
+func (s *Services) failAttempt(ctx context.Context, key string) {
+	s.loginAttempts.Fail(key)
+	s.ipAttempts.Fail(clientIP(ctx))
+}
+
+// loginAttemptKey ключ счетчика неудачных попыток входа по паролю.
+func loginAttemptKey(login string) string {
+	return "login:" + login
+}
+
+// twoFactorAttemptKey ключ счетчика неудачных попыток второго шага входа.
+func twoFactorAttemptKey(userID int) string {
+	return "2fa:" + strconv.Itoa(userID)
+}
+
+// clientIP получить адрес клиента из контекста запроса.
+func clientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(constants.KeyClientIP).(string)
+
+	return ip
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserTokenLockout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{
+		Limits: config.LimitSettings{
+			LoginAttempts: 2,
+			IPAttempts:    2,
+			LockoutBase:   time.Minute,
+			LockoutMax:    time.Hour,
+		},
+	}
+	s := NewServices(store, fs, crypter, &settings)
+	ctx := context.WithValue(context.Background(), constants.KeyClientIP, "10.0.0.1")
+
+	hashedPassword, err := hashPassword("secret")
+	require.NoError(t, err)
+
+	t.Run("login locked after failed attempts", func(t *testing.T) {
+		store.EXPECT().GetUserByLogin(gomock.Any(), "user").Times(3).
+			Return(models.User{ID: 1, Login: "user", Password: hashedPassword}, nil)
+
+		for range 3 {
+			_, err := s.CreateUserToken(ctx, models.CreateUserTokenRequest{Login: "user", Password: "wrong"})
+			require.ErrorIs(t, err, ErrUserLoginCreds)
+		}
+
+		_, err := s.CreateUserToken(ctx, models.CreateUserTokenRequest{Login: "user", Password: "secret"})
+
+		var retryErr *RetryAfterError
+		require.ErrorAs(t, err, &retryErr)
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+		assert.InDelta(t, time.Minute.Seconds(), retryErr.RetryAfter.Seconds(), 1)
+	})
+
+	t.Run("client address locked after failed attempts with other logins", func(t *testing.T) {
+		otherCtx := context.WithValue(context.Background(), constants.KeyClientIP, "10.0.0.1")
+
+		_, err := s.CreateUserToken(otherCtx, models.CreateUserTokenRequest{Login: "other", Password: "secret"})
+
+		require.ErrorIs(t, err, ErrTooManyAttempts)
+	})
+
+	t.Run("unknown logins counted", func(t *testing.T) {
+		ipCtx := context.WithValue(context.Background(), constants.KeyClientIP, "10.0.0.2")
+		store.EXPECT().GetUserByLogin(gomock.Any(), "ghost").Times(3).Return(models.User{}, storage.ErrUserNotFound)
+
+		for range 3 {
+			_, err := s.CreateUserToken(ipCtx, models.CreateUserTokenRequest{Login: "ghost", Password: "wrong"})
+			require.ErrorIs(t, err, ErrUserLoginCreds)
+		}
+
+		_, err := s.CreateUserToken(ipCtx, models.CreateUserTokenRequest{Login: "ghost", Password: "wrong"})
+
+		require.ErrorIs(t, err, ErrTooManyAttempts)
+	})
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestAddCard(t *testing.T) {
+func TestCardSecret(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -23,204 +23,69 @@ func TestAddCard(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
-
-	req := models.AddCardRequest{
-		Number:      "1234123412341234",
-		Owner:       "test",
-		ExpiryDate:  "11/2300",
-		CVV2:        "777",
-		Mark:        "test",
-		Description: "test",
-	}
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
+	userDataID := 1
 	dataType := "card"
+	clientData := []byte("client encrypted data")
 	encData := []byte("some data")
+	newMark := "new mark"
 
-	type sResponse struct {
-		id  int
-		err error
-	}
-	tests := []struct {
-		name      string
-		sResponse sResponse
-		wantErr   bool
-	}{
-		{
-			name: "add card success",
-			sResponse: sResponse{
-				id:  1,
-				err: nil,
-			},
-			wantErr: false,
-		},
-		{
-			name: "add card failed",
-			sResponse: sResponse{
-				id:  0,
-				err: errors.New("some error"),
-			},
-			wantErr: true,
-		},
+	req := models.AddSecretRequest{
+		Data:        clientData,
+		Mark:        "test",
+		Description: "test",
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			crypter.EXPECT().EncryptData(gomock.Any()).Times(1).Return(encData)
-			store.EXPECT().
-				AddUserData(ctx, encData, req.Mark, req.Description, dataType).
-				Times(1).Return(test.sResponse.id, test.sResponse.err)
+	t.Run("add card", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), clientData).Times(1).Return(encData, nil)
+		store.EXPECT().AddUserData(ctx, encData, req.Mark, req.Description, dataType, models.UserDataLabels{}).
+			Times(1).Return(userDataID, nil)
 
-			id, err := s.AddCard(ctx, &req)
+		id, err := s.AddCard(ctx, req)
 
-			if test.wantErr {
-				require.Error(t, err)
-				assert.ErrorContains(t, err, "failed to add user data", "some error")
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, test.sResponse.id, id)
-			}
-		})
-	}
-}
+		require.NoError(t, err)
+		assert.Equal(t, userDataID, id)
+	})
 
-func TestAddCardValidationFailed(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+	t.Run("get card", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), encData).Times(1).Return(clientData, nil)
 
-	store := mocks.NewMockStorager(mockCtrl)
-	fs := mocks.NewMockFileStorager(mockCtrl)
-	crypter := mocks.NewMockCrypter(mockCtrl)
-	settings := config.Settings{}
-	s := NewServices(store, fs, crypter, &settings)
+		resp, err := s.GetCard(ctx, userDataID)
 
-	ctx := context.Background()
+		require.NoError(t, err)
+		assert.Equal(t, models.Secret{
+			ID:          userDataID,
+			Data:        clientData,
+			Mark:        "test",
+			Description: "test",
+		}, resp)
+	})
 
-	type arg struct {
-		req models.AddCardRequest
-	}
-	type want struct {
-		err error
-	}
-	tests := []struct {
-		name string
-		arg  arg
-		want want
-	}{
-		{
-			name: "when user number invalid",
-			arg: arg{
-				req: models.AddCardRequest{
-					Number:      "test",
-					Owner:       "test",
-					ExpiryDate:  "11/2300",
-					CVV2:        "777",
-					Mark:        "test",
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserNumberInvalid,
-			},
-		},
-		{
-			name: "when user owner very big",
-			arg: arg{
-				req: models.AddCardRequest{
-					Number: "1234123412341234",
-					Owner: `testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest
-					testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest
-					testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest`,
-					ExpiryDate:  "11/2300",
-					CVV2:        "777",
-					Mark:        "test",
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserOwnerIsTooBig,
-			},
-		},
-		{
-			name: "when user expiry date invalid",
-			arg: arg{
-				req: models.AddCardRequest{
-					Number:      "1234123412341234",
-					Owner:       "test",
-					ExpiryDate:  "test",
-					CVV2:        "777",
-					Mark:        "test",
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserExpiryDateInvalid,
-			},
-		},
-		{
-			name: "when user cvv2 invalid",
-			arg: arg{
-				req: models.AddCardRequest{
-					Number:      "1234123412341234",
-					Owner:       "test",
-					ExpiryDate:  "11/2300",
-					CVV2:        "test",
-					Mark:        "test",
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserCVV2Invalid,
-			},
-		},
-		{
-			name: "when user mark very big",
-			arg: arg{
-				req: models.AddCardRequest{
-					Number:      "1234123412341234",
-					Owner:       "test",
-					ExpiryDate:  "11/2300",
-					CVV2:        "777",
-					Mark:        generateString(150),
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserMarkIsTooBig,
-			},
-		},
-		{
-			name: "when user description very big",
-			arg: arg{
-				req: models.AddCardRequest{
-					Number:      "1234123412341234",
-					Owner:       "test",
-					ExpiryDate:  "11/2300",
-					CVV2:        "777",
-					Mark:        "test",
-					Description: generateString(4000),
-				},
-			},
-			want: want{
-				err: ErrUserDescriptionIsTooBig,
-			},
-		},
-	}
+	t.Run("update card", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), clientData).Times(1).Return(encData, nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, req.Mark, req.Description, dataType, clearedLabels()).
+			Times(1).Return(nil)
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			crypter.EXPECT().EncryptData(gomock.Any()).Times(0)
-			store.EXPECT().AddUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		err := s.UpdateCard(ctx, userDataID, req)
 
-			_, err := s.AddCard(ctx, &test.arg.req)
+		require.NoError(t, err)
+	})
 
-			require.Error(t, err)
-			assert.ErrorContains(t, err, test.want.err.Error())
-		})
-	}
+	t.Run("patch card", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, newMark, "test", dataType, models.UserDataLabels{}).
+			Times(1).Return(nil)
+
+		err := s.PatchCard(ctx, userDataID, models.UpdateSecretRequest{Mark: &newMark})
+
+		require.NoError(t, err)
+	})
 }
 
-func TestGetCard(t *testing.T) {
+func TestDeleteCard(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -232,125 +97,46 @@ func TestGetCard(t *testing.T) {
 
 	ctx := context.Background()
 	userDataID := 1
-	decData := []byte("some data")
-	mark := "test"
-	description := "test"
 	dataType := "card"
 
-	type cResponse struct {
-		jsonData []byte
-		err      error
-	}
 	tests := []struct {
-		name      string
-		cResponse cResponse
-		wantErr   bool
-		errText   string
+		name    string
+		sErr    error
+		wantErr bool
+		errText string
 	}{
 		{
-			name: "get user data success",
-			cResponse: cResponse{
-				jsonData: []byte(`{"number":"1234123412341234","owner":"test","expiry_date":"11/2300","cvv2":"777"}`),
-				err:      nil,
-			},
+			name:    "delete card success",
+			sErr:    nil,
 			wantErr: false,
 			errText: "",
 		},
 		{
-			name: "when decrypt data failed",
-			cResponse: cResponse{
-				jsonData: nil,
-				err:      errors.New("some error"),
-			},
+			name:    "when card not found",
+			sErr:    storage.ErrUserDataNotFound,
 			wantErr: true,
-			errText: "failed to decrypt data",
+			errText: ErrNotFound.Error(),
 		},
 		{
-			name: "when generate user data failed",
-			cResponse: cResponse{
-				jsonData: []byte(`test`),
-				err:      nil,
-			},
+			name:    "delete card failed",
+			sErr:    errors.New("some error"),
 			wantErr: true,
-			errText: "failed to generate data",
+			errText: "failed to delete user data",
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetUserData(ctx, userDataID, dataType).
-				Times(1).Return(decData, mark, description, nil)
-
-			crypter.EXPECT().DecryptData(decData).Times(1).Return(test.cResponse.jsonData, test.cResponse.err)
+			store.EXPECT().DeleteUserData(ctx, userDataID, dataType).Times(1).Return(test.sErr)
 
-			resp, err := s.GetCard(ctx, userDataID)
+			err := s.DeleteCard(ctx, userDataID)
 
 			if test.wantErr {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, test.errText)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, models.Card{
-					ID:          userDataID,
-					Number:      "1234123412341234",
-					Owner:       "test",
-					ExpiryDate:  "11/2300",
-					CVV2:        "777",
-					Mark:        mark,
-					Description: description,
-				}, resp)
 			}
 		})
 	}
 }
-
-func TestGetCardFailedStorage(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	store := mocks.NewMockStorager(mockCtrl)
-	fs := mocks.NewMockFileStorager(mockCtrl)
-	crypter := mocks.NewMockCrypter(mockCtrl)
-	settings := config.Settings{}
-	s := NewServices(store, fs, crypter, &settings)
-
-	ctx := context.Background()
-	userDataID := 1
-	dataType := "card"
-
-	type sResponse struct {
-		err error
-	}
-	tests := []struct {
-		name      string
-		sResponse sResponse
-		errText   string
-	}{
-		{
-			name: "failed to get user data",
-			sResponse: sResponse{
-				err: errors.New("some error"),
-			},
-			errText: "failed to get user data",
-		},
-		{
-			name: "when user data not found",
-			sResponse: sResponse{
-				err: storage.ErrUserDataNotFound,
-			},
-			errText: "requested data no found",
-		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return([]byte{}, "", "", test.sResponse.err)
-			crypter.EXPECT().DecryptData(gomock.Any()).Times(0)
-
-			_, err := s.GetCard(ctx, userDataID)
-
-			require.Error(t, err)
-			assert.ErrorContains(t, err, test.errText, test.sResponse.err.Error())
-		})
-	}
-}
@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
 )
 
 // FetchUserData функция для получения базовой информации о данных пользователя.
@@ -16,3 +18,91 @@ func (s *Services) FetchUserData(ctx context.Context) ([]models.UserData, error)
 
 	return data, nil
 }
+
+// FetchUserDataChanges функция для получения изменений данных пользователя после курсора синхронизации
+// вместе с содержимым измененных записей, чтобы клиенту не нужно было запрашивать каждую запись отдельно.
+func (s *Services) FetchUserDataChanges(ctx context.Context, since int64) (models.UserDataChanges, error) {
+	changes, err := s.storage.FetchUserDataChanges(ctx, since)
+	if err != nil {
+		return changes, fmt.Errorf("failed to fetch user data changes: %w", err)
+	}
+
+	if len(changes.Secrets) == 0 {
+		return changes, nil
+	}
+
+	dataKey, err := s.dataKey(ctx)
+	if err != nil {
+		return changes, failedDecryptData(err)
+	}
+
+	for i, secret := range changes.Secrets {
+		data, err := s.crypter.DecryptData(dataKey, secret.Data)
+		if err != nil {
+			return changes, failedDecryptData(fmt.Errorf("failed to decrypt %w", err))
+		}
+
+		changes.Secrets[i].Data = data
+	}
+
+	return changes, nil
+}
+
+// ReserveDataID функция для резервирования ID данных пользователя до их добавления,
+// чтобы клиент зашифровал данные с привязкой к ID записи до ее создания.
+func (s *Services) ReserveDataID(ctx context.Context) (int, error) {
+	id, err := s.storage.ReserveUserDataID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve data id: %w", err)
+	}
+
+	return id, nil
+}
+
+// addUserData функция для добавления данных пользователя с зарезервированным ID.
+// Без ID (клиенты старых версий) ID назначает хранилище.
+func (s *Services) addUserData(
+	ctx context.Context,
+	id int,
+	encData []byte,
+	mark string,
+	description string,
+	dataType string,
+	labels models.UserDataLabels,
+) (int, error) {
+	var err error
+	if id == 0 {
+		id, err = s.storage.AddUserData(ctx, encData, mark, description, dataType, labels)
+	} else {
+		id, err = s.storage.AddReservedUserData(ctx, id, encData, mark, description, dataType, labels)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrDataIDNotReserved) {
+			return 0, ErrNotFound
+		}
+
+		return 0, failedAddUserData(err)
+	}
+
+	return id, nil
+}
+
+// deleteUserData функция для удаления данных пользователя заданного типа.
+func (s *Services) deleteUserData(ctx context.Context, id int, dataType string) error {
+	if err := s.storage.DeleteUserData(ctx, id, dataType); err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return failedDeleteUserData(err)
+	}
+
+	return nil
+}
+
+// patchValue заменяет значение, если новое значение было передано.
+func patchValue(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
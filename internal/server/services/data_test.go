@@ -74,3 +74,101 @@ func TestFetchUserData(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchUserDataChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	storage := mocks.NewMockStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(storage, mocks.NewMockFileStorager(mockCtrl), crypter, &settings)
+
+	ctx := context.Background()
+
+	t.Run("success fetch user data changes", func(t *testing.T) {
+		changes := models.UserDataChanges{
+			Changes: []models.UserData{{ID: 1, Type: "card", Mark: "Mark", Version: 2}},
+			Deleted: []int{3},
+			Cursor:  10,
+		}
+		storage.EXPECT().FetchUserDataChanges(ctx, int64(5)).Times(1).Return(changes, nil)
+
+		resp, err := s.FetchUserDataChanges(ctx, 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, changes, resp)
+	})
+
+	t.Run("fetch user data changes with secrets", func(t *testing.T) {
+		changes := models.UserDataChanges{
+			Changes: []models.UserData{{ID: 1, Type: "card"}, {ID: 2, Type: "text"}},
+			Secrets: []models.Secret{{ID: 1, Data: []byte("enc card")}, {ID: 2, Data: []byte("enc text")}},
+			Cursor:  10,
+		}
+		storage.EXPECT().FetchUserDataChanges(ctx, int64(5)).Times(1).Return(changes, nil)
+		storage.EXPECT().GetUserKey(ctx).Times(1).Return([]byte("wrapped key"), nil)
+		crypter.EXPECT().UnwrapDataKey([]byte("wrapped key")).Times(1).Return([]byte("data key"), nil)
+		crypter.EXPECT().DecryptData([]byte("data key"), []byte("enc card")).Times(1).Return([]byte("card"), nil)
+		crypter.EXPECT().DecryptData([]byte("data key"), []byte("enc text")).Times(1).Return([]byte("text"), nil)
+
+		resp, err := s.FetchUserDataChanges(ctx, 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.Secret{{ID: 1, Data: []byte("card")}, {ID: 2, Data: []byte("text")}}, resp.Secrets)
+	})
+
+	t.Run("decrypt secrets failed", func(t *testing.T) {
+		changes := models.UserDataChanges{Secrets: []models.Secret{{ID: 1, Data: []byte("enc card")}}}
+		storage.EXPECT().FetchUserDataChanges(ctx, int64(5)).Times(1).Return(changes, nil)
+		storage.EXPECT().GetUserKey(ctx).Times(1).Return([]byte("wrapped key"), nil)
+		crypter.EXPECT().UnwrapDataKey(gomock.Any()).Times(1).Return([]byte("data key"), nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.FetchUserDataChanges(ctx, 5)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to decrypt data")
+	})
+
+	t.Run("fetch user data changes failed", func(t *testing.T) {
+		storage.EXPECT().FetchUserDataChanges(ctx, int64(5)).Times(1).
+			Return(models.UserDataChanges{}, errors.New("some error"))
+
+		_, err := s.FetchUserDataChanges(ctx, 5)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch user data changes")
+	})
+}
+
+func TestReserveDataID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	storage := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(storage, fs, crypter, &settings)
+
+	ctx := context.Background()
+
+	t.Run("success reserve data id", func(t *testing.T) {
+		storage.EXPECT().ReserveUserDataID(ctx).Times(1).Return(7, nil)
+
+		id, err := s.ReserveDataID(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, id)
+	})
+
+	t.Run("reserve data id failed", func(t *testing.T) {
+		storage.EXPECT().ReserveUserDataID(ctx).Times(1).Return(0, errors.New("some error"))
+
+		_, err := s.ReserveDataID(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to reserve data id")
+	})
+}
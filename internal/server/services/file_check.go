@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+const fileCheckBatchSize = 100
+
+// CheckFiles функция проверки согласованности данных файлов пользователей и файлового хранилища.
+// Находит объекты, на которые не ссылаются данные пользователей и их история, и данные, объекты которых отсутствуют.
+// Если fix, потерянные объекты старше срока ожидания удаляются, данные без объектов отмечаются,
+// а устаревшие отметки о записи объектов удаляются; иначе только составляется отчет.
+func (s *Services) CheckFiles(ctx context.Context, fix bool) (models.FileCheckReport, error) {
+	var (
+		report  models.FileCheckReport
+		afterID int
+	)
+
+	for {
+		ids, err := s.storage.FetchUserIDs(ctx, afterID, fileCheckBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to fetch users %w", err)
+		}
+
+		for _, id := range ids {
+			afterID = id
+			userCtx := context.WithValue(ctx, constants.KeyUserID, id)
+
+			if err := s.checkUserFiles(userCtx, id, fix, &report); err != nil {
+				return report, fmt.Errorf("failed to check files of user %d: %w", id, err)
+			}
+			report.Users++
+		}
+
+		if len(ids) < fileCheckBatchSize {
+			return report, nil
+		}
+	}
+}
+
+// checkUserFiles проверяет файлы пользователя из контекста и дополняет отчет.
+// Список объектов читается последним: объект, записанный после чтения данных пользователя,
+// защищен отметкой о записи или временем изменения и не считается потерянным.
+func (s *Services) checkUserFiles(ctx context.Context, userID int, fix bool, report *models.FileCheckReport) error {
+	data, err := s.storage.FetchFileUserData(ctx)
+	if err != nil {
+		return failedGetUserData(err)
+	}
+
+	used := make(map[string]bool, len(data))
+	dataObjects := make([]string, len(data))
+
+	for i, d := range data {
+		fileData, err := s.decryptFileData(ctx, d.Data)
+		if err != nil {
+			return err
+		}
+
+		dataObjects[i] = fileObjectName(fileData)
+		used[dataObjects[i]] = true
+	}
+
+	history, err := s.fileHistoryObjects(ctx)
+	if err != nil {
+		return err
+	}
+	for _, names := range history {
+		for _, name := range names {
+			used[name] = true
+		}
+	}
+
+	uploads, err := s.storage.FetchFileUploadObjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch file uploads %w", err)
+	}
+	for _, name := range uploads {
+		used[name] = true
+	}
+
+	pending, err := s.storage.FetchPendingFileObjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch pending file objects %w", err)
+	}
+
+	files, err := s.fileStorage.ListFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list files from filestorage %w", err)
+	}
+
+	deadline := time.Now().Add(-s.settings.FileGC.PendingTTL)
+
+	var stale []string
+	for _, p := range pending {
+		if p.CreatedAt.After(deadline) {
+			used[p.ObjectName] = true
+		} else {
+			stale = append(stale, p.ObjectName)
+		}
+	}
+
+	stored := make(map[string]bool, len(files))
+	orphaned := make(map[string]bool)
+	for _, f := range files {
+		stored[f.Name] = true
+		if !used[f.Name] && f.ModTime.Before(deadline) {
+			orphaned[f.Name] = true
+		}
+	}
+
+	for i, d := range data {
+		missing := !stored[dataObjects[i]]
+		if missing {
+			report.MissingObjects = append(report.MissingObjects, models.FileIssue{
+				UserID: userID, DataID: d.ID, ObjectName: dataObjects[i],
+			})
+		}
+
+		if fix && missing != d.ObjectMissing {
+			if err := s.storage.SetFileUserDataMissing(ctx, d.ID, missing); err != nil {
+				return failedUpdateUserData(err)
+			}
+		}
+	}
+
+	for _, name := range sortedNames(orphaned) {
+		report.OrphanedObjects = append(report.OrphanedObjects, models.FileIssue{UserID: userID, ObjectName: name})
+
+		if !fix {
+			continue
+		}
+
+		if err := s.deleteFileObject(ctx, name); err != nil {
+			return err
+		}
+		report.DeletedObjects++
+	}
+
+	report.StalePending += len(stale)
+	if !fix {
+		return nil
+	}
+
+	for _, name := range stale {
+		if err := s.storage.DeletePendingFileObject(ctx, name); err != nil {
+			return fmt.Errorf("failed to delete pending file object %w", err)
+		}
+	}
+
+	return nil
+}
+
+// decryptFileData расшифровывает данные файла пользователя.
+func (s *Services) decryptFileData(ctx context.Context, encData []byte) (models.EncryptFileData, error) {
+	var fileData models.EncryptFileData
+
+	jsonData, err := s.decryptData(ctx, encData)
+	if err != nil {
+		return fileData, failedDecryptData(err)
+	}
+
+	if err := json.Unmarshal(jsonData, &fileData); err != nil {
+		return fileData, failedGenerateData(err)
+	}
+
+	return fileData, nil
+}
+
+func sortedNames(names map[string]bool) []string {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectUserFiles ожидает чтение файлов пользователя 1: данных пользователя и их истории, загрузок,
+// отметок о записи, счетчиков ссылок и объектов файлового хранилища.
+func expectUserFiles(
+	userCtx context.Context,
+	store *mocks.MockStorager,
+	fs *mocks.MockFileStorager,
+	crypter *mocks.MockCrypter,
+) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+
+	store.EXPECT().FetchFileUserData(userCtx).Times(1).Return([]models.FileUserData{
+		{ID: 1, Data: []byte("a")},
+		{ID: 2, Data: []byte("b")},
+		{ID: 3, Data: []byte("legacy"), ObjectMissing: true},
+	}, nil)
+	crypter.EXPECT().DecryptData(gomock.Any(), []byte("a")).Times(1).
+		Return([]byte(`{"file_name":"a.txt","object_name":"a"}`), nil)
+	crypter.EXPECT().DecryptData(gomock.Any(), []byte("b")).Times(1).
+		Return([]byte(`{"file_name":"b.txt","object_name":"b"}`), nil)
+	crypter.EXPECT().DecryptData(gomock.Any(), []byte("legacy")).Times(1).
+		Return([]byte(`{"file_name":"legacy.txt"}`), nil)
+	store.EXPECT().FetchFileUserDataHistory(userCtx).Times(1).Return([]models.FileUserData{
+		{ID: 1, Data: []byte("a-old")},
+	}, nil)
+	crypter.EXPECT().DecryptData(gomock.Any(), []byte("a-old")).Times(1).
+		Return([]byte(`{"file_name":"a.txt","object_name":"a-old"}`), nil)
+	store.EXPECT().FetchFileUploadObjects(userCtx).Times(1).Return([]string{"upload"}, nil)
+	store.EXPECT().FetchPendingFileObjects(userCtx).Times(1).Return([]models.PendingFileObject{
+		{ObjectName: "young", CreatedAt: now},
+		{ObjectName: "stale", CreatedAt: old},
+	}, nil)
+	fs.EXPECT().ListFiles(userCtx).Times(1).Return([]models.FileInfo{
+		{Name: "a", ModTime: old},
+		{Name: "a-old", ModTime: old},
+		{Name: "legacy.txt", ModTime: old},
+		{Name: "upload", ModTime: old},
+		{Name: "young", ModTime: old},
+		{Name: "stale", ModTime: old},
+		{Name: "orphan", ModTime: old},
+		{Name: "fresh", ModTime: now},
+	}, nil)
+}
+
+func TestCheckFiles(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{FileGC: config.FileGCSettings{PendingTTL: time.Hour}}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userCtx := context.WithValue(ctx, constants.KeyUserID, 1)
+	want := models.FileCheckReport{
+		Users:          1,
+		MissingObjects: []models.FileIssue{{UserID: 1, DataID: 2, ObjectName: "b"}},
+		OrphanedObjects: []models.FileIssue{
+			{UserID: 1, ObjectName: "orphan"},
+			{UserID: 1, ObjectName: "stale"},
+		},
+		StalePending: 1,
+	}
+
+	t.Run("report without changes", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, fileCheckBatchSize).Times(1).Return([]int{1}, nil)
+		expectUserFiles(userCtx, store, fs, crypter)
+
+		report, err := s.CheckFiles(ctx, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, report)
+	})
+
+	t.Run("collect garbage", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, fileCheckBatchSize).Times(1).Return([]int{1}, nil)
+		expectUserFiles(userCtx, store, fs, crypter)
+		store.EXPECT().SetFileUserDataMissing(userCtx, 2, true).Times(1).Return(nil)
+		store.EXPECT().SetFileUserDataMissing(userCtx, 3, false).Times(1).Return(nil)
+		fs.EXPECT().DeleteFile(userCtx, "orphan").Times(1).Return(nil)
+		fs.EXPECT().DeleteFile(userCtx, "stale").Times(1).Return(nil)
+		store.EXPECT().DeletePendingFileObject(userCtx, "stale").Times(1).Return(nil)
+
+		report, err := s.CheckFiles(ctx, true)
+
+		require.NoError(t, err)
+		collected := want
+		collected.DeletedObjects = 2
+		assert.Equal(t, collected, report)
+	})
+
+	t.Run("fetch users failed", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, fileCheckBatchSize).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.CheckFiles(ctx, true)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch users")
+	})
+
+	t.Run("list files failed", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, fileCheckBatchSize).Times(1).Return([]int{1}, nil)
+		store.EXPECT().FetchFileUserData(userCtx).Times(1).Return([]models.FileUserData{}, nil)
+		store.EXPECT().FetchFileUserDataHistory(userCtx).Times(1).Return([]models.FileUserData{}, nil)
+		store.EXPECT().FetchFileUploadObjects(userCtx).Times(1).Return([]string{}, nil)
+		store.EXPECT().FetchPendingFileObjects(userCtx).Times(1).Return([]models.PendingFileObject{}, nil)
+		fs.EXPECT().ListFiles(userCtx).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.CheckFiles(ctx, true)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to check files of user 1")
+		assert.ErrorContains(t, err, "failed to list files from filestorage")
+	})
+}
@@ -5,37 +5,95 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/google/uuid"
 )
 
 const fileDataType = "file"
 
+var ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// RangeNotSatisfiableError ошибка запроса диапазона байт за пределами файла размером Size.
+type RangeNotSatisfiableError struct {
+	Size int64
+}
+
+// Error возвращает текст ошибки.
+func (e *RangeNotSatisfiableError) Error() string {
+	return fmt.Sprintf("%s, file size %d", ErrRangeNotSatisfiable, e.Size)
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrRangeNotSatisfiable.
+func (e *RangeNotSatisfiableError) Unwrap() error {
+	return ErrRangeNotSatisfiable
+}
+
 // AddFile функция для добавления файла пользователя.
 func (s *Services) AddFile(ctx context.Context, req models.AddFileRequest) (int, error) {
 	if err := validateAddFileRequest(req); err != nil {
 		return 0, failedValidateFields(err)
 	}
 
-	if err := s.fileStorage.AddFile(ctx, req.File, req.FileName, req.FileSize); err != nil {
-		return 0, fmt.Errorf("failed to add file to filestorage %w", err)
+	labels, err := newAddedUserDataLabels(req.Folder, req.Tags)
+	if err != nil {
+		return 0, failedValidateFields(err)
 	}
 
-	data := models.EncryptFileData{
-		FileName: req.FileName,
+	if err := s.checkFileSize(ctx, 1, req.FileSize); err != nil {
+		return 0, err
 	}
-	jsonData, err := json.Marshal(data)
+
+	objectName := uuid.NewString()
+
+	if err := s.putFile(ctx, objectName, req.File, req.FileSize); err != nil {
+		return 0, err
+	}
+
+	id, err := s.addFileData(ctx, req.FileName, objectName, req.Mark, req.Description, req.FileSize, labels)
 	if err != nil {
-		return 0, failedGenerateJSONData(err)
+		return 0, err
 	}
 
-	encData := s.crypter.EncryptData(jsonData)
+	s.commitFileObject(ctx, objectName)
 
-	preparedMark := strings.ReplaceAll(strings.ToLower(req.Mark), " ", "_")
+	return id, nil
+}
 
-	id, err := s.storage.AddUserData(ctx, encData, preparedMark, req.Description, fileDataType)
+// addFileData сохраняет зашифрованные данные загруженного файла пользователя.
+// Если данные сохранить не удалось, объект файла удаляется.
+func (s *Services) addFileData(
+	ctx context.Context,
+	fileName, objectName, mark, description string,
+	fileSize int64,
+	labels models.UserDataLabels,
+) (int, error) {
+	id, err := s.addFileUserData(ctx, fileName, objectName, mark, description, fileSize, labels)
+	if err != nil {
+		if releaseErr := s.deleteFileObject(ctx, objectName); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *Services) addFileUserData(
+	ctx context.Context,
+	fileName, objectName, mark, description string,
+	fileSize int64,
+	labels models.UserDataLabels,
+) (int, error) {
+	encData, err := s.encryptFileData(ctx, fileName, objectName)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.storage.AddFileUserData(ctx, encData, prepareFileMark(mark), description, fileSize, labels)
 	if err != nil {
 		return 0, failedAddUserData(err)
 	}
@@ -43,37 +101,285 @@ func (s *Services) AddFile(ctx context.Context, req models.AddFileRequest) (int,
 	return id, nil
 }
 
-// GetFile функция для получения файла пользователя в виде массива байт.
-func (s *Services) GetFile(ctx context.Context, fileMark string) (models.File, error) {
+// GetFile функция для получения файла пользователя в виде потока.
+// Если запрошен диапазон байт и файл не изменился (IfRange), отдается только этот диапазон.
+func (s *Services) GetFile(ctx context.Context, id int, req models.GetFileRequest) (models.File, error) {
 	var resp models.File
-	decData, err := s.storage.GetFileUserData(ctx, fileMark)
+
+	fileData, err := s.getFileData(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	rng, info, err := s.fileRange(ctx, fileObjectName(fileData), req)
+	if err != nil {
+		return resp, err
+	}
+
+	resp, err = s.fileStorage.GetFile(ctx, fileObjectName(fileData), rng)
+	if err != nil {
+		return resp, fmt.Errorf("failed to get file from filestorage %w", err)
+	}
+
+	if rng != nil {
+		resp.Range = rng
+		resp.Size = info.Size
+		resp.ETag = info.ETag
+	}
+	resp.FileName = fileData.FileName
+
+	return resp, nil
+}
+
+// fileRange определяет отдаваемый диапазон байт файла. Возвращает nil, если нужно отдать весь файл.
+func (s *Services) fileRange(
+	ctx context.Context,
+	objectName string,
+	req models.GetFileRequest,
+) (*models.FileRange, models.File, error) {
+	var info models.File
+	if req.Range == nil {
+		return nil, info, nil
+	}
+
+	info, err := s.fileStorage.StatFile(ctx, objectName)
+	if err != nil {
+		return nil, info, fmt.Errorf("failed to stat file from filestorage %w", err)
+	}
+
+	if req.IfRange != "" && strings.Trim(req.IfRange, `"`) != strings.Trim(info.ETag, `"`) {
+		return nil, info, nil
+	}
+
+	rng, ok := resolveRange(*req.Range, info.Size)
+	if !ok {
+		return nil, info, &RangeNotSatisfiableError{Size: info.Size}
+	}
+
+	return &rng, info, nil
+}
+
+// resolveRange приводит запрошенный диапазон к границам файла размером size.
+func resolveRange(rng models.FileRange, size int64) (models.FileRange, bool) {
+	switch {
+	case rng.Start < 0:
+		if rng.End <= 0 || size == 0 {
+			return rng, false
+		}
+		rng.Start = max(size-rng.End, 0)
+		rng.End = size - 1
+	case rng.Start >= size:
+		return rng, false
+	case rng.End < 0 || rng.End >= size:
+		rng.End = size - 1
+	case rng.End < rng.Start:
+		return rng, false
+	}
+
+	return rng, true
+}
+
+// UpdateFile функция для замены файла пользователя. Папка и метки из req не используются.
+// Прежняя версия файла сохраняется в истории, ее объект остается в файловом хранилище.
+func (s *Services) UpdateFile(ctx context.Context, id int, req models.AddFileRequest) error {
+	if err := validateAddFileRequest(req); err != nil {
+		return failedValidateFields(err)
+	}
+
+	if _, err := s.getFileData(ctx, id); err != nil {
+		return err
+	}
+
+	// Размер заменяемого файла не вычитается: он остается в истории.
+	if err := s.checkFileSize(ctx, 0, req.FileSize); err != nil {
+		return err
+	}
+
+	objectName := uuid.NewString()
+
+	if err := s.putFile(ctx, objectName, req.File, req.FileSize); err != nil {
+		return err
+	}
+
+	if err := s.updateFileUserData(ctx, id, req, objectName); err != nil {
+		if releaseErr := s.deleteFileObject(ctx, objectName); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return err
+	}
+
+	s.commitFileObject(ctx, objectName)
+	s.pruneHistory(ctx, id, fileDataType)
+
+	return nil
+}
+
+// updateFileUserData сохраняет зашифрованные данные замененного файла пользователя вместе с его размером.
+func (s *Services) updateFileUserData(ctx context.Context, id int, req models.AddFileRequest, objectName string) error {
+	encData, err := s.encryptFileData(ctx, req.FileName, objectName)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.UpdateFileUserData(ctx, id, encData, prepareFileMark(req.Mark), req.Description, req.FileSize)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserDataNotFound) {
-			return resp, ErrNotFound
+			return ErrNotFound
 		}
 
-		return resp, failedGetUserData(err)
+		return failedUpdateUserData(err)
 	}
 
-	jsonData, err := s.crypter.DecryptData(decData)
+	return nil
+}
+
+// restoreFileRevision восстанавливает прежнюю версию файла пользователя. Версия из истории и текущий файл
+// меняются местами, поэтому ссылки на объекты файлов не меняются, а количество версий в истории остается прежним.
+func (s *Services) restoreFileRevision(ctx context.Context, id int, version int) error {
+	if err := s.storage.RestoreFileUserDataRevision(ctx, id, version); err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return failedUpdateUserData(err)
+	}
+
+	return nil
+}
+
+// encryptFileData шифрует данные файла пользователя.
+func (s *Services) encryptFileData(ctx context.Context, fileName, objectName string) ([]byte, error) {
+	data := models.EncryptFileData{
+		FileName:   fileName,
+		ObjectName: objectName,
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, failedGenerateJSONData(err)
+	}
+
+	encData, err := s.encryptData(ctx, jsonData)
+	if err != nil {
+		return nil, failedEncryptData(err)
+	}
+
+	return encData, nil
+}
+
+// PatchFile функция для обновления пометки, описания, папки и меток файла пользователя.
+func (s *Services) PatchFile(ctx context.Context, id int, req models.UpdateFileRequest) error {
+	labels, err := newUserDataLabels(req.Folder, req.Tags)
 	if err != nil {
-		return resp, failedDecryptData(err)
+		return failedValidateFields(err)
 	}
 
-	var encData models.EncryptFileData
+	if req.Mark != nil || req.Description != nil {
+		return s.patchFileData(ctx, id, req, labels)
+	}
 
-	if err = json.Unmarshal(jsonData, &encData); err != nil {
-		return resp, failedGenerateData(err)
+	if emptyLabels(labels) {
+		return s.checkUserData(ctx, id, fileDataType)
 	}
 
-	file, err := s.fileStorage.GetFile(ctx, encData.FileName)
+	return s.setUserDataLabels(ctx, id, fileDataType, labels)
+}
+
+// patchFileData функция для изменения метки и описания файла пользователя вместе с папкой и метками из labels.
+func (s *Services) patchFileData(
+	ctx context.Context,
+	id int,
+	req models.UpdateFileRequest,
+	labels models.UserDataLabels,
+) error {
+	encData, mark, description, err := s.storage.GetUserData(ctx, id, fileDataType)
 	if err != nil {
-		return resp, fmt.Errorf("failed to get file from filestorage %w", err)
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return failedGetUserData(err)
 	}
 
-	resp.File = file
+	patchValue(&mark, req.Mark)
+	patchValue(&description, req.Description)
 
-	return resp, nil
+	if err := validateAddFileRequest(models.AddFileRequest{Mark: mark, Description: description}); err != nil {
+		return failedValidateFields(err)
+	}
+
+	err = s.storage.UpdateUserData(ctx, id, req.Version, encData, prepareFileMark(mark), description, fileDataType, labels)
+	if err != nil {
+		return updateUserDataError(err)
+	}
+
+	return nil
+}
+
+// DeleteFile функция для удаления файла пользователя в корзину.
+// Объект файла остается в файловом хранилище, пока файл не удален из корзины окончательно.
+func (s *Services) DeleteFile(ctx context.Context, id int) error {
+	return s.deleteUserData(ctx, id, fileDataType)
+}
+
+// putFile сохраняет содержимое файла в файловое хранилище под именем objectName.
+// Перед записью объект отмечается как незавершенный: пока отметка не снята commitFileObject,
+// сборщик мусора не удаляет его, а после срока ожидания проверяет, что объект используется.
+// Каждый объект принадлежит одной версии одного файла: клиент шифрует каждый файл своим случайным ключом,
+// поэтому одинаковые файлы в хранилище все равно различаются и не объединяются.
+func (s *Services) putFile(ctx context.Context, objectName string, file io.Reader, size int64) error {
+	if err := s.storage.AddPendingFileObject(ctx, objectName); err != nil {
+		return fmt.Errorf("failed to add pending file object %w", err)
+	}
+
+	if err := s.fileStorage.AddFile(ctx, file, objectName, size); err != nil {
+		return fmt.Errorf("failed to add file to filestorage %w", err)
+	}
+
+	return nil
+}
+
+// commitFileObject снимает отметку о записи объекта после сохранения данных пользователя.
+// Данные уже сохранены, а оставшуюся отметку удалит сборщик мусора, поэтому ошибка не возвращается.
+func (s *Services) commitFileObject(ctx context.Context, pendingName string) {
+	_ = s.storage.DeletePendingFileObject(ctx, pendingName)
+}
+
+// deleteFileObject удаляет объект файла, который больше не используется, из файлового хранилища.
+func (s *Services) deleteFileObject(ctx context.Context, objectName string) error {
+	if err := s.fileStorage.DeleteFile(ctx, objectName); err != nil {
+		return fmt.Errorf("failed to delete file from filestorage %w", err)
+	}
+
+	return nil
+}
+
+// fileObjectName возвращает имя объекта файла в файловом хранилище.
+// Файлы, сохраненные до появления отдельных имен объектов, хранятся под именем файла.
+func fileObjectName(data models.EncryptFileData) string {
+	if data.ObjectName != "" {
+		return data.ObjectName
+	}
+
+	return data.FileName
+}
+
+func (s *Services) getFileData(ctx context.Context, id int) (models.EncryptFileData, error) {
+	var fileData models.EncryptFileData
+
+	encData, _, _, err := s.storage.GetUserData(ctx, id, fileDataType)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return fileData, ErrNotFound
+		}
+
+		return fileData, failedGetUserData(err)
+	}
+
+	return s.decryptFileData(ctx, encData)
+}
+
+func prepareFileMark(mark string) string {
+	return strings.ReplaceAll(strings.ToLower(mark), " ", "_")
 }
 
 func validateAddFileRequest(req models.AddFileRequest) error {
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"strings"
@@ -25,67 +26,107 @@ func TestAddFile(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
-
-	req := models.AddFileRequest{
-		File:        strings.NewReader("test"),
-		FileName:    "test",
-		FileSize:    int64(100),
-		Mark:        "test",
-		Description: "test",
-	}
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
-	dataType := "file"
 	encData := []byte("some data")
 
-	type sResponse struct {
-		id  int
-		err error
-	}
 	tests := []struct {
-		name      string
-		sResponse sResponse
-		wantErr   bool
+		addErr  error
+		name    string
+		id      int
+		wantErr bool
 	}{
 		{
 			name: "add file success",
-			sResponse: sResponse{
-				id:  1,
-				err: nil,
-			},
-			wantErr: false,
+			id:   1,
 		},
 		{
-			name: "add file failed",
-			sResponse: sResponse{
-				id:  0,
-				err: errors.New("some error"),
-			},
+			name:    "add file failed",
+			addErr:  errors.New("some error"),
 			wantErr: true,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fs.EXPECT().AddFile(ctx, req.File, req.FileName, req.FileSize).Times(1).Return(nil)
-			crypter.EXPECT().EncryptData(gomock.Any()).Times(1).Return(encData)
+			req := models.AddFileRequest{
+				File:        strings.NewReader("test"),
+				FileName:    "test",
+				FileSize:    int64(4),
+				Mark:        "test",
+				Description: "test",
+			}
+
+			uploaded := expectPutFile(ctx, t, fs, store, "test", !test.wantErr)
+
+			var stored models.EncryptFileData
+			crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(_ []byte, data []byte) ([]byte, error) {
+					require.NoError(t, json.Unmarshal(data, &stored))
+					return encData, nil
+				})
 			store.EXPECT().
-				AddUserData(ctx, encData, req.Mark, req.Description, dataType).
-				Times(1).Return(test.sResponse.id, test.sResponse.err)
+				AddFileUserData(ctx, encData, req.Mark, req.Description, req.FileSize, models.UserDataLabels{}).
+				Times(1).Return(test.id, test.addErr)
+			if test.addErr != nil {
+				fs.EXPECT().DeleteFile(ctx, gomock.Any()).Times(1).Return(nil)
+			}
 
 			id, err := s.AddFile(ctx, req)
 
 			if test.wantErr {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, "failed to add user data", "some error")
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, test.sResponse.id, id)
+				return
 			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.id, id)
+			assert.Equal(t, "test", stored.FileName)
+			assert.Equal(t, *uploaded, stored.ObjectName)
+			assert.NotEqual(t, req.FileName, stored.ObjectName)
 		})
 	}
 }
 
+// expectPutFile ожидает сохранение файла с содержимым content под новым именем объекта.
+// Если committed, после сохранения данных снимается отметка о незавершенной записи объекта.
+// Возвращает указатель на имя сохраненного объекта.
+func expectPutFile(
+	ctx context.Context,
+	t *testing.T,
+	fs *mocks.MockFileStorager,
+	store *mocks.MockStorager,
+	content string,
+	committed bool,
+) *string {
+	t.Helper()
+
+	var objectName string
+
+	store.EXPECT().AddPendingFileObject(ctx, gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, name string) error {
+			objectName = name
+			return nil
+		})
+	fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), int64(len(content))).Times(1).
+		DoAndReturn(func(_ context.Context, file io.Reader, name string, _ int64) error {
+			_, err := io.ReadAll(file)
+			assert.Equal(t, objectName, name)
+			return err
+		})
+	if committed {
+		store.EXPECT().DeletePendingFileObject(ctx, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, name string) error {
+				assert.Equal(t, objectName, name)
+				return errors.New("pending object is removed by garbage collector")
+			})
+	}
+
+	return &objectName
+}
+
 func TestAddFileFileStorageFailed(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -95,6 +136,7 @@ func TestAddFileFileStorageFailed(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
 	req := models.AddFileRequest{
@@ -107,10 +149,21 @@ func TestAddFileFileStorageFailed(t *testing.T) {
 
 	someErr := errors.New("some error")
 
+	t.Run("add pending file object failed", func(t *testing.T) {
+		store.EXPECT().AddPendingFileObject(ctx, gomock.Any()).Times(1).Return(someErr)
+		fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.AddFile(ctx, req)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to add pending file object")
+	})
+
 	t.Run("file storage failed", func(t *testing.T) {
-		fs.EXPECT().AddFile(ctx, req.File, req.FileName, req.FileSize).Times(1).Return(someErr)
-		crypter.EXPECT().EncryptData(gomock.Any()).Times(0)
-		store.EXPECT().AddUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().AddPendingFileObject(ctx, gomock.Any()).Times(1).Return(nil)
+		fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), req.FileSize).Times(1).Return(someErr)
+		crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().AddFileUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		_, err := s.AddFile(ctx, req)
 
@@ -128,6 +181,7 @@ func TestAddFileValidationFailed(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
 
@@ -177,8 +231,8 @@ func TestAddFileValidationFailed(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-			crypter.EXPECT().EncryptData(gomock.Any()).Times(0)
-			store.EXPECT().AddUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().AddFileUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 			_, err := s.AddFile(ctx, test.arg.req)
 
@@ -197,34 +251,59 @@ func TestGetFile(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
 	decData := []byte("some data")
-	fileMark := "test"
+	fileID := 1
 
-	jsonData := []byte(`{"file_name":"test"}`)
+	legacyData := []byte(`{"file_name":"test"}`)
 
 	type fsResponse struct {
-		file io.ReadCloser
+		file models.File
 		err  error
 	}
 	tests := []struct {
 		name       string
+		object     string
+		jsonData   []byte
 		fsResponse fsResponse
 		wantErr    bool
 	}{
 		{
-			name: "get user data success",
+			name:     "get file stored under object name success",
+			object:   "object",
+			jsonData: []byte(`{"file_name":"test","object_name":"object"}`),
+			fsResponse: fsResponse{
+				file: models.File{
+					File: io.NopCloser(strings.NewReader("some data")),
+					ETag: "etag",
+					Size: 9,
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "get legacy file stored under file name success",
+			object:   "test",
+			jsonData: legacyData,
 			fsResponse: fsResponse{
-				file: io.NopCloser(strings.NewReader("some data")),
-				err:  nil,
+				file: models.File{
+					File: io.NopCloser(strings.NewReader("some data")),
+					ETag: "etag",
+					Size: 9,
+				},
+				err: nil,
 			},
 			wantErr: false,
 		},
 		{
-			name: "when get file from fileserver failed",
+			name:     "when get file from fileserver failed",
+			object:   "test",
+			jsonData: legacyData,
 			fsResponse: fsResponse{
-				file: nil,
+				file: models.File{},
 				err:  errors.New("some error"),
 			},
 			wantErr: true,
@@ -233,18 +312,180 @@ func TestGetFile(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetFileUserData(ctx, fileMark).Times(1).Return(decData, nil)
-			crypter.EXPECT().DecryptData(decData).Times(1).Return(jsonData, nil)
-			fs.EXPECT().GetFile(ctx, "test").Times(1).Return(test.fsResponse.file, test.fsResponse.err)
+			store.EXPECT().GetUserData(ctx, fileID, "file").Times(1).Return(decData, "test", "", nil)
+			crypter.EXPECT().DecryptData(gomock.Any(), decData).Times(1).Return(test.jsonData, nil)
+			fs.EXPECT().GetFile(ctx, test.object, nil).Times(1).Return(test.fsResponse.file, test.fsResponse.err)
 
-			resp, err := s.GetFile(ctx, fileMark)
+			resp, err := s.GetFile(ctx, fileID, models.GetFileRequest{})
 
 			if test.wantErr {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, "failed to get file from filestorage")
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, models.File{File: test.fsResponse.file}, resp)
+				want := test.fsResponse.file
+				want.FileName = "test"
+				assert.Equal(t, want, resp)
+			}
+		})
+	}
+}
+
+func TestGetFileRange(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	decData := []byte("some data")
+	fileID := 1
+	jsonData := []byte(`{"file_name":"test"}`)
+	info := models.File{ETag: "etag", Size: 100}
+
+	type statResponse struct {
+		info models.File
+		err  error
+	}
+	tests := []struct {
+		name         string
+		req          models.GetFileRequest
+		statResponse statResponse
+		wantRange    *models.FileRange
+		getTimes     int
+		errText      string
+	}{
+		{
+			name:         "range from offset",
+			req:          models.GetFileRequest{Range: &models.FileRange{Start: 10, End: -1}},
+			statResponse: statResponse{info: info},
+			wantRange:    &models.FileRange{Start: 10, End: 99},
+			getTimes:     1,
+		},
+		{
+			name:         "range with matching if-range",
+			req:          models.GetFileRequest{Range: &models.FileRange{Start: -1, End: 20}, IfRange: `"etag"`},
+			statResponse: statResponse{info: info},
+			wantRange:    &models.FileRange{Start: 80, End: 99},
+			getTimes:     1,
+		},
+		{
+			name:         "whole file when if-range not match",
+			req:          models.GetFileRequest{Range: &models.FileRange{Start: 10, End: 20}, IfRange: `"other"`},
+			statResponse: statResponse{info: info},
+			wantRange:    nil,
+			getTimes:     1,
+		},
+		{
+			name:         "range not satisfiable",
+			req:          models.GetFileRequest{Range: &models.FileRange{Start: 100, End: -1}},
+			statResponse: statResponse{info: info},
+			getTimes:     0,
+			errText:      "requested range not satisfiable",
+		},
+		{
+			name:         "stat file failed",
+			req:          models.GetFileRequest{Range: &models.FileRange{Start: 10, End: -1}},
+			statResponse: statResponse{err: errors.New("some error")},
+			getTimes:     0,
+			errText:      "failed to stat file from filestorage",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().GetUserData(ctx, fileID, "file").Times(1).Return(decData, "test", "", nil)
+			crypter.EXPECT().DecryptData(gomock.Any(), decData).Times(1).Return(jsonData, nil)
+			fs.EXPECT().StatFile(ctx, "test").Times(1).Return(test.statResponse.info, test.statResponse.err)
+			fs.EXPECT().GetFile(ctx, "test", test.wantRange).Times(test.getTimes).
+				Return(models.File{File: io.NopCloser(strings.NewReader("data")), ETag: "etag", Size: 100}, nil)
+
+			resp, err := s.GetFile(ctx, fileID, test.req)
+
+			if test.errText != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.wantRange, resp.Range)
+			assert.Equal(t, int64(100), resp.Size)
+			assert.Equal(t, "etag", resp.ETag)
+			assert.Equal(t, "test", resp.FileName)
+		})
+	}
+}
+
+func TestResolveRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		rng    models.FileRange
+		size   int64
+		want   models.FileRange
+		wantOk bool
+	}{
+		{
+			name:   "closed range",
+			rng:    models.FileRange{Start: 0, End: 9},
+			size:   100,
+			want:   models.FileRange{Start: 0, End: 9},
+			wantOk: true,
+		},
+		{
+			name:   "open range",
+			rng:    models.FileRange{Start: 90, End: -1},
+			size:   100,
+			want:   models.FileRange{Start: 90, End: 99},
+			wantOk: true,
+		},
+		{
+			name:   "end after file",
+			rng:    models.FileRange{Start: 90, End: 200},
+			size:   100,
+			want:   models.FileRange{Start: 90, End: 99},
+			wantOk: true,
+		},
+		{
+			name:   "suffix range",
+			rng:    models.FileRange{Start: -1, End: 10},
+			size:   100,
+			want:   models.FileRange{Start: 90, End: 99},
+			wantOk: true,
+		},
+		{
+			name:   "suffix bigger than file",
+			rng:    models.FileRange{Start: -1, End: 200},
+			size:   100,
+			want:   models.FileRange{Start: 0, End: 99},
+			wantOk: true,
+		},
+		{
+			name:   "start after file",
+			rng:    models.FileRange{Start: 100, End: -1},
+			size:   100,
+			wantOk: false,
+		},
+		{
+			name:   "suffix of empty file",
+			rng:    models.FileRange{Start: -1, End: 10},
+			size:   0,
+			wantOk: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := resolveRange(test.rng, test.size)
+
+			assert.Equal(t, test.wantOk, ok)
+			if test.wantOk {
+				assert.Equal(t, test.want, got)
 			}
 		})
 	}
@@ -259,10 +500,11 @@ func TestGetFileDecryptDataFailed(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
 	decData := []byte("some data")
-	fileMark := "test"
+	fileID := 1
 
 	type cResponse struct {
 		jsonData []byte
@@ -296,11 +538,11 @@ func TestGetFileDecryptDataFailed(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetFileUserData(ctx, fileMark).Times(1).Return(decData, nil)
-			crypter.EXPECT().DecryptData(gomock.Any()).Times(1).Return(test.cResponse.jsonData, test.cResponse.err)
-			fs.EXPECT().GetFile(ctx, gomock.Any()).Times(0)
+			store.EXPECT().GetUserData(ctx, fileID, "file").Times(1).Return(decData, "test", "", nil)
+			crypter.EXPECT().DecryptData(gomock.Any(), gomock.Any()).Times(1).Return(test.cResponse.jsonData, test.cResponse.err)
+			fs.EXPECT().GetFile(ctx, gomock.Any(), gomock.Any()).Times(0)
 
-			_, err := s.GetFile(ctx, fileMark)
+			_, err := s.GetFile(ctx, fileID, models.GetFileRequest{})
 
 			require.Error(t, err)
 			assert.ErrorContains(t, err, test.errText)
@@ -317,9 +559,10 @@ func TestGetFileFailedStorage(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
-	fileMark := "test"
+	fileID := 1
 
 	type sResponse struct {
 		err error
@@ -347,14 +590,235 @@ func TestGetFileFailedStorage(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetFileUserData(ctx, fileMark).Times(1).Return([]byte{}, test.sResponse.err)
-			crypter.EXPECT().DecryptData(gomock.Any()).Times(0)
-			fs.EXPECT().GetFile(ctx, gomock.Any()).Times(0)
+			store.EXPECT().GetUserData(ctx, fileID, "file").Times(1).Return(nil, "", "", test.sResponse.err)
+			crypter.EXPECT().DecryptData(gomock.Any(), gomock.Any()).Times(0)
+			fs.EXPECT().GetFile(ctx, gomock.Any(), gomock.Any()).Times(0)
 
-			_, err := s.GetFile(ctx, fileMark)
+			_, err := s.GetFile(ctx, fileID, models.GetFileRequest{})
 
 			require.Error(t, err)
 			assert.ErrorContains(t, err, test.errText, test.sResponse.err.Error())
 		})
 	}
 }
+
+func TestUpdateFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{History: config.HistorySettings{Retention: 2}}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "file"
+	oldData := []byte("old data")
+	prunedData := []byte("pruned data")
+	encData := []byte("some data")
+
+	tests := []struct {
+		deleteErr    error
+		name         string
+		oldData      string
+		prunedObject string
+	}{
+		{
+			name:    "update file keeps old version in history",
+			oldData: `{"file_name":"old","object_name":"old-object"}`,
+		},
+		{
+			name:    "update legacy file stored under file name success",
+			oldData: `{"file_name":"old"}`,
+		},
+		{
+			name:         "update file releases object of pruned version",
+			oldData:      `{"file_name":"old","object_name":"old-object"}`,
+			prunedObject: "pruned-object",
+		},
+		{
+			name:         "when delete object of pruned version failed",
+			oldData:      `{"file_name":"old","object_name":"old-object"}`,
+			prunedObject: "pruned-object",
+			deleteErr:    errors.New("some error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := models.AddFileRequest{
+				File:        strings.NewReader("test"),
+				FileName:    "new",
+				FileSize:    int64(4),
+				Mark:        "New Mark",
+				Description: "test",
+			}
+
+			store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldData, "old", "old", nil)
+			crypter.EXPECT().DecryptData(gomock.Any(), oldData).Times(1).Return([]byte(test.oldData), nil)
+			uploaded := expectPutFile(ctx, t, fs, store, "test", true)
+			crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(_ []byte, data []byte) ([]byte, error) {
+					assert.JSONEq(t, `{"file_name":"new","object_name":"`+*uploaded+`"}`, string(data))
+					return encData, nil
+				})
+			store.EXPECT().UpdateFileUserData(ctx, userDataID, encData, "new_mark", req.Description, req.FileSize).
+				Times(1).Return(nil)
+
+			pruned := [][]byte{}
+			if test.prunedObject != "" {
+				pruned = append(pruned, prunedData)
+				crypter.EXPECT().DecryptData(gomock.Any(), prunedData).Times(1).
+					Return([]byte(`{"file_name":"pruned","object_name":"`+test.prunedObject+`"}`), nil)
+				fs.EXPECT().DeleteFile(ctx, test.prunedObject).Times(1).Return(test.deleteErr)
+			}
+			store.EXPECT().PruneUserDataHistory(ctx, userDataID, 2).Times(1).Return(pruned, nil)
+
+			require.NoError(t, s.UpdateFile(ctx, userDataID, req))
+		})
+	}
+}
+
+func TestUpdateFileNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	req := models.AddFileRequest{
+		File:     strings.NewReader("test"),
+		FileName: "test",
+		Mark:     "test",
+	}
+
+	t.Run("when file not found", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, 1, "file").Times(1).Return(nil, "", "", storage.ErrUserDataNotFound)
+		fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().UpdateFileUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		err := s.UpdateFile(ctx, 1, req)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestPatchFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "file"
+	encData := []byte("some data")
+	newMark := "New Mark"
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr bool
+		errText string
+	}{
+		{
+			name:    "patch file success",
+			sErr:    nil,
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:    "patch file failed",
+			sErr:    errors.New("some error"),
+			wantErr: true,
+			errText: "failed to update user data",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+			crypter.EXPECT().DecryptData(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, "new_mark", "test", dataType, models.UserDataLabels{}).
+				Times(1).Return(test.sErr)
+
+			err := s.PatchFile(ctx, userDataID, models.UpdateFileRequest{Mark: &newMark})
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeleteFile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "file"
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr bool
+		errText string
+	}{
+		{
+			name: "delete file success",
+		},
+		{
+			name:    "when file not found",
+			sErr:    storage.ErrUserDataNotFound,
+			wantErr: true,
+			errText: ErrNotFound.Error(),
+		},
+		{
+			name:    "when delete user data failed",
+			sErr:    errors.New("some error"),
+			wantErr: true,
+			errText: "some error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().DeleteUserData(ctx, userDataID, dataType).Times(1).Return(test.sErr)
+			fs.EXPECT().DeleteFile(gomock.Any(), gomock.Any()).Times(0)
+
+			err := s.DeleteFile(ctx, userDataID)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+)
+
+// historyDataTypes типы данных, для которых хранится история версий. У файлов в истории хранятся
+// прежние версии содержимого, объекты которых остаются в файловом хранилище, пока версии не удалены.
+var historyDataTypes = map[string]bool{
+	passwordDataType: true,
+	cardDataType:     true,
+	textDataType:     true,
+	otpDataType:      true,
+	fileDataType:     true,
+}
+
+// FetchHistory функция для получения списка прежних версий данных пользователя, начиная с последней.
+func (s *Services) FetchHistory(ctx context.Context, id int, dataType string) ([]models.UserDataRevision, error) {
+	if !historyDataTypes[dataType] {
+		return nil, ErrNotFound
+	}
+
+	revisions, err := s.storage.FetchUserDataHistory(ctx, id, dataType)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to fetch user data history: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RestoreRevision функция для восстановления прежней версии данных пользователя.
+// Текущая версия при этом сохраняется в истории, поэтому восстановление тоже можно отменить.
+func (s *Services) RestoreRevision(ctx context.Context, id int, version int, dataType string) error {
+	if !historyDataTypes[dataType] {
+		return ErrNotFound
+	}
+	if dataType == fileDataType {
+		return s.restoreFileRevision(ctx, id, version)
+	}
+
+	encData, mark, description, err := s.storage.GetUserDataRevision(ctx, id, version, dataType)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return failedGetUserData(err)
+	}
+
+	return s.replaceUserData(ctx, id, 0, encData, mark, description, dataType, models.UserDataLabels{})
+}
+
+// fileHistoryObjects возвращает имена объектов прежних версий файлов пользователя по ID данных файла.
+func (s *Services) fileHistoryObjects(ctx context.Context) (map[int][]string, error) {
+	history, err := s.storage.FetchFileUserDataHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file history %w", err)
+	}
+
+	objects := make(map[int][]string, len(history))
+	for _, h := range history {
+		fileData, err := s.decryptFileData(ctx, h.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		objects[h.ID] = append(objects[h.ID], fileObjectName(fileData))
+	}
+
+	return objects, nil
+}
+
+// pruneHistory удаляет из истории данных пользователя версии сверх настроенного количества
+// и освобождает объекты удаленных версий файла.
+func (s *Services) pruneHistory(ctx context.Context, id int, dataType string) {
+	keep := s.settings.History.Retention
+	if keep <= 0 || !historyDataTypes[dataType] {
+		return
+	}
+
+	// Данные уже изменены, лишние версии будут удалены при следующем изменении.
+	pruned, err := s.storage.PruneUserDataHistory(ctx, id, keep)
+	if err != nil || dataType != fileDataType {
+		return
+	}
+
+	// Объект, который не удалось удалить, останется потерянным и будет удален сборщиком мусора.
+	for _, data := range pruned {
+		fileData, err := s.decryptFileData(ctx, data)
+		if err != nil {
+			continue
+		}
+		_ = s.deleteFileObject(ctx, fileObjectName(fileData))
+	}
+}
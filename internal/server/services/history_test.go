@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+
+	ctx := context.Background()
+	revisions := []models.UserDataRevision{{Version: 1, Mark: "mark"}}
+
+	t.Run("success fetch history", func(t *testing.T) {
+		store.EXPECT().FetchUserDataHistory(ctx, 1, "password").Times(1).Return(revisions, nil)
+
+		resp, err := s.FetchHistory(ctx, 1, "password")
+
+		require.NoError(t, err)
+		assert.Equal(t, revisions, resp)
+	})
+
+	t.Run("success fetch file history", func(t *testing.T) {
+		store.EXPECT().FetchUserDataHistory(ctx, 1, "file").Times(1).Return(revisions, nil)
+
+		resp, err := s.FetchHistory(ctx, 1, "file")
+
+		require.NoError(t, err)
+		assert.Equal(t, revisions, resp)
+	})
+
+	t.Run("unknown data type has no history", func(t *testing.T) {
+		_, err := s.FetchHistory(ctx, 1, "unknown")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("user data not found", func(t *testing.T) {
+		store.EXPECT().FetchUserDataHistory(ctx, 1, "card").Times(1).Return(nil, storage.ErrUserDataNotFound)
+
+		_, err := s.FetchHistory(ctx, 1, "card")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("fetch history failed", func(t *testing.T) {
+		store.EXPECT().FetchUserDataHistory(ctx, 1, "text").Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.FetchHistory(ctx, 1, "text")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch user data history")
+	})
+}
+
+func TestRestoreRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{History: config.HistorySettings{Retention: 5}}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+
+	ctx := context.Background()
+	encData := []byte("data")
+	noLabels := models.UserDataLabels{}
+
+	t.Run("success restore revision", func(t *testing.T) {
+		store.EXPECT().GetUserDataRevision(ctx, 1, 2, "otp").Times(1).Return(encData, "mark", "description", nil)
+		store.EXPECT().UpdateUserData(ctx, 1, 0, encData, "mark", "description", "otp", noLabels).Times(1).Return(nil)
+		store.EXPECT().PruneUserDataHistory(ctx, 1, 5).Times(1).Return([][]byte{[]byte("old")}, nil)
+
+		require.NoError(t, s.RestoreRevision(ctx, 1, 2, "otp"))
+	})
+
+	t.Run("restore when prune failed", func(t *testing.T) {
+		store.EXPECT().GetUserDataRevision(ctx, 1, 2, "otp").Times(1).Return(encData, "mark", "description", nil)
+		store.EXPECT().UpdateUserData(ctx, 1, 0, encData, "mark", "description", "otp", noLabels).Times(1).Return(nil)
+		store.EXPECT().PruneUserDataHistory(ctx, 1, 5).Times(1).Return(nil, errors.New("some error"))
+
+		require.NoError(t, s.RestoreRevision(ctx, 1, 2, "otp"))
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		store.EXPECT().GetUserDataRevision(ctx, 1, 2, "password").Times(1).
+			Return(nil, "", "", storage.ErrUserDataNotFound)
+
+		err := s.RestoreRevision(ctx, 1, 2, "password")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("get revision failed", func(t *testing.T) {
+		store.EXPECT().GetUserDataRevision(ctx, 1, 2, "password").Times(1).
+			Return(nil, "", "", errors.New("some error"))
+
+		err := s.RestoreRevision(ctx, 1, 2, "password")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get user data")
+	})
+
+	t.Run("user data deleted while restore", func(t *testing.T) {
+		store.EXPECT().GetUserDataRevision(ctx, 1, 2, "card").Times(1).Return(encData, "mark", "description", nil)
+		store.EXPECT().UpdateUserData(ctx, 1, 0, encData, "mark", "description", "card", noLabels).Times(1).
+			Return(storage.ErrUserDataNotFound)
+
+		err := s.RestoreRevision(ctx, 1, 2, "card")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("success restore file revision", func(t *testing.T) {
+		store.EXPECT().RestoreFileUserDataRevision(ctx, 1, 2).Times(1).Return(nil)
+		store.EXPECT().PruneUserDataHistory(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		require.NoError(t, s.RestoreRevision(ctx, 1, 2, "file"))
+	})
+
+	t.Run("file revision not found", func(t *testing.T) {
+		store.EXPECT().RestoreFileUserDataRevision(ctx, 1, 2).Times(1).Return(storage.ErrUserDataNotFound)
+
+		err := s.RestoreRevision(ctx, 1, 2, "file")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("restore file revision failed", func(t *testing.T) {
+		store.EXPECT().RestoreFileUserDataRevision(ctx, 1, 2).Times(1).Return(errors.New("some error"))
+
+		err := s.RestoreRevision(ctx, 1, 2, "file")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to update user data")
+	})
+
+	t.Run("unknown data type has no history", func(t *testing.T) {
+		err := s.RestoreRevision(ctx, 1, 2, "unknown")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+)
+
+// dataKey функция получения ключа данных пользователя из контекста, ключ создается при первом обращении.
+func (s *Services) dataKey(ctx context.Context) ([]byte, error) {
+	wrappedKey, err := s.storage.GetUserKey(ctx)
+	if errors.Is(err, storage.ErrUserKeyNotFound) {
+		wrappedKey, err = s.createDataKey(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user key %w", err)
+	}
+
+	dataKey, err := s.crypter.UnwrapDataKey(wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap user key %w", err)
+	}
+
+	return dataKey, nil
+}
+
+// createDataKey функция создания ключа данных пользователя.
+func (s *Services) createDataKey(ctx context.Context) ([]byte, error) {
+	_, wrappedKey, err := s.crypter.GenerateDataKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user key %w", err)
+	}
+
+	if err := s.storage.AddUserKey(ctx, wrappedKey, s.crypter.MasterKeyID()); err != nil {
+		return nil, fmt.Errorf("failed to add user key %w", err)
+	}
+
+	// При параллельных запросах сохраняется ключ первого из них, поэтому ключ перечитывается.
+	wrappedKey, err = s.storage.GetUserKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user key %w", err)
+	}
+
+	return wrappedKey, nil
+}
+
+// encryptData функция шифрования данных ключом пользователя из контекста.
+func (s *Services) encryptData(ctx context.Context, data []byte) ([]byte, error) {
+	dataKey, err := s.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	encData, err := s.crypter.EncryptData(dataKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %w", err)
+	}
+
+	return encData, nil
+}
+
+// decryptData функция расшифровки данных ключом пользователя из контекста.
+func (s *Services) decryptData(ctx context.Context, encData []byte) ([]byte, error) {
+	dataKey, err := s.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.crypter.DecryptData(dataKey, encData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	wrappedKey := []byte("wrapped key")
+	dataKey := []byte("data key")
+
+	t.Run("existing key", func(t *testing.T) {
+		store.EXPECT().GetUserKey(ctx).Times(1).Return(wrappedKey, nil)
+		crypter.EXPECT().UnwrapDataKey(wrappedKey).Times(1).Return(dataKey, nil)
+
+		key, err := s.dataKey(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, key)
+	})
+
+	t.Run("create key on first use", func(t *testing.T) {
+		gomock.InOrder(
+			store.EXPECT().GetUserKey(ctx).Times(1).Return(nil, storage.ErrUserKeyNotFound),
+			crypter.EXPECT().GenerateDataKey().Times(1).Return(dataKey, wrappedKey, nil),
+			crypter.EXPECT().MasterKeyID().Times(1).Return("key id"),
+			store.EXPECT().AddUserKey(ctx, wrappedKey, "key id").Times(1).Return(nil),
+			store.EXPECT().GetUserKey(ctx).Times(1).Return(wrappedKey, nil),
+			crypter.EXPECT().UnwrapDataKey(wrappedKey).Times(1).Return(dataKey, nil),
+		)
+
+		key, err := s.dataKey(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, key)
+	})
+
+	t.Run("failed to add key", func(t *testing.T) {
+		store.EXPECT().GetUserKey(ctx).Times(1).Return(nil, storage.ErrUserKeyNotFound)
+		crypter.EXPECT().GenerateDataKey().Times(1).Return(dataKey, wrappedKey, nil)
+		crypter.EXPECT().MasterKeyID().Times(1).Return("key id")
+		store.EXPECT().AddUserKey(ctx, wrappedKey, "key id").Times(1).Return(errors.New("some error"))
+
+		_, err := s.dataKey(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to add user key")
+	})
+
+	t.Run("failed to unwrap key", func(t *testing.T) {
+		store.EXPECT().GetUserKey(ctx).Times(1).Return(wrappedKey, nil)
+		crypter.EXPECT().UnwrapDataKey(wrappedKey).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.dataKey(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to unwrap user key")
+	})
+}
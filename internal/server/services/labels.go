@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+var (
+	ErrInvalidTag    = errors.New("invalid tag")
+	ErrTooManyTags   = errors.New("too many tags")
+	ErrInvalidFolder = errors.New("invalid folder")
+
+	maxTagSize    = 50
+	maxTagsCount  = 20
+	maxFolderSize = 500
+)
+
+// newUserDataLabels функция для проверки и приведения к единому виду папки и меток данных пользователя.
+func newUserDataLabels(folder *string, tags *[]string) (models.UserDataLabels, error) {
+	labels := models.UserDataLabels{}
+
+	if folder != nil {
+		path, err := normalizeFolder(*folder)
+		if err != nil {
+			return labels, err
+		}
+
+		labels.Folder = &path
+	}
+
+	if tags != nil {
+		names, err := normalizeTags(*tags)
+		if err != nil {
+			return labels, err
+		}
+
+		labels.Tags = &names
+	}
+
+	return labels, nil
+}
+
+// newAddedUserDataLabels функция для папки и меток добавляемых данных пользователя:
+// пустые папка и метки не сохраняются.
+func newAddedUserDataLabels(folder string, tags []string) (models.UserDataLabels, error) {
+	var folderPtr *string
+	var tagsPtr *[]string
+
+	if folder != "" {
+		folderPtr = &folder
+	}
+	if len(tags) > 0 {
+		tagsPtr = &tags
+	}
+
+	return newUserDataLabels(folderPtr, tagsPtr)
+}
+
+// emptyLabels проверяет, что папку и метки не нужно менять.
+func emptyLabels(labels models.UserDataLabels) bool {
+	return labels.Folder == nil && labels.Tags == nil
+}
+
+// setUserDataLabels функция для изменения папки и меток данных пользователя.
+func (s *Services) setUserDataLabels(
+	ctx context.Context,
+	id int,
+	dataType string,
+	labels models.UserDataLabels,
+) error {
+	if emptyLabels(labels) {
+		return nil
+	}
+
+	if err := s.storage.SetUserDataLabels(ctx, id, dataType, labels); err != nil {
+		return updateUserDataError(err)
+	}
+
+	return nil
+}
+
+// normalizeFolder функция для приведения пути папки к виду "work/aws": пробелы и косые черты
+// по краям частей пути убираются. Пустые части внутри пути не допускаются.
+func normalizeFolder(folder string) (string, error) {
+	folder = strings.Trim(strings.TrimSpace(folder), "/")
+	if folder == "" {
+		return "", nil
+	}
+
+	parts := strings.Split(folder, "/")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+		if parts[i] == "" {
+			return "", ErrInvalidFolder
+		}
+	}
+
+	path := strings.Join(parts, "/")
+	if len([]rune(path)) > maxFolderSize {
+		return "", ErrInvalidFolder
+	}
+
+	return path, nil
+}
+
+// normalizeTags функция для приведения меток к единому виду: пробелы по краям убираются,
+// повторы удаляются, метки сортируются.
+func normalizeTags(tags []string) ([]string, error) {
+	names := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+		name := strings.TrimSpace(t)
+		if name == "" || len([]rune(name)) > maxTagSize {
+			return nil, ErrInvalidTag
+		}
+
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	if len(names) > maxTagsCount {
+		return nil, ErrTooManyTags
+	}
+
+	return names, nil
+}
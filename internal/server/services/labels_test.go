@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeFolder(t *testing.T) {
+	tests := []struct {
+		name    string
+		folder  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "plain path",
+			folder: "work/aws",
+			want:   "work/aws",
+		},
+		{
+			name:   "path with spaces and slashes",
+			folder: " /work / aws prod/ ",
+			want:   "work/aws prod",
+		},
+		{
+			name:   "empty path",
+			folder: " / ",
+			want:   "",
+		},
+		{
+			name:    "path with empty part",
+			folder:  "work//aws",
+			wantErr: ErrInvalidFolder,
+		},
+		{
+			name:    "too long path",
+			folder:  strings.Repeat("a", maxFolderSize+1),
+			wantErr: ErrInvalidFolder,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := normalizeFolder(test.folder)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, path)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, 0, maxTagsCount+1)
+	for range maxTagsCount + 1 {
+		tooMany = append(tooMany, generateString(10))
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "sorted without duplicates",
+			tags: []string{" team", "prod", "team "},
+			want: []string{"prod", "team"},
+		},
+		{
+			name: "no tags",
+			tags: nil,
+			want: []string{},
+		},
+		{
+			name:    "empty tag",
+			tags:    []string{"prod", " "},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:    "too long tag",
+			tags:    []string{strings.Repeat("a", maxTagSize+1)},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:    "too many tags",
+			tags:    tooMany,
+			wantErr: ErrTooManyTags,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tags, err := normalizeTags(test.tags)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, tags)
+		})
+	}
+}
+
+func TestUserDataLabels(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	encData := []byte("some data")
+	folder := "work/aws"
+	tags := []string{"team", "prod"}
+
+	t.Run("add secret with folder and tags", func(t *testing.T) {
+		req := models.AddSecretRequest{Data: []byte("data"), Mark: "aws", Folder: "/work/aws/", Tags: tags}
+
+		crypter.EXPECT().EncryptData(gomock.Any(), req.Data).Times(1).Return(encData, nil)
+		labels := models.UserDataLabels{Folder: &folder, Tags: &[]string{"prod", "team"}}
+		store.EXPECT().AddUserData(ctx, encData, "aws", "", "card", labels).Times(1).Return(userDataID, nil)
+		store.EXPECT().SetUserDataLabels(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		id, err := s.AddCard(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, userDataID, id)
+	})
+
+	t.Run("add secret with invalid tags", func(t *testing.T) {
+		req := models.AddSecretRequest{Data: []byte("data"), Mark: "aws", Tags: []string{""}}
+
+		store.EXPECT().AddUserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		_, err := s.AddCard(ctx, req)
+
+		require.ErrorIs(t, err, ErrInvalidTag)
+	})
+
+	t.Run("patch only secret folder", func(t *testing.T) {
+		store.EXPECT().GetUserData(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().SetUserDataLabels(ctx, userDataID, "password", models.UserDataLabels{Folder: &folder}).
+			Times(1).Return(nil)
+
+		err := s.PatchPassword(ctx, userDataID, models.UpdateSecretRequest{Folder: &folder})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("patch secret mark and tags", func(t *testing.T) {
+		mark := "new mark"
+		noTags := []string{}
+
+		store.EXPECT().GetUserData(ctx, userDataID, "text").Times(1).Return(encData, "mark", "description", nil)
+		store.EXPECT().
+			UpdateUserData(ctx, userDataID, 0, encData, mark, "description", "text", models.UserDataLabels{Tags: &noTags}).
+			Times(1).Return(nil)
+		store.EXPECT().SetUserDataLabels(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := s.PatchText(ctx, userDataID, models.UpdateSecretRequest{Mark: &mark, Tags: &noTags})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("patch only file tags", func(t *testing.T) {
+		store.EXPECT().GetUserData(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().SetUserDataLabels(ctx, userDataID, "file", models.UserDataLabels{Tags: &[]string{"prod", "team"}}).
+			Times(1).Return(nil)
+
+		err := s.PatchFile(ctx, userDataID, models.UpdateFileRequest{Tags: &tags})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("when data not found", func(t *testing.T) {
+		store.EXPECT().SetUserDataLabels(ctx, userDataID, "otp", models.UserDataLabels{Folder: &folder}).
+			Times(1).Return(storage.ErrUserDataNotFound)
+
+		err := s.PatchOTP(ctx, userDataID, models.UpdateSecretRequest{Folder: &folder})
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("failed set tags", func(t *testing.T) {
+		store.EXPECT().SetUserDataLabels(ctx, userDataID, "otp", models.UserDataLabels{Tags: &[]string{"prod", "team"}}).
+			Times(1).Return(errors.New("some error"))
+
+		err := s.PatchOTP(ctx, userDataID, models.UpdateSecretRequest{Tags: &tags})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to update user data")
+	})
+}
+
+// clearedLabels возвращает папку и метки данных после их полной замены запросом без папки и меток.
+func clearedLabels() models.UserDataLabels {
+	return models.UserDataLabels{Folder: new(string), Tags: &[]string{}}
+}
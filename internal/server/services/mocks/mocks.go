@@ -8,6 +8,7 @@ import (
 	context "context"
 	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/MihailSergeenkov/GophKeeper/internal/models"
 	gomock "github.com/golang/mock/gomock"
@@ -36,6 +37,78 @@ func (m *MockStorager) EXPECT() *MockStoragerMockRecorder {
 	return m.recorder
 }
 
+// AddFileUpload mocks base method.
+func (m *MockStorager) AddFileUpload(ctx context.Context, upload models.FileUpload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFileUpload", ctx, upload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFileUpload indicates an expected call of AddFileUpload.
+func (mr *MockStoragerMockRecorder) AddFileUpload(ctx, upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFileUpload", reflect.TypeOf((*MockStorager)(nil).AddFileUpload), ctx, upload)
+}
+
+// AddFileUserData mocks base method.
+func (m *MockStorager) AddFileUserData(ctx context.Context, encData []byte, mark, description string, fileSize int64, labels models.UserDataLabels) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFileUserData", ctx, encData, mark, description, fileSize, labels)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddFileUserData indicates an expected call of AddFileUserData.
+func (mr *MockStoragerMockRecorder) AddFileUserData(ctx, encData, mark, description, fileSize, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFileUserData", reflect.TypeOf((*MockStorager)(nil).AddFileUserData), ctx, encData, mark, description, fileSize, labels)
+}
+
+// AddPendingFileObject mocks base method.
+func (m *MockStorager) AddPendingFileObject(ctx context.Context, objectName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPendingFileObject", ctx, objectName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPendingFileObject indicates an expected call of AddPendingFileObject.
+func (mr *MockStoragerMockRecorder) AddPendingFileObject(ctx, objectName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPendingFileObject", reflect.TypeOf((*MockStorager)(nil).AddPendingFileObject), ctx, objectName)
+}
+
+// AddReservedUserData mocks base method.
+func (m *MockStorager) AddReservedUserData(ctx context.Context, id int, encData []byte, mark, description, dataType string, labels models.UserDataLabels) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReservedUserData", ctx, id, encData, mark, description, dataType, labels)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReservedUserData indicates an expected call of AddReservedUserData.
+func (mr *MockStoragerMockRecorder) AddReservedUserData(ctx, id, encData, mark, description, dataType, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReservedUserData", reflect.TypeOf((*MockStorager)(nil).AddReservedUserData), ctx, id, encData, mark, description, dataType, labels)
+}
+
+// AddSession mocks base method.
+func (m *MockStorager) AddSession(ctx context.Context, session models.Session, refreshTokenHash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSession", ctx, session, refreshTokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSession indicates an expected call of AddSession.
+func (mr *MockStoragerMockRecorder) AddSession(ctx, session, refreshTokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSession", reflect.TypeOf((*MockStorager)(nil).AddSession), ctx, session, refreshTokenHash)
+}
+
 // AddUser mocks base method.
 func (m *MockStorager) AddUser(ctx context.Context, userLogin string, userPassword []byte) error {
 	m.ctrl.T.Helper()
@@ -51,18 +124,224 @@ func (mr *MockStoragerMockRecorder) AddUser(ctx, userLogin, userPassword interfa
 }
 
 // AddUserData mocks base method.
-func (m *MockStorager) AddUserData(ctx context.Context, encData []byte, mark, description, dataType string) (int, error) {
+func (m *MockStorager) AddUserData(ctx context.Context, encData []byte, mark, description, dataType string, labels models.UserDataLabels) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUserData", ctx, encData, mark, description, dataType)
+	ret := m.ctrl.Call(m, "AddUserData", ctx, encData, mark, description, dataType, labels)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddUserData indicates an expected call of AddUserData.
-func (mr *MockStoragerMockRecorder) AddUserData(ctx, encData, mark, description, dataType interface{}) *gomock.Call {
+func (mr *MockStoragerMockRecorder) AddUserData(ctx, encData, mark, description, dataType, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserData", reflect.TypeOf((*MockStorager)(nil).AddUserData), ctx, encData, mark, description, dataType, labels)
+}
+
+// AddUserKey mocks base method.
+func (m *MockStorager) AddUserKey(ctx context.Context, dataKey []byte, keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserKey", ctx, dataKey, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserKey indicates an expected call of AddUserKey.
+func (mr *MockStoragerMockRecorder) AddUserKey(ctx, dataKey, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserKey", reflect.TypeOf((*MockStorager)(nil).AddUserKey), ctx, dataKey, keyID)
+}
+
+// CompleteFileUpload mocks base method.
+func (m *MockStorager) CompleteFileUpload(ctx context.Context, id string, encData []byte, mark, description string, fileSize int64, labels models.UserDataLabels) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteFileUpload", ctx, id, encData, mark, description, fileSize, labels)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteFileUpload indicates an expected call of CompleteFileUpload.
+func (mr *MockStoragerMockRecorder) CompleteFileUpload(ctx, id, encData, mark, description, fileSize, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteFileUpload", reflect.TypeOf((*MockStorager)(nil).CompleteFileUpload), ctx, id, encData, mark, description, fileSize, labels)
+}
+
+// CountUserKeysToRotate mocks base method.
+func (m *MockStorager) CountUserKeysToRotate(ctx context.Context, keyID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserKeysToRotate", ctx, keyID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserKeysToRotate indicates an expected call of CountUserKeysToRotate.
+func (mr *MockStoragerMockRecorder) CountUserKeysToRotate(ctx, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserKeysToRotate", reflect.TypeOf((*MockStorager)(nil).CountUserKeysToRotate), ctx, keyID)
+}
+
+// DeleteFileUpload mocks base method.
+func (m *MockStorager) DeleteFileUpload(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileUpload", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileUpload indicates an expected call of DeleteFileUpload.
+func (mr *MockStoragerMockRecorder) DeleteFileUpload(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileUpload", reflect.TypeOf((*MockStorager)(nil).DeleteFileUpload), ctx, id)
+}
+
+// DeletePendingFileObject mocks base method.
+func (m *MockStorager) DeletePendingFileObject(ctx context.Context, objectName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingFileObject", ctx, objectName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingFileObject indicates an expected call of DeletePendingFileObject.
+func (mr *MockStoragerMockRecorder) DeletePendingFileObject(ctx, objectName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingFileObject", reflect.TypeOf((*MockStorager)(nil).DeletePendingFileObject), ctx, objectName)
+}
+
+// DeleteUserData mocks base method.
+func (m *MockStorager) DeleteUserData(ctx context.Context, id int, dataType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserData", ctx, id, dataType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserData indicates an expected call of DeleteUserData.
+func (mr *MockStoragerMockRecorder) DeleteUserData(ctx, id, dataType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserData", reflect.TypeOf((*MockStorager)(nil).DeleteUserData), ctx, id, dataType)
+}
+
+// EnableUserTwoFactor mocks base method.
+func (m *MockStorager) EnableUserTwoFactor(ctx context.Context, step int64, recoveryCodeHashes [][]byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTwoFactor", ctx, step, recoveryCodeHashes)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserTwoFactor indicates an expected call of EnableUserTwoFactor.
+func (mr *MockStoragerMockRecorder) EnableUserTwoFactor(ctx, step, recoveryCodeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTwoFactor", reflect.TypeOf((*MockStorager)(nil).EnableUserTwoFactor), ctx, step, recoveryCodeHashes)
+}
+
+// FetchEncryptedUserData mocks base method.
+func (m *MockStorager) FetchEncryptedUserData(ctx context.Context, afterID, limit int) ([]models.EncryptedUserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchEncryptedUserData", ctx, afterID, limit)
+	ret0, _ := ret[0].([]models.EncryptedUserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchEncryptedUserData indicates an expected call of FetchEncryptedUserData.
+func (mr *MockStoragerMockRecorder) FetchEncryptedUserData(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchEncryptedUserData", reflect.TypeOf((*MockStorager)(nil).FetchEncryptedUserData), ctx, afterID, limit)
+}
+
+// FetchFileUploadObjects mocks base method.
+func (m *MockStorager) FetchFileUploadObjects(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchFileUploadObjects", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchFileUploadObjects indicates an expected call of FetchFileUploadObjects.
+func (mr *MockStoragerMockRecorder) FetchFileUploadObjects(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchFileUploadObjects", reflect.TypeOf((*MockStorager)(nil).FetchFileUploadObjects), ctx)
+}
+
+// FetchFileUserData mocks base method.
+func (m *MockStorager) FetchFileUserData(ctx context.Context) ([]models.FileUserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchFileUserData", ctx)
+	ret0, _ := ret[0].([]models.FileUserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchFileUserData indicates an expected call of FetchFileUserData.
+func (mr *MockStoragerMockRecorder) FetchFileUserData(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserData", reflect.TypeOf((*MockStorager)(nil).AddUserData), ctx, encData, mark, description, dataType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchFileUserData", reflect.TypeOf((*MockStorager)(nil).FetchFileUserData), ctx)
+}
+
+// FetchFileUserDataHistory mocks base method.
+func (m *MockStorager) FetchFileUserDataHistory(ctx context.Context) ([]models.FileUserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchFileUserDataHistory", ctx)
+	ret0, _ := ret[0].([]models.FileUserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchFileUserDataHistory indicates an expected call of FetchFileUserDataHistory.
+func (mr *MockStoragerMockRecorder) FetchFileUserDataHistory(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchFileUserDataHistory", reflect.TypeOf((*MockStorager)(nil).FetchFileUserDataHistory), ctx)
+}
+
+// FetchPendingFileObjects mocks base method.
+func (m *MockStorager) FetchPendingFileObjects(ctx context.Context) ([]models.PendingFileObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPendingFileObjects", ctx)
+	ret0, _ := ret[0].([]models.PendingFileObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPendingFileObjects indicates an expected call of FetchPendingFileObjects.
+func (mr *MockStoragerMockRecorder) FetchPendingFileObjects(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPendingFileObjects", reflect.TypeOf((*MockStorager)(nil).FetchPendingFileObjects), ctx)
+}
+
+// FetchTrash mocks base method.
+func (m *MockStorager) FetchTrash(ctx context.Context) ([]models.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTrash", ctx)
+	ret0, _ := ret[0].([]models.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTrash indicates an expected call of FetchTrash.
+func (mr *MockStoragerMockRecorder) FetchTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTrash", reflect.TypeOf((*MockStorager)(nil).FetchTrash), ctx)
+}
+
+// FetchTrashedUserData mocks base method.
+func (m *MockStorager) FetchTrashedUserData(ctx context.Context, before time.Time) ([]models.TrashedUserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchTrashedUserData", ctx, before)
+	ret0, _ := ret[0].([]models.TrashedUserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchTrashedUserData indicates an expected call of FetchTrashedUserData.
+func (mr *MockStoragerMockRecorder) FetchTrashedUserData(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTrashedUserData", reflect.TypeOf((*MockStorager)(nil).FetchTrashedUserData), ctx, before)
 }
 
 // FetchUserData mocks base method.
@@ -80,19 +359,79 @@ func (mr *MockStoragerMockRecorder) FetchUserData(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserData", reflect.TypeOf((*MockStorager)(nil).FetchUserData), ctx)
 }
 
-// GetFileUserData mocks base method.
-func (m *MockStorager) GetFileUserData(ctx context.Context, fileMark string) ([]byte, error) {
+// FetchUserDataChanges mocks base method.
+func (m *MockStorager) FetchUserDataChanges(ctx context.Context, since int64) (models.UserDataChanges, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFileUserData", ctx, fileMark)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "FetchUserDataChanges", ctx, since)
+	ret0, _ := ret[0].(models.UserDataChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchUserDataChanges indicates an expected call of FetchUserDataChanges.
+func (mr *MockStoragerMockRecorder) FetchUserDataChanges(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserDataChanges", reflect.TypeOf((*MockStorager)(nil).FetchUserDataChanges), ctx, since)
+}
+
+// FetchUserDataHistory mocks base method.
+func (m *MockStorager) FetchUserDataHistory(ctx context.Context, id int, dataType string) ([]models.UserDataRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchUserDataHistory", ctx, id, dataType)
+	ret0, _ := ret[0].([]models.UserDataRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchUserDataHistory indicates an expected call of FetchUserDataHistory.
+func (mr *MockStoragerMockRecorder) FetchUserDataHistory(ctx, id, dataType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserDataHistory", reflect.TypeOf((*MockStorager)(nil).FetchUserDataHistory), ctx, id, dataType)
+}
+
+// FetchUserIDs mocks base method.
+func (m *MockStorager) FetchUserIDs(ctx context.Context, afterID, limit int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchUserIDs", ctx, afterID, limit)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchUserIDs indicates an expected call of FetchUserIDs.
+func (mr *MockStoragerMockRecorder) FetchUserIDs(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchUserIDs", reflect.TypeOf((*MockStorager)(nil).FetchUserIDs), ctx, afterID, limit)
+}
+
+// GetFileUpload mocks base method.
+func (m *MockStorager) GetFileUpload(ctx context.Context, id string) (models.FileUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileUpload", ctx, id)
+	ret0, _ := ret[0].(models.FileUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileUpload indicates an expected call of GetFileUpload.
+func (mr *MockStoragerMockRecorder) GetFileUpload(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUpload", reflect.TypeOf((*MockStorager)(nil).GetFileUpload), ctx, id)
+}
+
+// GetUserByID mocks base method.
+func (m *MockStorager) GetUserByID(ctx context.Context, userID int) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFileUserData indicates an expected call of GetFileUserData.
-func (mr *MockStoragerMockRecorder) GetFileUserData(ctx, fileMark interface{}) *gomock.Call {
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockStoragerMockRecorder) GetUserByID(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUserData", reflect.TypeOf((*MockStorager)(nil).GetFileUserData), ctx, fileMark)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStorager)(nil).GetUserByID), ctx, userID)
 }
 
 // GetUserByLogin mocks base method.
@@ -127,6 +466,99 @@ func (mr *MockStoragerMockRecorder) GetUserData(ctx, id, dataType interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserData", reflect.TypeOf((*MockStorager)(nil).GetUserData), ctx, id, dataType)
 }
 
+// GetUserDataRevision mocks base method.
+func (m *MockStorager) GetUserDataRevision(ctx context.Context, id, version int, dataType string) ([]byte, string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDataRevision", ctx, id, version, dataType)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(string)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetUserDataRevision indicates an expected call of GetUserDataRevision.
+func (mr *MockStoragerMockRecorder) GetUserDataRevision(ctx, id, version, dataType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDataRevision", reflect.TypeOf((*MockStorager)(nil).GetUserDataRevision), ctx, id, version, dataType)
+}
+
+// GetUserKey mocks base method.
+func (m *MockStorager) GetUserKey(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserKey", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserKey indicates an expected call of GetUserKey.
+func (mr *MockStoragerMockRecorder) GetUserKey(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKey", reflect.TypeOf((*MockStorager)(nil).GetUserKey), ctx)
+}
+
+// GetUserTwoFactor mocks base method.
+func (m *MockStorager) GetUserTwoFactor(ctx context.Context) (models.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTwoFactor", ctx)
+	ret0, _ := ret[0].(models.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTwoFactor indicates an expected call of GetUserTwoFactor.
+func (mr *MockStoragerMockRecorder) GetUserTwoFactor(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTwoFactor", reflect.TypeOf((*MockStorager)(nil).GetUserTwoFactor), ctx)
+}
+
+// GetUserUsage mocks base method.
+func (m *MockStorager) GetUserUsage(ctx context.Context) (models.UserUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserUsage", ctx)
+	ret0, _ := ret[0].(models.UserUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserUsage indicates an expected call of GetUserUsage.
+func (mr *MockStoragerMockRecorder) GetUserUsage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserUsage", reflect.TypeOf((*MockStorager)(nil).GetUserUsage), ctx)
+}
+
+// GetUserVault mocks base method.
+func (m *MockStorager) GetUserVault(ctx context.Context) ([]byte, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserVault", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserVault indicates an expected call of GetUserVault.
+func (mr *MockStoragerMockRecorder) GetUserVault(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVault", reflect.TypeOf((*MockStorager)(nil).GetUserVault), ctx)
+}
+
+// ListSessions mocks base method.
+func (m *MockStorager) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockStoragerMockRecorder) ListSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockStorager)(nil).ListSessions), ctx, userID)
+}
+
 // Ping mocks base method.
 func (m *MockStorager) Ping(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -141,6 +573,297 @@ func (mr *MockStoragerMockRecorder) Ping(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStorager)(nil).Ping), ctx)
 }
 
+// PruneUserDataHistory mocks base method.
+func (m *MockStorager) PruneUserDataHistory(ctx context.Context, id, keep int) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneUserDataHistory", ctx, id, keep)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneUserDataHistory indicates an expected call of PruneUserDataHistory.
+func (mr *MockStoragerMockRecorder) PruneUserDataHistory(ctx, id, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneUserDataHistory", reflect.TypeOf((*MockStorager)(nil).PruneUserDataHistory), ctx, id, keep)
+}
+
+// PruneUserDataTombstones mocks base method.
+func (m *MockStorager) PruneUserDataTombstones(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneUserDataTombstones", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneUserDataTombstones indicates an expected call of PruneUserDataTombstones.
+func (mr *MockStoragerMockRecorder) PruneUserDataTombstones(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneUserDataTombstones", reflect.TypeOf((*MockStorager)(nil).PruneUserDataTombstones), ctx, before)
+}
+
+// PurgeUserData mocks base method.
+func (m *MockStorager) PurgeUserData(ctx context.Context, id int, before time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUserData", ctx, id, before)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUserData indicates an expected call of PurgeUserData.
+func (mr *MockStoragerMockRecorder) PurgeUserData(ctx, id, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUserData", reflect.TypeOf((*MockStorager)(nil).PurgeUserData), ctx, id, before)
+}
+
+// ReplaceEncryptedUserData mocks base method.
+func (m *MockStorager) ReplaceEncryptedUserData(ctx context.Context, id int, oldData, newData []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceEncryptedUserData", ctx, id, oldData, newData)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceEncryptedUserData indicates an expected call of ReplaceEncryptedUserData.
+func (mr *MockStoragerMockRecorder) ReplaceEncryptedUserData(ctx, id, oldData, newData interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceEncryptedUserData", reflect.TypeOf((*MockStorager)(nil).ReplaceEncryptedUserData), ctx, id, oldData, newData)
+}
+
+// ReserveUserDataID mocks base method.
+func (m *MockStorager) ReserveUserDataID(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveUserDataID", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveUserDataID indicates an expected call of ReserveUserDataID.
+func (mr *MockStoragerMockRecorder) ReserveUserDataID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveUserDataID", reflect.TypeOf((*MockStorager)(nil).ReserveUserDataID), ctx)
+}
+
+// RestoreFileUserDataRevision mocks base method.
+func (m *MockStorager) RestoreFileUserDataRevision(ctx context.Context, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFileUserDataRevision", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreFileUserDataRevision indicates an expected call of RestoreFileUserDataRevision.
+func (mr *MockStoragerMockRecorder) RestoreFileUserDataRevision(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFileUserDataRevision", reflect.TypeOf((*MockStorager)(nil).RestoreFileUserDataRevision), ctx, id, version)
+}
+
+// RestoreUserData mocks base method.
+func (m *MockStorager) RestoreUserData(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUserData", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUserData indicates an expected call of RestoreUserData.
+func (mr *MockStoragerMockRecorder) RestoreUserData(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUserData", reflect.TypeOf((*MockStorager)(nil).RestoreUserData), ctx, id)
+}
+
+// RevokeSession mocks base method.
+func (m *MockStorager) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockStoragerMockRecorder) RevokeSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockStorager)(nil).RevokeSession), ctx, userID, sessionID)
+}
+
+// RotateSessionRefreshToken mocks base method.
+func (m *MockStorager) RotateSessionRefreshToken(ctx context.Context, oldHash, newHash []byte, expiresAt time.Time) (models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSessionRefreshToken", ctx, oldHash, newHash, expiresAt)
+	ret0, _ := ret[0].(models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSessionRefreshToken indicates an expected call of RotateSessionRefreshToken.
+func (mr *MockStoragerMockRecorder) RotateSessionRefreshToken(ctx, oldHash, newHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSessionRefreshToken", reflect.TypeOf((*MockStorager)(nil).RotateSessionRefreshToken), ctx, oldHash, newHash, expiresAt)
+}
+
+// RotateUserKeys mocks base method.
+func (m *MockStorager) RotateUserKeys(ctx context.Context, keyID string, limit int, rewrap func([]byte) ([]byte, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateUserKeys", ctx, keyID, limit, rewrap)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateUserKeys indicates an expected call of RotateUserKeys.
+func (mr *MockStoragerMockRecorder) RotateUserKeys(ctx, keyID, limit, rewrap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateUserKeys", reflect.TypeOf((*MockStorager)(nil).RotateUserKeys), ctx, keyID, limit, rewrap)
+}
+
+// SearchUserData mocks base method.
+func (m *MockStorager) SearchUserData(ctx context.Context, filter models.UserDataFilter) (models.UserDataPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUserData", ctx, filter)
+	ret0, _ := ret[0].(models.UserDataPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUserData indicates an expected call of SearchUserData.
+func (mr *MockStoragerMockRecorder) SearchUserData(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUserData", reflect.TypeOf((*MockStorager)(nil).SearchUserData), ctx, filter)
+}
+
+// SetFileUserDataMissing mocks base method.
+func (m *MockStorager) SetFileUserDataMissing(ctx context.Context, id int, missing bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFileUserDataMissing", ctx, id, missing)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFileUserDataMissing indicates an expected call of SetFileUserDataMissing.
+func (mr *MockStoragerMockRecorder) SetFileUserDataMissing(ctx, id, missing interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFileUserDataMissing", reflect.TypeOf((*MockStorager)(nil).SetFileUserDataMissing), ctx, id, missing)
+}
+
+// SetUserDataLabels mocks base method.
+func (m *MockStorager) SetUserDataLabels(ctx context.Context, id int, dataType string, labels models.UserDataLabels) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserDataLabels", ctx, id, dataType, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserDataLabels indicates an expected call of SetUserDataLabels.
+func (mr *MockStoragerMockRecorder) SetUserDataLabels(ctx, id, dataType, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDataLabels", reflect.TypeOf((*MockStorager)(nil).SetUserDataLabels), ctx, id, dataType, labels)
+}
+
+// SetUserTwoFactorSecret mocks base method.
+func (m *MockStorager) SetUserTwoFactorSecret(ctx context.Context, secret []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTwoFactorSecret", ctx, secret)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserTwoFactorSecret indicates an expected call of SetUserTwoFactorSecret.
+func (mr *MockStoragerMockRecorder) SetUserTwoFactorSecret(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTwoFactorSecret", reflect.TypeOf((*MockStorager)(nil).SetUserTwoFactorSecret), ctx, secret)
+}
+
+// SetUserVaultCheck mocks base method.
+func (m *MockStorager) SetUserVaultCheck(ctx context.Context, check []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserVaultCheck", ctx, check)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserVaultCheck indicates an expected call of SetUserVaultCheck.
+func (mr *MockStoragerMockRecorder) SetUserVaultCheck(ctx, check interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserVaultCheck", reflect.TypeOf((*MockStorager)(nil).SetUserVaultCheck), ctx, check)
+}
+
+// SetUserVaultSalt mocks base method.
+func (m *MockStorager) SetUserVaultSalt(ctx context.Context, salt []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserVaultSalt", ctx, salt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserVaultSalt indicates an expected call of SetUserVaultSalt.
+func (mr *MockStoragerMockRecorder) SetUserVaultSalt(ctx, salt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserVaultSalt", reflect.TypeOf((*MockStorager)(nil).SetUserVaultSalt), ctx, salt)
+}
+
+// UpdateFileUserData mocks base method.
+func (m *MockStorager) UpdateFileUserData(ctx context.Context, id int, encData []byte, mark, description string, fileSize int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileUserData", ctx, id, encData, mark, description, fileSize)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileUserData indicates an expected call of UpdateFileUserData.
+func (mr *MockStoragerMockRecorder) UpdateFileUserData(ctx, id, encData, mark, description, fileSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileUserData", reflect.TypeOf((*MockStorager)(nil).UpdateFileUserData), ctx, id, encData, mark, description, fileSize)
+}
+
+// UpdateUserData mocks base method.
+func (m *MockStorager) UpdateUserData(ctx context.Context, id, version int, encData []byte, mark, description, dataType string, labels models.UserDataLabels) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserData", ctx, id, version, encData, mark, description, dataType, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserData indicates an expected call of UpdateUserData.
+func (mr *MockStoragerMockRecorder) UpdateUserData(ctx, id, version, encData, mark, description, dataType, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserData", reflect.TypeOf((*MockStorager)(nil).UpdateUserData), ctx, id, version, encData, mark, description, dataType, labels)
+}
+
+// UpdateUserTwoFactorStep mocks base method.
+func (m *MockStorager) UpdateUserTwoFactorStep(ctx context.Context, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTwoFactorStep", ctx, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTwoFactorStep indicates an expected call of UpdateUserTwoFactorStep.
+func (mr *MockStoragerMockRecorder) UpdateUserTwoFactorStep(ctx, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTwoFactorStep", reflect.TypeOf((*MockStorager)(nil).UpdateUserTwoFactorStep), ctx, step)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStorager) UseRecoveryCode(ctx context.Context, codeHash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoragerMockRecorder) UseRecoveryCode(ctx, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStorager)(nil).UseRecoveryCode), ctx, codeHash)
+}
+
 // MockCrypter is a mock of Crypter interface.
 type MockCrypter struct {
 	ctrl     *gomock.Controller
@@ -165,32 +888,107 @@ func (m *MockCrypter) EXPECT() *MockCrypterMockRecorder {
 }
 
 // DecryptData mocks base method.
-func (m *MockCrypter) DecryptData(data []byte) ([]byte, error) {
+func (m *MockCrypter) DecryptData(dataKey, data []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DecryptData", data)
+	ret := m.ctrl.Call(m, "DecryptData", dataKey, data)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DecryptData indicates an expected call of DecryptData.
-func (mr *MockCrypterMockRecorder) DecryptData(data interface{}) *gomock.Call {
+func (mr *MockCrypterMockRecorder) DecryptData(dataKey, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptData", reflect.TypeOf((*MockCrypter)(nil).DecryptData), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptData", reflect.TypeOf((*MockCrypter)(nil).DecryptData), dataKey, data)
 }
 
 // EncryptData mocks base method.
-func (m *MockCrypter) EncryptData(data []byte) []byte {
+func (m *MockCrypter) EncryptData(dataKey, data []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EncryptData", data)
+	ret := m.ctrl.Call(m, "EncryptData", dataKey, data)
 	ret0, _ := ret[0].([]byte)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // EncryptData indicates an expected call of EncryptData.
-func (mr *MockCrypterMockRecorder) EncryptData(data interface{}) *gomock.Call {
+func (mr *MockCrypterMockRecorder) EncryptData(dataKey, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptData", reflect.TypeOf((*MockCrypter)(nil).EncryptData), dataKey, data)
+}
+
+// GenerateDataKey mocks base method.
+func (m *MockCrypter) GenerateDataKey() ([]byte, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateDataKey")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateDataKey indicates an expected call of GenerateDataKey.
+func (mr *MockCrypterMockRecorder) GenerateDataKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDataKey", reflect.TypeOf((*MockCrypter)(nil).GenerateDataKey))
+}
+
+// IsLegacyData mocks base method.
+func (m *MockCrypter) IsLegacyData(dataKey, data []byte) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLegacyData", dataKey, data)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLegacyData indicates an expected call of IsLegacyData.
+func (mr *MockCrypterMockRecorder) IsLegacyData(dataKey, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLegacyData", reflect.TypeOf((*MockCrypter)(nil).IsLegacyData), dataKey, data)
+}
+
+// MasterKeyID mocks base method.
+func (m *MockCrypter) MasterKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MasterKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// MasterKeyID indicates an expected call of MasterKeyID.
+func (mr *MockCrypterMockRecorder) MasterKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MasterKeyID", reflect.TypeOf((*MockCrypter)(nil).MasterKeyID))
+}
+
+// UnwrapDataKey mocks base method.
+func (m *MockCrypter) UnwrapDataKey(wrappedKey []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnwrapDataKey", wrappedKey)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnwrapDataKey indicates an expected call of UnwrapDataKey.
+func (mr *MockCrypterMockRecorder) UnwrapDataKey(wrappedKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnwrapDataKey", reflect.TypeOf((*MockCrypter)(nil).UnwrapDataKey), wrappedKey)
+}
+
+// WrapDataKey mocks base method.
+func (m *MockCrypter) WrapDataKey(dataKey []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WrapDataKey", dataKey)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WrapDataKey indicates an expected call of WrapDataKey.
+func (mr *MockCrypterMockRecorder) WrapDataKey(dataKey interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptData", reflect.TypeOf((*MockCrypter)(nil).EncryptData), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WrapDataKey", reflect.TypeOf((*MockCrypter)(nil).WrapDataKey), dataKey)
 }
 
 // MockFileStorager is a mock of FileStorager interface.
@@ -216,6 +1014,20 @@ func (m *MockFileStorager) EXPECT() *MockFileStoragerMockRecorder {
 	return m.recorder
 }
 
+// AbortUpload mocks base method.
+func (m *MockFileStorager) AbortUpload(ctx context.Context, objectName, uploadID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortUpload", ctx, objectName, uploadID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortUpload indicates an expected call of AbortUpload.
+func (mr *MockFileStoragerMockRecorder) AbortUpload(ctx, objectName, uploadID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortUpload", reflect.TypeOf((*MockFileStorager)(nil).AbortUpload), ctx, objectName, uploadID)
+}
+
 // AddFile mocks base method.
 func (m *MockFileStorager) AddFile(ctx context.Context, file io.Reader, objectName string, objectSize int64) error {
 	m.ctrl.T.Helper()
@@ -230,17 +1042,120 @@ func (mr *MockFileStoragerMockRecorder) AddFile(ctx, file, objectName, objectSiz
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFile", reflect.TypeOf((*MockFileStorager)(nil).AddFile), ctx, file, objectName, objectSize)
 }
 
+// CompleteUpload mocks base method.
+func (m *MockFileStorager) CompleteUpload(ctx context.Context, objectName, uploadID string, parts []models.FileUploadPart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteUpload", ctx, objectName, uploadID, parts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteUpload indicates an expected call of CompleteUpload.
+func (mr *MockFileStoragerMockRecorder) CompleteUpload(ctx, objectName, uploadID, parts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteUpload", reflect.TypeOf((*MockFileStorager)(nil).CompleteUpload), ctx, objectName, uploadID, parts)
+}
+
+// DeleteFile mocks base method.
+func (m *MockFileStorager) DeleteFile(ctx context.Context, objectName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, objectName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockFileStoragerMockRecorder) DeleteFile(ctx, objectName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockFileStorager)(nil).DeleteFile), ctx, objectName)
+}
+
 // GetFile mocks base method.
-func (m *MockFileStorager) GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+func (m *MockFileStorager) GetFile(ctx context.Context, objectName string, rng *models.FileRange) (models.File, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFile", ctx, objectName)
-	ret0, _ := ret[0].(io.ReadCloser)
+	ret := m.ctrl.Call(m, "GetFile", ctx, objectName, rng)
+	ret0, _ := ret[0].(models.File)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFile indicates an expected call of GetFile.
-func (mr *MockFileStoragerMockRecorder) GetFile(ctx, objectName interface{}) *gomock.Call {
+func (mr *MockFileStoragerMockRecorder) GetFile(ctx, objectName, rng interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockFileStorager)(nil).GetFile), ctx, objectName, rng)
+}
+
+// InitUpload mocks base method.
+func (m *MockFileStorager) InitUpload(ctx context.Context, objectName string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitUpload", ctx, objectName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitUpload indicates an expected call of InitUpload.
+func (mr *MockFileStoragerMockRecorder) InitUpload(ctx, objectName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitUpload", reflect.TypeOf((*MockFileStorager)(nil).InitUpload), ctx, objectName)
+}
+
+// ListFiles mocks base method.
+func (m *MockFileStorager) ListFiles(ctx context.Context) ([]models.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", ctx)
+	ret0, _ := ret[0].([]models.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockFileStoragerMockRecorder) ListFiles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockFileStorager)(nil).ListFiles), ctx)
+}
+
+// ListUploadParts mocks base method.
+func (m *MockFileStorager) ListUploadParts(ctx context.Context, objectName, uploadID string) ([]models.FileUploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUploadParts", ctx, objectName, uploadID)
+	ret0, _ := ret[0].([]models.FileUploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUploadParts indicates an expected call of ListUploadParts.
+func (mr *MockFileStoragerMockRecorder) ListUploadParts(ctx, objectName, uploadID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUploadParts", reflect.TypeOf((*MockFileStorager)(nil).ListUploadParts), ctx, objectName, uploadID)
+}
+
+// StatFile mocks base method.
+func (m *MockFileStorager) StatFile(ctx context.Context, objectName string) (models.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatFile", ctx, objectName)
+	ret0, _ := ret[0].(models.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatFile indicates an expected call of StatFile.
+func (mr *MockFileStoragerMockRecorder) StatFile(ctx, objectName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatFile", reflect.TypeOf((*MockFileStorager)(nil).StatFile), ctx, objectName)
+}
+
+// UploadPart mocks base method.
+func (m *MockFileStorager) UploadPart(ctx context.Context, objectName, uploadID string, number int, part io.Reader, size int64) (models.FileUploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPart", ctx, objectName, uploadID, number, part, size)
+	ret0, _ := ret[0].(models.FileUploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadPart indicates an expected call of UploadPart.
+func (mr *MockFileStoragerMockRecorder) UploadPart(ctx, objectName, uploadID, number, part, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockFileStorager)(nil).GetFile), ctx, objectName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPart", reflect.TypeOf((*MockFileStorager)(nil).UploadPart), ctx, objectName, uploadID, number, part, size)
 }
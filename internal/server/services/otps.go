@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+const otpDataType = "otp"
+
+// AddOTP функция для добавления секрета TOTP пользователя.
+func (s *Services) AddOTP(ctx context.Context, req models.AddSecretRequest) (int, error) {
+	return s.addSecret(ctx, req, otpDataType)
+}
+
+// GetOTP функция для получения секрета TOTP пользователя.
+func (s *Services) GetOTP(ctx context.Context, id int) (models.Secret, error) {
+	return s.getSecret(ctx, id, otpDataType)
+}
+
+// UpdateOTP функция для полной замены секрета TOTP пользователя.
+func (s *Services) UpdateOTP(ctx context.Context, id int, req models.AddSecretRequest) error {
+	return s.updateSecret(ctx, id, req, otpDataType)
+}
+
+// PatchOTP функция для частичного обновления секрета TOTP пользователя.
+func (s *Services) PatchOTP(ctx context.Context, id int, req models.UpdateSecretRequest) error {
+	return s.patchSecret(ctx, id, req, otpDataType)
+}
+
+// DeleteOTP функция для удаления секрета TOTP пользователя.
+func (s *Services) DeleteOTP(ctx context.Context, id int) error {
+	return s.deleteUserData(ctx, id, otpDataType)
+}
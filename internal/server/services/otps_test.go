@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTPSecret(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "otp"
+	clientData := []byte("client encrypted data")
+	encData := []byte("some data")
+	newMark := "new mark"
+
+	req := models.AddSecretRequest{
+		Data:        clientData,
+		Mark:        "test",
+		Description: "test",
+	}
+
+	t.Run("add otp", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), clientData).Times(1).Return(encData, nil)
+		store.EXPECT().AddUserData(ctx, encData, req.Mark, req.Description, dataType, models.UserDataLabels{}).
+			Times(1).Return(userDataID, nil)
+
+		id, err := s.AddOTP(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, userDataID, id)
+	})
+
+	t.Run("get otp", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), encData).Times(1).Return(clientData, nil)
+
+		resp, err := s.GetOTP(ctx, userDataID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.Secret{
+			ID:          userDataID,
+			Data:        clientData,
+			Mark:        "test",
+			Description: "test",
+		}, resp)
+	})
+
+	t.Run("update otp", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), clientData).Times(1).Return(encData, nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, req.Mark, req.Description, dataType, clearedLabels()).
+			Times(1).Return(nil)
+
+		err := s.UpdateOTP(ctx, userDataID, req)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("patch otp", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, newMark, "test", dataType, models.UserDataLabels{}).
+			Times(1).Return(nil)
+
+		err := s.PatchOTP(ctx, userDataID, models.UpdateSecretRequest{Mark: &newMark})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestDeleteOTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "otp"
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr bool
+		errOTP  string
+	}{
+		{
+			name:    "delete otp success",
+			sErr:    nil,
+			wantErr: false,
+			errOTP:  "",
+		},
+		{
+			name:    "when otp not found",
+			sErr:    storage.ErrUserDataNotFound,
+			wantErr: true,
+			errOTP:  ErrNotFound.Error(),
+		},
+		{
+			name:    "delete otp failed",
+			sErr:    errors.New("some error"),
+			wantErr: true,
+			errOTP:  "failed to delete user data",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().DeleteUserData(ctx, userDataID, dataType).Times(1).Return(test.sErr)
+
+			err := s.DeleteOTP(ctx, userDataID)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errOTP)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestAddPassword(t *testing.T) {
+func TestPasswordSecret(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -23,164 +23,69 @@ func TestAddPassword(t *testing.T) {
 	crypter := mocks.NewMockCrypter(mockCtrl)
 	settings := config.Settings{}
 	s := NewServices(store, fs, crypter, &settings)
-
-	req := models.AddPasswordRequest{
-		Login:       "test",
-		Password:    "test",
-		Mark:        "test",
-		Description: "test",
-	}
+	expectDataKey(store, crypter)
 
 	ctx := context.Background()
+	userDataID := 1
 	dataType := "password"
+	clientData := []byte("client encrypted data")
 	encData := []byte("some data")
+	newMark := "new mark"
 
-	type sResponse struct {
-		id  int
-		err error
-	}
-	tests := []struct {
-		name      string
-		sResponse sResponse
-		wantErr   bool
-	}{
-		{
-			name: "add password success",
-			sResponse: sResponse{
-				id:  1,
-				err: nil,
-			},
-			wantErr: false,
-		},
-		{
-			name: "add password failed",
-			sResponse: sResponse{
-				id:  0,
-				err: errors.New("some error"),
-			},
-			wantErr: true,
-		},
+	req := models.AddSecretRequest{
+		Data:        clientData,
+		Mark:        "test",
+		Description: "test",
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			crypter.EXPECT().EncryptData(gomock.Any()).Times(1).Return(encData)
-			store.EXPECT().
-				AddUserData(ctx, encData, req.Mark, req.Description, dataType).
-				Times(1).Return(test.sResponse.id, test.sResponse.err)
+	t.Run("add password", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), clientData).Times(1).Return(encData, nil)
+		store.EXPECT().AddUserData(ctx, encData, req.Mark, req.Description, dataType, models.UserDataLabels{}).
+			Times(1).Return(userDataID, nil)
 
-			id, err := s.AddPassword(ctx, req)
+		id, err := s.AddPassword(ctx, req)
 
-			if test.wantErr {
-				require.Error(t, err)
-				assert.ErrorContains(t, err, "failed to add user data", "some error")
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, test.sResponse.id, id)
-			}
-		})
-	}
-}
+		require.NoError(t, err)
+		assert.Equal(t, userDataID, id)
+	})
 
-func TestAddPasswordValidationFailed(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+	t.Run("get password", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), encData).Times(1).Return(clientData, nil)
 
-	store := mocks.NewMockStorager(mockCtrl)
-	fs := mocks.NewMockFileStorager(mockCtrl)
-	crypter := mocks.NewMockCrypter(mockCtrl)
-	settings := config.Settings{}
-	s := NewServices(store, fs, crypter, &settings)
+		resp, err := s.GetPassword(ctx, userDataID)
 
-	ctx := context.Background()
+		require.NoError(t, err)
+		assert.Equal(t, models.Secret{
+			ID:          userDataID,
+			Data:        clientData,
+			Mark:        "test",
+			Description: "test",
+		}, resp)
+	})
 
-	type arg struct {
-		req models.AddPasswordRequest
-	}
-	type want struct {
-		err error
-	}
-	tests := []struct {
-		name string
-		arg  arg
-		want want
-	}{
-		{
-			name: "when user login very big",
-			arg: arg{
-				req: models.AddPasswordRequest{
-					Login: `testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest
-					testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest
-					testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest`,
-					Password:    "test",
-					Mark:        "test",
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserLoginIsTooBig,
-			},
-		},
-		{
-			name: "when user password very big",
-			arg: arg{
-				req: models.AddPasswordRequest{
-					Login: "test",
-					Password: `testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest
-					testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest
-					testtesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttesttest`,
-					Mark:        "test",
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserPasswordIsTooBig,
-			},
-		},
-		{
-			name: "when user mark very big",
-			arg: arg{
-				req: models.AddPasswordRequest{
-					Login:       "test",
-					Password:    "test",
-					Mark:        generateString(150),
-					Description: "test",
-				},
-			},
-			want: want{
-				err: ErrUserMarkIsTooBig,
-			},
-		},
-		{
-			name: "when user description very big",
-			arg: arg{
-				req: models.AddPasswordRequest{
-					Login:       "test",
-					Password:    "test",
-					Mark:        "test",
-					Description: generateString(4000),
-				},
-			},
-			want: want{
-				err: ErrUserDescriptionIsTooBig,
-			},
-		},
-	}
+	t.Run("update password", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), clientData).Times(1).Return(encData, nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, req.Mark, req.Description, dataType, clearedLabels()).
+			Times(1).Return(nil)
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			crypter.EXPECT().EncryptData(gomock.Any()).Times(0)
-			store.EXPECT().AddUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		err := s.UpdatePassword(ctx, userDataID, req)
 
-			_, err := s.AddPassword(ctx, test.arg.req)
+		require.NoError(t, err)
+	})
 
-			require.Error(t, err)
-			assert.ErrorContains(t, err, test.want.err.Error())
-		})
-	}
+	t.Run("patch password", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, "test", "test", nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, newMark, "test", dataType, models.UserDataLabels{}).
+			Times(1).Return(nil)
+
+		err := s.PatchPassword(ctx, userDataID, models.UpdateSecretRequest{Mark: &newMark})
+
+		require.NoError(t, err)
+	})
 }
 
-func TestGetPassword(t *testing.T) {
+func TestDeletePassword(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -192,123 +97,46 @@ func TestGetPassword(t *testing.T) {
 
 	ctx := context.Background()
 	userDataID := 1
-	decData := []byte("some data")
-	mark := "test"
-	description := "test"
 	dataType := "password"
 
-	type cResponse struct {
-		jsonData []byte
-		err      error
-	}
 	tests := []struct {
-		name      string
-		cResponse cResponse
-		wantErr   bool
-		errText   string
+		name    string
+		sErr    error
+		wantErr bool
+		errText string
 	}{
 		{
-			name: "get user data success",
-			cResponse: cResponse{
-				jsonData: []byte(`{"login":"test","password":"test"}`),
-				err:      nil,
-			},
+			name:    "delete password success",
+			sErr:    nil,
 			wantErr: false,
 			errText: "",
 		},
 		{
-			name: "when decrypt data failed",
-			cResponse: cResponse{
-				jsonData: nil,
-				err:      errors.New("some error"),
-			},
+			name:    "when password not found",
+			sErr:    storage.ErrUserDataNotFound,
 			wantErr: true,
-			errText: "failed to decrypt data",
+			errText: ErrNotFound.Error(),
 		},
 		{
-			name: "when generate user data failed",
-			cResponse: cResponse{
-				jsonData: []byte(`test`),
-				err:      nil,
-			},
+			name:    "delete password failed",
+			sErr:    errors.New("some error"),
 			wantErr: true,
-			errText: "failed to generate data",
+			errText: "failed to delete user data",
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetUserData(ctx, userDataID, dataType).
-				Times(1).Return(decData, mark, description, nil)
+			store.EXPECT().DeleteUserData(ctx, userDataID, dataType).Times(1).Return(test.sErr)
 
-			crypter.EXPECT().DecryptData(decData).Times(1).Return(test.cResponse.jsonData, test.cResponse.err)
-
-			resp, err := s.GetPassword(ctx, userDataID)
+			err := s.DeletePassword(ctx, userDataID)
 
 			if test.wantErr {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, test.errText)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, models.Password{
-					ID:          userDataID,
-					Login:       "test",
-					Password:    "test",
-					Mark:        mark,
-					Description: description,
-				}, resp)
 			}
 		})
 	}
 }
-
-func TestGetPasswordFailedStorage(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	store := mocks.NewMockStorager(mockCtrl)
-	fs := mocks.NewMockFileStorager(mockCtrl)
-	crypter := mocks.NewMockCrypter(mockCtrl)
-	settings := config.Settings{}
-	s := NewServices(store, fs, crypter, &settings)
-
-	ctx := context.Background()
-	userDataID := 1
-	dataType := "password"
-
-	type sResponse struct {
-		err error
-	}
-	tests := []struct {
-		name      string
-		sResponse sResponse
-		errText   string
-	}{
-		{
-			name: "failed to get user data",
-			sResponse: sResponse{
-				err: errors.New("some error"),
-			},
-			errText: "failed to get user data",
-		},
-		{
-			name: "when user data not found",
-			sResponse: sResponse{
-				err: storage.ErrUserDataNotFound,
-			},
-			errText: "requested data no found",
-		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return([]byte{}, "", "", test.sResponse.err)
-			crypter.EXPECT().DecryptData(gomock.Any()).Times(0)
-
-			_, err := s.GetPassword(ctx, userDataID)
-
-			require.Error(t, err)
-			assert.ErrorContains(t, err, test.errText, test.sResponse.err.Error())
-		})
-	}
-}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("user storage quota exceeded")
+	ErrFileTooBig    = errors.New("file is too big")
+)
+
+// GetUsage функция для получения использования хранилища пользователем и его ограничений.
+func (s *Services) GetUsage(ctx context.Context) (models.UserUsage, error) {
+	usage, err := s.storage.GetUserUsage(ctx)
+	if err != nil {
+		return usage, fmt.Errorf("failed to get user usage %w", err)
+	}
+
+	quotas := s.settings.Quotas
+	usage.MaxBytes = quotas.MaxBytes
+	usage.MaxItems = quotas.MaxItems
+	usage.MaxFileSize = quotas.MaxFileSize
+
+	return usage, nil
+}
+
+// checkQuota проверяет, что после добавления items записей и bytes байт пользователь
+// не превысит свои ограничения. Если ограничения не заданы, хранилище не запрашивается.
+func (s *Services) checkQuota(ctx context.Context, items int, bytes int64) error {
+	quotas := s.settings.Quotas
+	if quotas.MaxBytes == 0 && (quotas.MaxItems == 0 || items == 0) {
+		return nil
+	}
+
+	usage, err := s.storage.GetUserUsage(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get user usage %w", err)
+	}
+
+	if quotas.MaxItems > 0 && items > 0 && usage.Items+items > quotas.MaxItems {
+		return fmt.Errorf("%w: %d of %d items used", ErrQuotaExceeded, usage.Items, quotas.MaxItems)
+	}
+	if quotas.MaxBytes > 0 && usage.UsedBytes+bytes > quotas.MaxBytes {
+		return fmt.Errorf("%w: %d of %d bytes used", ErrQuotaExceeded, usage.UsedBytes, quotas.MaxBytes)
+	}
+
+	return nil
+}
+
+// checkFileSize проверяет размер файла и ограничения пользователя перед сохранением файла.
+func (s *Services) checkFileSize(ctx context.Context, items int, size int64) error {
+	if maxSize := s.settings.Quotas.MaxFileSize; maxSize > 0 && size > maxSize {
+		return fmt.Errorf("%w: %d bytes, max %d bytes", ErrFileTooBig, size, maxSize)
+	}
+
+	return s.checkQuota(ctx, items, size)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{Quotas: config.QuotaSettings{MaxBytes: 1000, MaxItems: 10, MaxFileSize: 500}}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+	ctx := context.Background()
+
+	t.Run("get usage success", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(models.UserUsage{UsedBytes: 100, Items: 2}, nil)
+
+		usage, err := s.GetUsage(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserUsage{UsedBytes: 100, MaxBytes: 1000, Items: 2, MaxItems: 10, MaxFileSize: 500}, usage)
+	})
+
+	t.Run("get usage failed", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(models.UserUsage{}, errors.New("some error"))
+
+		_, err := s.GetUsage(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get user usage")
+	})
+}
+
+func TestCheckQuota(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	ctx := context.Background()
+	usage := models.UserUsage{UsedBytes: 900, Items: 9}
+
+	tests := []struct {
+		name       string
+		quotas     config.QuotaSettings
+		items      int
+		bytes      int64
+		fetchTimes int
+		wantErr    error
+	}{
+		{
+			name:   "without limits",
+			items:  1,
+			bytes:  1 << 40,
+			quotas: config.QuotaSettings{},
+		},
+		{
+			name:       "within limits",
+			quotas:     config.QuotaSettings{MaxBytes: 1000, MaxItems: 10},
+			items:      1,
+			bytes:      100,
+			fetchTimes: 1,
+		},
+		{
+			name:       "too many items",
+			quotas:     config.QuotaSettings{MaxBytes: 1000, MaxItems: 9},
+			items:      1,
+			bytes:      1,
+			fetchTimes: 1,
+			wantErr:    ErrQuotaExceeded,
+		},
+		{
+			name:       "too many bytes",
+			quotas:     config.QuotaSettings{MaxBytes: 1000, MaxItems: 10},
+			items:      1,
+			bytes:      101,
+			fetchTimes: 1,
+			wantErr:    ErrQuotaExceeded,
+		},
+		{
+			name:       "replace does not add items",
+			quotas:     config.QuotaSettings{MaxBytes: 1000, MaxItems: 9},
+			bytes:      100,
+			fetchTimes: 1,
+		},
+		{
+			name:   "replace without bytes limit",
+			quotas: config.QuotaSettings{MaxItems: 9},
+			bytes:  1 << 40,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := config.Settings{Quotas: test.quotas}
+			s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+			store.EXPECT().GetUserUsage(ctx).Times(test.fetchTimes).Return(usage, nil)
+
+			err := s.checkQuota(ctx, test.items, test.bytes)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddDataOverQuota(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{Quotas: config.QuotaSettings{MaxBytes: 1000, MaxItems: 10, MaxFileSize: 500}}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+	ctx := context.Background()
+	full := models.UserUsage{UsedBytes: 1000, Items: 1}
+
+	t.Run("file is too big", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(0)
+		fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.AddFile(ctx, models.AddFileRequest{File: strings.NewReader(""), FileName: "big", FileSize: 501})
+
+		require.ErrorIs(t, err, ErrFileTooBig)
+	})
+
+	t.Run("add file over quota", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(full, nil)
+		fs.EXPECT().AddFile(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.AddFile(ctx, models.AddFileRequest{File: strings.NewReader("test"), FileName: "test", FileSize: 4})
+
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("init upload over quota", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(full, nil)
+		fs.EXPECT().InitUpload(ctx, gomock.Any()).Times(0)
+
+		_, err := s.InitFileUpload(ctx, models.InitFileUploadRequest{FileName: "test", FileSize: 4})
+
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("init upload over items quota", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(models.UserUsage{Items: 10}, nil)
+		fs.EXPECT().InitUpload(ctx, gomock.Any()).Times(0)
+
+		_, err := s.InitFileUpload(ctx, models.InitFileUploadRequest{FileName: "test", FileSize: 4})
+
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("upload part over declared size", func(t *testing.T) {
+		upload := models.FileUpload{ID: "upload", UploadID: "s3-upload", ObjectName: "object", FileSize: 4}
+		store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(upload, nil)
+		fs.EXPECT().UploadPart(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.UploadFilePart(ctx, "upload", 2, strings.NewReader("test"), 4)
+
+		require.ErrorIs(t, err, ErrInvalidUploadPart)
+	})
+
+	t.Run("add secret over quota", func(t *testing.T) {
+		crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(1).Return([]byte("data"), nil)
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(full, nil)
+		store.EXPECT().AddUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.addSecret(ctx, models.AddSecretRequest{Data: []byte("data"), Mark: "test"}, "password")
+
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("get usage failed", func(t *testing.T) {
+		store.EXPECT().GetUserUsage(ctx).Times(1).Return(models.UserUsage{}, errors.New("some error"))
+
+		_, err := s.AddFile(ctx, models.AddFileRequest{File: strings.NewReader("test"), FileName: "test", FileSize: 4})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get user usage")
+	})
+}
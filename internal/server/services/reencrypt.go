@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+const reencryptBatchSize = 100
+
+// ReencryptLegacyData функция перешифровки ключами пользователей данных, сохраненных в устаревших форматах.
+// Данные обрабатываются порциями, возвращается количество перешифрованных записей.
+func (s *Services) ReencryptLegacyData(ctx context.Context) (int, error) {
+	var count, afterID int
+
+	for {
+		batch, err := s.storage.FetchEncryptedUserData(ctx, afterID, reencryptBatchSize)
+		if err != nil {
+			return count, failedGetUserData(err)
+		}
+
+		dataKeys := make(map[int][]byte)
+
+		for _, d := range batch {
+			afterID = d.ID
+			userCtx := context.WithValue(ctx, constants.KeyUserID, d.UserID)
+
+			dataKey, ok := dataKeys[d.UserID]
+			if !ok {
+				dataKey, err = s.dataKey(userCtx)
+				if err != nil {
+					return count, err
+				}
+				dataKeys[d.UserID] = dataKey
+			}
+
+			if !s.crypter.IsLegacyData(dataKey, d.Data) {
+				continue
+			}
+
+			data, err := s.crypter.DecryptData(dataKey, d.Data)
+			if err != nil {
+				return count, fmt.Errorf("failed to decrypt user data %d: %w", d.ID, err)
+			}
+
+			encData, err := s.crypter.EncryptData(dataKey, data)
+			if err != nil {
+				return count, failedEncryptData(err)
+			}
+
+			replaced, err := s.storage.ReplaceEncryptedUserData(ctx, d.ID, d.Data, encData)
+			if err != nil {
+				return count, failedUpdateUserData(err)
+			}
+			if replaced {
+				count++
+			}
+		}
+
+		if len(batch) < reencryptBatchSize {
+			return count, nil
+		}
+	}
+}
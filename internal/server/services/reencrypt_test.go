@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReencryptLegacyData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.Background()
+	wrappedKey := []byte("wrapped key")
+	dataKey := []byte("data key")
+	legacy := []byte("legacy")
+	current := []byte("current")
+
+	t.Run("reencrypt legacy rows", func(t *testing.T) {
+		batch := []models.EncryptedUserData{
+			{ID: 1, UserID: 1, Data: legacy},
+			{ID: 2, UserID: 1, Data: current},
+			{ID: 3, UserID: 2, Data: legacy},
+		}
+
+		store.EXPECT().FetchEncryptedUserData(ctx, 0, reencryptBatchSize).Times(1).Return(batch, nil)
+		store.EXPECT().GetUserKey(gomock.Any()).Times(2).Return(wrappedKey, nil)
+		crypter.EXPECT().UnwrapDataKey(wrappedKey).Times(2).Return(dataKey, nil)
+		crypter.EXPECT().IsLegacyData(dataKey, legacy).Times(2).Return(true)
+		crypter.EXPECT().IsLegacyData(dataKey, current).Times(1).Return(false)
+		crypter.EXPECT().DecryptData(dataKey, legacy).Times(2).Return([]byte("data"), nil)
+		crypter.EXPECT().EncryptData(dataKey, []byte("data")).Times(2).Return(current, nil)
+		store.EXPECT().ReplaceEncryptedUserData(ctx, 1, legacy, current).Times(1).Return(true, nil)
+		store.EXPECT().ReplaceEncryptedUserData(ctx, 3, legacy, current).Times(1).Return(false, nil)
+
+		count, err := s.ReencryptLegacyData(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("reencrypt failed when fetch failed", func(t *testing.T) {
+		store.EXPECT().FetchEncryptedUserData(ctx, 0, reencryptBatchSize).Times(1).
+			Return(nil, errors.New("some error"))
+
+		_, err := s.ReencryptLegacyData(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get user data")
+	})
+
+	t.Run("reencrypt failed when decrypt failed", func(t *testing.T) {
+		batch := []models.EncryptedUserData{{ID: 1, UserID: 1, Data: legacy}}
+
+		store.EXPECT().FetchEncryptedUserData(ctx, 0, reencryptBatchSize).Times(1).Return(batch, nil)
+		store.EXPECT().GetUserKey(gomock.Any()).Times(1).Return(wrappedKey, nil)
+		crypter.EXPECT().UnwrapDataKey(wrappedKey).Times(1).Return(dataKey, nil)
+		crypter.EXPECT().IsLegacyData(dataKey, legacy).Times(1).Return(true)
+		crypter.EXPECT().DecryptData(dataKey, legacy).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.ReencryptLegacyData(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to decrypt user data 1")
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+// RotateMasterKey функция перешифровки ключей данных пользователей текущим мастер-ключом.
+// Ключи обрабатываются порциями в отдельных транзакциях, уже перешифрованные ключи пропускаются,
+// поэтому прерванную ротацию можно запустить повторно. Возвращает количество перешифрованных ключей.
+func (s *Services) RotateMasterKey(
+	ctx context.Context,
+	oldCrypter Crypter,
+	batchSize int,
+	progress func(done, total int),
+) (int, error) {
+	keyID := s.crypter.MasterKeyID()
+
+	total, err := s.storage.CountUserKeysToRotate(ctx, keyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user keys %w", err)
+	}
+
+	rewrap := func(wrappedKey []byte) ([]byte, error) {
+		dataKey, err := oldCrypter.UnwrapDataKey(wrappedKey)
+		if err != nil {
+			// Ключ мог быть уже зашифрован новым мастер-ключом без отметки об этом.
+			if _, newErr := s.crypter.UnwrapDataKey(wrappedKey); newErr == nil {
+				return wrappedKey, nil
+			}
+
+			return nil, fmt.Errorf("failed to unwrap user key with old master key %w", err)
+		}
+
+		newKey, err := s.crypter.WrapDataKey(dataKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap user key with new master key %w", err)
+		}
+
+		return newKey, nil
+	}
+
+	var done int
+
+	for {
+		n, err := s.storage.RotateUserKeys(ctx, keyID, batchSize, rewrap)
+		if err != nil {
+			return done, fmt.Errorf("failed to rotate user keys %w", err)
+		}
+		if n == 0 {
+			return done, nil
+		}
+
+		done += n
+		progress(done, total)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateMasterKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	oldCrypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.Background()
+	keyID := "new key id"
+
+	t.Run("rotate in batches", func(t *testing.T) {
+		var progress []int
+
+		crypter.EXPECT().MasterKeyID().Times(1).Return(keyID)
+		store.EXPECT().CountUserKeysToRotate(ctx, keyID).Times(1).Return(3, nil)
+		gomock.InOrder(
+			store.EXPECT().RotateUserKeys(ctx, keyID, 2, gomock.Any()).Return(2, nil),
+			store.EXPECT().RotateUserKeys(ctx, keyID, 2, gomock.Any()).Return(1, nil),
+			store.EXPECT().RotateUserKeys(ctx, keyID, 2, gomock.Any()).Return(0, nil),
+		)
+
+		done, err := s.RotateMasterKey(ctx, oldCrypter, 2, func(done, total int) {
+			assert.Equal(t, 3, total)
+			progress = append(progress, done)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, done)
+		assert.Equal(t, []int{2, 3}, progress)
+	})
+
+	t.Run("rewrap user key", func(t *testing.T) {
+		crypter.EXPECT().MasterKeyID().Times(1).Return(keyID)
+		store.EXPECT().CountUserKeysToRotate(ctx, keyID).Times(1).Return(1, nil)
+		store.EXPECT().RotateUserKeys(ctx, keyID, 10, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ string, _ int, rewrap func([]byte) ([]byte, error)) (int, error) {
+				newKey, err := rewrap([]byte("old wrapped"))
+				require.NoError(t, err)
+				assert.Equal(t, []byte("new wrapped"), newKey)
+
+				_, err = rewrap([]byte("unknown"))
+				require.Error(t, err)
+
+				return 0, nil
+			})
+		oldCrypter.EXPECT().UnwrapDataKey([]byte("old wrapped")).Times(1).Return([]byte("data key"), nil)
+		crypter.EXPECT().WrapDataKey([]byte("data key")).Times(1).Return([]byte("new wrapped"), nil)
+		oldCrypter.EXPECT().UnwrapDataKey([]byte("unknown")).Times(1).Return(nil, errors.New("some error"))
+		crypter.EXPECT().UnwrapDataKey([]byte("unknown")).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.RotateMasterKey(ctx, oldCrypter, 10, func(done, total int) {})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("rotate failed", func(t *testing.T) {
+		crypter.EXPECT().MasterKeyID().Times(1).Return(keyID)
+		store.EXPECT().CountUserKeysToRotate(ctx, keyID).Times(1).Return(1, nil)
+		store.EXPECT().RotateUserKeys(ctx, keyID, 10, gomock.Any()).Times(1).Return(0, errors.New("some error"))
+
+		_, err := s.RotateMasterKey(ctx, oldCrypter, 10, func(done, total int) {})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to rotate user keys")
+	})
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 1000
+)
+
+// ErrInvalidFilter фильтр поиска данных пользователя содержит недопустимые значения.
+var ErrInvalidFilter = errors.New("invalid user data filter")
+
+// searchDataTypes типы данных, по которым можно искать.
+var searchDataTypes = map[string]bool{
+	passwordDataType: true,
+	cardDataType:     true,
+	textDataType:     true,
+	otpDataType:      true,
+	fileDataType:     true,
+}
+
+// SearchUserData функция для поиска данных пользователя по фильтру.
+// Без ограничения количества возвращается не больше defaultSearchLimit данных.
+// Папка и метка приводятся к тому же виду, что и при сохранении.
+func (s *Services) SearchUserData(ctx context.Context, filter models.UserDataFilter) (models.UserDataPage, error) {
+	if filter.Type != "" && !searchDataTypes[filter.Type] {
+		return models.UserDataPage{}, ErrInvalidFilter
+	}
+	if filter.Limit < 0 || filter.Limit > maxSearchLimit || filter.Offset < 0 {
+		return models.UserDataPage{}, ErrInvalidFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultSearchLimit
+	}
+
+	folder, err := normalizeFolder(filter.Folder)
+	if err != nil {
+		return models.UserDataPage{}, ErrInvalidFilter
+	}
+	filter.Folder = folder
+	filter.Tag = strings.TrimSpace(filter.Tag)
+
+	page, err := s.storage.SearchUserData(ctx, filter)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidSort) {
+			return page, ErrInvalidFilter
+		}
+
+		return page, fmt.Errorf("failed to search user data: %w", err)
+	}
+
+	return page, nil
+}
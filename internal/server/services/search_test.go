@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+
+	ctx := context.Background()
+	page := models.UserDataPage{Items: []models.UserDataItem{{ID: 1, Type: "card", Mark: "aws"}}, Total: 1}
+
+	tests := []struct {
+		name        string
+		filter      models.UserDataFilter
+		storeFilter models.UserDataFilter
+		storeTimes  int
+		storeErr    error
+		wantErr     error
+		errText     string
+	}{
+		{
+			name:        "search with default limit",
+			filter:      models.UserDataFilter{Type: "card", Search: "aws"},
+			storeFilter: models.UserDataFilter{Type: "card", Search: "aws", Limit: defaultSearchLimit},
+			storeTimes:  1,
+		},
+		{
+			name:        "search with limit",
+			filter:      models.UserDataFilter{Limit: 20, Offset: 40},
+			storeFilter: models.UserDataFilter{Limit: 20, Offset: 40},
+			storeTimes:  1,
+		},
+		{
+			name:        "search by tag and folder",
+			filter:      models.UserDataFilter{Tag: " prod ", Folder: "/work/aws/"},
+			storeFilter: models.UserDataFilter{Tag: "prod", Folder: "work/aws", Limit: defaultSearchLimit},
+			storeTimes:  1,
+		},
+		{
+			name:    "invalid folder",
+			filter:  models.UserDataFilter{Folder: "work//aws"},
+			wantErr: ErrInvalidFilter,
+		},
+		{
+			name:    "unknown type",
+			filter:  models.UserDataFilter{Type: "note"},
+			wantErr: ErrInvalidFilter,
+		},
+		{
+			name:    "too big limit",
+			filter:  models.UserDataFilter{Limit: maxSearchLimit + 1},
+			wantErr: ErrInvalidFilter,
+		},
+		{
+			name:    "negative offset",
+			filter:  models.UserDataFilter{Offset: -1},
+			wantErr: ErrInvalidFilter,
+		},
+		{
+			name:        "unknown sort",
+			filter:      models.UserDataFilter{Sort: "data"},
+			storeFilter: models.UserDataFilter{Sort: "data", Limit: defaultSearchLimit},
+			storeTimes:  1,
+			storeErr:    storage.ErrInvalidSort,
+			wantErr:     ErrInvalidFilter,
+		},
+		{
+			name:        "search failed",
+			filter:      models.UserDataFilter{},
+			storeFilter: models.UserDataFilter{Limit: defaultSearchLimit},
+			storeTimes:  1,
+			storeErr:    errors.New("some error"),
+			errText:     "failed to search user data",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().SearchUserData(ctx, test.storeFilter).Times(test.storeTimes).Return(page, test.storeErr)
+
+			resp, err := s.SearchUserData(ctx, test.filter)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, page, resp)
+			}
+		})
+	}
+}
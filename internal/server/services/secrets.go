@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+)
+
+var (
+	ErrUserDataIsEmpty  = errors.New("user data is empty")
+	ErrUserDataIsTooBig = errors.New("user data is too big")
+
+	maxSecretDataSize = 64 * 1024
+)
+
+// addSecret функция для добавления данных пользователя, зашифрованных на клиенте.
+func (s *Services) addSecret(ctx context.Context, req models.AddSecretRequest, dataType string) (int, error) {
+	if err := validateSecretRequest(req); err != nil {
+		return 0, failedValidateFields(err)
+	}
+
+	labels, err := newAddedUserDataLabels(req.Folder, req.Tags)
+	if err != nil {
+		return 0, failedValidateFields(err)
+	}
+
+	encData, err := s.encryptData(ctx, req.Data)
+	if err != nil {
+		return 0, failedEncryptData(err)
+	}
+
+	if err := s.checkQuota(ctx, 1, int64(len(encData))); err != nil {
+		return 0, err
+	}
+
+	return s.addUserData(ctx, req.ID, encData, req.Mark, req.Description, dataType, labels)
+}
+
+// getSecret функция для получения данных пользователя, зашифрованных на клиенте.
+func (s *Services) getSecret(ctx context.Context, id int, dataType string) (models.Secret, error) {
+	resp := models.Secret{}
+
+	encData, mark, description, err := s.storage.GetUserData(ctx, id, dataType)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return resp, ErrNotFound
+		}
+
+		return resp, failedGetUserData(err)
+	}
+
+	data, err := s.decryptData(ctx, encData)
+	if err != nil {
+		return resp, failedDecryptData(err)
+	}
+
+	resp.ID = id
+	resp.Data = data
+	resp.Mark = mark
+	resp.Description = description
+
+	return resp, nil
+}
+
+// updateSecret функция для полной замены данных пользователя, зашифрованных на клиенте, вместе с папкой и метками.
+// Данные, папка и метки записываются в одной транзакции.
+func (s *Services) updateSecret(ctx context.Context, id int, req models.AddSecretRequest, dataType string) error {
+	labels, err := newUserDataLabels(&req.Folder, &req.Tags)
+	if err != nil {
+		return failedValidateFields(err)
+	}
+
+	return s.replaceSecret(ctx, id, req, dataType, labels)
+}
+
+// replaceSecret функция для замены данных пользователя, зашифрованных на клиенте, и папки и меток из labels.
+func (s *Services) replaceSecret(
+	ctx context.Context,
+	id int,
+	req models.AddSecretRequest,
+	dataType string,
+	labels models.UserDataLabels,
+) error {
+	if err := validateSecretRequest(req); err != nil {
+		return failedValidateFields(err)
+	}
+
+	encData, err := s.encryptData(ctx, req.Data)
+	if err != nil {
+		return failedEncryptData(err)
+	}
+
+	return s.replaceUserData(ctx, id, req.Version, encData, req.Mark, req.Description, dataType, labels)
+}
+
+// patchSecret функция для частичного обновления данных пользователя, зашифрованных на клиенте.
+// Если изменяются только папка или метки, версия данных не меняется, а пустой запрос ничего не меняет.
+func (s *Services) patchSecret(ctx context.Context, id int, req models.UpdateSecretRequest, dataType string) error {
+	labels, err := newUserDataLabels(req.Folder, req.Tags)
+	if err != nil {
+		return failedValidateFields(err)
+	}
+
+	if req.Data != nil || req.Mark != nil || req.Description != nil {
+		return s.patchSecretData(ctx, id, req, dataType, labels)
+	}
+
+	if emptyLabels(labels) {
+		return s.checkUserData(ctx, id, dataType)
+	}
+
+	return s.setUserDataLabels(ctx, id, dataType, labels)
+}
+
+// patchSecretData функция для частичного обновления данных, метки и описания данных пользователя
+// вместе с папкой и метками из labels.
+func (s *Services) patchSecretData(
+	ctx context.Context,
+	id int,
+	req models.UpdateSecretRequest,
+	dataType string,
+	labels models.UserDataLabels,
+) error {
+	encData, mark, description, err := s.storage.GetUserData(ctx, id, dataType)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return failedGetUserData(err)
+	}
+
+	patchValue(&mark, req.Mark)
+	patchValue(&description, req.Description)
+
+	if req.Data == nil {
+		if err := validateMarkAndDescription(mark, description); err != nil {
+			return failedValidateFields(err)
+		}
+
+		return s.replaceUserData(ctx, id, req.Version, encData, mark, description, dataType, labels)
+	}
+
+	return s.replaceSecret(ctx, id, models.AddSecretRequest{
+		Data:        req.Data,
+		Mark:        mark,
+		Description: description,
+		Version:     req.Version,
+	}, dataType, labels)
+}
+
+// checkUserData функция для проверки, что у пользователя есть данные с указанным ID и типом.
+func (s *Services) checkUserData(ctx context.Context, id int, dataType string) error {
+	if _, _, _, err := s.storage.GetUserData(ctx, id, dataType); err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return failedGetUserData(err)
+	}
+
+	return nil
+}
+
+// replaceUserData функция для замены уже зашифрованных данных пользователя и папки и меток из labels.
+// Если version не 0, данные заменяются, только если их не изменил другой клиент.
+// Прежняя версия сохраняется в истории.
+func (s *Services) replaceUserData(
+	ctx context.Context,
+	id int,
+	version int,
+	encData []byte,
+	mark, description, dataType string,
+	labels models.UserDataLabels,
+) error {
+	err := s.storage.UpdateUserData(ctx, id, version, encData, mark, description, dataType, labels)
+	if err != nil {
+		return updateUserDataError(err)
+	}
+
+	s.pruneHistory(ctx, id, dataType)
+
+	return nil
+}
+
+// updateUserDataError приводит ошибку обновления данных пользователя в хранилище к ошибке сервиса.
+func updateUserDataError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUserDataNotFound):
+		return ErrNotFound
+	case errors.Is(err, storage.ErrVersionConflict):
+		return ErrVersionConflict
+	default:
+		return failedUpdateUserData(err)
+	}
+}
+
+func validateSecretRequest(req models.AddSecretRequest) error {
+	if len(req.Data) == 0 {
+		return ErrUserDataIsEmpty
+	}
+	if len(req.Data) > maxSecretDataSize {
+		return ErrUserDataIsTooBig
+	}
+
+	return validateMarkAndDescription(req.Mark, req.Description)
+}
+
+func validateMarkAndDescription(mark, description string) error {
+	if len([]rune(mark)) > maxMarkSize {
+		return ErrUserMarkIsTooBig
+	}
+	if len([]rune(description)) > maxDescriptionSize {
+		return ErrUserDescriptionIsTooBig
+	}
+
+	return nil
+}
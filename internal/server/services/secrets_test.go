@@ -0,0 +1,544 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSecret(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	req := models.AddSecretRequest{
+		Data:        []byte("client encrypted data"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	ctx := context.Background()
+	dataType := "password"
+	encData := []byte("some data")
+
+	type sResponse struct {
+		id  int
+		err error
+	}
+	tests := []struct {
+		name      string
+		sResponse sResponse
+		wantErr   bool
+	}{
+		{
+			name: "add secret success",
+			sResponse: sResponse{
+				id:  1,
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "add secret failed",
+			sResponse: sResponse{
+				id:  0,
+				err: errors.New("some error"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crypter.EXPECT().EncryptData(gomock.Any(), req.Data).Times(1).Return(encData, nil)
+			store.EXPECT().
+				AddUserData(ctx, encData, req.Mark, req.Description, dataType, models.UserDataLabels{}).
+				Times(1).Return(test.sResponse.id, test.sResponse.err)
+
+			id, err := s.addSecret(ctx, req, dataType)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, "failed to add user data", "some error")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.sResponse.id, id)
+			}
+		})
+	}
+}
+
+func TestAddSecretWithReservedID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	req := models.AddSecretRequest{
+		Data: []byte("client encrypted data"),
+		Mark: "test",
+		ID:   42,
+	}
+
+	ctx := context.Background()
+	dataType := "password"
+	encData := []byte("some data")
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr error
+		errText string
+	}{
+		{
+			name: "add secret success",
+		},
+		{
+			name:    "id is not reserved",
+			sErr:    storage.ErrDataIDNotReserved,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "add secret failed",
+			sErr:    errors.New("some error"),
+			errText: "failed to add user data",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crypter.EXPECT().EncryptData(gomock.Any(), req.Data).Times(1).Return(encData, nil)
+			store.EXPECT().AddUserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+			store.EXPECT().
+				AddReservedUserData(ctx, 42, encData, req.Mark, req.Description, dataType, models.UserDataLabels{}).
+				Times(1).Return(42, test.sErr)
+
+			id, err := s.addSecret(ctx, req, dataType)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, 42, id)
+			}
+		})
+	}
+}
+
+func TestAddSecretValidationFailed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+
+	type arg struct {
+		req models.AddSecretRequest
+	}
+	type want struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		arg  arg
+		want want
+	}{
+		{
+			name: "when user data empty",
+			arg: arg{
+				req: models.AddSecretRequest{
+					Mark:        "test",
+					Description: "test",
+				},
+			},
+			want: want{
+				err: ErrUserDataIsEmpty,
+			},
+		},
+		{
+			name: "when user data very big",
+			arg: arg{
+				req: models.AddSecretRequest{
+					Data:        make([]byte, maxSecretDataSize+1),
+					Mark:        "test",
+					Description: "test",
+				},
+			},
+			want: want{
+				err: ErrUserDataIsTooBig,
+			},
+		},
+		{
+			name: "when user mark very big",
+			arg: arg{
+				req: models.AddSecretRequest{
+					Data:        []byte("test"),
+					Mark:        generateString(150),
+					Description: "test",
+				},
+			},
+			want: want{
+				err: ErrUserMarkIsTooBig,
+			},
+		},
+		{
+			name: "when user description very big",
+			arg: arg{
+				req: models.AddSecretRequest{
+					Data:        []byte("test"),
+					Mark:        "test",
+					Description: generateString(4000),
+				},
+			},
+			want: want{
+				err: ErrUserDescriptionIsTooBig,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().AddUserData(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := s.addSecret(ctx, test.arg.req, "password")
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, test.want.err)
+		})
+	}
+}
+
+func TestGetSecret(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	encData := []byte("some data")
+	mark := "test"
+	description := "test"
+	dataType := "password"
+
+	t.Run("get secret success", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, mark, description, nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), encData).Times(1).Return([]byte("client encrypted data"), nil)
+
+		resp, err := s.getSecret(ctx, userDataID, dataType)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.Secret{
+			ID:          userDataID,
+			Data:        []byte("client encrypted data"),
+			Mark:        mark,
+			Description: description,
+		}, resp)
+	})
+
+	t.Run("when decrypt data failed", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(encData, mark, description, nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), encData).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.getSecret(ctx, userDataID, dataType)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to decrypt data")
+	})
+}
+
+func TestGetSecretFailedStorage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "password"
+
+	tests := []struct {
+		name    string
+		sErr    error
+		errText string
+	}{
+		{
+			name:    "failed to get user data",
+			sErr:    errors.New("some error"),
+			errText: "failed to get user data",
+		},
+		{
+			name:    "when user data not found",
+			sErr:    storage.ErrUserDataNotFound,
+			errText: ErrNotFound.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(nil, "", "", test.sErr)
+			crypter.EXPECT().DecryptData(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := s.getSecret(ctx, userDataID, dataType)
+
+			require.Error(t, err)
+			assert.ErrorContains(t, err, test.errText)
+		})
+	}
+}
+
+func TestUpdateSecret(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	req := models.AddSecretRequest{
+		Data:        []byte("client encrypted data"),
+		Mark:        "test",
+		Description: "test",
+	}
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "password"
+	encData := []byte("some data")
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr bool
+		errText string
+	}{
+		{
+			name:    "update secret success",
+			sErr:    nil,
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:    "when secret not found",
+			sErr:    storage.ErrUserDataNotFound,
+			wantErr: true,
+			errText: ErrNotFound.Error(),
+		},
+		{
+			name:    "update secret changed by another client",
+			sErr:    storage.ErrVersionConflict,
+			wantErr: true,
+			errText: ErrVersionConflict.Error(),
+		},
+		{
+			name:    "update secret failed",
+			sErr:    errors.New("some error"),
+			wantErr: true,
+			errText: "failed to update user data",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crypter.EXPECT().EncryptData(gomock.Any(), req.Data).Times(1).Return(encData, nil)
+			store.EXPECT().
+				UpdateUserData(ctx, userDataID, 0, encData, req.Mark, req.Description, dataType, clearedLabels()).
+				Times(1).Return(test.sErr)
+
+			err := s.updateSecret(ctx, userDataID, req, dataType)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPatchSecret(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	userDataID := 1
+	dataType := "password"
+	oldEncData := []byte("old data")
+	encData := []byte("new data")
+	newMark := "new mark"
+
+	t.Run("patch secret data and mark", func(t *testing.T) {
+		req := models.UpdateSecretRequest{
+			Data: []byte("new client data"),
+			Mark: &newMark,
+		}
+
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldEncData, "test", "test", nil)
+		crypter.EXPECT().EncryptData(gomock.Any(), req.Data).Times(1).Return(encData, nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, encData, newMark, "test", dataType, models.UserDataLabels{}).
+			Times(1).Return(nil)
+
+		err := s.patchSecret(ctx, userDataID, req, dataType)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("patch secret with version", func(t *testing.T) {
+		req := models.UpdateSecretRequest{
+			Mark:    &newMark,
+			Version: 3,
+		}
+
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldEncData, "test", "test", nil)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 3, oldEncData, newMark, "test", dataType, models.UserDataLabels{}).
+			Times(1).Return(storage.ErrVersionConflict)
+
+		err := s.patchSecret(ctx, userDataID, req, dataType)
+
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("patch secret mark only keeps data", func(t *testing.T) {
+		req := models.UpdateSecretRequest{
+			Mark: &newMark,
+		}
+
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldEncData, "test", "test", nil)
+		crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().UpdateUserData(ctx, userDataID, 0, oldEncData, newMark, "test", dataType, models.UserDataLabels{}).
+			Times(1).Return(nil)
+
+		err := s.patchSecret(ctx, userDataID, req, dataType)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("patch secret data and folder in one update", func(t *testing.T) {
+		folder := "work"
+		req := models.UpdateSecretRequest{
+			Data:   []byte("new client data"),
+			Folder: &folder,
+		}
+
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldEncData, "test", "test", nil)
+		crypter.EXPECT().EncryptData(gomock.Any(), req.Data).Times(1).Return(encData, nil)
+		store.EXPECT().
+			UpdateUserData(ctx, userDataID, 0, encData, "test", "test", dataType, models.UserDataLabels{Folder: &folder}).
+			Times(1).Return(nil)
+		store.EXPECT().SetUserDataLabels(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := s.patchSecret(ctx, userDataID, req, dataType)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("empty patch changes nothing", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldEncData, "test", "test", nil)
+		store.EXPECT().
+			UpdateUserData(
+				ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			).
+			Times(0)
+		store.EXPECT().SetUserDataLabels(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := s.patchSecret(ctx, userDataID, models.UpdateSecretRequest{}, dataType)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("empty patch when secret not found", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).
+			Return(nil, "", "", storage.ErrUserDataNotFound)
+
+		err := s.patchSecret(ctx, userDataID, models.UpdateSecretRequest{}, dataType)
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("when patched mark very big", func(t *testing.T) {
+		bigMark := generateString(150)
+		req := models.UpdateSecretRequest{
+			Mark: &bigMark,
+		}
+
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).Return(oldEncData, "test", "test", nil)
+		store.EXPECT().
+			UpdateUserData(
+				ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			).
+			Times(0)
+
+		err := s.patchSecret(ctx, userDataID, req, dataType)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUserMarkIsTooBig)
+	})
+
+	t.Run("when secret not found", func(t *testing.T) {
+		store.EXPECT().GetUserData(ctx, userDataID, dataType).Times(1).
+			Return(nil, "", "", storage.ErrUserDataNotFound)
+		crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().
+			UpdateUserData(
+				ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			).
+			Times(0)
+
+		err := s.patchSecret(ctx, userDataID, models.UpdateSecretRequest{Mark: &newMark}, dataType)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
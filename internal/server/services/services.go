@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/ratelimit"
 )
 
 var (
 	ErrNotFound                = errors.New("requested data no found")
+	ErrVersionConflict         = errors.New("user data was changed by another client")
 	ErrUserMarkIsTooBig        = errors.New("user mark is too big")
 	ErrUserDescriptionIsTooBig = errors.New("user description is too big")
 
@@ -21,10 +24,12 @@ var (
 
 // Services структура для работы с сервисами приложения.
 type Services struct {
-	storage     Storager
-	fileStorage FileStorager
-	crypter     Crypter
-	settings    *config.Settings
+	storage       Storager
+	fileStorage   FileStorager
+	crypter       Crypter
+	settings      *config.Settings
+	loginAttempts *ratelimit.Attempts
+	ipAttempts    *ratelimit.Attempts
 }
 
 // Storager интерфейс для хранилища данных.
@@ -32,31 +37,128 @@ type Storager interface {
 	Ping(ctx context.Context) error
 	AddUser(ctx context.Context, userLogin string, userPassword []byte) error
 	GetUserByLogin(ctx context.Context, userLogin string) (models.User, error)
+	GetUserByID(ctx context.Context, userID int) (models.User, error)
 	FetchUserData(ctx context.Context) ([]models.UserData, error)
-	AddUserData(ctx context.Context, encData []byte, mark string, description string, dataType string) (int, error)
+	FetchUserDataChanges(ctx context.Context, since int64) (models.UserDataChanges, error)
+	PruneUserDataTombstones(ctx context.Context, before time.Time) (int, error)
+	SearchUserData(ctx context.Context, filter models.UserDataFilter) (models.UserDataPage, error)
+	AddUserData(
+		ctx context.Context, encData []byte, mark string, description string, dataType string, labels models.UserDataLabels,
+	) (int, error)
+	ReserveUserDataID(ctx context.Context) (int, error)
+	AddReservedUserData(
+		ctx context.Context,
+		id int,
+		encData []byte,
+		mark string,
+		description string,
+		dataType string,
+		labels models.UserDataLabels,
+	) (int, error)
 	GetUserData(ctx context.Context, id int, dataType string) ([]byte, string, string, error)
-	GetFileUserData(ctx context.Context, fileMark string) ([]byte, error)
+	UpdateUserData(
+		ctx context.Context,
+		id int,
+		version int,
+		encData []byte,
+		mark string,
+		description string,
+		dataType string,
+		labels models.UserDataLabels,
+	) error
+	DeleteUserData(ctx context.Context, id int, dataType string) error
+	SetUserDataLabels(ctx context.Context, id int, dataType string, labels models.UserDataLabels) error
+	FetchUserDataHistory(ctx context.Context, id int, dataType string) ([]models.UserDataRevision, error)
+	GetUserDataRevision(ctx context.Context, id int, version int, dataType string) ([]byte, string, string, error)
+	PruneUserDataHistory(ctx context.Context, id int, keep int) ([][]byte, error)
+	RestoreFileUserDataRevision(ctx context.Context, id int, version int) error
+	FetchFileUserDataHistory(ctx context.Context) ([]models.FileUserData, error)
+	FetchTrash(ctx context.Context) ([]models.TrashItem, error)
+	RestoreUserData(ctx context.Context, id int) error
+	FetchTrashedUserData(ctx context.Context, before time.Time) ([]models.TrashedUserData, error)
+	PurgeUserData(ctx context.Context, id int, before time.Time) (bool, error)
+	FetchEncryptedUserData(ctx context.Context, afterID int, limit int) ([]models.EncryptedUserData, error)
+	ReplaceEncryptedUserData(ctx context.Context, id int, oldData []byte, newData []byte) (bool, error)
+	GetUserKey(ctx context.Context) ([]byte, error)
+	AddSession(ctx context.Context, session models.Session, refreshTokenHash []byte) error
+	RotateSessionRefreshToken(ctx context.Context, oldHash, newHash []byte, expiresAt time.Time) (models.Session, error)
+	ListSessions(ctx context.Context, userID int) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	GetUserVault(ctx context.Context) ([]byte, []byte, error)
+	SetUserVaultSalt(ctx context.Context, salt []byte) error
+	SetUserVaultCheck(ctx context.Context, check []byte) (bool, error)
+	GetUserTwoFactor(ctx context.Context) (models.TwoFactor, error)
+	SetUserTwoFactorSecret(ctx context.Context, secret []byte) (bool, error)
+	EnableUserTwoFactor(ctx context.Context, step int64, recoveryCodeHashes [][]byte) (bool, error)
+	UpdateUserTwoFactorStep(ctx context.Context, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, codeHash []byte) error
+	AddUserKey(ctx context.Context, dataKey []byte, keyID string) error
+	CountUserKeysToRotate(ctx context.Context, keyID string) (int, error)
+	RotateUserKeys(ctx context.Context, keyID string, limit int, rewrap func(dataKey []byte) ([]byte, error)) (int, error)
+	AddFileUpload(ctx context.Context, upload models.FileUpload) error
+	GetFileUpload(ctx context.Context, id string) (models.FileUpload, error)
+	CompleteFileUpload(
+		ctx context.Context,
+		id string,
+		encData []byte,
+		mark string,
+		description string,
+		fileSize int64,
+		labels models.UserDataLabels,
+	) (int, error)
+	DeleteFileUpload(ctx context.Context, id string) error
+	AddPendingFileObject(ctx context.Context, objectName string) error
+	DeletePendingFileObject(ctx context.Context, objectName string) error
+	FetchPendingFileObjects(ctx context.Context) ([]models.PendingFileObject, error)
+	FetchFileUploadObjects(ctx context.Context) ([]string, error)
+	FetchUserIDs(ctx context.Context, afterID int, limit int) ([]int, error)
+	FetchFileUserData(ctx context.Context) ([]models.FileUserData, error)
+	SetFileUserDataMissing(ctx context.Context, id int, missing bool) error
+	AddFileUserData(
+		ctx context.Context, encData []byte, mark, description string, fileSize int64, labels models.UserDataLabels,
+	) (int, error)
+	UpdateFileUserData(ctx context.Context, id int, encData []byte, mark, description string, fileSize int64) error
+	GetUserUsage(ctx context.Context) (models.UserUsage, error)
 }
 
 // Crypter интерфейс для криптографии.
 type Crypter interface {
-	EncryptData(data []byte) []byte
-	DecryptData(data []byte) ([]byte, error)
+	MasterKeyID() string
+	GenerateDataKey() ([]byte, []byte, error)
+	WrapDataKey(dataKey []byte) ([]byte, error)
+	UnwrapDataKey(wrappedKey []byte) ([]byte, error)
+	EncryptData(dataKey []byte, data []byte) ([]byte, error)
+	DecryptData(dataKey []byte, data []byte) ([]byte, error)
+	IsLegacyData(dataKey []byte, data []byte) bool
 }
 
 // FileStorager интерфейс для файлового хранилища данных.
 type FileStorager interface {
 	AddFile(ctx context.Context, file io.Reader, objectName string, objectSize int64) error
-	GetFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+	GetFile(ctx context.Context, objectName string, rng *models.FileRange) (models.File, error)
+	StatFile(ctx context.Context, objectName string) (models.File, error)
+	InitUpload(ctx context.Context, objectName string) (string, error)
+	UploadPart(
+		ctx context.Context, objectName, uploadID string, number int, part io.Reader, size int64,
+	) (models.FileUploadPart, error)
+	ListUploadParts(ctx context.Context, objectName, uploadID string) ([]models.FileUploadPart, error)
+	CompleteUpload(ctx context.Context, objectName, uploadID string, parts []models.FileUploadPart) error
+	AbortUpload(ctx context.Context, objectName, uploadID string) error
+	DeleteFile(ctx context.Context, objectName string) error
+	ListFiles(ctx context.Context) ([]models.FileInfo, error)
 }
 
 // NewServices функция инициализации сервисов приложения.
 func NewServices(storage Storager, fileStorage FileStorager, crypter Crypter, settings *config.Settings) *Services {
+	limits := settings.Limits
+
 	return &Services{
-		storage:     storage,
-		fileStorage: fileStorage,
-		crypter:     crypter,
-		settings:    settings,
+		storage:       storage,
+		fileStorage:   fileStorage,
+		crypter:       crypter,
+		settings:      settings,
+		loginAttempts: ratelimit.NewAttempts(limits.LoginAttempts, limits.LockoutBase, limits.LockoutMax),
+		ipAttempts:    ratelimit.NewAttempts(limits.IPAttempts, limits.LockoutBase, limits.LockoutMax),
 	}
 }
 
@@ -80,6 +182,21 @@ func failedGetUserData(err error) error {
 	return fmt.Errorf("failed to get user data %w", err)
 }
 
+// failedUpdateUserData оберта ошибки обновления данных пользователя.
+func failedUpdateUserData(err error) error {
+	return fmt.Errorf("failed to update user data %w", err)
+}
+
+// failedDeleteUserData оберта ошибки удаления данных пользователя.
+func failedDeleteUserData(err error) error {
+	return fmt.Errorf("failed to delete user data %w", err)
+}
+
+// failedEncryptData оберта ошибки шифрования данных пользователя.
+func failedEncryptData(err error) error {
+	return fmt.Errorf("failed to encrypt data %w", err)
+}
+
 // failedDecryptData оберта ошибки расшифрования данных пользователя.
 func failedDecryptData(err error) error {
 	return fmt.Errorf("failed to decrypt data %w", err)
@@ -22,6 +22,8 @@ func TestNewServices(t *testing.T) {
 		storage := mocks.NewMockStorager(mockCtrl)
 		fs := mocks.NewMockFileStorager(mockCtrl)
 		crypter := mocks.NewMockCrypter(mockCtrl)
+		t.Setenv("JWT_KEY", "jwt key")
+		t.Setenv("MASTER_KEY", "master key")
 		settings, err := config.Setup(false)
 		require.NoError(t, err)
 
@@ -31,6 +33,12 @@ func TestNewServices(t *testing.T) {
 	})
 }
 
+// expectDataKey разрешает сервисам получать ключ данных пользователя в тестах.
+func expectDataKey(store *mocks.MockStorager, crypter *mocks.MockCrypter) {
+	store.EXPECT().GetUserKey(gomock.Any()).AnyTimes().Return([]byte("wrapped key"), nil)
+	crypter.EXPECT().UnwrapDataKey(gomock.Any()).AnyTimes().Return([]byte("data key"), nil)
+}
+
 func generateString(n int) string {
 	b := make([]byte, n)
 	for i := range b {
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+const (
+	refreshTokenSize     = 32
+	maxDeviceNameSize    = 255
+	maxClientVersionSize = 64
+)
+
+// RefreshUserToken обновить токен доступа пользователя по refresh токену.
+// Refresh токен одноразовый: вместе с токеном доступа выдается новый refresh токен.
+func (s *Services) RefreshUserToken(
+	ctx context.Context,
+	req models.RefreshUserTokenRequest,
+) (models.CreateUserTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return models.CreateUserTokenResponse{}, ErrSessionNotFound
+	}
+
+	refreshToken, refreshTokenHash, err := generateRefreshToken()
+	if err != nil {
+		return models.CreateUserTokenResponse{}, err
+	}
+
+	session, err := s.storage.RotateSessionRefreshToken(
+		ctx,
+		hashRefreshToken(req.RefreshToken),
+		refreshTokenHash,
+		time.Now().Add(s.settings.Tokens.RefreshTTL),
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return models.CreateUserTokenResponse{}, ErrSessionNotFound
+		}
+
+		return models.CreateUserTokenResponse{}, fmt.Errorf("failed to rotate refresh token %w", err)
+	}
+
+	return s.buildTokenResponse(session, refreshToken)
+}
+
+// LogoutUser отозвать текущую сессию пользователя.
+func (s *Services) LogoutUser(ctx context.Context) error {
+	userID, _ := ctx.Value(constants.KeyUserID).(int)
+	sessionID, _ := ctx.Value(constants.KeySessionID).(string)
+
+	if err := s.storage.RevokeSession(ctx, userID, sessionID); err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+
+		return fmt.Errorf("failed to revoke session %w", err)
+	}
+
+	return nil
+}
+
+// GetSessions получить активные сессии пользователя.
+func (s *Services) GetSessions(ctx context.Context) ([]models.Session, error) {
+	userID, _ := ctx.Value(constants.KeyUserID).(int)
+	currentSessionID, _ := ctx.Value(constants.KeySessionID).(string)
+
+	sessions, err := s.storage.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions %w", err)
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession отозвать сессию пользователя по идентификатору.
+func (s *Services) RevokeSession(ctx context.Context, sessionID string) error {
+	userID, _ := ctx.Value(constants.KeyUserID).(int)
+
+	if err := s.storage.RevokeSession(ctx, userID, sessionID); err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+
+		return fmt.Errorf("failed to revoke session %w", err)
+	}
+
+	return nil
+}
+
+// createSession создать сессию пользователя и выдать для нее токены.
+func (s *Services) createSession(
+	ctx context.Context,
+	userID int,
+	req models.CreateUserTokenRequest,
+) (models.CreateUserTokenResponse, error) {
+	refreshToken, refreshTokenHash, err := generateRefreshToken()
+	if err != nil {
+		return models.CreateUserTokenResponse{}, err
+	}
+
+	ip, _ := ctx.Value(constants.KeyClientIP).(string)
+
+	session := models.Session{
+		ID:            uuid.NewString(),
+		UserID:        userID,
+		ExpiresAt:     time.Now().Add(s.settings.Tokens.RefreshTTL),
+		DeviceName:    truncate(req.DeviceName, maxDeviceNameSize),
+		ClientVersion: truncate(req.ClientVersion, maxClientVersionSize),
+		IP:            ip,
+	}
+
+	if err := s.storage.AddSession(ctx, session, refreshTokenHash); err != nil {
+		return models.CreateUserTokenResponse{}, fmt.Errorf("failed to add session %w", err)
+	}
+
+	return s.buildTokenResponse(session, refreshToken)
+}
+
+func (s *Services) buildTokenResponse(
+	session models.Session,
+	refreshToken string,
+) (models.CreateUserTokenResponse, error) {
+	authToken, err := buildJWTString(s.settings.JWTKey, s.settings.Tokens.AccessTTL, session)
+	if err != nil {
+		return models.CreateUserTokenResponse{}, fmt.Errorf("failed to build auth token: %w", err)
+	}
+
+	return models.CreateUserTokenResponse{
+		AuthToken:    authToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.settings.Tokens.AccessTTL.Seconds()),
+	}, nil
+}
+
+func buildJWTString(jwtKey string, ttl time.Duration, session models.Session) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		SessionID: session.ID,
+		UserID:    session.UserID,
+	})
+
+	tokenString, err := token.SignedString([]byte(jwtKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to signed token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// generateRefreshToken создает refresh токен и его хеш, который хранится в БД вместо токена.
+func generateRefreshToken() (string, []byte, error) {
+	b := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+// truncate обрезает строку до заданного количества символов.
+func truncate(s string, size int) string {
+	r := []rune(s)
+	if len(r) <= size {
+		return s
+	}
+
+	return string(r[:size])
+}
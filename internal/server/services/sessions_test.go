@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenSettings() *config.Settings {
+	return &config.Settings{
+		JWTKey: "jwt key",
+		Tokens: config.TokenSettings{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: time.Hour,
+		},
+	}
+}
+
+func parseTestToken(t *testing.T, settings *config.Settings, tokenString string) *models.Claims {
+	t.Helper()
+
+	claims := &models.Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(settings.JWTKey), nil
+	}, jwt.WithExpirationRequired())
+	require.NoError(t, err)
+
+	return claims
+}
+
+func TestCreateUserTokenSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := newTokenSettings()
+	s := NewServices(store, fs, crypter, settings)
+	ctx := context.Background()
+
+	user := models.User{
+		ID:       1,
+		Login:    "test",
+		Password: []byte("$2a$10$eqoHdZljD4bk/zPKKGAPre6Mmq2mj8XxSrjF4SpavRy.pT/uxijYa"),
+	}
+
+	var (
+		session     models.Session
+		refreshHash []byte
+	)
+
+	ctx = context.WithValue(ctx, constants.KeyClientIP, "192.0.2.1")
+
+	store.EXPECT().GetUserByLogin(ctx, "test").Times(1).Return(user, nil)
+	store.EXPECT().AddSession(ctx, gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, sn models.Session, hash []byte) error {
+			session = sn
+			refreshHash = hash
+			return nil
+		})
+
+	resp, err := s.CreateUserToken(ctx, models.CreateUserTokenRequest{
+		Login:         "test",
+		Password:      "test",
+		DeviceName:    "laptop",
+		ClientVersion: "0.0.1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, user.ID, session.UserID)
+	assert.Equal(t, "laptop", session.DeviceName)
+	assert.Equal(t, "0.0.1", session.ClientVersion)
+	assert.Equal(t, "192.0.2.1", session.IP)
+	assert.WithinDuration(t, time.Now().Add(settings.Tokens.RefreshTTL), session.ExpiresAt, time.Minute)
+	assert.Equal(t, hashRefreshToken(resp.RefreshToken), refreshHash)
+	assert.Equal(t, int(settings.Tokens.AccessTTL.Seconds()), resp.ExpiresIn)
+
+	claims := parseTestToken(t, settings, resp.AuthToken)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, session.ID, claims.SessionID)
+	assert.NotEmpty(t, claims.ID)
+	assert.NotNil(t, claims.IssuedAt)
+}
+
+func TestRefreshUserToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := newTokenSettings()
+	s := NewServices(store, fs, crypter, settings)
+	ctx := context.Background()
+
+	refreshToken := "refresh token"
+	session := models.Session{ID: "session", UserID: 1}
+
+	t.Run("refresh user token success", func(t *testing.T) {
+		store.EXPECT().
+			RotateSessionRefreshToken(ctx, hashRefreshToken(refreshToken), gomock.Any(), gomock.Any()).
+			Times(1).Return(session, nil)
+
+		resp, err := s.RefreshUserToken(ctx, models.RefreshUserTokenRequest{RefreshToken: refreshToken})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, refreshToken, resp.RefreshToken)
+		claims := parseTestToken(t, settings, resp.AuthToken)
+		assert.Equal(t, session.ID, claims.SessionID)
+		assert.Equal(t, session.UserID, claims.UserID)
+	})
+
+	t.Run("when session not found", func(t *testing.T) {
+		store.EXPECT().
+			RotateSessionRefreshToken(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(models.Session{}, storage.ErrSessionNotFound)
+
+		_, err := s.RefreshUserToken(ctx, models.RefreshUserTokenRequest{RefreshToken: refreshToken})
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("when refresh token empty", func(t *testing.T) {
+		store.EXPECT().RotateSessionRefreshToken(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.RefreshUserToken(ctx, models.RefreshUserTokenRequest{})
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("when rotate refresh token failed", func(t *testing.T) {
+		store.EXPECT().
+			RotateSessionRefreshToken(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).Return(models.Session{}, errors.New("some error"))
+
+		_, err := s.RefreshUserToken(ctx, models.RefreshUserTokenRequest{RefreshToken: refreshToken})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to rotate refresh token")
+	})
+}
+
+func TestLogoutUser(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	s := NewServices(store, fs, crypter, newTokenSettings())
+
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	ctx = context.WithValue(ctx, constants.KeySessionID, "session")
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr error
+	}{
+		{
+			name:    "logout user success",
+			sErr:    nil,
+			wantErr: nil,
+		},
+		{
+			name:    "when session already revoked",
+			sErr:    storage.ErrSessionNotFound,
+			wantErr: ErrSessionNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().RevokeSession(ctx, 1, "session").Times(1).Return(test.sErr)
+
+			err := s.LogoutUser(ctx)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	s := NewServices(store, fs, crypter, newTokenSettings())
+
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	ctx = context.WithValue(ctx, constants.KeySessionID, "current")
+
+	t.Run("get sessions success", func(t *testing.T) {
+		store.EXPECT().ListSessions(ctx, 1).Times(1).
+			Return([]models.Session{{ID: "current"}, {ID: "other"}}, nil)
+
+		sessions, err := s.GetSessions(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.Session{{ID: "current", Current: true}, {ID: "other"}}, sessions)
+	})
+
+	t.Run("get sessions failed", func(t *testing.T) {
+		store.EXPECT().ListSessions(ctx, 1).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.GetSessions(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to list sessions")
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	s := NewServices(store, fs, crypter, newTokenSettings())
+
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr error
+	}{
+		{
+			name:    "revoke session success",
+			sErr:    nil,
+			wantErr: nil,
+		},
+		{
+			name:    "when session not found",
+			sErr:    storage.ErrSessionNotFound,
+			wantErr: ErrSessionNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().RevokeSession(ctx, 1, "other").Times(1).Return(test.sErr)
+
+			err := s.RevokeSession(ctx, "other")
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, "ab", truncate("abc", 2))
+	assert.Equal(t, "пр", truncate("привет", 2))
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+)
+
+const trashPurgeBatchSize = 100
+
+// FetchTrash функция для получения данных пользователя в корзине.
+func (s *Services) FetchTrash(ctx context.Context) ([]models.TrashItem, error) {
+	items, err := s.storage.FetchTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trash: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreTrash функция для восстановления данных пользователя из корзины.
+func (s *Services) RestoreTrash(ctx context.Context, id int) error {
+	if err := s.storage.RestoreUserData(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrUserDataNotFound) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("failed to restore user data: %w", err)
+	}
+
+	return nil
+}
+
+// EmptyTrash функция для окончательного удаления всех данных пользователя из корзины.
+// Возвращает количество удаленных записей.
+func (s *Services) EmptyTrash(ctx context.Context) (int, error) {
+	return s.purgeTrash(ctx, time.Now())
+}
+
+// PurgeTrash функция для окончательного удаления данных всех пользователей,
+// которые находятся в корзине дольше срока хранения. Отметки об удалении старше срока хранения
+// тоже удаляются: клиенты, не синхронизировавшиеся дольше, получат все данные заново.
+// Возвращает количество удаленных записей.
+func (s *Services) PurgeTrash(ctx context.Context) (int, error) {
+	before := time.Now().Add(-s.settings.Trash.Retention)
+
+	var count, afterID int
+
+	for {
+		ids, err := s.storage.FetchUserIDs(ctx, afterID, trashPurgeBatchSize)
+		if err != nil {
+			return count, fmt.Errorf("failed to fetch users %w", err)
+		}
+
+		for _, id := range ids {
+			afterID = id
+			userCtx := context.WithValue(ctx, constants.KeyUserID, id)
+
+			n, err := s.purgeTrash(userCtx, before)
+			count += n
+			if err != nil {
+				return count, fmt.Errorf("failed to purge trash of user %d: %w", id, err)
+			}
+
+			if _, err := s.storage.PruneUserDataTombstones(userCtx, before); err != nil {
+				return count, fmt.Errorf("failed to prune tombstones of user %d: %w", id, err)
+			}
+		}
+
+		if len(ids) < trashPurgeBatchSize {
+			return count, nil
+		}
+	}
+}
+
+// purgeTrash окончательно удаляет данные пользователя из контекста, удаленные в корзину не позже before,
+// и освобождает объекты удаленных файлов и их прежних версий. Данные, восстановленные во время удаления,
+// пропускаются.
+func (s *Services) purgeTrash(ctx context.Context, before time.Time) (int, error) {
+	data, err := s.storage.FetchTrashedUserData(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch trashed user data %w", err)
+	}
+
+	var history map[int][]string
+
+	count := 0
+
+	for _, d := range data {
+		var objectNames []string
+		if d.Type == fileDataType {
+			if history == nil {
+				if history, err = s.fileHistoryObjects(ctx); err != nil {
+					return count, err
+				}
+			}
+
+			fileData, err := s.decryptFileData(ctx, d.Data)
+			if err != nil {
+				return count, err
+			}
+			objectNames = append([]string{fileObjectName(fileData)}, history[d.ID]...)
+		}
+
+		deleted, err := s.storage.PurgeUserData(ctx, d.ID, before)
+		if err != nil {
+			return count, fmt.Errorf("failed to purge user data %w", err)
+		}
+		if !deleted {
+			continue
+		}
+		count++
+
+		// Объект, который не удалось удалить, останется потерянным и будет удален сборщиком мусора.
+		for _, name := range objectNames {
+			if err := s.deleteFileObject(ctx, name); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, nil
+}
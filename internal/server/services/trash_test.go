@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+
+	ctx := context.Background()
+	items := []models.TrashItem{{ID: 1, Type: "card", Mark: "mark"}}
+
+	t.Run("success fetch trash", func(t *testing.T) {
+		store.EXPECT().FetchTrash(ctx).Times(1).Return(items, nil)
+
+		resp, err := s.FetchTrash(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, items, resp)
+	})
+
+	t.Run("fetch trash failed", func(t *testing.T) {
+		store.EXPECT().FetchTrash(ctx).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.FetchTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch trash")
+	})
+}
+
+func TestRestoreTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		sErr    error
+		wantErr error
+		errText string
+	}{
+		{
+			name: "success restore",
+		},
+		{
+			name:    "when user data not in trash",
+			sErr:    storage.ErrUserDataNotFound,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "restore failed",
+			sErr:    errors.New("some error"),
+			errText: "failed to restore user data",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().RestoreUserData(ctx, 1).Times(1).Return(test.sErr)
+
+			err := s.RestoreTrash(ctx, 1)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+	expectDataKey(store, crypter)
+
+	ctx := context.Background()
+	fileData := []byte("file data")
+	trashed := []models.TrashedUserData{
+		{ID: 1, Type: "card", Data: []byte("card data")},
+		{ID: 2, Type: "file", Data: fileData},
+	}
+
+	t.Run("empty trash with files", func(t *testing.T) {
+		store.EXPECT().FetchTrashedUserData(ctx, gomock.Any()).Times(1).Return(trashed, nil)
+		store.EXPECT().FetchFileUserDataHistory(ctx).Times(1).Return([]models.FileUserData{
+			{ID: 2, Data: []byte("old file data")},
+			{ID: 5, Data: []byte("other file data")},
+		}, nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), []byte("old file data")).Times(1).
+			Return([]byte(`{"file_name":"test","object_name":"old-object"}`), nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), []byte("other file data")).Times(1).
+			Return([]byte(`{"file_name":"other","object_name":"other-object"}`), nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), fileData).Times(1).
+			Return([]byte(`{"file_name":"test","object_name":"object"}`), nil)
+		store.EXPECT().PurgeUserData(ctx, 1, gomock.Any()).Times(1).Return(true, nil)
+		store.EXPECT().PurgeUserData(ctx, 2, gomock.Any()).Times(1).Return(true, nil)
+		fs.EXPECT().DeleteFile(ctx, "object").Times(1).Return(nil)
+		fs.EXPECT().DeleteFile(ctx, "old-object").Times(1).Return(nil)
+
+		count, err := s.EmptyTrash(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("file restored while purge", func(t *testing.T) {
+		store.EXPECT().FetchTrashedUserData(ctx, gomock.Any()).Times(1).Return(trashed[1:], nil)
+		store.EXPECT().FetchFileUserDataHistory(ctx).Times(1).Return([]models.FileUserData{}, nil)
+		crypter.EXPECT().DecryptData(gomock.Any(), fileData).Times(1).
+			Return([]byte(`{"file_name":"test","object_name":"object"}`), nil)
+		store.EXPECT().PurgeUserData(ctx, 2, gomock.Any()).Times(1).Return(false, nil)
+
+		count, err := s.EmptyTrash(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("fetch file history failed", func(t *testing.T) {
+		store.EXPECT().FetchTrashedUserData(ctx, gomock.Any()).Times(1).Return(trashed[1:], nil)
+		store.EXPECT().FetchFileUserDataHistory(ctx).Times(1).Return(nil, errors.New("some error"))
+		store.EXPECT().PurgeUserData(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.EmptyTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch file history")
+	})
+
+	t.Run("purge failed", func(t *testing.T) {
+		store.EXPECT().FetchTrashedUserData(ctx, gomock.Any()).Times(1).Return(trashed[:1], nil)
+		store.EXPECT().PurgeUserData(ctx, 1, gomock.Any()).Times(1).Return(false, errors.New("some error"))
+
+		_, err := s.EmptyTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to purge user data")
+	})
+
+	t.Run("fetch trashed user data failed", func(t *testing.T) {
+		store.EXPECT().FetchTrashedUserData(ctx, gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.EmptyTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch trashed user data")
+	})
+}
+
+func TestPurgeTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	settings := config.Settings{Trash: config.TrashSettings{Retention: time.Hour}}
+	s := NewServices(store, mocks.NewMockFileStorager(mockCtrl), mocks.NewMockCrypter(mockCtrl), &settings)
+
+	ctx := context.Background()
+
+	t.Run("purge expired trash", func(t *testing.T) {
+		start := time.Now()
+
+		store.EXPECT().FetchUserIDs(ctx, 0, trashPurgeBatchSize).Times(1).Return([]int{1}, nil)
+		store.EXPECT().FetchTrashedUserData(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, before time.Time) ([]models.TrashedUserData, error) {
+				assert.WithinDuration(t, start.Add(-time.Hour), before, time.Minute)
+				return []models.TrashedUserData{{ID: 3, Type: "text"}}, nil
+			})
+		store.EXPECT().PurgeUserData(gomock.Any(), 3, gomock.Any()).Times(1).Return(true, nil)
+		store.EXPECT().PruneUserDataTombstones(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(ctx context.Context, before time.Time) (int, error) {
+				assert.Equal(t, 1, ctx.Value(constants.KeyUserID))
+				assert.WithinDuration(t, start.Add(-time.Hour), before, time.Minute)
+				return 2, nil
+			})
+
+		count, err := s.PurgeTrash(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("prune tombstones failed", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, trashPurgeBatchSize).Times(1).Return([]int{1}, nil)
+		store.EXPECT().FetchTrashedUserData(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+		store.EXPECT().PruneUserDataTombstones(gomock.Any(), gomock.Any()).Times(1).Return(0, errors.New("some error"))
+
+		_, err := s.PurgeTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to prune tombstones of user 1")
+	})
+
+	t.Run("purge trash of user failed", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, trashPurgeBatchSize).Times(1).Return([]int{1}, nil)
+		store.EXPECT().FetchTrashedUserData(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.PurgeTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to purge trash of user 1")
+	})
+
+	t.Run("fetch users failed", func(t *testing.T) {
+		store.EXPECT().FetchUserIDs(ctx, 0, trashPurgeBatchSize).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := s.PurgeTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to fetch users")
+	})
+}
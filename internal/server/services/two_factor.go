@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/MihailSergeenkov/GophKeeper/internal/totp"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two factor authentication already enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two factor authentication not enrolled")
+	ErrTwoFactorCode        = errors.New("invalid two factor code")
+)
+
+const (
+	twoFactorIssuer   = "GophKeeper"
+	twoFactorPurpose  = "2fa"
+	twoFactorTokenTTL = 5 * time.Minute
+
+	recoveryCodesCount = 10
+	recoveryCodeSize   = 10 // 16 символов base32
+	recoveryCodeGroup  = 4
+)
+
+// EnrollTwoFactor создать секрет для приложения-аутентификатора.
+// Двухфакторная аутентификация включается только после подтверждения кодом.
+func (s *Services) EnrollTwoFactor(ctx context.Context) (models.EnrollTwoFactorResponse, error) {
+	userID, _ := ctx.Value(constants.KeyUserID).(int)
+
+	user, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.EnrollTwoFactorResponse{}, fmt.Errorf("failed to get user from DB %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return models.EnrollTwoFactorResponse{}, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return models.EnrollTwoFactorResponse{}, err //nolint:wrapcheck // Ошибка уже обернута
+	}
+
+	encSecret, err := s.encryptData(ctx, secret)
+	if err != nil {
+		return models.EnrollTwoFactorResponse{}, err
+	}
+
+	ok, err := s.storage.SetUserTwoFactorSecret(ctx, encSecret)
+	if err != nil {
+		return models.EnrollTwoFactorResponse{}, fmt.Errorf("failed to set totp secret %w", err)
+	}
+	if !ok {
+		return models.EnrollTwoFactorResponse{}, ErrTwoFactorEnabled
+	}
+
+	return models.EnrollTwoFactorResponse{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(twoFactorIssuer, user.Login, secret),
+	}, nil
+}
+
+// VerifyTwoFactor подтвердить подключение двухфакторной аутентификации и выдать коды восстановления.
+func (s *Services) VerifyTwoFactor(
+	ctx context.Context,
+	req models.VerifyTwoFactorRequest,
+) (models.VerifyTwoFactorResponse, error) {
+	tf, err := s.storage.GetUserTwoFactor(ctx)
+	if err != nil {
+		return models.VerifyTwoFactorResponse{}, fmt.Errorf("failed to get two factor %w", err)
+	}
+
+	if tf.Enabled {
+		return models.VerifyTwoFactorResponse{}, ErrTwoFactorEnabled
+	}
+	if tf.Secret == nil {
+		return models.VerifyTwoFactorResponse{}, ErrTwoFactorNotEnrolled
+	}
+
+	secret, err := s.decryptData(ctx, tf.Secret)
+	if err != nil {
+		return models.VerifyTwoFactorResponse{}, err
+	}
+
+	step, ok := totp.Validate(secret, req.Code, time.Now())
+	if !ok {
+		return models.VerifyTwoFactorResponse{}, ErrTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return models.VerifyTwoFactorResponse{}, err
+	}
+
+	ok, err = s.storage.EnableUserTwoFactor(ctx, step, hashes)
+	if err != nil {
+		return models.VerifyTwoFactorResponse{}, fmt.Errorf("failed to enable two factor %w", err)
+	}
+	if !ok {
+		return models.VerifyTwoFactorResponse{}, ErrTwoFactorEnabled
+	}
+
+	return models.VerifyTwoFactorResponse{RecoveryCodes: codes}, nil
+}
+
+// LoginTwoFactor второй шаг получения ключа доступа: проверка одноразового пароля или кода восстановления.
+func (s *Services) LoginTwoFactor(
+	ctx context.Context,
+	req models.LoginTwoFactorRequest,
+) (models.CreateUserTokenResponse, error) {
+	userID, err := parseTwoFactorToken(s.settings.JWTKey, req.TwoFactorToken)
+	if err != nil {
+		return models.CreateUserTokenResponse{}, ErrUserLoginCreds
+	}
+
+	ctx = context.WithValue(ctx, constants.KeyUserID, userID)
+	attemptKey := twoFactorAttemptKey(userID)
+
+	if err := s.checkAttempts(ctx, attemptKey); err != nil {
+		return models.CreateUserTokenResponse{}, err
+	}
+
+	tf, err := s.storage.GetUserTwoFactor(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return models.CreateUserTokenResponse{}, ErrUserLoginCreds
+		}
+
+		return models.CreateUserTokenResponse{}, fmt.Errorf("failed to get two factor %w", err)
+	}
+	if !tf.Enabled {
+		return models.CreateUserTokenResponse{}, ErrUserLoginCreds
+	}
+
+	if err := s.checkTwoFactorCode(ctx, tf, req.Code); err != nil {
+		if errors.Is(err, ErrTwoFactorCode) {
+			s.failAttempt(ctx, attemptKey)
+		}
+
+		return models.CreateUserTokenResponse{}, err
+	}
+
+	s.loginAttempts.Reset(attemptKey)
+
+	return s.createSession(ctx, userID, models.CreateUserTokenRequest{
+		DeviceName:    req.DeviceName,
+		ClientVersion: req.ClientVersion,
+	})
+}
+
+// checkTwoFactorCode проверить одноразовый пароль или код восстановления, каждый из них принимается один раз.
+func (s *Services) checkTwoFactorCode(ctx context.Context, tf models.TwoFactor, code string) error {
+	if recoveryCode, ok := normalizeRecoveryCode(code); ok {
+		err := s.storage.UseRecoveryCode(ctx, hashRecoveryCode(recoveryCode))
+		if err != nil {
+			if errors.Is(err, storage.ErrRecoveryCodeUsed) {
+				return ErrTwoFactorCode
+			}
+
+			return fmt.Errorf("failed to use recovery code %w", err)
+		}
+
+		return nil
+	}
+
+	secret, err := s.decryptData(ctx, tf.Secret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok || step <= tf.LastStep {
+		return ErrTwoFactorCode
+	}
+
+	ok, err = s.storage.UpdateUserTwoFactorStep(ctx, step)
+	if err != nil {
+		return fmt.Errorf("failed to update totp step %w", err)
+	}
+	if !ok {
+		return ErrTwoFactorCode
+	}
+
+	return nil
+}
+
+// buildTwoFactorToken создать короткоживущий токен для второго шага входа.
+func buildTwoFactorToken(jwtKey string, userID int) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(twoFactorTokenTTL)),
+		},
+		Purpose: twoFactorPurpose,
+		UserID:  userID,
+	})
+
+	tokenString, err := token.SignedString([]byte(jwtKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to signed token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// parseTwoFactorToken проверить токен второго шага входа и получить ID пользователя.
+func parseTwoFactorToken(jwtKey, tokenString string) (int, error) {
+	claims := &models.Claims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(jwtKey), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if claims.Purpose != twoFactorPurpose {
+		return 0, errors.New("token is not two factor token")
+	}
+
+	return claims.UserID, nil
+}
+
+// generateRecoveryCodes создать коды восстановления и их хеши для хранения в БД.
+func generateRecoveryCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([][]byte, 0, recoveryCodesCount)
+
+	for range recoveryCodesCount {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+		hashes = append(hashes, hashRecoveryCode(code))
+
+		groups := make([]string, 0, len(code)/recoveryCodeGroup)
+		for i := 0; i < len(code); i += recoveryCodeGroup {
+			groups = append(groups, code[i:i+recoveryCodeGroup])
+		}
+		codes = append(codes, strings.Join(groups, "-"))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode привести код восстановления к виду без разделителей.
+// Возвращает false, если строка не похожа на код восстановления.
+func normalizeRecoveryCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	if len(code) != base32.StdEncoding.WithPadding(base32.NoPadding).EncodedLen(recoveryCodeSize) {
+		return "", false
+	}
+
+	return code, true
+}
+
+func hashRecoveryCode(code string) []byte {
+	hash := sha256.Sum256([]byte(code))
+	return hash[:]
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/MihailSergeenkov/GophKeeper/internal/totp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTOTPSecret = []byte("12345678901234567890")
+
+// expectPlainCrypter шифрование в тестах возвращает данные без изменений.
+func expectPlainCrypter(store *mocks.MockStorager, crypter *mocks.MockCrypter) {
+	expectDataKey(store, crypter)
+	crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_, data []byte) ([]byte, error) { return data, nil })
+	crypter.EXPECT().DecryptData(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_, data []byte) ([]byte, error) { return data, nil })
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	s := NewServices(store, fs, crypter, newTokenSettings())
+	expectPlainCrypter(store, crypter)
+
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+
+	t.Run("enroll success", func(t *testing.T) {
+		var secret []byte
+
+		store.EXPECT().GetUserByID(ctx, 1).Times(1).Return(models.User{ID: 1, Login: "user"}, nil)
+		store.EXPECT().SetUserTwoFactorSecret(ctx, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, s []byte) (bool, error) {
+				secret = s
+				return true, nil
+			})
+
+		resp, err := s.EnrollTwoFactor(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, totp.EncodeSecret(secret), resp.Secret)
+		assert.True(t, strings.HasPrefix(resp.URI, "otpauth://totp/GophKeeper:user?"))
+	})
+
+	t.Run("when two factor already enabled", func(t *testing.T) {
+		store.EXPECT().GetUserByID(ctx, 1).Times(1).Return(models.User{ID: 1, TOTPEnabled: true}, nil)
+		store.EXPECT().SetUserTwoFactorSecret(ctx, gomock.Any()).Times(0)
+
+		_, err := s.EnrollTwoFactor(ctx)
+
+		require.ErrorIs(t, err, ErrTwoFactorEnabled)
+	})
+
+	t.Run("when two factor enabled concurrently", func(t *testing.T) {
+		store.EXPECT().GetUserByID(ctx, 1).Times(1).Return(models.User{ID: 1}, nil)
+		store.EXPECT().SetUserTwoFactorSecret(ctx, gomock.Any()).Times(1).Return(false, nil)
+
+		_, err := s.EnrollTwoFactor(ctx)
+
+		require.ErrorIs(t, err, ErrTwoFactorEnabled)
+	})
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	s := NewServices(store, fs, crypter, newTokenSettings())
+	expectPlainCrypter(store, crypter)
+
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	enrolled := models.TwoFactor{Secret: testTOTPSecret}
+
+	t.Run("verify success", func(t *testing.T) {
+		var hashes [][]byte
+
+		store.EXPECT().GetUserTwoFactor(ctx).Times(1).Return(enrolled, nil)
+		store.EXPECT().EnableUserTwoFactor(ctx, gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, h [][]byte) (bool, error) {
+				hashes = h
+				return true, nil
+			})
+
+		resp, err := s.VerifyTwoFactor(ctx, models.VerifyTwoFactorRequest{Code: totp.Code(testTOTPSecret, time.Now())})
+
+		require.NoError(t, err)
+		require.Len(t, resp.RecoveryCodes, recoveryCodesCount)
+		require.Len(t, hashes, recoveryCodesCount)
+
+		code, ok := normalizeRecoveryCode(resp.RecoveryCodes[0])
+		require.True(t, ok)
+		assert.Equal(t, hashRecoveryCode(code), hashes[0])
+	})
+
+	t.Run("when code invalid", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(ctx).Times(1).Return(enrolled, nil)
+		store.EXPECT().EnableUserTwoFactor(ctx, gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.VerifyTwoFactor(ctx, models.VerifyTwoFactorRequest{Code: "000000x"})
+
+		require.ErrorIs(t, err, ErrTwoFactorCode)
+	})
+
+	t.Run("when not enrolled", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(ctx).Times(1).Return(models.TwoFactor{}, nil)
+
+		_, err := s.VerifyTwoFactor(ctx, models.VerifyTwoFactorRequest{Code: "123456"})
+
+		require.ErrorIs(t, err, ErrTwoFactorNotEnrolled)
+	})
+
+	t.Run("when already enabled", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(ctx).Times(1).Return(models.TwoFactor{Secret: testTOTPSecret, Enabled: true}, nil)
+
+		_, err := s.VerifyTwoFactor(ctx, models.VerifyTwoFactorRequest{Code: "123456"})
+
+		require.ErrorIs(t, err, ErrTwoFactorEnabled)
+	})
+}
+
+func TestCreateUserTokenTwoFactorRequired(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := newTokenSettings()
+	s := NewServices(store, fs, crypter, settings)
+	ctx := context.Background()
+
+	user := models.User{
+		ID:          1,
+		Login:       "test",
+		Password:    []byte("$2a$10$eqoHdZljD4bk/zPKKGAPre6Mmq2mj8XxSrjF4SpavRy.pT/uxijYa"),
+		TOTPEnabled: true,
+	}
+
+	store.EXPECT().GetUserByLogin(ctx, "test").Times(1).Return(user, nil)
+	store.EXPECT().AddSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.CreateUserToken(ctx, models.CreateUserTokenRequest{Login: "test", Password: "test"})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.AuthToken)
+	assert.Empty(t, resp.RefreshToken)
+
+	userID, err := parseTwoFactorToken(settings.JWTKey, resp.TwoFactorToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, userID)
+
+	claims := parseTestToken(t, settings, resp.TwoFactorToken)
+	assert.Empty(t, claims.SessionID, "two factor token must not be accepted as access token")
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := newTokenSettings()
+	s := NewServices(store, fs, crypter, settings)
+	expectPlainCrypter(store, crypter)
+
+	ctx := context.Background()
+	userCtx := context.WithValue(ctx, constants.KeyUserID, 1)
+
+	twoFactorToken, err := buildTwoFactorToken(settings.JWTKey, 1)
+	require.NoError(t, err)
+
+	enabled := models.TwoFactor{Secret: testTOTPSecret, Enabled: true}
+	now := time.Now()
+	code := totp.Code(testTOTPSecret, now)
+	step := totp.Step(now)
+
+	t.Run("login with totp code", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(userCtx).Times(1).Return(enabled, nil)
+		store.EXPECT().UpdateUserTwoFactorStep(userCtx, step).Times(1).Return(true, nil)
+		store.EXPECT().AddSession(userCtx, gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, session models.Session, _ []byte) error {
+				assert.Equal(t, 1, session.UserID)
+				assert.Equal(t, "laptop", session.DeviceName)
+				return nil
+			})
+
+		resp, err := s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{
+			TwoFactorToken: twoFactorToken,
+			Code:           code,
+			DeviceName:     "laptop",
+		})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.AuthToken)
+	})
+
+	t.Run("when totp code already used", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(userCtx).Times(1).
+			Return(models.TwoFactor{Secret: testTOTPSecret, Enabled: true, LastStep: step + 1}, nil)
+		store.EXPECT().UpdateUserTwoFactorStep(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{TwoFactorToken: twoFactorToken, Code: code})
+
+		require.ErrorIs(t, err, ErrTwoFactorCode)
+	})
+
+	t.Run("when totp code used concurrently", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(userCtx).Times(1).Return(enabled, nil)
+		store.EXPECT().UpdateUserTwoFactorStep(userCtx, step).Times(1).Return(false, nil)
+
+		_, err := s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{TwoFactorToken: twoFactorToken, Code: code})
+
+		require.ErrorIs(t, err, ErrTwoFactorCode)
+	})
+
+	t.Run("login with recovery code", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(userCtx).Times(1).Return(enabled, nil)
+		store.EXPECT().UseRecoveryCode(userCtx, hashRecoveryCode("ABCDEFGHIJKLMNOP")).Times(1).Return(nil)
+		store.EXPECT().AddSession(userCtx, gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+		_, err := s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{
+			TwoFactorToken: twoFactorToken,
+			Code:           "abcd-efgh-ijkl-mnop",
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("when recovery code already used", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(userCtx).Times(1).Return(enabled, nil)
+		store.EXPECT().UseRecoveryCode(userCtx, gomock.Any()).Times(1).Return(storage.ErrRecoveryCodeUsed)
+
+		_, err := s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{
+			TwoFactorToken: twoFactorToken,
+			Code:           "ABCD-EFGH-IJKL-MNOP",
+		})
+
+		require.ErrorIs(t, err, ErrTwoFactorCode)
+	})
+
+	t.Run("when recovery code storage failed", func(t *testing.T) {
+		store.EXPECT().GetUserTwoFactor(userCtx).Times(1).Return(enabled, nil)
+		store.EXPECT().UseRecoveryCode(userCtx, gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{
+			TwoFactorToken: twoFactorToken,
+			Code:           "ABCD-EFGH-IJKL-MNOP",
+		})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to use recovery code")
+	})
+
+	t.Run("when two factor token invalid", func(t *testing.T) {
+		accessToken, err := buildJWTString(settings.JWTKey, time.Minute, models.Session{ID: "session", UserID: 1})
+		require.NoError(t, err)
+
+		_, err = s.LoginTwoFactor(ctx, models.LoginTwoFactorRequest{TwoFactorToken: accessToken, Code: code})
+
+		require.ErrorIs(t, err, ErrUserLoginCreds)
+	})
+}
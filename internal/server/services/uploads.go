@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	// UploadPartSize размер части файла при загрузке по частям. Меньше может быть только последняя часть.
+	UploadPartSize int64 = 8 << 20
+	// maxUploadParts максимальное количество частей в загрузке S3.
+	maxUploadParts = 10000
+)
+
+var (
+	ErrInvalidUploadPart = errors.New("invalid upload part")
+	ErrInvalidFileSize   = errors.New("invalid file size")
+	ErrUploadIncomplete  = errors.New("file upload is incomplete")
+)
+
+// InitFileUpload функция для начала загрузки файла пользователя по частям.
+// Папка и метки запоминаются вместе с загрузкой и задаются файлу при ее завершении.
+// Зарезервированный ID файла переходит к загрузке, и файл получает его при завершении.
+func (s *Services) InitFileUpload(
+	ctx context.Context,
+	req models.InitFileUploadRequest,
+) (models.FileUploadStatus, error) {
+	var resp models.FileUploadStatus
+
+	if err := validateAddFileRequest(models.AddFileRequest{Mark: req.Mark, Description: req.Description}); err != nil {
+		return resp, failedValidateFields(err)
+	}
+	if req.FileName == "" || req.FileSize <= 0 || req.FileSize > UploadPartSize*maxUploadParts {
+		return resp, failedValidateFields(ErrInvalidFileSize)
+	}
+	labels, err := newAddedUserDataLabels(req.Folder, req.Tags)
+	if err != nil {
+		return resp, failedValidateFields(err)
+	}
+	if err := s.checkFileSize(ctx, 1, req.FileSize); err != nil {
+		return resp, err
+	}
+
+	objectName := uuid.NewString()
+
+	uploadID, err := s.fileStorage.InitUpload(ctx, objectName)
+	if err != nil {
+		return resp, fmt.Errorf("failed to init upload in filestorage %w", err)
+	}
+
+	upload := models.FileUpload{
+		ID:          uuid.NewString(),
+		UploadID:    uploadID,
+		ObjectName:  objectName,
+		FileName:    req.FileName,
+		Mark:        prepareFileMark(req.Mark),
+		Description: req.Description,
+		FileSize:    req.FileSize,
+		DataID:      req.ID,
+	}
+	if labels.Folder != nil {
+		upload.Folder = *labels.Folder
+	}
+	if labels.Tags != nil {
+		upload.Tags = *labels.Tags
+	}
+
+	if err := s.storage.AddFileUpload(ctx, upload); err != nil {
+		if abortErr := s.fileStorage.AbortUpload(ctx, upload.ObjectName, uploadID); abortErr != nil {
+			err = errors.Join(err, abortErr)
+		}
+		if errors.Is(err, storage.ErrDataIDNotReserved) {
+			return resp, ErrNotFound
+		}
+		return resp, fmt.Errorf("failed to add file upload %w", err)
+	}
+
+	resp.UploadID = upload.ID
+	resp.PartSize = UploadPartSize
+	resp.FileSize = upload.FileSize
+	resp.Parts = []models.FileUploadPart{}
+
+	return resp, nil
+}
+
+// GetFileUpload функция для получения состояния загрузки файла по частям.
+func (s *Services) GetFileUpload(ctx context.Context, id string) (models.FileUploadStatus, error) {
+	var resp models.FileUploadStatus
+
+	upload, err := s.getFileUpload(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	parts, err := s.fileStorage.ListUploadParts(ctx, upload.ObjectName, upload.UploadID)
+	if err != nil {
+		return resp, fmt.Errorf("failed to list upload parts from filestorage %w", err)
+	}
+
+	resp.UploadID = upload.ID
+	resp.PartSize = UploadPartSize
+	resp.FileSize = upload.FileSize
+	resp.Parts = parts
+
+	return resp, nil
+}
+
+// UploadFilePart функция для загрузки части файла. Повторная загрузка части с тем же номером заменяет ее.
+// Номер и размер части должны соответствовать размеру файла, заявленному при начале загрузки.
+func (s *Services) UploadFilePart(
+	ctx context.Context,
+	id string,
+	number int,
+	part io.Reader,
+	size int64,
+) (models.FileUploadPart, error) {
+	if number < 1 || number > maxUploadParts || size <= 0 || size > UploadPartSize {
+		return models.FileUploadPart{}, ErrInvalidUploadPart
+	}
+
+	upload, err := s.getFileUpload(ctx, id)
+	if err != nil {
+		return models.FileUploadPart{}, err
+	}
+
+	if size != uploadPartSize(upload.FileSize, number) {
+		return models.FileUploadPart{}, ErrInvalidUploadPart
+	}
+
+	uploaded, err := s.fileStorage.UploadPart(ctx, upload.ObjectName, upload.UploadID, number, part, size)
+	if err != nil {
+		return models.FileUploadPart{}, fmt.Errorf("failed to upload part to filestorage %w", err)
+	}
+
+	return uploaded, nil
+}
+
+// CompleteFileUpload функция для завершения загрузки файла по частям.
+// Файл сохраняется, только если загружены все его байты.
+func (s *Services) CompleteFileUpload(ctx context.Context, id string) (int, error) {
+	upload, err := s.getFileUpload(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	parts, err := s.fileStorage.ListUploadParts(ctx, upload.ObjectName, upload.UploadID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list upload parts from filestorage %w", err)
+	}
+
+	var size int64
+	for _, p := range parts {
+		size += p.Size
+	}
+	if size != upload.FileSize {
+		return 0, fmt.Errorf("%w: uploaded %d of %d bytes", ErrUploadIncomplete, size, upload.FileSize)
+	}
+
+	fileName := upload.FileName
+	if fileName == "" {
+		// Загрузки, начатые до появления отдельных имен объектов, хранятся под именем файла.
+		fileName = upload.ObjectName
+	}
+
+	labels, err := newAddedUserDataLabels(upload.Folder, upload.Tags)
+	if err != nil {
+		return 0, failedValidateFields(err)
+	}
+
+	encData, err := s.encryptFileData(ctx, fileName, upload.ObjectName)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.fileStorage.CompleteUpload(ctx, upload.ObjectName, upload.UploadID, parts); err != nil {
+		return 0, fmt.Errorf("failed to complete upload in filestorage %w", err)
+	}
+
+	// Сессия загрузки удаляется в одной транзакции с добавлением данных: при одновременном завершении
+	// данные файла добавляются только один раз.
+	fileID, err := s.storage.CompleteFileUpload(
+		ctx, upload.ID, encData, upload.Mark, upload.Description, upload.FileSize, labels,
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileUploadNotFound) {
+			// Загрузку завершили одновременно, и объект уже принадлежит сохраненному файлу.
+			return 0, ErrNotFound
+		}
+		if deleteErr := s.deleteFileObject(ctx, upload.ObjectName); deleteErr != nil {
+			err = errors.Join(err, deleteErr)
+		}
+
+		return 0, failedAddUserData(err)
+	}
+
+	return fileID, nil
+}
+
+// AbortFileUpload функция для отмены загрузки файла по частям.
+func (s *Services) AbortFileUpload(ctx context.Context, id string) error {
+	upload, err := s.getFileUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.fileStorage.AbortUpload(ctx, upload.ObjectName, upload.UploadID); err != nil {
+		return fmt.Errorf("failed to abort upload in filestorage %w", err)
+	}
+
+	if err := s.storage.DeleteFileUpload(ctx, upload.ID); err != nil {
+		return fmt.Errorf("failed to delete file upload %w", err)
+	}
+
+	return nil
+}
+
+// uploadPartSize возвращает размер части number при загрузке файла размером fileSize
+// или 0, если у файла нет части с таким номером.
+func uploadPartSize(fileSize int64, number int) int64 {
+	parts := (fileSize + UploadPartSize - 1) / UploadPartSize
+	if number < 1 || int64(number) > parts {
+		return 0
+	}
+
+	return min(UploadPartSize, fileSize-int64(number-1)*UploadPartSize)
+}
+
+// getFileUpload получает сессию загрузки файла пользователя.
+func (s *Services) getFileUpload(ctx context.Context, id string) (models.FileUpload, error) {
+	upload, err := s.storage.GetFileUpload(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileUploadNotFound) {
+			return upload, ErrNotFound
+		}
+
+		return upload, fmt.Errorf("failed to get file upload %w", err)
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUploadServices(t *testing.T) (*Services, *mocks.MockStorager, *mocks.MockFileStorager, *mocks.MockCrypter) {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+
+	return NewServices(store, fs, crypter, &settings), store, fs, crypter
+}
+
+func TestInitFileUpload(t *testing.T) {
+	s, store, fs, _ := newUploadServices(t)
+	ctx := context.Background()
+	someErr := errors.New("some error")
+
+	req := models.InitFileUploadRequest{
+		FileName: "file.bin", Mark: "mark", Description: "desc", Folder: "/work/", Tags: []string{"team", "prod"},
+		FileSize: 100, ID: 7,
+	}
+
+	tests := []struct {
+		name      string
+		req       models.InitFileUploadRequest
+		initTimes int
+		initErr   error
+		addTimes  int
+		addErr    error
+		abortTime int
+		wantErr   error
+		errText   string
+	}{
+		{
+			name:      "init upload success",
+			req:       req,
+			initTimes: 1,
+			addTimes:  1,
+		},
+		{
+			name:    "invalid file size",
+			req:     models.InitFileUploadRequest{FileName: "file.bin", Mark: "mark"},
+			wantErr: ErrInvalidFileSize,
+		},
+		{
+			name: "mark is too big",
+			req: models.InitFileUploadRequest{
+				FileName: "file.bin", Mark: strings.Repeat("a", maxMarkSize+1), FileSize: 100,
+			},
+			wantErr: ErrUserMarkIsTooBig,
+		},
+		{
+			name: "invalid tag",
+			req: models.InitFileUploadRequest{
+				FileName: "file.bin", Mark: "mark", Tags: []string{" "}, FileSize: 100,
+			},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:      "init upload in filestorage failed",
+			req:       req,
+			initTimes: 1,
+			initErr:   someErr,
+			errText:   "failed to init upload in filestorage",
+		},
+		{
+			name:      "save upload failed",
+			req:       req,
+			initTimes: 1,
+			addTimes:  1,
+			addErr:    someErr,
+			abortTime: 1,
+			errText:   "failed to add file upload",
+		},
+		{
+			name:      "data id is not reserved",
+			req:       req,
+			initTimes: 1,
+			addTimes:  1,
+			addErr:    storage.ErrDataIDNotReserved,
+			abortTime: 1,
+			wantErr:   ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var objectName string
+			fs.EXPECT().InitUpload(ctx, gomock.Any()).Times(test.initTimes).
+				DoAndReturn(func(_ context.Context, name string) (string, error) {
+					objectName = name
+					return "s3-upload", test.initErr
+				})
+			store.EXPECT().AddFileUpload(ctx, gomock.Any()).Times(test.addTimes).
+				DoAndReturn(func(_ context.Context, upload models.FileUpload) error {
+					assert.NotEmpty(t, upload.ID)
+					assert.Equal(t, "s3-upload", upload.UploadID)
+					assert.Equal(t, objectName, upload.ObjectName)
+					assert.NotEqual(t, "file.bin", upload.ObjectName)
+					assert.Equal(t, "file.bin", upload.FileName)
+					assert.Equal(t, int64(100), upload.FileSize)
+					assert.Equal(t, "work", upload.Folder)
+					assert.Equal(t, []string{"prod", "team"}, upload.Tags)
+					assert.Equal(t, 7, upload.DataID)
+					return test.addErr
+				})
+			fs.EXPECT().AbortUpload(ctx, gomock.Any(), "s3-upload").Times(test.abortTime).Return(nil)
+
+			resp, err := s.InitFileUpload(ctx, test.req)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.NotEmpty(t, resp.UploadID)
+				assert.Equal(t, UploadPartSize, resp.PartSize)
+				assert.Equal(t, int64(100), resp.FileSize)
+				assert.Empty(t, resp.Parts)
+			}
+		})
+	}
+}
+
+func TestGetFileUploadStatus(t *testing.T) {
+	s, store, fs, _ := newUploadServices(t)
+	ctx := context.Background()
+	upload := models.FileUpload{ID: "upload", UploadID: "s3-upload", ObjectName: "object", FileSize: 100}
+	parts := []models.FileUploadPart{{Number: 1, Size: 60, ETag: "etag"}}
+
+	t.Run("get upload success", func(t *testing.T) {
+		store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(upload, nil)
+		fs.EXPECT().ListUploadParts(ctx, "object", "s3-upload").Times(1).Return(parts, nil)
+
+		resp, err := s.GetFileUpload(ctx, "upload")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.FileUploadStatus{
+			UploadID: "upload", Parts: parts, PartSize: UploadPartSize, FileSize: 100,
+		}, resp)
+	})
+
+	t.Run("upload not found", func(t *testing.T) {
+		store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(models.FileUpload{}, storage.ErrFileUploadNotFound)
+
+		_, err := s.GetFileUpload(ctx, "upload")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestUploadFilePart(t *testing.T) {
+	s, store, fs, _ := newUploadServices(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		number   int
+		size     int64
+		getTimes int
+		putTimes int
+		putErr   error
+		wantErr  error
+		errText  string
+	}{
+		{
+			name:     "upload part success",
+			number:   1,
+			size:     UploadPartSize,
+			getTimes: 1,
+			putTimes: 1,
+		},
+		{
+			name:     "upload last part first",
+			number:   2,
+			size:     4,
+			getTimes: 1,
+			putTimes: 1,
+		},
+		{name: "invalid part number", number: 0, size: 4, wantErr: ErrInvalidUploadPart},
+		{name: "part is too big", number: 1, size: UploadPartSize + 1, wantErr: ErrInvalidUploadPart},
+		{name: "part number above file parts", number: 3, size: 4, getTimes: 1, wantErr: ErrInvalidUploadPart},
+		{name: "part size differs from expected", number: 1, size: 4, getTimes: 1, wantErr: ErrInvalidUploadPart},
+		{name: "last part size differs from expected", number: 2, size: 5, getTimes: 1, wantErr: ErrInvalidUploadPart},
+		{
+			name:     "upload part to filestorage failed",
+			number:   2,
+			size:     4,
+			getTimes: 1,
+			putTimes: 1,
+			putErr:   errors.New("some error"),
+			errText:  "failed to upload part to filestorage",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upload := models.FileUpload{
+				ID: "upload", UploadID: "s3-upload", ObjectName: "object", FileSize: UploadPartSize + 4,
+			}
+			store.EXPECT().GetFileUpload(ctx, "upload").Times(test.getTimes).Return(upload, nil)
+			fs.EXPECT().UploadPart(ctx, "object", "s3-upload", test.number, gomock.Any(), test.size).Times(test.putTimes).
+				DoAndReturn(func(_ context.Context, _, _ string, _ int, part io.Reader, _ int64) (models.FileUploadPart, error) {
+					data, err := io.ReadAll(part)
+					require.NoError(t, err)
+					assert.Equal(t, "data", string(data))
+					return models.FileUploadPart{Number: test.number, Size: test.size, ETag: "etag"}, test.putErr
+				})
+
+			resp, err := s.UploadFilePart(ctx, "upload", test.number, strings.NewReader("data"), test.size)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, models.FileUploadPart{Number: test.number, Size: test.size, ETag: "etag"}, resp)
+			}
+		})
+	}
+}
+
+func TestCompleteFileUpload(t *testing.T) {
+	s, store, fs, crypter := newUploadServices(t)
+	expectDataKey(store, crypter)
+	ctx := context.Background()
+	upload := models.FileUpload{
+		ID: "upload", UploadID: "s3-upload", ObjectName: "object", FileName: "file.bin",
+		Mark: "mark", Description: "desc", Folder: "work", Tags: []string{"prod"}, FileSize: 100,
+	}
+	folder := "work"
+	labels := models.UserDataLabels{Folder: &folder, Tags: &[]string{"prod"}}
+	fullParts := []models.FileUploadPart{{Number: 1, Size: 60, ETag: "a"}, {Number: 2, Size: 40, ETag: "b"}}
+
+	tests := []struct {
+		name          string
+		upload        models.FileUpload
+		parts         []models.FileUploadPart
+		completeTimes int
+		completeErr   error
+		saveTimes     int
+		saveErr       error
+		wantErr       error
+		errText       string
+	}{
+		{
+			name:          "complete upload success",
+			upload:        upload,
+			parts:         fullParts,
+			completeTimes: 1,
+			saveTimes:     1,
+		},
+		{
+			name:    "upload is incomplete",
+			upload:  upload,
+			parts:   fullParts[:1],
+			wantErr: ErrUploadIncomplete,
+		},
+		{
+			name:          "complete upload in filestorage failed",
+			upload:        upload,
+			parts:         fullParts,
+			completeTimes: 1,
+			completeErr:   errors.New("some error"),
+			errText:       "failed to complete upload in filestorage",
+		},
+		{
+			name:          "upload completed concurrently",
+			upload:        upload,
+			parts:         fullParts,
+			completeTimes: 1,
+			saveTimes:     1,
+			saveErr:       storage.ErrFileUploadNotFound,
+			wantErr:       ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(test.upload, nil)
+			fs.EXPECT().ListUploadParts(ctx, "object", "s3-upload").Times(1).Return(test.parts, nil)
+			fs.EXPECT().CompleteUpload(ctx, "object", "s3-upload", test.parts).Times(test.completeTimes).
+				Return(test.completeErr)
+			data := `{"file_name":"file.bin","object_name":"object"}`
+			crypter.EXPECT().EncryptData(gomock.Any(), []byte(data)).Times(test.completeTimes).
+				Return([]byte("enc"), nil)
+			store.EXPECT().CompleteFileUpload(ctx, "upload", []byte("enc"), "mark", "desc", int64(100), labels).
+				Times(test.saveTimes).Return(7, test.saveErr)
+			fs.EXPECT().DeleteFile(gomock.Any(), gomock.Any()).Times(0)
+
+			id, err := s.CompleteFileUpload(ctx, "upload")
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, 7, id)
+			}
+		})
+	}
+}
+
+func TestCompleteFileUploadSaveFailed(t *testing.T) {
+	s, store, fs, crypter := newUploadServices(t)
+	expectDataKey(store, crypter)
+	ctx := context.Background()
+	upload := models.FileUpload{
+		ID: "upload", UploadID: "s3-upload", ObjectName: "object", FileName: "file.bin", FileSize: 4,
+	}
+	parts := []models.FileUploadPart{{Number: 1, Size: 4, ETag: "a"}}
+
+	store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(upload, nil)
+	fs.EXPECT().ListUploadParts(ctx, "object", "s3-upload").Times(1).Return(parts, nil)
+	fs.EXPECT().CompleteUpload(ctx, "object", "s3-upload", parts).Times(1).Return(nil)
+	crypter.EXPECT().EncryptData(gomock.Any(), gomock.Any()).Times(1).Return([]byte("enc"), nil)
+	store.EXPECT().CompleteFileUpload(ctx, "upload", gomock.Any(), gomock.Any(), gomock.Any(), int64(4), gomock.Any()).
+		Times(1).Return(0, errors.New("some error"))
+	fs.EXPECT().DeleteFile(ctx, "object").Times(1).Return(nil)
+
+	_, err := s.CompleteFileUpload(ctx, "upload")
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed to add user data")
+}
+
+func TestAbortFileUpload(t *testing.T) {
+	s, store, fs, _ := newUploadServices(t)
+	ctx := context.Background()
+	upload := models.FileUpload{ID: "upload", UploadID: "s3-upload", ObjectName: "object", FileSize: 100}
+
+	t.Run("abort upload success", func(t *testing.T) {
+		store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(upload, nil)
+		fs.EXPECT().AbortUpload(ctx, "object", "s3-upload").Times(1).Return(nil)
+		store.EXPECT().DeleteFileUpload(ctx, "upload").Times(1).Return(nil)
+
+		require.NoError(t, s.AbortFileUpload(ctx, "upload"))
+	})
+
+	t.Run("abort upload in filestorage failed", func(t *testing.T) {
+		store.EXPECT().GetFileUpload(ctx, "upload").Times(1).Return(upload, nil)
+		fs.EXPECT().AbortUpload(ctx, "object", "s3-upload").Times(1).Return(errors.New("some error"))
+		store.EXPECT().DeleteFileUpload(ctx, gomock.Any()).Times(0)
+
+		err := s.AbortFileUpload(ctx, "upload")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to abort upload in filestorage")
+	})
+}
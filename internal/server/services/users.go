@@ -6,9 +6,7 @@ import (
 	"fmt"
 
 	"github.com/MihailSergeenkov/GophKeeper/internal/models"
-	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
 
@@ -51,26 +49,34 @@ func (s *Services) CreateUserToken(
 ) (models.CreateUserTokenResponse, error) {
 	resp := models.CreateUserTokenResponse{}
 
+	attemptKey := loginAttemptKey(req.Login)
+
+	if err := s.checkAttempts(ctx, attemptKey); err != nil {
+		return resp, err
+	}
+
 	user, err := s.storage.GetUserByLogin(ctx, req.Login)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
+			s.failAttempt(ctx, attemptKey)
 			return resp, ErrUserLoginCreds
 		}
 		return resp, fmt.Errorf("failed to get user from DB %w", err)
 	}
 
 	if err := verifyPassword(user.Password, req.Password); err != nil {
+		s.failAttempt(ctx, attemptKey)
 		return resp, ErrUserLoginCreds
 	}
 
-	authToken, err := buildJWTString(s.settings, user.ID)
-	if err != nil {
-		return resp, fmt.Errorf("failed to build auth token: %w", err)
-	}
+	s.loginAttempts.Reset(attemptKey)
 
-	resp.AuthToken = authToken
+	if user.TOTPEnabled {
+		resp.TwoFactorToken, err = buildTwoFactorToken(s.settings.JWTKey, user.ID)
+		return resp, err
+	}
 
-	return resp, nil
+	return s.createSession(ctx, user.ID, req)
 }
 
 func validateRegisterUserRequest(req models.RegisterUserRequest) error {
@@ -101,16 +107,3 @@ func verifyPassword(hashedPassword []byte, candidatePassword string) error {
 
 	return nil
 }
-
-func buildJWTString(settings *config.Settings, userID int) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.Claims{
-		UserID: userID,
-	})
-
-	tokenString, err := token.SignedString([]byte(settings.SecretKey))
-	if err != nil {
-		return "", fmt.Errorf("failed to signed token: %w", err)
-	}
-
-	return tokenString, nil
-}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+)
+
+var ErrVaultCheckExist = errors.New("vault check already exist")
+
+const vaultSaltSize = 16
+
+// GetVault функция для получения параметров ключа хранилища пользователя, соль создается при первом обращении.
+func (s *Services) GetVault(ctx context.Context) (models.Vault, error) {
+	salt, check, err := s.storage.GetUserVault(ctx)
+	if err != nil {
+		return models.Vault{}, fmt.Errorf("failed to get user vault %w", err)
+	}
+
+	if salt == nil {
+		newSalt := make([]byte, vaultSaltSize)
+		if _, err := rand.Read(newSalt); err != nil {
+			return models.Vault{}, fmt.Errorf("failed to generate vault salt %w", err)
+		}
+
+		if err := s.storage.SetUserVaultSalt(ctx, newSalt); err != nil {
+			return models.Vault{}, fmt.Errorf("failed to set vault salt %w", err)
+		}
+
+		// При параллельных запросах сохраняется соль первого из них, поэтому она перечитывается.
+		salt, check, err = s.storage.GetUserVault(ctx)
+		if err != nil {
+			return models.Vault{}, fmt.Errorf("failed to get user vault %w", err)
+		}
+	}
+
+	return models.Vault{Salt: salt, Check: check}, nil
+}
+
+// SetVaultCheck функция для сохранения проверочного блока ключа хранилища пользователя.
+func (s *Services) SetVaultCheck(ctx context.Context, req models.UpdateVaultRequest) error {
+	if len(req.Check) == 0 {
+		return failedValidateFields(ErrUserDataIsEmpty)
+	}
+
+	ok, err := s.storage.SetUserVaultCheck(ctx, req.Check)
+	if err != nil {
+		return fmt.Errorf("failed to set vault check %w", err)
+	}
+	if !ok {
+		return ErrVaultCheckExist
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/config"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVault(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.Background()
+	salt := []byte("0123456789abcdef")
+	check := []byte("check")
+
+	t.Run("when vault exists", func(t *testing.T) {
+		store.EXPECT().GetUserVault(ctx).Times(1).Return(salt, check, nil)
+		store.EXPECT().SetUserVaultSalt(ctx, gomock.Any()).Times(0)
+
+		vault, err := s.GetVault(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.Vault{Salt: salt, Check: check}, vault)
+	})
+
+	t.Run("when vault salt generated", func(t *testing.T) {
+		gomock.InOrder(
+			store.EXPECT().GetUserVault(ctx).Times(1).Return(nil, nil, nil),
+			store.EXPECT().SetUserVaultSalt(ctx, gomock.Len(vaultSaltSize)).Times(1).Return(nil),
+			store.EXPECT().GetUserVault(ctx).Times(1).Return(salt, nil, nil),
+		)
+
+		vault, err := s.GetVault(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.Vault{Salt: salt}, vault)
+	})
+
+	t.Run("when get vault failed", func(t *testing.T) {
+		store.EXPECT().GetUserVault(ctx).Times(1).Return(nil, nil, errors.New("some error"))
+
+		_, err := s.GetVault(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get user vault")
+	})
+}
+
+func TestSetVaultCheck(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	store := mocks.NewMockStorager(mockCtrl)
+	fs := mocks.NewMockFileStorager(mockCtrl)
+	crypter := mocks.NewMockCrypter(mockCtrl)
+	settings := config.Settings{}
+	s := NewServices(store, fs, crypter, &settings)
+
+	ctx := context.Background()
+	req := models.UpdateVaultRequest{Check: []byte("check")}
+
+	type sResponse struct {
+		ok  bool
+		err error
+	}
+	tests := []struct {
+		name      string
+		sResponse sResponse
+		wantErr   error
+	}{
+		{
+			name:      "set vault check success",
+			sResponse: sResponse{ok: true, err: nil},
+			wantErr:   nil,
+		},
+		{
+			name:      "when vault check exist",
+			sResponse: sResponse{ok: false, err: nil},
+			wantErr:   ErrVaultCheckExist,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.EXPECT().SetUserVaultCheck(ctx, req.Check).Times(1).Return(test.sResponse.ok, test.sResponse.err)
+
+			err := s.SetVaultCheck(ctx, req)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("when vault check empty", func(t *testing.T) {
+		store.EXPECT().SetUserVaultCheck(ctx, gomock.Any()).Times(0)
+
+		err := s.SetVaultCheck(ctx, models.UpdateVaultRequest{})
+
+		require.ErrorIs(t, err, ErrUserDataIsEmpty)
+	})
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+// AddPendingFileObject отметить, что начата запись объекта в файловое хранилище.
+// Пока отметка не удалена, объект без данных пользователя не считается потерянным.
+func (s *Storage) AddPendingFileObject(ctx context.Context, objectName string) error {
+	const stmt = `
+		INSERT INTO pending_file_objects (user_id, object_name) VALUES ($1, $2)
+		ON CONFLICT (user_id, object_name) DO NOTHING
+	`
+
+	_, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), objectName)
+	if err != nil {
+		return fmt.Errorf("failed to execute add pending file object query: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePendingFileObject удалить отметку о записи объекта в файловое хранилище.
+func (s *Storage) DeletePendingFileObject(ctx context.Context, objectName string) error {
+	const stmt = `DELETE FROM pending_file_objects WHERE user_id = $1 AND object_name = $2`
+
+	_, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), objectName)
+	if err != nil {
+		return fmt.Errorf("failed to execute delete pending file object query: %w", err)
+	}
+
+	return nil
+}
+
+// FetchPendingFileObjects получить объекты пользователя, запись которых в файловое хранилище не подтверждена.
+func (s *Storage) FetchPendingFileObjects(ctx context.Context) ([]models.PendingFileObject, error) {
+	const query = `SELECT object_name, created_at FROM pending_file_objects WHERE user_id = $1`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	objects := []models.PendingFileObject{}
+
+	for rows.Next() {
+		var o models.PendingFileObject
+		if err := rows.Scan(&o.ObjectName, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		objects = append(objects, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return objects, nil
+}
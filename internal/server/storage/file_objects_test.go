@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPendingFileObjects(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	someErr := errors.New("some error")
+
+	t.Run("add pending object", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, gomock.Any(), 1, "object").Times(1).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+		require.NoError(t, storage.AddPendingFileObject(ctx, "object"))
+
+		pool.EXPECT().Exec(ctx, gomock.Any(), 1, "object").Times(1).Return(pgconn.NewCommandTag(""), someErr)
+		err := storage.AddPendingFileObject(ctx, "object")
+		assert.ErrorContains(t, err, "failed to execute add pending file object query")
+	})
+
+	t.Run("delete pending object", func(t *testing.T) {
+		stmt := `DELETE FROM pending_file_objects WHERE user_id = $1 AND object_name = $2`
+
+		pool.EXPECT().Exec(ctx, stmt, 1, "object").Times(1).Return(pgconn.NewCommandTag("DELETE 1"), nil)
+		require.NoError(t, storage.DeletePendingFileObject(ctx, "object"))
+
+		pool.EXPECT().Exec(ctx, stmt, 1, "object").Times(1).Return(pgconn.NewCommandTag(""), someErr)
+		err := storage.DeletePendingFileObject(ctx, "object")
+		assert.ErrorContains(t, err, "failed to execute delete pending file object query")
+	})
+
+	t.Run("fetch pending objects", func(t *testing.T) {
+		rows := mocks.NewMockRows(mockCtrl)
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*string) = "object"
+			*dest[1].(*time.Time) = createdAt
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		objects, err := storage.FetchPendingFileObjects(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.PendingFileObject{{ObjectName: "object", CreatedAt: createdAt}}, objects)
+	})
+
+	t.Run("failed fetch pending objects", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(nil, someErr)
+
+		_, err := storage.FetchPendingFileObjects(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddFileUpload сохранить сессию загрузки файла по частям вместе с папкой и метками будущего файла.
+// Если задан зарезервированный ID файла, резерв переходит к сессии в той же транзакции. Если ID
+// не зарезервирован пользователем или уже использован, сессия не сохраняется и возвращается ErrDataIDNotReserved.
+func (s *Storage) AddFileUpload(ctx context.Context, upload models.FileUpload) error {
+	const stmt = `
+		WITH reserved AS (
+			DELETE FROM user_data_reservations WHERE id = $11 AND user_id = $2 RETURNING id
+		)
+		INSERT INTO file_uploads (
+			id, user_id, upload_id, object_name, file_name, mark, description, file_size, folder, tags, data_id
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10::text[], '{}'), (SELECT id FROM reserved)
+		WHERE $11 = 0 OR EXISTS (SELECT 1 FROM reserved)
+	`
+
+	tag, err := s.pool.Exec(
+		ctx, stmt,
+		upload.ID, ctx.Value(constants.KeyUserID), upload.UploadID, upload.ObjectName, upload.FileName,
+		upload.Mark, upload.Description, upload.FileSize, upload.Folder, upload.Tags, upload.DataID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute add file upload query: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDataIDNotReserved
+	}
+
+	return nil
+}
+
+// GetFileUpload получить сессию загрузки файла пользователя.
+func (s *Storage) GetFileUpload(ctx context.Context, id string) (models.FileUpload, error) {
+	const query = `
+		SELECT id, user_id, upload_id, object_name, file_name, mark, description, file_size, folder, tags, created_at
+		FROM file_uploads WHERE id = $1 AND user_id = $2
+	`
+
+	var upload models.FileUpload
+
+	row := s.pool.QueryRow(ctx, query, id, ctx.Value(constants.KeyUserID))
+	err := row.Scan(
+		&upload.ID, &upload.UserID, &upload.UploadID, &upload.ObjectName, &upload.FileName,
+		&upload.Mark, &upload.Description, &upload.FileSize, &upload.Folder, &upload.Tags, &upload.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.FileUpload{}, ErrFileUploadNotFound
+		}
+
+		return models.FileUpload{}, fmt.Errorf(failedScanStr, err)
+	}
+
+	return upload, nil
+}
+
+// CompleteFileUpload завершить загрузку файла пользователя: удалить сессию загрузки id и добавить данные файла
+// с размером fileSize, папкой и метками в одной транзакции. Файл получает ID, зарезервированный
+// при начале загрузки, если он был задан. Если сессию уже завершили или отменили,
+// данные не добавляются и возвращается ErrFileUploadNotFound.
+func (s *Storage) CompleteFileUpload(
+	ctx context.Context,
+	id string,
+	encData []byte,
+	mark string,
+	description string,
+	fileSize int64,
+	labels models.UserDataLabels,
+) (int, error) {
+	const stmt = `
+		WITH upload AS (
+			DELETE FROM file_uploads WHERE id = $6 AND user_id = $1 RETURNING data_id
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM upload) RETURNING data_revision
+		)
+		INSERT INTO user_data (id, user_id, data, mark, description, type, file_size, revision)
+		OVERRIDING SYSTEM VALUE
+		SELECT
+			COALESCE(data_id, nextval(pg_get_serial_sequence('user_data', 'id'))),
+			$1, $2, $3, $4, 'file', $5, (SELECT data_revision FROM rev)
+		FROM upload
+		RETURNING id
+	`
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	dataID, err := s.addUserData(ctx, userID, labels, stmt, userID, encData, mark, description, fileSize, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrFileUploadNotFound
+		}
+
+		return 0, err
+	}
+
+	return dataID, nil
+}
+
+// DeleteFileUpload удалить сессию загрузки файла пользователя.
+func (s *Storage) DeleteFileUpload(ctx context.Context, id string) error {
+	const stmt = `DELETE FROM file_uploads WHERE id = $1 AND user_id = $2`
+
+	tag, err := s.pool.Exec(ctx, stmt, id, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return fmt.Errorf("failed to execute delete file upload query: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFileUploadNotFound
+	}
+
+	return nil
+}
+
+// FetchFileUploadObjects получить имена объектов незавершенных загрузок файлов пользователя.
+func (s *Storage) FetchFileUploadObjects(ctx context.Context) ([]string, error) {
+	const query = `SELECT object_name FROM file_uploads WHERE user_id = $1`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return names, nil
+}
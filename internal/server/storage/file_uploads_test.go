@@ -0,0 +1,297 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAddFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	upload := models.FileUpload{
+		ID:          "upload",
+		UploadID:    "s3-upload",
+		ObjectName:  "object",
+		FileName:    "file.bin",
+		Mark:        "mark",
+		Description: "description",
+		Folder:      "work",
+		Tags:        []string{"prod"},
+		FileSize:    100,
+	}
+
+	t.Run("success add file upload", func(t *testing.T) {
+		pool.EXPECT().
+			Exec(ctx, gomock.Any(), "upload", 1, "s3-upload", "object", "file.bin", "mark", "description", int64(100),
+				"work", []string{"prod"}, 0).
+			Times(1).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		err := storage.AddFileUpload(ctx, upload)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("failed add file upload", func(t *testing.T) {
+		pool.EXPECT().
+			Exec(ctx, gomock.Any(), "upload", 1, "s3-upload", "object", "file.bin", "mark", "description", int64(100),
+				"work", []string{"prod"}, 0).
+			Times(1).Return(pgconn.CommandTag{}, errors.New("some error"))
+
+		err := storage.AddFileUpload(ctx, upload)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute add file upload query")
+	})
+
+	t.Run("data id is not reserved", func(t *testing.T) {
+		reserved := upload
+		reserved.DataID = 7
+
+		pool.EXPECT().
+			Exec(ctx, gomock.Any(), "upload", 1, "s3-upload", "object", "file.bin", "mark", "description", int64(100),
+				"work", []string{"prod"}, 7).
+			Times(1).Return(pgconn.NewCommandTag("INSERT 0 0"), nil)
+
+		err := storage.AddFileUpload(ctx, reserved)
+
+		require.ErrorIs(t, err, ErrDataIDNotReserved)
+	})
+}
+
+func TestGetFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	row := mocks.NewMockRow(mockCtrl)
+
+	tests := []struct {
+		name    string
+		rowErr  error
+		wantErr error
+		errText string
+	}{
+		{
+			name:    "success get file upload",
+			rowErr:  nil,
+			wantErr: nil,
+			errText: "",
+		},
+		{
+			name:    "file upload not found",
+			rowErr:  pgx.ErrNoRows,
+			wantErr: ErrFileUploadNotFound,
+			errText: "",
+		},
+		{
+			name:    "failed read row",
+			rowErr:  errors.New("some error"),
+			wantErr: nil,
+			errText: "failed to scan a response row",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, gomock.Any(), "upload", 1).Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+
+			_, err := storage.GetFileUpload(ctx, "upload")
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCompleteFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	encData := []byte("some data")
+
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+		errText string
+	}{
+		{
+			name: "success complete file upload",
+		},
+		{
+			name:    "file upload already completed",
+			scanErr: pgx.ErrNoRows,
+			wantErr: ErrFileUploadNotFound,
+		},
+		{
+			name:    "failed complete file upload",
+			scanErr: errors.New("some error"),
+			errText: "failed to scan a response row",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := mocks.NewMockTx(mockCtrl)
+			row := mocks.NewMockRow(mockCtrl)
+
+			pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+			tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, encData, "mark", "desc", int64(100), "upload").Times(1).
+				Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+				*dest[0].(*int) = 5
+				return test.scanErr
+			})
+			if test.scanErr != nil {
+				tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+			} else {
+				tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+			}
+
+			id, err := storage.CompleteFileUpload(ctx, "upload", encData, "mark", "desc", 100, models.UserDataLabels{})
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, 5, id)
+			}
+		})
+	}
+}
+
+func TestDeleteFileUpload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+
+	tests := []struct {
+		name    string
+		tag     pgconn.CommandTag
+		execErr error
+		wantErr error
+		errText string
+	}{
+		{
+			name: "success delete file upload",
+			tag:  pgconn.NewCommandTag("DELETE 1"),
+		},
+		{
+			name:    "file upload not found",
+			tag:     pgconn.NewCommandTag("DELETE 0"),
+			wantErr: ErrFileUploadNotFound,
+		},
+		{
+			name:    "failed delete file upload",
+			execErr: errors.New("some error"),
+			errText: "failed to execute delete file upload query",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, gomock.Any(), "upload", 1).Times(1).Return(test.tag, test.execErr)
+
+			err := storage.DeleteFileUpload(ctx, "upload")
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFetchFileUploadObjects(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	stmt := `SELECT object_name FROM file_uploads WHERE user_id = $1`
+	rows := mocks.NewMockRows(mockCtrl)
+
+	t.Run("success fetch", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, stmt, 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*string) = "object"
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		names, err := storage.FetchFileUploadObjects(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"object"}, names)
+	})
+
+	t.Run("failed read rows", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, stmt, 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(false)
+		rows.EXPECT().Err().Times(1).Return(errors.New("some error"))
+
+		_, err := storage.FetchFileUploadObjects(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read query")
+	})
+}
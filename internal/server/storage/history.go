@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/jackc/pgx/v5"
+)
+
+// FetchUserDataHistory получить прежние версии данных пользователя, начиная с последней.
+// Если данных нет, возвращается ErrUserDataNotFound.
+func (s *Storage) FetchUserDataHistory(
+	ctx context.Context,
+	id int,
+	dataType string,
+) ([]models.UserDataRevision, error) {
+	const query = `
+		SELECT h.version, h.mark, h.description, h.created_at FROM user_data_history h
+		JOIN user_data d ON d.id = h.data_id
+		WHERE d.user_id = $1 AND d.id = $2 AND d.type = $3 AND d.deleted_at IS NULL
+		ORDER BY h.version DESC
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID), id, dataType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.UserDataRevision{}
+
+	for rows.Next() {
+		var r models.UserDataRevision
+		if err := rows.Scan(&r.Version, &r.Mark, &r.Description, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		revisions = append(revisions, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	if len(revisions) > 0 {
+		return revisions, nil
+	}
+
+	exists, err := s.userDataExists(ctx, id, dataType)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserDataNotFound
+	}
+
+	return revisions, nil
+}
+
+// GetUserDataRevision получить зашифрованные данные, метку и описание прежней версии данных пользователя.
+func (s *Storage) GetUserDataRevision(
+	ctx context.Context,
+	id int,
+	version int,
+	dataType string,
+) ([]byte, string, string, error) {
+	const query = `
+		SELECT h.data, h.mark, h.description FROM user_data_history h
+		JOIN user_data d ON d.id = h.data_id
+		WHERE d.user_id = $1 AND d.id = $2 AND d.type = $3 AND d.deleted_at IS NULL AND h.version = $4
+	`
+
+	var (
+		data        []byte
+		mark        string
+		description string
+	)
+
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID), id, dataType, version)
+	if err := row.Scan(&data, &mark, &description); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", "", ErrUserDataNotFound
+		}
+
+		return nil, "", "", fmt.Errorf(failedScanStr, err)
+	}
+
+	return data, mark, description, nil
+}
+
+// RestoreFileUserDataRevision восстановить прежнюю версию файла пользователя. Версия переносится
+// из истории в данные файла, а текущая версия сохраняется в истории, поэтому на каждый объект файла
+// по-прежнему ссылается одна запись. Если данных или версии нет, возвращается ErrUserDataNotFound.
+func (s *Storage) RestoreFileUserDataRevision(ctx context.Context, id int, version int) error {
+	const stmt = `
+		WITH restored AS (
+			DELETE FROM user_data_history h USING user_data d
+			WHERE d.user_id = $1 AND d.id = $2 AND d.type = 'file' AND d.deleted_at IS NULL
+				AND h.data_id = d.id AND h.version = $3
+			RETURNING h.data, h.mark, h.description, h.file_size
+		), old AS (
+			SELECT id, user_id, version, data, mark, description, file_size FROM user_data
+			WHERE user_id = $1 AND id = $2 AND type = 'file' AND deleted_at IS NULL
+				AND EXISTS (SELECT 1 FROM restored) FOR UPDATE
+		), history AS (
+			INSERT INTO user_data_history (data_id, user_id, version, data, mark, description, file_size)
+			SELECT id, user_id, version, data, mark, description, file_size FROM old
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM restored) RETURNING data_revision
+		)
+		UPDATE user_data SET data = restored.data, mark = restored.mark, description = restored.description,
+			file_size = restored.file_size, object_missing_at = NULL,
+			version = version + 1, revision = (SELECT data_revision FROM rev), updated_at = now()
+		FROM restored WHERE user_id = $1 AND id = $2 AND type = 'file' AND deleted_at IS NULL
+	`
+
+	tag, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), id, version)
+	if err != nil {
+		return fmt.Errorf("failed to execute restore file revision query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserDataNotFound
+	}
+
+	return nil
+}
+
+// FetchFileUserDataHistory получить зашифрованные данные прежних версий всех файлов пользователя,
+// в том числе в корзине: их объекты используются, пока версии не удалены из истории.
+func (s *Storage) FetchFileUserDataHistory(ctx context.Context) ([]models.FileUserData, error) {
+	const query = `
+		SELECT h.data_id, h.data FROM user_data_history h
+		JOIN user_data d ON d.id = h.data_id
+		WHERE h.user_id = $1 AND d.type = 'file'
+		ORDER BY h.data_id, h.version
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	data := []models.FileUserData{}
+
+	for rows.Next() {
+		var d models.FileUserData
+		if err := rows.Scan(&d.ID, &d.Data); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		data = append(data, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return data, nil
+}
+
+// PruneUserDataHistory оставить в истории данных пользователя только keep последних версий.
+// Возвращает зашифрованные данные удаленных версий, чтобы освободить объекты прежних версий файлов.
+func (s *Storage) PruneUserDataHistory(ctx context.Context, id int, keep int) ([][]byte, error) {
+	const stmt = `
+		DELETE FROM user_data_history WHERE user_id = $1 AND data_id = $2 AND id NOT IN (
+			SELECT id FROM user_data_history WHERE user_id = $1 AND data_id = $2 ORDER BY version DESC LIMIT $3
+		)
+		RETURNING data
+	`
+
+	rows, err := s.pool.Query(ctx, stmt, ctx.Value(constants.KeyUserID), id, keep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute prune user data history query: %w", err)
+	}
+	defer rows.Close()
+
+	pruned := [][]byte{}
+
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		pruned = append(pruned, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return pruned, nil
+}
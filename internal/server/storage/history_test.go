@@ -0,0 +1,290 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFetchUserDataHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+	row := mocks.NewMockRow(mockCtrl)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	const existsQuery = `
+		SELECT EXISTS(SELECT 1 FROM user_data WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL)
+	`
+
+	expectRows := func(count int) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, 2, "password").Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(count).Return(true)
+		rows.EXPECT().Next().Times(1).Return(false)
+		rows.EXPECT().Scan(gomock.Any()).Times(count).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 1
+			*dest[1].(*string) = "mark"
+			*dest[2].(*string) = "description"
+			*dest[3].(*time.Time) = createdAt
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+	}
+	expectExists := func(exists bool) {
+		pool.EXPECT().QueryRow(ctx, existsQuery, 1, 2, "password").Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*bool) = exists
+			return nil
+		})
+	}
+
+	t.Run("fetch history", func(t *testing.T) {
+		expectRows(1)
+
+		revisions, err := storage.FetchUserDataHistory(ctx, 2, "password")
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.UserDataRevision{
+			{Version: 1, Mark: "mark", Description: "description", CreatedAt: createdAt},
+		}, revisions)
+	})
+
+	t.Run("empty history", func(t *testing.T) {
+		expectRows(0)
+		expectExists(true)
+
+		revisions, err := storage.FetchUserDataHistory(ctx, 2, "password")
+
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+
+	t.Run("when user data not found", func(t *testing.T) {
+		expectRows(0)
+		expectExists(false)
+
+		_, err := storage.FetchUserDataHistory(ctx, 2, "password")
+
+		require.ErrorIs(t, err, ErrUserDataNotFound)
+	})
+
+	t.Run("failed fetch history", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, 2, "password").Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchUserDataHistory(ctx, 2, "password")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestGetUserDataRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	row := mocks.NewMockRow(mockCtrl)
+
+	tests := []struct {
+		name    string
+		scanErr error
+		wantErr error
+		errText string
+	}{
+		{
+			name: "get revision",
+		},
+		{
+			name:    "when revision not found",
+			scanErr: pgx.ErrNoRows,
+			wantErr: ErrUserDataNotFound,
+		},
+		{
+			name:    "failed get revision",
+			scanErr: errors.New("some error"),
+			errText: "failed to scan a response row",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, gomock.Any(), 1, 2, "text", 3).Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+				if test.scanErr != nil {
+					return test.scanErr
+				}
+
+				*dest[0].(*[]byte) = []byte("data")
+				*dest[1].(*string) = "mark"
+				*dest[2].(*string) = "description"
+				return nil
+			})
+
+			data, mark, description, err := storage.GetUserDataRevision(ctx, 2, 3, "text")
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, []byte("data"), data)
+				assert.Equal(t, "mark", mark)
+				assert.Equal(t, "description", description)
+			}
+		})
+	}
+}
+
+func TestRestoreFileUserDataRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+
+	tests := []struct {
+		execErr error
+		name    string
+		tag     pgconn.CommandTag
+		wantErr error
+		errText string
+	}{
+		{name: "restore file revision", tag: pgconn.NewCommandTag("UPDATE 1")},
+		{name: "when revision not found", tag: pgconn.NewCommandTag("UPDATE 0"), wantErr: ErrUserDataNotFound},
+		{
+			name:    "failed restore file revision",
+			execErr: errors.New("some error"),
+			errText: "failed to execute restore file revision query",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, gomock.Any(), 1, 2, 3).Times(1).Return(test.tag, test.execErr)
+
+			err := storage.RestoreFileUserDataRevision(ctx, 2, 3)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFetchFileUserDataHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+
+	t.Run("fetch file history", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 2
+			*dest[1].(*[]byte) = []byte("old data")
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		data, err := storage.FetchFileUserDataHistory(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.FileUserData{{ID: 2, Data: []byte("old data")}}, data)
+	})
+
+	t.Run("failed fetch file history", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchFileUserDataHistory(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestPruneUserDataHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+
+	t.Run("prune history", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, 2, 10).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*[]byte) = []byte("old data")
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		pruned, err := storage.PruneUserDataHistory(ctx, 2, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("old data")}, pruned)
+	})
+
+	t.Run("failed prune history", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, 2, 10).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.PruneUserDataHistory(ctx, 2, 10)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute prune user data history query")
+	})
+}
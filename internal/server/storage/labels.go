@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// SetUserDataLabels изменить папку и метки данных пользователя указанного типа.
+// Папка и метки меняются только после проверки, что данные есть и принадлежат пользователю.
+// Недостающие папка и метки создаются, а папки и метки, которые больше не используются, удаляются.
+// Данные получают новый номер изменения, а их версия не меняется.
+func (s *Storage) SetUserDataLabels(
+	ctx context.Context,
+	id int,
+	dataType string,
+	labels models.UserDataLabels,
+) (err error) {
+	const (
+		lockStmt = `
+			SELECT id FROM user_data WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL
+			FOR UPDATE
+		`
+		revisionStmt = `
+			WITH rev AS (UPDATE users SET data_revision = data_revision + 1 WHERE id = $1 RETURNING data_revision)
+			UPDATE user_data SET revision = (SELECT data_revision FROM rev), updated_at = now() WHERE id = $2
+		`
+	)
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("failed to rollback transaction", zap.Error(rbErr))
+			}
+		}
+	}()
+
+	var dataID int
+	if err = tx.QueryRow(ctx, lockStmt, userID, id, dataType).Scan(&dataID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserDataNotFound
+		}
+
+		return fmt.Errorf(failedScanStr, err)
+	}
+
+	if err = writeUserDataLabels(ctx, tx, userID, id, labels); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, revisionStmt, userID, id); err != nil {
+		return fmt.Errorf("failed to execute update user data revision query: %w", err)
+	}
+
+	if err = deleteUnusedLabels(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceUserDataLabels функция для замены папки и меток данных пользователя в транзакции tx
+// с удалением папок и меток, которые больше не используются.
+func replaceUserDataLabels(ctx context.Context, tx pgx.Tx, userID any, id int, labels models.UserDataLabels) error {
+	if err := writeUserDataLabels(ctx, tx, userID, id, labels); err != nil {
+		return err
+	}
+
+	return deleteUnusedLabels(ctx, tx, userID)
+}
+
+// deleteUnusedLabels функция для удаления в транзакции tx папок и меток пользователя без данных.
+func deleteUnusedLabels(ctx context.Context, tx pgx.Tx, userID any) error {
+	const stmt = `
+		WITH unused_folders AS (
+			DELETE FROM folders WHERE user_id = $1
+			AND NOT EXISTS (SELECT 1 FROM user_data WHERE user_data.folder_id = folders.id)
+		)
+		DELETE FROM tags WHERE user_id = $1
+		AND NOT EXISTS (SELECT 1 FROM user_data_tags WHERE user_data_tags.tag_id = tags.id)
+	`
+
+	if _, err := tx.Exec(ctx, stmt, userID); err != nil {
+		return fmt.Errorf("failed to execute delete unused labels query: %w", err)
+	}
+
+	return nil
+}
+
+// writeUserDataLabels функция для записи папки и меток данных пользователя в транзакции tx.
+// Метки должны быть без повторов, недостающие папка и метки создаются.
+func writeUserDataLabels(ctx context.Context, tx pgx.Tx, userID any, id int, labels models.UserDataLabels) error {
+	const (
+		folderStmt = `
+			WITH folder AS (
+				INSERT INTO folders (user_id, path) SELECT $1, $3::text WHERE $3::text <> ''
+				ON CONFLICT (user_id, path) DO UPDATE SET path = EXCLUDED.path RETURNING id
+			)
+			UPDATE user_data SET folder_id = (SELECT id FROM folder) WHERE id = $2
+		`
+		tagsStmt = `
+			WITH tag AS (
+				INSERT INTO tags (user_id, name) SELECT $1, name FROM unnest($3::text[]) AS name
+				ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name RETURNING id
+			), removed AS (
+				DELETE FROM user_data_tags WHERE data_id = $2 AND tag_id NOT IN (SELECT id FROM tag)
+			)
+			INSERT INTO user_data_tags (data_id, tag_id) SELECT $2, id FROM tag ON CONFLICT DO NOTHING
+		`
+	)
+
+	if labels.Folder != nil {
+		if _, err := tx.Exec(ctx, folderStmt, userID, id, *labels.Folder); err != nil {
+			return fmt.Errorf("failed to execute set user data folder query: %w", err)
+		}
+	}
+
+	if labels.Tags != nil {
+		if _, err := tx.Exec(ctx, tagsStmt, userID, id, *labels.Tags); err != nil {
+			return fmt.Errorf("failed to execute set user data tags query: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSetUserDataLabels(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	folder := "work/aws"
+	tags := []string{"prod", "team"}
+	labels := models.UserDataLabels{Folder: &folder, Tags: &tags}
+
+	t.Run("set user data folder and tags", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		gomock.InOrder(
+			pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil),
+			tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, 2, "card").Times(1).Return(row),
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(nil),
+			tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2, folder).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil),
+			tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2, tags).Times(1).Return(pgconn.NewCommandTag("INSERT 0 2"), nil),
+			tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil),
+			tx.EXPECT().Exec(ctx, gomock.Any(), 1).Times(1).Return(pgconn.NewCommandTag("DELETE 1"), nil),
+			tx.EXPECT().Commit(ctx).Times(1).Return(nil),
+		)
+
+		err := storage.SetUserDataLabels(ctx, 2, "card", labels)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("set only user data tags", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, 2, "card").Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2, tags).Times(1).Return(pgconn.NewCommandTag("INSERT 0 2"), nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), 1).Times(1).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+		tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+
+		err := storage.SetUserDataLabels(ctx, 2, "card", models.UserDataLabels{Tags: &tags})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("when user data not found", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, 2, "card").Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(pgx.ErrNoRows)
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		err := storage.SetUserDataLabels(ctx, 2, "card", labels)
+
+		require.ErrorIs(t, err, ErrUserDataNotFound)
+	})
+
+	t.Run("failed set user data tags", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, 2, "card").Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2, folder).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), 1, 2, tags).Times(1).Return(pgconn.CommandTag{}, errors.New("some error"))
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		err := storage.SetUserDataLabels(ctx, 2, "card", labels)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute set user data tags query")
+	})
+
+	t.Run("failed begin transaction", func(t *testing.T) {
+		pool.EXPECT().Begin(ctx).Times(1).Return(nil, errors.New("some error"))
+
+		err := storage.SetUserDataLabels(ctx, 2, "card", labels)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to begin transaction")
+	})
+}
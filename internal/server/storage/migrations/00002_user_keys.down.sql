@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE user_keys;
+
+COMMIT;
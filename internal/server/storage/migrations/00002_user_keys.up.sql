@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE user_keys(
+	user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	data_key BYTEA NOT NULL
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_keys DROP COLUMN key_id;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_keys ADD COLUMN key_id VARCHAR(64) NOT NULL DEFAULT '';
+
+COMMIT;
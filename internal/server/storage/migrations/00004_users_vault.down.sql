@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users DROP COLUMN vault_check;
+ALTER TABLE users DROP COLUMN vault_salt;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users ADD COLUMN vault_salt BYTEA;
+ALTER TABLE users ADD COLUMN vault_check BYTEA;
+
+COMMIT;
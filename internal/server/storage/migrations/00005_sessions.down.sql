@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE sessions;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE sessions(
+	id VARCHAR(36) PRIMARY KEY,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	refresh_token_hash BYTEA NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	expires_at TIMESTAMPTZ NOT NULL,
+	revoked_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX sessions_refresh_token_hash_index ON sessions(refresh_token_hash);
+CREATE INDEX sessions_user_id_index ON sessions(user_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE sessions DROP COLUMN ip;
+ALTER TABLE sessions DROP COLUMN client_version;
+ALTER TABLE sessions DROP COLUMN device_name;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE sessions ADD COLUMN device_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN client_version VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN ip VARCHAR(64) NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users ADD COLUMN totp_secret BYTEA;
+ALTER TABLE users ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes(
+	id SERIAL PRIMARY KEY,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	code_hash BYTEA NOT NULL,
+	used_at TIMESTAMPTZ
+);
+CREATE INDEX recovery_codes_user_id_index ON recovery_codes(user_id);
+
+COMMIT;
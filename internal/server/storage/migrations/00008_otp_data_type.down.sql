@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+DELETE FROM user_data WHERE type = 'otp';
+
+ALTER TYPE user_data_type RENAME TO user_data_type_old;
+CREATE TYPE user_data_type AS ENUM ('password', 'card', 'text', 'file');
+ALTER TABLE user_data ALTER COLUMN type TYPE user_data_type USING type::text::user_data_type;
+DROP TYPE user_data_type_old;
+
+COMMIT;
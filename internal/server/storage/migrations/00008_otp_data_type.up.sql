@@ -0,0 +1,2 @@
+-- ALTER TYPE ... ADD VALUE нельзя выполнять внутри блока транзакции в PostgreSQL до 12 версии.
+ALTER TYPE user_data_type ADD VALUE IF NOT EXISTS 'otp';
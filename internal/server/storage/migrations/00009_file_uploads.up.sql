@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE file_uploads(
+	id VARCHAR(36) PRIMARY KEY,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	upload_id TEXT NOT NULL,
+	object_name TEXT NOT NULL,
+	mark VARCHAR(100) NOT NULL,
+	description VARCHAR(3000) NOT NULL,
+	file_size BIGINT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX file_uploads_user_id_index ON file_uploads(user_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_uploads DROP COLUMN hashed_parts;
+ALTER TABLE file_uploads DROP COLUMN hash_state;
+ALTER TABLE file_uploads DROP COLUMN file_name;
+
+DROP TABLE file_objects;
+
+COMMIT;
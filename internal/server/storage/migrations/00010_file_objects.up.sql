@@ -0,0 +1,16 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE file_objects(
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	object_name TEXT NOT NULL,
+	content_hash TEXT,
+	refs INT NOT NULL,
+	PRIMARY KEY (user_id, object_name),
+	UNIQUE (user_id, content_hash)
+);
+
+ALTER TABLE file_uploads ADD COLUMN file_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE file_uploads ADD COLUMN hash_state BYTEA;
+ALTER TABLE file_uploads ADD COLUMN hashed_parts INT NOT NULL DEFAULT 0;
+
+COMMIT;
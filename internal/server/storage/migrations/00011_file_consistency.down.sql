@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_data DROP COLUMN object_missing_at;
+
+DROP TABLE pending_file_objects;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE pending_file_objects(
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	object_name TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (user_id, object_name)
+);
+
+ALTER TABLE user_data ADD COLUMN object_missing_at TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_data DROP COLUMN file_size;
+
+COMMIT;
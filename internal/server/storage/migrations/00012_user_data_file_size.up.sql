@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_data ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN TRANSACTION;
+
+DROP TABLE user_data_tombstones;
+
+DROP INDEX user_data_user_id_revision_index;
+ALTER TABLE user_data DROP COLUMN revision;
+ALTER TABLE user_data DROP COLUMN version;
+
+ALTER TABLE users DROP COLUMN data_revision;
+
+COMMIT;
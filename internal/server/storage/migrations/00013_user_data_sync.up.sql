@@ -0,0 +1,18 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users ADD COLUMN data_revision BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE user_data ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE user_data ADD COLUMN revision BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX user_data_user_id_revision_index ON user_data(user_id, revision);
+
+CREATE TABLE user_data_tombstones(
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	data_id INT NOT NULL,
+	revision BIGINT NOT NULL,
+	deleted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (user_id, data_id)
+);
+CREATE INDEX user_data_tombstones_user_id_revision_index ON user_data_tombstones(user_id, revision);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE user_data_history;
+
+COMMIT;
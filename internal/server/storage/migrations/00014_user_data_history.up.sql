@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE user_data_history(
+	id INT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	data_id INT REFERENCES user_data(id) ON DELETE CASCADE NOT NULL,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	version INT NOT NULL,
+	data BYTEA NOT NULL,
+	mark VARCHAR(100) NOT NULL,
+	description VARCHAR(3000) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	UNIQUE (data_id, version)
+);
+
+COMMIT;
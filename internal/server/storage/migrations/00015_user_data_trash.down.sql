@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DROP INDEX IF EXISTS user_data_deleted_at_index;
+ALTER TABLE user_data DROP COLUMN deleted_at;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_data ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX user_data_deleted_at_index ON user_data(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+DROP INDEX user_data_user_id_mark_index;
+DROP INDEX user_data_search_vector_index;
+
+ALTER TABLE user_data DROP COLUMN search_vector;
+ALTER TABLE user_data DROP COLUMN updated_at;
+ALTER TABLE user_data DROP COLUMN created_at;
+
+COMMIT;
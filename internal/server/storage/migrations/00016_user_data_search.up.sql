@@ -0,0 +1,12 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_data ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE user_data ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE user_data ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', mark), 'A') || setweight(to_tsvector('simple', description), 'B')
+) STORED;
+
+CREATE INDEX user_data_search_vector_index ON user_data USING GIN (search_vector);
+CREATE INDEX user_data_user_id_mark_index ON user_data(user_id, lower(mark) text_pattern_ops);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+DROP TABLE user_data_tags;
+DROP TABLE tags;
+
+ALTER TABLE user_data DROP COLUMN folder_id;
+DROP TABLE folders;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE folders(
+	id INT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	path VARCHAR(500) NOT NULL,
+	UNIQUE (user_id, path)
+);
+
+ALTER TABLE user_data ADD COLUMN folder_id INT REFERENCES folders(id) ON DELETE SET NULL;
+CREATE INDEX user_data_folder_id_index ON user_data(folder_id);
+
+CREATE TABLE tags(
+	id INT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	UNIQUE (user_id, name)
+);
+
+CREATE TABLE user_data_tags(
+	data_id INT REFERENCES user_data(id) ON DELETE CASCADE NOT NULL,
+	tag_id INT REFERENCES tags(id) ON DELETE CASCADE NOT NULL,
+	PRIMARY KEY (data_id, tag_id)
+);
+CREATE INDEX user_data_tags_tag_id_index ON user_data_tags(tag_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_uploads DROP COLUMN tags;
+ALTER TABLE file_uploads DROP COLUMN folder;
+
+COMMIT;
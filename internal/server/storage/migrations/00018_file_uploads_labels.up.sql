@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_uploads ADD COLUMN folder VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE file_uploads ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
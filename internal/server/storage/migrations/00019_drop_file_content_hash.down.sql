@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_uploads ADD COLUMN hashed_parts INT NOT NULL DEFAULT -1;
+ALTER TABLE file_uploads ADD COLUMN hash_state BYTEA;
+ALTER TABLE file_objects ADD COLUMN content_hash TEXT;
+ALTER TABLE file_objects ADD CONSTRAINT file_objects_user_id_content_hash_key UNIQUE (user_id, content_hash);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_objects DROP COLUMN content_hash;
+ALTER TABLE file_uploads DROP COLUMN hash_state;
+ALTER TABLE file_uploads DROP COLUMN hashed_parts;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DELETE FROM user_data_history h USING user_data d WHERE d.id = h.data_id AND d.type = 'file';
+ALTER TABLE user_data_history DROP COLUMN file_size;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE user_data_history ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DROP INDEX user_data_tombstones_user_id_deleted_at_index;
+ALTER TABLE users DROP COLUMN tombstones_pruned_revision;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users ADD COLUMN tombstones_pruned_revision BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX user_data_tombstones_user_id_deleted_at_index ON user_data_tombstones(user_id, deleted_at);
+
+COMMIT;
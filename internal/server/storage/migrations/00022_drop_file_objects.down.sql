@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE file_objects(
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	object_name TEXT NOT NULL,
+	refs INT NOT NULL,
+	PRIMARY KEY (user_id, object_name)
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE file_objects;
+
+COMMIT;
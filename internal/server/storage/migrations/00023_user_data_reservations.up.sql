@@ -0,0 +1,11 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE user_data_reservations(
+	id INT PRIMARY KEY,
+	user_id INT REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_data_reservations_user_id_index ON user_data_reservations(user_id);
+
+COMMIT;
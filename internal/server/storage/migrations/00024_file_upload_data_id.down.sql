@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_uploads DROP COLUMN data_id;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE file_uploads ADD COLUMN data_id INT;
+
+COMMIT;
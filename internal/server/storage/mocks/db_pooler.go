@@ -36,6 +36,21 @@ func (m *MockDBPooler) EXPECT() *MockDBPoolerMockRecorder {
 	return m.recorder
 }
 
+// Begin mocks base method.
+func (m *MockDBPooler) Begin(ctx context.Context) (pgx.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockDBPoolerMockRecorder) Begin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockDBPooler)(nil).Begin), ctx)
+}
+
 // Close mocks base method.
 func (m *MockDBPooler) Close() {
 	m.ctrl.T.Helper()
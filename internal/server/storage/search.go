@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+// ErrInvalidSort поле сортировки данных пользователя не поддерживается.
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// userDataSortColumns выражения для сортировки найденных данных пользователя по полям фильтра.
+var userDataSortColumns = map[string]string{
+	"id":      "id",
+	"type":    "type",
+	"mark":    "lower(mark)",
+	"created": "created_at",
+	"updated": "updated_at",
+}
+
+// likeEscaper экранирует специальные символы шаблона LIKE.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUserData найти данные пользователя по фильтру. Данные в корзине не возвращаются,
+// а при поиске по папке возвращаются и данные из вложенных папок.
+// Без сортировки при полнотекстовом поиске данные упорядочены по релевантности, иначе по ID.
+func (s *Storage) SearchUserData(ctx context.Context, filter models.UserDataFilter) (models.UserDataPage, error) {
+	page := models.UserDataPage{Items: []models.UserDataItem{}}
+
+	where, countArgs := userDataConditions(ctx, filter)
+
+	order, args, err := userDataOrder(filter, countArgs)
+	if err != nil {
+		return page, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM user_data WHERE ` + where
+	if err := s.pool.QueryRow(ctx, countQuery, countArgs...).Scan(&page.Total); err != nil {
+		return page, fmt.Errorf(failedScanStr, err)
+	}
+	if page.Total == 0 || filter.Offset >= page.Total {
+		return page, nil
+	}
+
+	query := `SELECT id, type, mark, description, version, created_at, updated_at FROM user_data WHERE ` +
+		where + ` ORDER BY ` + order
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $` + strconv.Itoa(len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += ` OFFSET $` + strconv.Itoa(len(args))
+	}
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return page, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i models.UserDataItem
+		if err := rows.Scan(&i.ID, &i.Type, &i.Mark, &i.Description, &i.Version, &i.CreatedAt, &i.UpdatedAt); err != nil {
+			return page, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		page.Items = append(page.Items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return page, nil
+}
+
+// userDataConditions возвращает условия поиска данных пользователя из контекста по фильтру и их параметры.
+func userDataConditions(ctx context.Context, filter models.UserDataFilter) (string, []any) {
+	conds := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []any{ctx.Value(constants.KeyUserID)}
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if filter.Type != "" {
+		add("type = ?", filter.Type)
+	}
+	if filter.MarkPrefix != "" {
+		add(`lower(mark) LIKE ? ESCAPE '\'`, likeEscaper.Replace(strings.ToLower(filter.MarkPrefix))+"%")
+	}
+	if filter.Search != "" {
+		add("search_vector @@ plainto_tsquery('simple', ?)", filter.Search)
+	}
+	if filter.Tag != "" {
+		add("id IN (SELECT dt.data_id FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id "+
+			"WHERE t.user_id = $1 AND t.name = ?)", filter.Tag)
+	}
+	if filter.Folder != "" {
+		add("folder_id IN (SELECT id FROM folders WHERE user_id = $1 AND (path = ? OR starts_with(path, ? || '/')))",
+			filter.Folder)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("created_at <= ?", filter.CreatedTo)
+	}
+	if !filter.UpdatedFrom.IsZero() {
+		add("updated_at >= ?", filter.UpdatedFrom)
+	}
+	if !filter.UpdatedTo.IsZero() {
+		add("updated_at <= ?", filter.UpdatedTo)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// userDataOrder возвращает порядок найденных данных пользователя и копию параметров запроса,
+// дополненную параметрами порядка.
+func userDataOrder(filter models.UserDataFilter, args []any) (string, []any, error) {
+	if filter.Sort == "" {
+		if filter.Search == "" {
+			return "id", args, nil
+		}
+
+		args = append(args[:len(args):len(args)], filter.Search)
+		return "ts_rank(search_vector, plainto_tsquery('simple', $" + strconv.Itoa(len(args)) + ")) DESC, id", args, nil
+	}
+
+	field, desc := strings.CutPrefix(filter.Sort, "-")
+
+	order, ok := userDataSortColumns[field]
+	if !ok {
+		return "", args, ErrInvalidSort
+	}
+	if desc {
+		order += " DESC"
+	}
+	if field != "id" {
+		order += ", id"
+	}
+
+	return order, args, nil
+}
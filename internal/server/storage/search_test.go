@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSearchUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+	row := mocks.NewMockRow(mockCtrl)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	expectCount := func(query string, total int, args ...any) {
+		pool.EXPECT().QueryRow(ctx, query, args...).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = total
+			return nil
+		})
+	}
+	expectRows := func(query string, args ...any) {
+		pool.EXPECT().Query(ctx, query, args...).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(true)
+		rows.EXPECT().Next().Times(1).Return(false)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 2
+			*dest[1].(*string) = "card"
+			*dest[2].(*string) = "aws"
+			*dest[3].(*string) = "root account"
+			*dest[4].(*int) = 3
+			*dest[5].(*time.Time) = createdAt
+			*dest[6].(*time.Time) = createdAt
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+	}
+	item := models.UserDataItem{
+		ID: 2, Type: "card", Mark: "aws", Description: "root account", Version: 3, CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+
+	t.Run("search without filters", func(t *testing.T) {
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE user_id = $1 AND deleted_at IS NULL`, 1, 1)
+		expectRows(`SELECT id, type, mark, description, version, created_at, updated_at FROM user_data `+
+			`WHERE user_id = $1 AND deleted_at IS NULL ORDER BY id`, 1)
+
+		page, err := storage.SearchUserData(ctx, models.UserDataFilter{})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserDataPage{Items: []models.UserDataItem{item}, Total: 1}, page)
+	})
+
+	t.Run("search with all filters", func(t *testing.T) {
+		filter := models.UserDataFilter{
+			Type:        "card",
+			MarkPrefix:  "A_w%",
+			Search:      "root",
+			CreatedFrom: createdAt,
+			CreatedTo:   createdAt.Add(time.Hour),
+			UpdatedFrom: createdAt,
+			UpdatedTo:   createdAt.Add(time.Hour),
+			Sort:        "-updated",
+			Limit:       20,
+			Offset:      1,
+		}
+		where := `user_id = $1 AND deleted_at IS NULL AND type = $2 AND lower(mark) LIKE $3 ESCAPE '\' ` +
+			`AND search_vector @@ plainto_tsquery('simple', $4) AND created_at >= $5 AND created_at <= $6 ` +
+			`AND updated_at >= $7 AND updated_at <= $8`
+		args := []any{1, "card", `a\_w\%%`, "root", createdAt, createdAt.Add(time.Hour), createdAt, createdAt.Add(time.Hour)}
+
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE `+where, 5, args...)
+		expectRows(`SELECT id, type, mark, description, version, created_at, updated_at FROM user_data WHERE `+
+			where+` ORDER BY updated_at DESC, id LIMIT $9 OFFSET $10`, append(args, 20, 1)...)
+
+		page, err := storage.SearchUserData(ctx, filter)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserDataPage{Items: []models.UserDataItem{item}, Total: 5}, page)
+	})
+
+	t.Run("search ordered by relevance", func(t *testing.T) {
+		where := `user_id = $1 AND deleted_at IS NULL AND search_vector @@ plainto_tsquery('simple', $2)`
+
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE `+where, 1, 1, "root")
+		expectRows(`SELECT id, type, mark, description, version, created_at, updated_at FROM user_data WHERE `+
+			where+` ORDER BY ts_rank(search_vector, plainto_tsquery('simple', $3)) DESC, id`, 1, "root", "root")
+
+		page, err := storage.SearchUserData(ctx, models.UserDataFilter{Search: "root"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+	})
+
+	t.Run("search by tag and folder", func(t *testing.T) {
+		where := `user_id = $1 AND deleted_at IS NULL AND id IN (SELECT dt.data_id FROM user_data_tags dt ` +
+			`JOIN tags t ON t.id = dt.tag_id WHERE t.user_id = $1 AND t.name = $2) AND folder_id IN ` +
+			`(SELECT id FROM folders WHERE user_id = $1 AND (path = $3 OR starts_with(path, $3 || '/')))`
+
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE `+where, 1, 1, "prod", "work")
+		expectRows(`SELECT id, type, mark, description, version, created_at, updated_at FROM user_data WHERE `+
+			where+` ORDER BY id`, 1, "prod", "work")
+
+		page, err := storage.SearchUserData(ctx, models.UserDataFilter{Tag: "prod", Folder: "work"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE user_id = $1 AND deleted_at IS NULL AND type = $2`, 0, 1, "otp")
+
+		page, err := storage.SearchUserData(ctx, models.UserDataFilter{Type: "otp"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserDataPage{Items: []models.UserDataItem{}}, page)
+	})
+
+	t.Run("offset after last found", func(t *testing.T) {
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE user_id = $1 AND deleted_at IS NULL`, 3, 1)
+
+		page, err := storage.SearchUserData(ctx, models.UserDataFilter{Offset: 3})
+
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, 3, page.Total)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, err := storage.SearchUserData(ctx, models.UserDataFilter{Sort: "data"})
+
+		require.ErrorIs(t, err, ErrInvalidSort)
+	})
+
+	t.Run("failed count", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, gomock.Any(), 1).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.SearchUserData(ctx, models.UserDataFilter{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+
+	t.Run("failed search", func(t *testing.T) {
+		expectCount(`SELECT COUNT(*) FROM user_data WHERE user_id = $1 AND deleted_at IS NULL`, 1, 1)
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.SearchUserData(ctx, models.UserDataFilter{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
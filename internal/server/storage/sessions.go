@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionColumns столбцы сессии в порядке, ожидаемом scanSession.
+const sessionColumns = `id, user_id, created_at, last_used_at, expires_at, device_name, client_version, ip`
+
+// AddSession сохранить новую сессию пользователя.
+func (s *Storage) AddSession(ctx context.Context, session models.Session, refreshTokenHash []byte) error {
+	const stmt = `
+		INSERT INTO sessions (id, user_id, refresh_token_hash, expires_at, device_name, client_version, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := s.pool.Exec(
+		ctx, stmt,
+		session.ID, session.UserID, refreshTokenHash, session.ExpiresAt,
+		session.DeviceName, session.ClientVersion, session.IP,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute add session query: %w", err)
+	}
+
+	return nil
+}
+
+// TouchSession получить активную сессию и отметить время и адрес последнего обращения.
+func (s *Storage) TouchSession(ctx context.Context, sessionID, ip string) (models.Session, error) {
+	const query = `
+		UPDATE sessions SET last_used_at = now(), ip = $2
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > now()
+		RETURNING ` + sessionColumns
+
+	return scanSession(s.pool.QueryRow(ctx, query, sessionID, ip))
+}
+
+// ListSessions получить активные сессии пользователя.
+func (s *Storage) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	const query = `
+		SELECT ` + sessionColumns + ` FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_used_at DESC
+	`
+
+	sessions := []models.Session{}
+
+	rows, err := s.pool.Query(ctx, query, userID)
+	if err != nil {
+		return []models.Session{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return []models.Session{}, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []models.Session{}, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RotateSessionRefreshToken заменить refresh токен активной сессии и продлить ее.
+// Старый токен после замены становится недействительным.
+func (s *Storage) RotateSessionRefreshToken(
+	ctx context.Context,
+	oldHash, newHash []byte,
+	expiresAt time.Time,
+) (models.Session, error) {
+	const query = `
+		UPDATE sessions SET refresh_token_hash = $2, expires_at = $3, last_used_at = now()
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > now()
+		RETURNING ` + sessionColumns
+
+	return scanSession(s.pool.QueryRow(ctx, query, oldHash, newHash, expiresAt))
+}
+
+// RevokeSession отозвать сессию пользователя.
+func (s *Storage) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	const stmt = `
+		UPDATE sessions SET revoked_at = now()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := s.pool.Exec(ctx, stmt, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to execute revoke session query: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// scanSession прочитать сессию из строки ответа.
+func scanSession(row pgx.Row) (models.Session, error) {
+	var session models.Session
+
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt,
+		&session.DeviceName, &session.ClientVersion, &session.IP,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Session{}, ErrSessionNotFound
+		}
+
+		return models.Session{}, fmt.Errorf(failedScanStr, err)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAddSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	session := models.Session{
+		ID:            "session",
+		UserID:        1,
+		ExpiresAt:     time.Now(),
+		DeviceName:    "laptop",
+		ClientVersion: "0.0.1",
+		IP:            "192.0.2.1",
+	}
+	hash := []byte("hash")
+
+	t.Run("success add session", func(t *testing.T) {
+		pool.EXPECT().
+			Exec(ctx, gomock.Any(), session.ID, session.UserID, hash, session.ExpiresAt, "laptop", "0.0.1", "192.0.2.1").
+			Times(1).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		err := storage.AddSession(ctx, session, hash)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("failed add session", func(t *testing.T) {
+		pool.EXPECT().
+			Exec(ctx, gomock.Any(), session.ID, session.UserID, hash, session.ExpiresAt, "laptop", "0.0.1", "192.0.2.1").
+			Times(1).Return(pgconn.CommandTag{}, errors.New("some error"))
+
+		err := storage.AddSession(ctx, session, hash)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute add session query")
+	})
+}
+
+func TestTouchSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	row := mocks.NewMockRow(mockCtrl)
+	sessionID := "session"
+
+	tests := []struct {
+		name    string
+		rowErr  error
+		wantErr error
+		errText string
+	}{
+		{
+			name:    "success get session",
+			rowErr:  nil,
+			wantErr: nil,
+			errText: "",
+		},
+		{
+			name:    "session not found",
+			rowErr:  pgx.ErrNoRows,
+			wantErr: ErrSessionNotFound,
+			errText: "session not found",
+		},
+		{
+			name:    "failed read row",
+			rowErr:  errors.New("some error"),
+			wantErr: nil,
+			errText: "failed to scan a response row",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, gomock.Any(), sessionID, "192.0.2.1").Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+
+			_, err := storage.TouchSession(ctx, sessionID, "192.0.2.1")
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	rows := mocks.NewMockRows(mockCtrl)
+	userID := 1
+
+	t.Run("success list sessions", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), userID).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).Return(nil)
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		sessions, err := storage.ListSessions(ctx, userID)
+
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1)
+	})
+
+	t.Run("failed scan session", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), userID).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(true)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.ListSessions(ctx, userID)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+
+	t.Run("failed execute query", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), userID).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.ListSessions(ctx, userID)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestRotateSessionRefreshToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	row := mocks.NewMockRow(mockCtrl)
+	oldHash := []byte("old")
+	newHash := []byte("new")
+	expiresAt := time.Now()
+
+	tests := []struct {
+		name    string
+		rowErr  error
+		wantErr error
+	}{
+		{
+			name:    "success rotate refresh token",
+			rowErr:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "refresh token already used",
+			rowErr:  pgx.ErrNoRows,
+			wantErr: ErrSessionNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, gomock.Any(), oldHash, newHash, expiresAt).Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+
+			_, err := storage.RotateSessionRefreshToken(ctx, oldHash, newHash, expiresAt)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	userID := 1
+	sessionID := "session"
+
+	tests := []struct {
+		name    string
+		tag     pgconn.CommandTag
+		execErr error
+		wantErr bool
+		errText string
+	}{
+		{
+			name:    "success revoke session",
+			tag:     pgconn.NewCommandTag("UPDATE 1"),
+			execErr: nil,
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:    "when session not found",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			execErr: nil,
+			wantErr: true,
+			errText: "session not found",
+		},
+		{
+			name:    "failed revoke session",
+			tag:     pgconn.CommandTag{},
+			execErr: errors.New("some error"),
+			wantErr: true,
+			errText: "failed to execute revoke session query",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, gomock.Any(), sessionID, userID).Times(1).Return(test.tag, test.execErr)
+
+			err := storage.RevokeSession(ctx, userID, sessionID)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
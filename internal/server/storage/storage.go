@@ -18,8 +18,14 @@ import (
 )
 
 var (
-	ErrUserNotFound     = errors.New("user not found")
-	ErrUserDataNotFound = errors.New("user data not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserDataNotFound   = errors.New("user data not found")
+	ErrUserKeyNotFound    = errors.New("user key not found")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRecoveryCodeUsed   = errors.New("recovery code not found or already used")
+	ErrFileUploadNotFound = errors.New("file upload not found")
+	ErrVersionConflict    = errors.New("user data version conflict")
+	ErrDataIDNotReserved  = errors.New("user data id is not reserved")
 )
 
 const failedScanStr = "failed to scan a response row: %w"
@@ -30,6 +36,7 @@ type DBPooler interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
 	Ping(ctx context.Context) error
 	Close()
 }
@@ -128,12 +135,12 @@ func (s *Storage) AddUser(ctx context.Context, userLogin string, userPassword []
 
 // GetUserByLogin получить пользователя по логину.
 func (s *Storage) GetUserByLogin(ctx context.Context, userLogin string) (models.User, error) {
-	const query = `SELECT id, login, password FROM users WHERE login = $1 LIMIT 1`
+	const query = `SELECT id, login, password, totp_enabled FROM users WHERE login = $1 LIMIT 1`
 
 	row := s.pool.QueryRow(ctx, query, userLogin)
 
 	var u models.User
-	err := row.Scan(&u.ID, &u.Login, &u.Password)
+	err := row.Scan(&u.ID, &u.Login, &u.Password, &u.TOTPEnabled)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%w with ID: %s", ErrUserNotFound, userLogin)
@@ -147,12 +154,12 @@ func (s *Storage) GetUserByLogin(ctx context.Context, userLogin string) (models.
 
 // GetUserByID получить пользователя по его ID.
 func (s *Storage) GetUserByID(ctx context.Context, userID int) (models.User, error) {
-	const query = `SELECT id, login, password FROM users WHERE id = $1 LIMIT 1`
+	const query = `SELECT id, login, password, totp_enabled FROM users WHERE id = $1 LIMIT 1`
 
 	row := s.pool.QueryRow(ctx, query, userID)
 
 	var u models.User
-	err := row.Scan(&u.ID, &u.Login, &u.Password)
+	err := row.Scan(&u.ID, &u.Login, &u.Password, &u.TOTPEnabled)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%w with ID: %d", ErrUserNotFound, userID)
@@ -164,9 +171,15 @@ func (s *Storage) GetUserByID(ctx context.Context, userID int) (models.User, err
 	return u, nil
 }
 
-// FetchUserData получить базовую информацию о данных пользователя.
+// FetchUserData получить базовую информацию о данных пользователя вместе с папками и метками.
 func (s *Storage) FetchUserData(ctx context.Context) ([]models.UserData, error) {
-	const query = `SELECT id, type, mark, description FROM user_data WHERE user_id = $1`
+	const query = `
+		SELECT d.id, d.type, d.mark, d.description, d.version, COALESCE(f.path, ''), ARRAY(
+			SELECT t.name FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id WHERE dt.data_id = d.id ORDER BY t.name
+		)
+		FROM user_data d LEFT JOIN folders f ON f.id = d.folder_id
+		WHERE d.user_id = $1 AND d.deleted_at IS NULL
+	`
 
 	data := []models.UserData{}
 
@@ -178,7 +191,7 @@ func (s *Storage) FetchUserData(ctx context.Context) ([]models.UserData, error)
 
 	for rows.Next() {
 		var d models.UserData
-		err = rows.Scan(&d.ID, &d.Type, &d.Mark, &d.Description)
+		err = rows.Scan(&d.ID, &d.Type, &d.Mark, &d.Description, &d.Version, &d.Folder, &d.Tags)
 		if err != nil {
 			return []models.UserData{}, fmt.Errorf("failed to scan query: %w", err)
 		}
@@ -194,35 +207,213 @@ func (s *Storage) FetchUserData(ctx context.Context) ([]models.UserData, error)
 	return data, nil
 }
 
-// AddUserData добавить данные пользователя.
+// AddUserData добавить данные пользователя вместе с папкой и метками.
 func (s *Storage) AddUserData(
 	ctx context.Context,
 	encData []byte,
 	mark string,
 	description string,
-	dataType string) (int, error) {
+	dataType string,
+	labels models.UserDataLabels) (int, error) {
+	const stmt = `
+		WITH rev AS (UPDATE users SET data_revision = data_revision + 1 WHERE id = $1 RETURNING data_revision)
+		INSERT INTO user_data (user_id, data, mark, description, type, revision)
+		VALUES ($1, $2, $3, $4, $5, (SELECT data_revision FROM rev))
+		RETURNING id
+	`
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	return s.addUserData(ctx, userID, labels, stmt, userID, encData, mark, description, dataType)
+}
+
+// ReserveUserDataID зарезервировать ID для данных пользователя, которые будут добавлены позже.
+// ID берется из той же последовательности, что и ID данных, поэтому он не совпадет с ID других данных.
+// Неиспользованные резервы пользователя старше суток удаляются.
+func (s *Storage) ReserveUserDataID(ctx context.Context) (int, error) {
 	const stmt = `
-		INSERT INTO user_data (user_id, data, mark, description, type) 
-		VALUES ($1, $2, $3, $4, $5) 
+		WITH stale AS (
+			DELETE FROM user_data_reservations WHERE user_id = $1 AND created_at < now() - interval '1 day'
+		)
+		INSERT INTO user_data_reservations (id, user_id)
+		VALUES (nextval(pg_get_serial_sequence('user_data', 'id')), $1)
 		RETURNING id
 	`
-	row := s.pool.QueryRow(ctx, stmt, ctx.Value(constants.KeyUserID), encData, mark, description, dataType)
 
 	var id int
 
-	err := row.Scan(&id)
+	row := s.pool.QueryRow(ctx, stmt, ctx.Value(constants.KeyUserID))
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf(failedScanStr, err)
+	}
+
+	return id, nil
+}
+
+// AddReservedUserData добавить данные пользователя с зарезервированным ID вместе с папкой и метками.
+// Резерв удаляется в одной транзакции с добавлением данных. Если ID не зарезервирован пользователем
+// или уже использован, данные не добавляются и возвращается ErrDataIDNotReserved.
+func (s *Storage) AddReservedUserData(
+	ctx context.Context,
+	id int,
+	encData []byte,
+	mark string,
+	description string,
+	dataType string,
+	labels models.UserDataLabels,
+) (int, error) {
+	const stmt = `
+		WITH reserved AS (
+			DELETE FROM user_data_reservations WHERE id = $6 AND user_id = $1 RETURNING id
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM reserved) RETURNING data_revision
+		)
+		INSERT INTO user_data (id, user_id, data, mark, description, type, revision)
+		OVERRIDING SYSTEM VALUE
+		SELECT id, $1, $2, $3, $4, $5, (SELECT data_revision FROM rev) FROM reserved
+		RETURNING id
+	`
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	dataID, err := s.addUserData(ctx, userID, labels, stmt, userID, encData, mark, description, dataType, id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to scan a response row: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrDataIDNotReserved
+		}
+
+		return 0, err
+	}
+
+	return dataID, nil
+}
+
+// AddFileUserData добавить данные файла пользователя с размером файла fileSize, который учитывается в квоте,
+// вместе с папкой и метками.
+func (s *Storage) AddFileUserData(
+	ctx context.Context,
+	encData []byte,
+	mark string,
+	description string,
+	fileSize int64,
+	labels models.UserDataLabels,
+) (int, error) {
+	const stmt = `
+		WITH rev AS (UPDATE users SET data_revision = data_revision + 1 WHERE id = $1 RETURNING data_revision)
+		INSERT INTO user_data (user_id, data, mark, description, type, file_size, revision)
+		VALUES ($1, $2, $3, $4, 'file', $5, (SELECT data_revision FROM rev))
+		RETURNING id
+	`
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	return s.addUserData(ctx, userID, labels, stmt, userID, encData, mark, description, fileSize)
+}
+
+// addUserData функция для добавления данных пользователя запросом stmt и записи их папки и меток
+// в одной транзакции: данные без папки и меток не сохраняются.
+func (s *Storage) addUserData(
+	ctx context.Context,
+	userID any,
+	labels models.UserDataLabels,
+	stmt string,
+	args ...any,
+) (id int, err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("failed to rollback transaction", zap.Error(rbErr))
+			}
+		}
+	}()
+
+	if err = tx.QueryRow(ctx, stmt, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf(failedScanStr, err)
+	}
+
+	if err = writeUserDataLabels(ctx, tx, userID, id, labels); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return id, nil
 }
 
+// UpdateFileUserData заменить данные файла пользователя вместе с размером файла.
+// Заменяемая версия файла сохраняется в истории вместе со ссылкой на ее объект.
+func (s *Storage) UpdateFileUserData(
+	ctx context.Context,
+	id int,
+	encData []byte,
+	mark string,
+	description string,
+	fileSize int64,
+) error {
+	const stmt = `
+		WITH old AS (
+			SELECT id, user_id, version, data, mark, description, file_size FROM user_data
+			WHERE user_id = $5 AND id = $6 AND type = 'file' AND deleted_at IS NULL FOR UPDATE
+		), history AS (
+			INSERT INTO user_data_history (data_id, user_id, version, data, mark, description, file_size)
+			SELECT id, user_id, version, data, mark, description, file_size FROM old
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $5 AND EXISTS (SELECT 1 FROM old) RETURNING data_revision
+		)
+		UPDATE user_data SET data = $1, mark = $2, description = $3, file_size = $4, object_missing_at = NULL,
+			version = version + 1, revision = (SELECT data_revision FROM rev), updated_at = now()
+		WHERE user_id = $5 AND id = $6 AND type = 'file' AND deleted_at IS NULL
+	`
+
+	tag, err := s.pool.Exec(ctx, stmt, encData, mark, description, fileSize, ctx.Value(constants.KeyUserID), id)
+	if err != nil {
+		return fmt.Errorf("failed to execute update user data query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserDataNotFound
+	}
+
+	return nil
+}
+
+// GetUserUsage получить количество записей пользователя и занятый ими объем.
+// В объем входят зашифрованные данные, размеры файлов, в том числе прежних версий из истории,
+// и заявленные размеры незавершенных загрузок. Незавершенные загрузки учитываются и как записи:
+// каждая из них после завершения станет файлом. Данные в корзине учитываются, пока не удалены окончательно.
+func (s *Storage) GetUserUsage(ctx context.Context) (models.UserUsage, error) {
+	const query = `
+		SELECT
+			(SELECT COUNT(*) FROM user_data WHERE user_id = $1) +
+			(SELECT COUNT(*) FROM file_uploads WHERE user_id = $1),
+			(SELECT COALESCE(SUM(octet_length(data) + file_size), 0)::BIGINT FROM user_data WHERE user_id = $1) +
+			(SELECT COALESCE(SUM(file_size), 0)::BIGINT FROM user_data_history WHERE user_id = $1) +
+			(SELECT COALESCE(SUM(file_size), 0)::BIGINT FROM file_uploads WHERE user_id = $1)
+	`
+
+	var usage models.UserUsage
+
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID))
+	if err := row.Scan(&usage.Items, &usage.UsedBytes); err != nil {
+		return usage, fmt.Errorf(failedScanStr, err)
+	}
+
+	return usage, nil
+}
+
 // GetUserData получить данные пользователя.
 func (s *Storage) GetUserData(ctx context.Context, id int, dataType string) ([]byte, string, string, error) {
 	const query = `
 		SELECT data, mark, description FROM user_data 
-		WHERE user_id = $1 AND id = $2 AND type = $3 LIMIT 1
+		WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL LIMIT 1
 	`
 
 	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID), id, dataType)
@@ -243,25 +434,418 @@ func (s *Storage) GetUserData(ctx context.Context, id int, dataType string) ([]b
 	return data, mark, description, nil
 }
 
-// GetFileUserData получить файл пользователя.
-func (s *Storage) GetFileUserData(ctx context.Context, fileMark string) ([]byte, error) {
+// UpdateUserData обновить данные пользователя вместе с папкой и метками в одной транзакции.
+// Если version не 0, данные обновляются, только если их текущая версия равна version, иначе возвращается
+// ErrVersionConflict. Пустые папка и метки в labels не меняются.
+// Заменяемая версия данных, кроме файлов, сохраняется в истории: у файлов так меняются только метка
+// и описание, а в истории хранятся прежние версии содержимого (UpdateFileUserData).
+func (s *Storage) UpdateUserData(
+	ctx context.Context,
+	id int,
+	version int,
+	encData []byte,
+	mark string,
+	description string,
+	dataType string,
+	labels models.UserDataLabels,
+) (err error) {
+	const stmt = `
+		WITH old AS (
+			SELECT id, user_id, version, data, mark, description FROM user_data
+			WHERE user_id = $4 AND id = $5 AND type = $6 AND deleted_at IS NULL AND ($7 = 0 OR version = $7) FOR UPDATE
+		), history AS (
+			INSERT INTO user_data_history (data_id, user_id, version, data, mark, description)
+			SELECT id, user_id, version, data, mark, description FROM old WHERE $6 <> 'file'
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $4 AND EXISTS (SELECT 1 FROM old) RETURNING data_revision
+		)
+		UPDATE user_data SET data = $1, mark = $2, description = $3, object_missing_at = NULL,
+			version = version + 1, revision = (SELECT data_revision FROM rev), updated_at = now()
+		WHERE user_id = $4 AND id = $5 AND type = $6 AND deleted_at IS NULL AND ($7 = 0 OR version = $7)
+	`
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("failed to rollback transaction", zap.Error(rbErr))
+			}
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, stmt, encData, mark, description, userID, id, dataType, version)
+	if err != nil {
+		return fmt.Errorf("failed to execute update user data query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		if version == 0 {
+			return ErrUserDataNotFound
+		}
+
+		err = s.checkUserDataExists(ctx, id, dataType)
+		return err
+	}
+
+	if labels.Folder != nil || labels.Tags != nil {
+		if err = replaceUserDataLabels(ctx, tx, userID, id, labels); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// checkUserDataExists возвращает ErrVersionConflict, если данные пользователя есть,
+// и ErrUserDataNotFound, если их нет.
+func (s *Storage) checkUserDataExists(ctx context.Context, id int, dataType string) error {
+	exists, err := s.userDataExists(ctx, id, dataType)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrUserDataNotFound
+	}
+
+	return ErrVersionConflict
+}
+
+// userDataExists проверяет, есть ли у пользователя данные с указанным ID и типом.
+func (s *Storage) userDataExists(ctx context.Context, id int, dataType string) (bool, error) {
 	const query = `
-		SELECT data FROM user_data 
-		WHERE user_id = $1 AND mark = $2 AND type = 'file' LIMIT 1
+		SELECT EXISTS(SELECT 1 FROM user_data WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL)
 	`
 
-	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID), fileMark)
+	var exists bool
 
-	var data []byte
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID), id, dataType)
+	if err := row.Scan(&exists); err != nil {
+		return false, fmt.Errorf(failedScanStr, err)
+	}
+
+	return exists, nil
+}
+
+// DeleteUserData переместить данные пользователя в корзину. Данные остаются в базе до окончательного удаления,
+// а для синхронизации сохраняется отметка об удалении, по которой клиенты узнают об удалении.
+func (s *Storage) DeleteUserData(ctx context.Context, id int, dataType string) error {
+	const stmt = `
+		WITH trashed AS (
+			SELECT id FROM user_data WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL FOR UPDATE
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM trashed) RETURNING data_revision
+		), tombstone AS (
+			INSERT INTO user_data_tombstones (user_id, data_id, revision)
+			SELECT $1, trashed.id, rev.data_revision FROM trashed, rev
+			ON CONFLICT (user_id, data_id) DO UPDATE SET revision = EXCLUDED.revision, deleted_at = now()
+		)
+		UPDATE user_data SET deleted_at = now(), revision = (SELECT data_revision FROM rev)
+		FROM trashed WHERE user_data.id = trashed.id
+	`
 
-	err := row.Scan(&data)
+	tag, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), id, dataType)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrUserDataNotFound
+		return fmt.Errorf("failed to execute delete user data query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserDataNotFound
+	}
+
+	return nil
+}
+
+// FetchEncryptedUserData получить порцию зашифрованных данных всех пользователей, начиная после указанного ID.
+func (s *Storage) FetchEncryptedUserData(ctx context.Context, afterID int, limit int) ([]models.EncryptedUserData, error) {
+	const query = `SELECT id, user_id, data FROM user_data WHERE id > $1 ORDER BY id LIMIT $2`
+
+	data := []models.EncryptedUserData{}
+
+	rows, err := s.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return []models.EncryptedUserData{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.EncryptedUserData
+		err = rows.Scan(&d.ID, &d.UserID, &d.Data)
+		if err != nil {
+			return []models.EncryptedUserData{}, fmt.Errorf("failed to scan query: %w", err)
 		}
 
-		return nil, fmt.Errorf("failed to scan a response row: %w", err)
+		data = append(data, d)
+	}
+
+	rowsErr := rows.Err()
+	if rowsErr != nil {
+		return []models.EncryptedUserData{}, fmt.Errorf("failed to read query: %w", rowsErr)
+	}
+
+	return data, nil
+}
+
+// ReplaceEncryptedUserData заменить зашифрованные данные, если они не изменились с момента чтения.
+func (s *Storage) ReplaceEncryptedUserData(ctx context.Context, id int, oldData []byte, newData []byte) (bool, error) {
+	const stmt = `UPDATE user_data SET data = $1 WHERE id = $2 AND data = $3`
+
+	tag, err := s.pool.Exec(ctx, stmt, newData, id, oldData)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute replace user data query: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// FetchUserIDs получить идентификаторы пользователей порциями по limit, начиная после afterID.
+func (s *Storage) FetchUserIDs(ctx context.Context, afterID int, limit int) ([]int, error) {
+	const query = `SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2`
+
+	rows, err := s.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return ids, nil
+}
+
+// FetchFileUserData получить зашифрованные данные всех файлов пользователя, в том числе в корзине:
+// их объекты используются, пока файлы не удалены окончательно.
+func (s *Storage) FetchFileUserData(ctx context.Context) ([]models.FileUserData, error) {
+	const query = `
+		SELECT id, data, object_missing_at IS NOT NULL FROM user_data
+		WHERE user_id = $1 AND type = 'file' ORDER BY id
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	data := []models.FileUserData{}
+
+	for rows.Next() {
+		var d models.FileUserData
+		if err := rows.Scan(&d.ID, &d.Data, &d.ObjectMissing); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		data = append(data, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
 	}
 
 	return data, nil
 }
+
+// SetFileUserDataMissing отметить данные файла пользователя, объект которых не найден в файловом хранилище,
+// или снять отметку. Время первого обнаружения сохраняется.
+func (s *Storage) SetFileUserDataMissing(ctx context.Context, id int, missing bool) error {
+	const stmt = `
+		UPDATE user_data SET object_missing_at = CASE WHEN $3 THEN COALESCE(object_missing_at, now()) END
+		WHERE id = $1 AND user_id = $2 AND type = 'file'
+	`
+
+	_, err := s.pool.Exec(ctx, stmt, id, ctx.Value(constants.KeyUserID), missing)
+	if err != nil {
+		return fmt.Errorf("failed to execute update file user data query: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserKey получить зашифрованный ключ данных пользователя.
+func (s *Storage) GetUserKey(ctx context.Context) ([]byte, error) {
+	const query = `SELECT data_key FROM user_keys WHERE user_id = $1`
+
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID))
+
+	var dataKey []byte
+
+	err := row.Scan(&dataKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserKeyNotFound
+		}
+
+		return nil, fmt.Errorf(failedScanStr, err)
+	}
+
+	return dataKey, nil
+}
+
+// AddUserKey сохранить зашифрованный ключ данных пользователя, если он еще не создан.
+func (s *Storage) AddUserKey(ctx context.Context, dataKey []byte, keyID string) error {
+	const stmt = `
+		INSERT INTO user_keys (user_id, data_key, key_id) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+
+	_, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), dataKey, keyID)
+	if err != nil {
+		return fmt.Errorf("failed to execute add user key query: %w", err)
+	}
+
+	return nil
+}
+
+// CountUserKeysToRotate получить количество ключей данных, зашифрованных не указанным мастер-ключом.
+func (s *Storage) CountUserKeysToRotate(ctx context.Context, keyID string) (int, error) {
+	const query = `SELECT COUNT(*) FROM user_keys WHERE key_id <> $1`
+
+	row := s.pool.QueryRow(ctx, query, keyID)
+
+	var count int
+
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf(failedScanStr, err)
+	}
+
+	return count, nil
+}
+
+// RotateUserKeys перешифровать в одной транзакции порцию ключей данных, зашифрованных не указанным мастер-ключом.
+// Возвращает количество обработанных ключей, 0 означает что ротация завершена.
+func (s *Storage) RotateUserKeys(
+	ctx context.Context,
+	keyID string,
+	limit int,
+	rewrap func(dataKey []byte) ([]byte, error)) (n int, err error) {
+	const (
+		query = `
+			SELECT user_id, data_key FROM user_keys
+			WHERE key_id <> $1 ORDER BY user_id LIMIT $2 FOR UPDATE
+		`
+		stmt = `UPDATE user_keys SET data_key = $1, key_id = $2 WHERE user_id = $3`
+	)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("failed to rollback transaction", zap.Error(rbErr))
+			}
+		}
+	}()
+
+	rows, err := tx.Query(ctx, query, keyID, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	keys := map[int][]byte{}
+	userIDs := []int{}
+
+	for rows.Next() {
+		var userID int
+		var dataKey []byte
+		if err = rows.Scan(&userID, &dataKey); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		keys[userID] = dataKey
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		newKey, rwErr := rewrap(keys[userID])
+		if rwErr != nil {
+			err = fmt.Errorf("failed to rewrap key of user %d: %w", userID, rwErr)
+			return 0, err
+		}
+
+		if _, err = tx.Exec(ctx, stmt, newKey, keyID, userID); err != nil {
+			return 0, fmt.Errorf("failed to execute update user key query: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(userIDs), nil
+}
+
+// GetUserVault получить соль и проверочный блок ключа хранилища пользователя.
+func (s *Storage) GetUserVault(ctx context.Context) ([]byte, []byte, error) {
+	const query = `SELECT vault_salt, vault_check FROM users WHERE id = $1`
+
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID))
+
+	var salt, check []byte
+
+	err := row.Scan(&salt, &check)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrUserNotFound
+		}
+
+		return nil, nil, fmt.Errorf(failedScanStr, err)
+	}
+
+	return salt, check, nil
+}
+
+// SetUserVaultSalt сохранить соль ключа хранилища пользователя, если она еще не задана.
+func (s *Storage) SetUserVaultSalt(ctx context.Context, salt []byte) error {
+	const stmt = `UPDATE users SET vault_salt = $1 WHERE id = $2 AND vault_salt IS NULL`
+
+	_, err := s.pool.Exec(ctx, stmt, salt, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return fmt.Errorf("failed to execute set vault salt query: %w", err)
+	}
+
+	return nil
+}
+
+// SetUserVaultCheck сохранить проверочный блок ключа хранилища пользователя, если он еще не задан.
+func (s *Storage) SetUserVaultCheck(ctx context.Context, check []byte) (bool, error) {
+	const stmt = `UPDATE users SET vault_check = $1 WHERE id = $2 AND vault_check IS NULL`
+
+	tag, err := s.pool.Exec(ctx, stmt, check, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return false, fmt.Errorf("failed to execute set vault check query: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
 	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
 	"github.com/golang/mock/gomock"
@@ -137,7 +138,7 @@ func TestGetUserByLogin(t *testing.T) {
 		logger: logger,
 	}
 	ctx := context.Background()
-	stmt := `SELECT id, login, password FROM users WHERE login = $1 LIMIT 1`
+	stmt := `SELECT id, login, password, totp_enabled FROM users WHERE login = $1 LIMIT 1`
 
 	row := mocks.NewMockRow(mockCtrl)
 	login := "login"
@@ -196,7 +197,7 @@ func TestGetUserByID(t *testing.T) {
 		logger: logger,
 	}
 	ctx := context.Background()
-	stmt := `SELECT id, login, password FROM users WHERE id = $1 LIMIT 1`
+	stmt := `SELECT id, login, password, totp_enabled FROM users WHERE id = $1 LIMIT 1`
 
 	row := mocks.NewMockRow(mockCtrl)
 	userID := 1
@@ -256,7 +257,13 @@ func TestFetchUserData(t *testing.T) {
 	}
 	currentUserID := "some_id"
 	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
-	stmt := `SELECT id, type, mark, description FROM user_data WHERE user_id = $1`
+	stmt := `
+		SELECT d.id, d.type, d.mark, d.description, d.version, COALESCE(f.path, ''), ARRAY(
+			SELECT t.name FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id WHERE dt.data_id = d.id ORDER BY t.name
+		)
+		FROM user_data d LEFT JOIN folders f ON f.id = d.folder_id
+		WHERE d.user_id = $1 AND d.deleted_at IS NULL
+	`
 
 	rows := mocks.NewMockRows(mockCtrl)
 
@@ -311,7 +318,13 @@ func TestFetchUserData_Failed(t *testing.T) {
 	}
 	currentUserID := "some_id"
 	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
-	stmt := `SELECT id, type, mark, description FROM user_data WHERE user_id = $1`
+	stmt := `
+		SELECT d.id, d.type, d.mark, d.description, d.version, COALESCE(f.path, ''), ARRAY(
+			SELECT t.name FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id WHERE dt.data_id = d.id ORDER BY t.name
+		)
+		FROM user_data d LEFT JOIN folders f ON f.id = d.folder_id
+		WHERE d.user_id = $1 AND d.deleted_at IS NULL
+	`
 
 	rows := mocks.NewMockRows(mockCtrl)
 	someErr := errors.New("some error")
@@ -339,45 +352,238 @@ func TestAddUserData(t *testing.T) {
 	currentUserID := "some_id"
 	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
 	const stmt = `
-		INSERT INTO user_data (user_id, data, mark, description, type) 
-		VALUES ($1, $2, $3, $4, $5) 
+		WITH rev AS (UPDATE users SET data_revision = data_revision + 1 WHERE id = $1 RETURNING data_revision)
+		INSERT INTO user_data (user_id, data, mark, description, type, revision)
+		VALUES ($1, $2, $3, $4, $5, (SELECT data_revision FROM rev))
 		RETURNING id
 	`
 
-	row := mocks.NewMockRow(mockCtrl)
+	encData := []byte("some data")
+	mark := "test"
+	description := "test"
+	dataType := "files"
+	folder := "work"
+	labels := models.UserDataLabels{Folder: &folder}
+
+	t.Run("success add user data", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, stmt, currentUserID, encData, mark, description, dataType).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 5
+			return nil
+		})
+		tx.EXPECT().Exec(ctx, gomock.Any(), currentUserID, 5, folder).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+
+		id, err := storage.AddUserData(ctx, encData, mark, description, dataType, labels)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, id)
+	})
+
+	t.Run("failed read row", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, stmt, currentUserID, encData, mark, description, dataType).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.AddUserData(ctx, encData, mark, description, dataType, labels)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+
+	t.Run("failed set folder", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, stmt, currentUserID, encData, mark, description, dataType).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), currentUserID, 0, folder).Times(1).
+			Return(pgconn.CommandTag{}, errors.New("some error"))
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.AddUserData(ctx, encData, mark, description, dataType, labels)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute set user data folder query")
+	})
+}
+
+func TestReserveUserDataID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := "some_id"
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	const stmt = `
+		WITH stale AS (
+			DELETE FROM user_data_reservations WHERE user_id = $1 AND created_at < now() - interval '1 day'
+		)
+		INSERT INTO user_data_reservations (id, user_id)
+		VALUES (nextval(pg_get_serial_sequence('user_data', 'id')), $1)
+		RETURNING id
+	`
+
+	t.Run("success reserve user data id", func(t *testing.T) {
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().QueryRow(ctx, stmt, currentUserID).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 7
+			return nil
+		})
+
+		id, err := storage.ReserveUserDataID(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, id)
+	})
+
+	t.Run("failed read row", func(t *testing.T) {
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().QueryRow(ctx, stmt, currentUserID).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.ReserveUserDataID(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+}
+
+func TestAddReservedUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := "some_id"
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	const stmt = `
+		WITH reserved AS (
+			DELETE FROM user_data_reservations WHERE id = $6 AND user_id = $1 RETURNING id
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM reserved) RETURNING data_revision
+		)
+		INSERT INTO user_data (id, user_id, data, mark, description, type, revision)
+		OVERRIDING SYSTEM VALUE
+		SELECT id, $1, $2, $3, $4, $5, (SELECT data_revision FROM rev) FROM reserved
+		RETURNING id
+	`
 
 	encData := []byte("some data")
 	mark := "test"
 	description := "test"
+	dataType := "password"
+
+	t.Run("success add reserved user data", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, stmt, currentUserID, encData, mark, description, dataType, 7).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 7
+			return nil
+		})
+		tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+
+		id, err := storage.AddReservedUserData(ctx, 7, encData, mark, description, dataType, models.UserDataLabels{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, id)
+	})
+
+	t.Run("id is not reserved", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, stmt, currentUserID, encData, mark, description, dataType, 7).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(pgx.ErrNoRows)
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.AddReservedUserData(ctx, 7, encData, mark, description, dataType, models.UserDataLabels{})
+
+		require.ErrorIs(t, err, ErrDataIDNotReserved)
+	})
+}
+
+func TestGetUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	currentUserID := "some_id"
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	stmt := `
+		SELECT data, mark, description FROM user_data 
+		WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL LIMIT 1
+	`
+
+	row := mocks.NewMockRow(mockCtrl)
+	userDataID := 1
 	dataType := "files"
 
 	tests := []struct {
 		name    string
 		wantErr bool
+		errText string
 		rowErr  error
 	}{
 		{
-			name:    "success add user data",
+			name:    "success get",
 			wantErr: false,
+			errText: "",
 			rowErr:  nil,
 		},
 		{
 			name:    "failed read row",
 			wantErr: true,
+			errText: "failed to scan a response row",
 			rowErr:  errors.New("some error"),
 		},
+		{
+			name:    "not found row",
+			wantErr: true,
+			errText: "user data not found",
+			rowErr:  pgx.ErrNoRows,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pool.EXPECT().QueryRow(ctx, stmt, currentUserID, encData, mark, description, dataType).Times(1).Return(row)
+			pool.EXPECT().QueryRow(ctx, stmt, currentUserID, userDataID, dataType).Times(1).Return(row)
 
 			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
 
-			_, err := storage.AddUserData(ctx, encData, mark, description, dataType)
+			_, _, _, err := storage.GetUserData(ctx, userDataID, dataType)
 
 			if test.wantErr {
 				require.Error(t, err)
-				assert.ErrorContains(t, err, "failed to scan a response row", "some error")
+				assert.ErrorContains(t, err, test.errText)
 			} else {
 				require.NoError(t, err)
 			}
@@ -385,7 +591,7 @@ func TestAddUserData(t *testing.T) {
 	}
 }
 
-func TestGetUserData(t *testing.T) {
+func TestUpdateUserData(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -397,47 +603,123 @@ func TestGetUserData(t *testing.T) {
 	}
 	currentUserID := "some_id"
 	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
-	stmt := `
-		SELECT data, mark, description FROM user_data 
-		WHERE user_id = $1 AND id = $2 AND type = $3 LIMIT 1
+	const stmt = `
+		WITH old AS (
+			SELECT id, user_id, version, data, mark, description FROM user_data
+			WHERE user_id = $4 AND id = $5 AND type = $6 AND deleted_at IS NULL AND ($7 = 0 OR version = $7) FOR UPDATE
+		), history AS (
+			INSERT INTO user_data_history (data_id, user_id, version, data, mark, description)
+			SELECT id, user_id, version, data, mark, description FROM old WHERE $6 <> 'file'
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $4 AND EXISTS (SELECT 1 FROM old) RETURNING data_revision
+		)
+		UPDATE user_data SET data = $1, mark = $2, description = $3, object_missing_at = NULL,
+			version = version + 1, revision = (SELECT data_revision FROM rev), updated_at = now()
+		WHERE user_id = $4 AND id = $5 AND type = $6 AND deleted_at IS NULL AND ($7 = 0 OR version = $7)
+	`
+	const existsQuery = `
+		SELECT EXISTS(SELECT 1 FROM user_data WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL)
 	`
 
-	row := mocks.NewMockRow(mockCtrl)
 	userDataID := 1
-	dataType := "files"
+	encData := []byte("some data")
+	mark := "test"
+	description := "test"
+	dataType := "password"
+	row := mocks.NewMockRow(mockCtrl)
+
+	folder := "work"
 
 	tests := []struct {
-		name    string
-		wantErr bool
-		errText string
-		rowErr  error
+		labels      models.UserDataLabels
+		name        string
+		tag         pgconn.CommandTag
+		execErr     error
+		version     int
+		existsCount int
+		labelsCount int
+		exists      bool
+		wantErr     bool
+		errText     string
 	}{
 		{
-			name:    "success get",
+			name:    "success update user data",
+			tag:     pgconn.NewCommandTag("UPDATE 1"),
+			execErr: nil,
 			wantErr: false,
 			errText: "",
-			rowErr:  nil,
 		},
 		{
-			name:    "failed read row",
-			wantErr: true,
-			errText: "failed to scan a response row",
-			rowErr:  errors.New("some error"),
+			name:    "success update user data with version",
+			tag:     pgconn.NewCommandTag("UPDATE 1"),
+			version: 2,
+			wantErr: false,
 		},
 		{
-			name:    "not found row",
+			name:        "success update user data with folder",
+			tag:         pgconn.NewCommandTag("UPDATE 1"),
+			labels:      models.UserDataLabels{Folder: &folder},
+			labelsCount: 1,
+			wantErr:     false,
+		},
+		{
+			name:    "when user data not found",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			execErr: nil,
 			wantErr: true,
 			errText: "user data not found",
-			rowErr:  pgx.ErrNoRows,
+		},
+		{
+			name:        "when user data with version not found",
+			tag:         pgconn.NewCommandTag("UPDATE 0"),
+			version:     2,
+			existsCount: 1,
+			exists:      false,
+			wantErr:     true,
+			errText:     "user data not found",
+		},
+		{
+			name:        "when user data version changed",
+			tag:         pgconn.NewCommandTag("UPDATE 0"),
+			version:     2,
+			existsCount: 1,
+			exists:      true,
+			wantErr:     true,
+			errText:     "user data version conflict",
+		},
+		{
+			name:    "failed update user data",
+			tag:     pgconn.CommandTag{},
+			execErr: errors.New("some error"),
+			wantErr: true,
+			errText: "failed to execute update user data query",
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pool.EXPECT().QueryRow(ctx, stmt, currentUserID, userDataID, dataType).Times(1).Return(row)
-
-			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+			tx := mocks.NewMockTx(mockCtrl)
+			pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+			tx.EXPECT().
+				Exec(ctx, stmt, encData, mark, description, currentUserID, userDataID, dataType, test.version).
+				Times(1).Return(test.tag, test.execErr)
+			tx.EXPECT().Exec(ctx, gomock.Any(), currentUserID, userDataID, folder).
+				Times(test.labelsCount).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+			tx.EXPECT().Exec(ctx, gomock.Any(), currentUserID).
+				Times(test.labelsCount).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+			if test.wantErr {
+				tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+			} else {
+				tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+			}
+			pool.EXPECT().QueryRow(ctx, existsQuery, currentUserID, userDataID, dataType).
+				Times(test.existsCount).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(test.existsCount).DoAndReturn(func(dest ...any) error {
+				*dest[0].(*bool) = test.exists
+				return nil
+			})
 
-			_, _, _, err := storage.GetUserData(ctx, userDataID, dataType)
+			err := storage.UpdateUserData(ctx, userDataID, test.version, encData, mark, description, dataType, test.labels)
 
 			if test.wantErr {
 				require.Error(t, err)
@@ -449,7 +731,7 @@ func TestGetUserData(t *testing.T) {
 	}
 }
 
-func TestGetFileUserData(t *testing.T) {
+func TestDeleteUserData(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -461,46 +743,58 @@ func TestGetFileUserData(t *testing.T) {
 	}
 	currentUserID := "some_id"
 	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
-	stmt := `
-		SELECT data FROM user_data 
-		WHERE user_id = $1 AND mark = $2 AND type = 'file' LIMIT 1
+	const stmt = `
+		WITH trashed AS (
+			SELECT id FROM user_data WHERE user_id = $1 AND id = $2 AND type = $3 AND deleted_at IS NULL FOR UPDATE
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM trashed) RETURNING data_revision
+		), tombstone AS (
+			INSERT INTO user_data_tombstones (user_id, data_id, revision)
+			SELECT $1, trashed.id, rev.data_revision FROM trashed, rev
+			ON CONFLICT (user_id, data_id) DO UPDATE SET revision = EXCLUDED.revision, deleted_at = now()
+		)
+		UPDATE user_data SET deleted_at = now(), revision = (SELECT data_revision FROM rev)
+		FROM trashed WHERE user_data.id = trashed.id
 	`
 
-	row := mocks.NewMockRow(mockCtrl)
-	fileMark := "test"
+	userDataID := 1
+	dataType := "card"
 
 	tests := []struct {
 		name    string
+		tag     pgconn.CommandTag
+		execErr error
 		wantErr bool
 		errText string
-		rowErr  error
 	}{
 		{
-			name:    "success get",
+			name:    "success delete user data",
+			tag:     pgconn.NewCommandTag("UPDATE 1"),
+			execErr: nil,
 			wantErr: false,
 			errText: "",
-			rowErr:  nil,
 		},
 		{
-			name:    "failed read row",
+			name:    "when user data not found",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			execErr: nil,
 			wantErr: true,
-			errText: "failed to scan a response row",
-			rowErr:  errors.New("some error"),
+			errText: "user data not found",
 		},
 		{
-			name:    "not found row",
+			name:    "failed delete user data",
+			tag:     pgconn.CommandTag{},
+			execErr: errors.New("some error"),
 			wantErr: true,
-			errText: "user data not found",
-			rowErr:  pgx.ErrNoRows,
+			errText: "failed to execute delete user data query",
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pool.EXPECT().QueryRow(ctx, stmt, currentUserID, fileMark).Times(1).Return(row)
-
-			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+			pool.EXPECT().Exec(ctx, stmt, currentUserID, userDataID, dataType).Times(1).Return(test.tag, test.execErr)
 
-			_, err := storage.GetFileUserData(ctx, fileMark)
+			err := storage.DeleteUserData(ctx, userDataID, dataType)
 
 			if test.wantErr {
 				require.Error(t, err)
@@ -511,3 +805,649 @@ func TestGetFileUserData(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchEncryptedUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	ctx := context.Background()
+	stmt := `SELECT id, user_id, data FROM user_data WHERE id > $1 ORDER BY id LIMIT $2`
+
+	rows := mocks.NewMockRows(mockCtrl)
+
+	t.Run("success fetch", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, stmt, 10, 100).Times(1).Return(rows, nil)
+
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(false)
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		data, err := storage.FetchEncryptedUserData(ctx, 10, 100)
+
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, stmt, 10, 100).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchEncryptedUserData(ctx, 10, 100)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestReplaceEncryptedUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	ctx := context.Background()
+	stmt := `UPDATE user_data SET data = $1 WHERE id = $2 AND data = $3`
+	oldData := []byte("old")
+	newData := []byte("new")
+
+	tests := []struct {
+		name     string
+		tag      pgconn.CommandTag
+		err      error
+		replaced bool
+		wantErr  bool
+	}{
+		{
+			name:     "success replace",
+			tag:      pgconn.NewCommandTag("UPDATE 1"),
+			err:      nil,
+			replaced: true,
+			wantErr:  false,
+		},
+		{
+			name:     "data changed concurrently",
+			tag:      pgconn.NewCommandTag("UPDATE 0"),
+			err:      nil,
+			replaced: false,
+			wantErr:  false,
+		},
+		{
+			name:     "failed replace",
+			tag:      pgconn.NewCommandTag(""),
+			err:      errors.New("some error"),
+			replaced: false,
+			wantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, stmt, newData, 1, oldData).Times(1).Return(test.tag, test.err)
+
+			replaced, err := storage.ReplaceEncryptedUserData(ctx, 1, oldData, newData)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, "failed to execute replace user data query")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.replaced, replaced)
+		})
+	}
+}
+
+func TestFetchUserIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+	stmt := `SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2`
+	rows := mocks.NewMockRows(mockCtrl)
+
+	t.Run("success fetch", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, stmt, 10, 100).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 11
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		ids, err := storage.FetchUserIDs(ctx, 10, 100)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{11}, ids)
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, stmt, 10, 100).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchUserIDs(ctx, 10, 100)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestFetchFileUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+
+	t.Run("success fetch", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			rows.EXPECT().Next().Times(1).Return(true),
+			rows.EXPECT().Next().Times(1).Return(false),
+		)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 7
+			*dest[1].(*[]byte) = []byte("data")
+			*dest[2].(*bool) = true
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		data, err := storage.FetchFileUserData(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.FileUserData{{ID: 7, Data: []byte("data"), ObjectMissing: true}}, data)
+	})
+
+	t.Run("failed scan", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(true)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.FetchFileUserData(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan query")
+	})
+}
+
+func TestSetFileUserDataMissing(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+
+	t.Run("success update", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, gomock.Any(), 7, 1, true).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		require.NoError(t, storage.SetFileUserDataMissing(ctx, 7, true))
+	})
+
+	t.Run("failed update", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, gomock.Any(), 7, 1, false).Times(1).
+			Return(pgconn.NewCommandTag(""), errors.New("some error"))
+
+		err := storage.SetFileUserDataMissing(ctx, 7, false)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute update file user data query")
+	})
+}
+
+func TestAddFileUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	encData := []byte("some data")
+
+	t.Run("success add file user data", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, encData, "mark", "desc", int64(100)).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 5
+			return nil
+		})
+		tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+
+		id, err := storage.AddFileUserData(ctx, encData, "mark", "desc", 100, models.UserDataLabels{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, id)
+	})
+
+	t.Run("failed read row", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, encData, "mark", "desc", int64(100)).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.AddFileUserData(ctx, encData, "mark", "desc", 100, models.UserDataLabels{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+
+	t.Run("failed commit transaction", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		row := mocks.NewMockRow(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().QueryRow(ctx, gomock.Any(), 1, encData, "mark", "desc", int64(100)).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(nil)
+		tx.EXPECT().Commit(ctx).Times(1).Return(errors.New("some error"))
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.AddFileUserData(ctx, encData, "mark", "desc", 100, models.UserDataLabels{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to commit transaction")
+	})
+}
+
+func TestUpdateFileUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	encData := []byte("some data")
+
+	tests := []struct {
+		name    string
+		tag     pgconn.CommandTag
+		execErr error
+		wantErr error
+	}{
+		{
+			name: "success update file user data",
+			tag:  pgconn.NewCommandTag("UPDATE 1"),
+		},
+		{
+			name:    "when user data not found",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			wantErr: ErrUserDataNotFound,
+		},
+		{
+			name:    "failed update file user data",
+			execErr: errors.New("some error"),
+			wantErr: errors.New("failed to execute update user data query"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, gomock.Any(), encData, "mark", "desc", int64(100), 1, 7).
+				Times(1).Return(test.tag, test.execErr)
+
+			err := storage.UpdateFileUserData(ctx, 7, encData, "mark", "desc", 100)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetUserUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	row := mocks.NewMockRow(mockCtrl)
+
+	t.Run("success get usage", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, gomock.Any(), 1).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 3
+			*dest[1].(*int64) = 1024
+			return nil
+		})
+
+		usage, err := storage.GetUserUsage(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserUsage{Items: 3, UsedBytes: 1024}, usage)
+	})
+
+	t.Run("failed read row", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, gomock.Any(), 1).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.GetUserUsage(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+}
+
+func TestGetUserKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	stmt := `SELECT data_key FROM user_keys WHERE user_id = $1`
+
+	row := mocks.NewMockRow(mockCtrl)
+
+	tests := []struct {
+		name    string
+		rowErr  error
+		wantErr error
+	}{
+		{
+			name:    "success get user key",
+			rowErr:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "user key not found",
+			rowErr:  pgx.ErrNoRows,
+			wantErr: ErrUserKeyNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, stmt, currentUserID).Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+
+			_, err := storage.GetUserKey(ctx)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAddUserKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	const stmt = `
+		INSERT INTO user_keys (user_id, data_key, key_id) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+	dataKey := []byte("wrapped key")
+	keyID := "key id"
+
+	t.Run("success add user key", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, stmt, currentUserID, dataKey, keyID).Times(1).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		err := storage.AddUserKey(ctx, dataKey, keyID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("failed add user key", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, stmt, currentUserID, dataKey, keyID).Times(1).
+			Return(pgconn.NewCommandTag(""), errors.New("some error"))
+
+		err := storage.AddUserKey(ctx, dataKey, keyID)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute add user key query")
+	})
+}
+
+func TestCountUserKeysToRotate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	ctx := context.Background()
+	stmt := `SELECT COUNT(*) FROM user_keys WHERE key_id <> $1`
+
+	row := mocks.NewMockRow(mockCtrl)
+
+	t.Run("failed count", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, stmt, "key id").Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.CountUserKeysToRotate(ctx, "key id")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan a response row")
+	})
+}
+
+func TestRotateUserKeys(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	ctx := context.Background()
+	const (
+		query = `
+			SELECT user_id, data_key FROM user_keys
+			WHERE key_id <> $1 ORDER BY user_id LIMIT $2 FOR UPDATE
+		`
+		stmt = `UPDATE user_keys SET data_key = $1, key_id = $2 WHERE user_id = $3`
+	)
+	keyID := "key id"
+	rewrap := func(dataKey []byte) ([]byte, error) {
+		if string(dataKey) == "bad" {
+			return nil, errors.New("some error")
+		}
+
+		return append([]byte("new "), dataKey...), nil
+	}
+
+	scanKey := func(userID int, dataKey string) func(dest ...any) error {
+		return func(dest ...any) error {
+			*dest[0].(*int) = userID
+			*dest[1].(*[]byte) = []byte(dataKey)
+			return nil
+		}
+	}
+
+	t.Run("success rotate", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		rows := mocks.NewMockRows(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().Query(ctx, query, keyID, 10).Times(1).Return(rows, nil)
+		gomock.InOrder(
+			rows.EXPECT().Next().Return(true),
+			rows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(scanKey(1, "old")),
+			rows.EXPECT().Next().Return(false),
+		)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Err().Times(1).Return(nil)
+		tx.EXPECT().Exec(ctx, stmt, []byte("new old"), keyID, 1).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+
+		n, err := storage.RotateUserKeys(ctx, keyID, 10, rewrap)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("rollback when rewrap failed", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+		rows := mocks.NewMockRows(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().Query(ctx, query, keyID, 10).Times(1).Return(rows, nil)
+		gomock.InOrder(
+			rows.EXPECT().Next().Return(true),
+			rows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(scanKey(2, "bad")),
+			rows.EXPECT().Next().Return(false),
+		)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Err().Times(1).Return(nil)
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.RotateUserKeys(ctx, keyID, 10, rewrap)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to rewrap key of user 2")
+	})
+
+	t.Run("failed begin transaction", func(t *testing.T) {
+		pool.EXPECT().Begin(ctx).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.RotateUserKeys(ctx, keyID, 10, rewrap)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to begin transaction")
+	})
+}
+
+func TestGetUserVault(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	stmt := `SELECT vault_salt, vault_check FROM users WHERE id = $1`
+
+	row := mocks.NewMockRow(mockCtrl)
+
+	tests := []struct {
+		name    string
+		rowErr  error
+		wantErr error
+	}{
+		{
+			name:    "success get user vault",
+			rowErr:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "user not found",
+			rowErr:  pgx.ErrNoRows,
+			wantErr: ErrUserNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, stmt, currentUserID).Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+
+			_, _, err := storage.GetUserVault(ctx)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetUserVaultCheck(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	logger := zap.NewNop()
+	storage := Storage{
+		pool:   pool,
+		logger: logger,
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	const stmt = `UPDATE users SET vault_check = $1 WHERE id = $2 AND vault_check IS NULL`
+	check := []byte("check")
+
+	t.Run("success set user vault check", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, stmt, check, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		ok, err := storage.SetUserVaultCheck(ctx, check)
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("when user vault check exist", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, stmt, check, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		ok, err := storage.SetUserVaultCheck(ctx, check)
+
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
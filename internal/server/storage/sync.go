@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/jackc/pgx/v5"
+)
+
+// FetchUserDataChanges получить изменения данных пользователя после курсора since.
+// Курсор - номер изменения данных пользователя: каждое добавление, изменение и удаление увеличивает его
+// под блокировкой строки пользователя, поэтому изменения с номером не больше курсора уже сохранены.
+// Если since равен 0, больше текущего курсора или меньше номера последней удаленной отметки об удалении,
+// возвращаются все данные пользователя без удалений.
+func (s *Storage) FetchUserDataChanges(ctx context.Context, since int64) (models.UserDataChanges, error) {
+	const cursorQuery = `SELECT data_revision, tombstones_pruned_revision FROM users WHERE id = $1`
+
+	changes := models.UserDataChanges{Changes: []models.UserData{}, Deleted: []int{}}
+	userID := ctx.Value(constants.KeyUserID)
+
+	var prunedRevision int64
+
+	if err := s.pool.QueryRow(ctx, cursorQuery, userID).Scan(&changes.Cursor, &prunedRevision); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return changes, ErrUserNotFound
+		}
+
+		return changes, fmt.Errorf(failedScanStr, err)
+	}
+
+	// Отметки об удалениях после такого курсора могли быть уже удалены, поэтому клиент получает все данные.
+	if since > changes.Cursor || since < prunedRevision {
+		since = 0
+	}
+	changes.Full = since == 0
+
+	data, secrets, err := s.fetchChangedUserData(ctx, since, changes.Cursor)
+	if err != nil {
+		return changes, err
+	}
+	changes.Changes = data
+	changes.Secrets = secrets
+
+	if changes.Full {
+		return changes, nil
+	}
+
+	deleted, err := s.fetchDeletedUserData(ctx, since, changes.Cursor)
+	if err != nil {
+		return changes, err
+	}
+	changes.Deleted = deleted
+
+	return changes, nil
+}
+
+// fetchChangedUserData получить данные пользователя, измененные после since и не позже cursor.
+// Данные возвращаются вместе с папками и метками, а для всех типов, кроме файлов, еще и зашифрованное
+// содержимое. Данные в корзине не возвращаются: при удалении в корзину сохраняется отметка об удалении.
+// Если since равен 0, возвращаются все данные, в том числе не менявшиеся с появления курсора.
+func (s *Storage) fetchChangedUserData(
+	ctx context.Context,
+	since, cursor int64,
+) ([]models.UserData, []models.Secret, error) {
+	const query = `
+		SELECT d.id, d.type, d.mark, d.description, d.version, COALESCE(f.path, ''), ARRAY(
+			SELECT t.name FROM user_data_tags dt JOIN tags t ON t.id = dt.tag_id WHERE dt.data_id = d.id ORDER BY t.name
+		), CASE WHEN d.type <> 'file' THEN d.data END
+		FROM user_data d LEFT JOIN folders f ON f.id = d.folder_id
+		WHERE d.user_id = $1 AND d.deleted_at IS NULL AND ($2 = 0 OR d.revision > $2) AND d.revision <= $3
+		ORDER BY d.revision, d.id
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID), since, cursor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	data := []models.UserData{}
+	secrets := []models.Secret{}
+
+	for rows.Next() {
+		var (
+			d       models.UserData
+			encData []byte
+		)
+		err := rows.Scan(&d.ID, &d.Type, &d.Mark, &d.Description, &d.Version, &d.Folder, &d.Tags, &encData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		data = append(data, d)
+		if encData != nil {
+			secrets = append(secrets, models.Secret{ID: d.ID, Data: encData, Mark: d.Mark, Description: d.Description})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return data, secrets, nil
+}
+
+// fetchDeletedUserData получить ID данных пользователя, удаленных после since и не позже cursor.
+func (s *Storage) fetchDeletedUserData(ctx context.Context, since, cursor int64) ([]int, error) {
+	const query = `
+		SELECT data_id FROM user_data_tombstones
+		WHERE user_id = $1 AND revision > $2 AND revision <= $3
+		ORDER BY revision
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID), since, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return ids, nil
+}
+
+// PruneUserDataTombstones удалить отметки об удалении данных пользователя, сделанные не позже before.
+// Номер последней удаленной отметки запоминается: клиенты с более ранним курсором получат все данные.
+// Возвращает количество удаленных отметок.
+func (s *Storage) PruneUserDataTombstones(ctx context.Context, before time.Time) (int, error) {
+	const query = `
+		WITH pruned AS (
+			DELETE FROM user_data_tombstones WHERE user_id = $1 AND deleted_at <= $2 RETURNING revision
+		), cursor AS (
+			UPDATE users
+			SET tombstones_pruned_revision = GREATEST(tombstones_pruned_revision, (SELECT MAX(revision) FROM pruned))
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM pruned)
+		)
+		SELECT COUNT(*) FROM pruned
+	`
+
+	var count int
+
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID), before)
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf(failedScanStr, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFetchUserDataChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	row := mocks.NewMockRow(mockCtrl)
+	dataRows := mocks.NewMockRows(mockCtrl)
+	deletedRows := mocks.NewMockRows(mockCtrl)
+
+	const cursorQuery = `SELECT data_revision, tombstones_pruned_revision FROM users WHERE id = $1`
+
+	expectPrunedCursor := func(cursor, pruned int64) {
+		pool.EXPECT().QueryRow(ctx, cursorQuery, 1).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int64) = cursor
+			*dest[1].(*int64) = pruned
+			return nil
+		})
+	}
+	expectCursor := func(cursor int64) {
+		expectPrunedCursor(cursor, 0)
+	}
+	expectData := func(since, cursor int64) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, since, cursor).Times(1).Return(dataRows, nil)
+		dataRows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			dataRows.EXPECT().Next().Times(1).Return(true),
+			dataRows.EXPECT().Next().Times(1).Return(false),
+		)
+		dataRows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 2
+			*dest[1].(*string) = "card"
+			*dest[2].(*string) = "mark"
+			*dest[3].(*string) = "description"
+			*dest[4].(*int) = 3
+			*dest[5].(*string) = "work/aws"
+			*dest[6].(*[]string) = []string{"prod"}
+			*dest[7].(*[]byte) = []byte("enc data")
+			return nil
+		})
+		dataRows.EXPECT().Err().Times(1).Return(nil)
+	}
+
+	t.Run("fetch changes after cursor", func(t *testing.T) {
+		expectCursor(10)
+		expectData(5, 10)
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, int64(5), int64(10)).Times(1).Return(deletedRows, nil)
+		deletedRows.EXPECT().Close().Times(1)
+		gomock.InOrder(
+			deletedRows.EXPECT().Next().Times(1).Return(true),
+			deletedRows.EXPECT().Next().Times(1).Return(false),
+		)
+		deletedRows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 7
+			return nil
+		})
+		deletedRows.EXPECT().Err().Times(1).Return(nil)
+
+		changes, err := storage.FetchUserDataChanges(ctx, 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserDataChanges{
+			Changes: []models.UserData{{
+				ID: 2, Type: "card", Mark: "mark", Description: "description", Version: 3,
+				Folder: "work/aws", Tags: []string{"prod"},
+			}},
+			Secrets: []models.Secret{{ID: 2, Data: []byte("enc data"), Mark: "mark", Description: "description"}},
+			Deleted: []int{7},
+			Cursor:  10,
+		}, changes)
+	})
+
+	t.Run("fetch all data without cursor", func(t *testing.T) {
+		expectCursor(10)
+		expectData(0, 10)
+
+		changes, err := storage.FetchUserDataChanges(ctx, 0)
+
+		require.NoError(t, err)
+		assert.True(t, changes.Full)
+		assert.Empty(t, changes.Deleted)
+		assert.Len(t, changes.Changes, 1)
+	})
+
+	t.Run("fetch all data when cursor is ahead", func(t *testing.T) {
+		expectCursor(10)
+		expectData(0, 10)
+
+		changes, err := storage.FetchUserDataChanges(ctx, 20)
+
+		require.NoError(t, err)
+		assert.True(t, changes.Full)
+		assert.Equal(t, int64(10), changes.Cursor)
+	})
+
+	t.Run("fetch all data when tombstones after cursor are pruned", func(t *testing.T) {
+		expectPrunedCursor(10, 6)
+		expectData(0, 10)
+
+		changes, err := storage.FetchUserDataChanges(ctx, 5)
+
+		require.NoError(t, err)
+		assert.True(t, changes.Full)
+		assert.Empty(t, changes.Deleted)
+	})
+
+	t.Run("when user not found", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, cursorQuery, 1).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(1).Return(pgx.ErrNoRows)
+
+		_, err := storage.FetchUserDataChanges(ctx, 5)
+
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("failed fetch changes", func(t *testing.T) {
+		expectCursor(10)
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, int64(5), int64(10)).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchUserDataChanges(ctx, 5)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestPruneUserDataTombstones(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	row := mocks.NewMockRow(mockCtrl)
+	before := time.Now()
+
+	t.Run("prune tombstones", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, gomock.Any(), 1, before).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 3
+			return nil
+		})
+
+		count, err := storage.PruneUserDataTombstones(ctx, before)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("failed prune tombstones", func(t *testing.T) {
+		pool.EXPECT().QueryRow(ctx, gomock.Any(), 1, before).Times(1).Return(row)
+		row.EXPECT().Scan(gomock.Any()).Times(1).Return(errors.New("some error"))
+
+		_, err := storage.PruneUserDataTombstones(ctx, before)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to scan")
+	})
+}
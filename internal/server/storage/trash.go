@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+)
+
+// FetchTrash получить данные пользователя в корзине, начиная с последних удаленных.
+func (s *Storage) FetchTrash(ctx context.Context) ([]models.TrashItem, error) {
+	const query = `
+		SELECT id, type, mark, description, deleted_at FROM user_data
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+
+	for rows.Next() {
+		var i models.TrashItem
+		if err := rows.Scan(&i.ID, &i.Type, &i.Mark, &i.Description, &i.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreUserData восстановить данные пользователя из корзины. Данные получают новый номер изменения,
+// а отметка об удалении удаляется, поэтому клиенты получат их при синхронизации как измененные.
+func (s *Storage) RestoreUserData(ctx context.Context, id int) error {
+	const stmt = `
+		WITH trashed AS (
+			SELECT id FROM user_data WHERE user_id = $1 AND id = $2 AND deleted_at IS NOT NULL FOR UPDATE
+		), rev AS (
+			UPDATE users SET data_revision = data_revision + 1
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM trashed) RETURNING data_revision
+		), tombstone AS (
+			DELETE FROM user_data_tombstones WHERE user_id = $1 AND data_id IN (SELECT id FROM trashed)
+		)
+		UPDATE user_data SET deleted_at = NULL, revision = (SELECT data_revision FROM rev)
+		FROM trashed WHERE user_data.id = trashed.id
+	`
+
+	tag, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), id)
+	if err != nil {
+		return fmt.Errorf("failed to execute restore user data query: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserDataNotFound
+	}
+
+	return nil
+}
+
+// FetchTrashedUserData получить зашифрованные данные пользователя, удаленные в корзину не позже before.
+func (s *Storage) FetchTrashedUserData(ctx context.Context, before time.Time) ([]models.TrashedUserData, error) {
+	const query = `
+		SELECT id, type, data FROM user_data
+		WHERE user_id = $1 AND deleted_at IS NOT NULL AND deleted_at <= $2
+		ORDER BY id
+	`
+
+	rows, err := s.pool.Query(ctx, query, ctx.Value(constants.KeyUserID), before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	data := []models.TrashedUserData{}
+
+	for rows.Next() {
+		var d models.TrashedUserData
+		if err := rows.Scan(&d.ID, &d.Type, &d.Data); err != nil {
+			return nil, fmt.Errorf("failed to scan query: %w", err)
+		}
+
+		data = append(data, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	return data, nil
+}
+
+// PurgeUserData окончательно удалить данные пользователя, удаленные в корзину не позже before,
+// вместе с их историей. Возвращает false, если данных нет в корзине, например, их уже восстановили.
+func (s *Storage) PurgeUserData(ctx context.Context, id int, before time.Time) (bool, error) {
+	const stmt = `
+		DELETE FROM user_data
+		WHERE user_id = $1 AND id = $2 AND deleted_at IS NOT NULL AND deleted_at <= $3
+	`
+
+	tag, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), id, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute purge user data query: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFetchTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("fetch trash", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(true)
+		rows.EXPECT().Next().Times(1).Return(false)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 2
+			*dest[1].(*string) = "card"
+			*dest[2].(*string) = "mark"
+			*dest[3].(*string) = "description"
+			*dest[4].(*time.Time) = deletedAt
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		items, err := storage.FetchTrash(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.TrashItem{
+			{ID: 2, Type: "card", Mark: "mark", Description: "description", DeletedAt: deletedAt},
+		}, items)
+	})
+
+	t.Run("failed fetch trash", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchTrash(ctx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestRestoreUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+
+	tests := []struct {
+		name    string
+		tag     pgconn.CommandTag
+		execErr error
+		wantErr error
+		errText string
+	}{
+		{
+			name: "restore user data",
+			tag:  pgconn.NewCommandTag("UPDATE 1"),
+		},
+		{
+			name:    "when user data not in trash",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			wantErr: ErrUserDataNotFound,
+		},
+		{
+			name:    "failed restore user data",
+			execErr: errors.New("some error"),
+			errText: "failed to execute restore user data query",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, gomock.Any(), 1, 2).Times(1).Return(test.tag, test.execErr)
+
+			err := storage.RestoreUserData(ctx, 2)
+
+			switch {
+			case test.wantErr != nil:
+				require.ErrorIs(t, err, test.wantErr)
+			case test.errText != "":
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFetchTrashedUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	rows := mocks.NewMockRows(mockCtrl)
+	before := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("fetch trashed user data", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, before).Times(1).Return(rows, nil)
+		rows.EXPECT().Close().Times(1)
+		rows.EXPECT().Next().Times(1).Return(true)
+		rows.EXPECT().Next().Times(1).Return(false)
+		rows.EXPECT().Scan(gomock.Any()).Times(1).DoAndReturn(func(dest ...any) error {
+			*dest[0].(*int) = 2
+			*dest[1].(*string) = "file"
+			*dest[2].(*[]byte) = []byte("data")
+			return nil
+		})
+		rows.EXPECT().Err().Times(1).Return(nil)
+
+		data, err := storage.FetchTrashedUserData(ctx, before)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.TrashedUserData{{ID: 2, Type: "file", Data: []byte("data")}}, data)
+	})
+
+	t.Run("failed fetch trashed user data", func(t *testing.T) {
+		pool.EXPECT().Query(ctx, gomock.Any(), 1, before).Times(1).Return(nil, errors.New("some error"))
+
+		_, err := storage.FetchTrashedUserData(ctx, before)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute query")
+	})
+}
+
+func TestPurgeUserData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, 1)
+	before := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("purge user data", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, gomock.Any(), 1, 2, before).Times(1).Return(pgconn.NewCommandTag("DELETE 1"), nil)
+
+		deleted, err := storage.PurgeUserData(ctx, 2, before)
+
+		require.NoError(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("when user data restored", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, gomock.Any(), 1, 2, before).Times(1).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+		deleted, err := storage.PurgeUserData(ctx, 2, before)
+
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("failed purge user data", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, gomock.Any(), 1, 2, before).Times(1).
+			Return(pgconn.CommandTag{}, errors.New("some error"))
+
+		_, err := storage.PurgeUserData(ctx, 2, before)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute purge user data query")
+	})
+}
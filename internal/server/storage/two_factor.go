@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/models"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// GetUserTwoFactor получить состояние двухфакторной аутентификации пользователя.
+func (s *Storage) GetUserTwoFactor(ctx context.Context) (models.TwoFactor, error) {
+	const query = `SELECT totp_secret, totp_enabled, totp_last_step FROM users WHERE id = $1`
+
+	row := s.pool.QueryRow(ctx, query, ctx.Value(constants.KeyUserID))
+
+	var tf models.TwoFactor
+
+	err := row.Scan(&tf.Secret, &tf.Enabled, &tf.LastStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.TwoFactor{}, ErrUserNotFound
+		}
+
+		return models.TwoFactor{}, fmt.Errorf(failedScanStr, err)
+	}
+
+	return tf, nil
+}
+
+// SetUserTwoFactorSecret сохранить новый секрет, если двухфакторная аутентификация еще не включена.
+func (s *Storage) SetUserTwoFactorSecret(ctx context.Context, secret []byte) (bool, error) {
+	const stmt = `UPDATE users SET totp_secret = $1 WHERE id = $2 AND NOT totp_enabled`
+
+	tag, err := s.pool.Exec(ctx, stmt, secret, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return false, fmt.Errorf("failed to execute set totp secret query: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// EnableUserTwoFactor включить двухфакторную аутентификацию и заменить коды восстановления.
+func (s *Storage) EnableUserTwoFactor(
+	ctx context.Context,
+	step int64,
+	recoveryCodeHashes [][]byte,
+) (ok bool, err error) {
+	const (
+		enableStmt = `
+			UPDATE users SET totp_enabled = true, totp_last_step = $1
+			WHERE id = $2 AND NOT totp_enabled AND totp_secret IS NOT NULL
+		`
+		deleteStmt = `DELETE FROM recovery_codes WHERE user_id = $1`
+		insertStmt = `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+	)
+
+	userID := ctx.Value(constants.KeyUserID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil || !ok {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("failed to rollback transaction", zap.Error(rbErr))
+			}
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, enableStmt, step, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute enable totp query: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err = tx.Exec(ctx, deleteStmt, userID); err != nil {
+		return false, fmt.Errorf("failed to execute delete recovery codes query: %w", err)
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		if _, err = tx.Exec(ctx, insertStmt, userID, hash); err != nil {
+			return false, fmt.Errorf("failed to execute add recovery code query: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// UpdateUserTwoFactorStep запомнить шаг использованного одноразового пароля.
+// Возвращает false, если пароль этого или более позднего шага уже использовался.
+func (s *Storage) UpdateUserTwoFactorStep(ctx context.Context, step int64) (bool, error) {
+	const stmt = `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1`
+
+	tag, err := s.pool.Exec(ctx, stmt, step, ctx.Value(constants.KeyUserID))
+	if err != nil {
+		return false, fmt.Errorf("failed to execute update totp step query: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// UseRecoveryCode отметить код восстановления использованным.
+func (s *Storage) UseRecoveryCode(ctx context.Context, codeHash []byte) error {
+	const stmt = `
+		UPDATE recovery_codes SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	tag, err := s.pool.Exec(ctx, stmt, ctx.Value(constants.KeyUserID), codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to execute use recovery code query: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRecoveryCodeUsed
+	}
+
+	return nil
+}
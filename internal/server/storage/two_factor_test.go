@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/constants"
+	"github.com/MihailSergeenkov/GophKeeper/internal/server/storage/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetUserTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	stmt := `SELECT totp_secret, totp_enabled, totp_last_step FROM users WHERE id = $1`
+
+	row := mocks.NewMockRow(mockCtrl)
+
+	tests := []struct {
+		name    string
+		rowErr  error
+		wantErr error
+	}{
+		{
+			name:    "success get two factor",
+			rowErr:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "user not found",
+			rowErr:  pgx.ErrNoRows,
+			wantErr: ErrUserNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().QueryRow(ctx, stmt, currentUserID).Times(1).Return(row)
+			row.EXPECT().Scan(gomock.Any()).Times(1).Return(test.rowErr)
+
+			_, err := storage.GetUserTwoFactor(ctx)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetUserTwoFactorSecret(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	stmt := `UPDATE users SET totp_secret = $1 WHERE id = $2 AND NOT totp_enabled`
+	secret := []byte("secret")
+
+	tests := []struct {
+		name    string
+		tag     pgconn.CommandTag
+		execErr error
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "secret set",
+			tag:     pgconn.NewCommandTag("UPDATE 1"),
+			execErr: nil,
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:    "two factor already enabled",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			execErr: nil,
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:    "failed set secret",
+			tag:     pgconn.CommandTag{},
+			execErr: errors.New("some error"),
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, stmt, secret, currentUserID).Times(1).Return(test.tag, test.execErr)
+
+			ok, err := storage.SetUserTwoFactorSecret(ctx, secret)
+
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.want, ok)
+		})
+	}
+}
+
+func TestEnableUserTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	hashes := [][]byte{[]byte("hash1"), []byte("hash2")}
+	var step int64 = 100
+
+	t.Run("success enable", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), step, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.EXPECT().Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, currentUserID).
+			Times(1).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+		tx.EXPECT().
+			Exec(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, currentUserID, gomock.Any()).
+			Times(len(hashes)).Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+		tx.EXPECT().Commit(ctx).Times(1).Return(nil)
+
+		ok, err := storage.EnableUserTwoFactor(ctx, step, hashes)
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), step, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		ok, err := storage.EnableUserTwoFactor(ctx, step, hashes)
+
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("failed add recovery code", func(t *testing.T) {
+		tx := mocks.NewMockTx(mockCtrl)
+
+		pool.EXPECT().Begin(ctx).Times(1).Return(tx, nil)
+		tx.EXPECT().Exec(ctx, gomock.Any(), step, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.EXPECT().Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, currentUserID).
+			Times(1).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+		tx.EXPECT().
+			Exec(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, currentUserID, gomock.Any()).
+			Times(1).Return(pgconn.CommandTag{}, errors.New("some error"))
+		tx.EXPECT().Rollback(ctx).Times(1).Return(nil)
+
+		_, err := storage.EnableUserTwoFactor(ctx, step, hashes)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to execute add recovery code query")
+	})
+}
+
+func TestUpdateUserTwoFactorStep(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	stmt := `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1`
+	var step int64 = 100
+
+	t.Run("step updated", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, stmt, step, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		ok, err := storage.UpdateUserTwoFactorStep(ctx, step)
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("step already used", func(t *testing.T) {
+		pool.EXPECT().Exec(ctx, stmt, step, currentUserID).Times(1).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		ok, err := storage.UpdateUserTwoFactorStep(ctx, step)
+
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pool := mocks.NewMockDBPooler(mockCtrl)
+	storage := Storage{
+		pool:   pool,
+		logger: zap.NewNop(),
+	}
+	currentUserID := 1
+	ctx := context.WithValue(context.Background(), constants.KeyUserID, currentUserID)
+	hash := []byte("hash")
+
+	tests := []struct {
+		name    string
+		tag     pgconn.CommandTag
+		execErr error
+		wantErr bool
+		errText string
+	}{
+		{
+			name:    "success use recovery code",
+			tag:     pgconn.NewCommandTag("UPDATE 1"),
+			execErr: nil,
+			wantErr: false,
+			errText: "",
+		},
+		{
+			name:    "recovery code already used",
+			tag:     pgconn.NewCommandTag("UPDATE 0"),
+			execErr: nil,
+			wantErr: true,
+			errText: "recovery code not found or already used",
+		},
+		{
+			name:    "failed use recovery code",
+			tag:     pgconn.CommandTag{},
+			execErr: errors.New("some error"),
+			wantErr: true,
+			errText: "failed to execute use recovery code query",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool.EXPECT().Exec(ctx, gomock.Any(), currentUserID, hash).Times(1).Return(test.tag, test.execErr)
+
+			err := storage.UseRecoveryCode(ctx, hash)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.errText)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
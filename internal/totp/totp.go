@@ -0,0 +1,264 @@
+// Package totp реализует одноразовые пароли по времени (RFC 6238).
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // SHA1 используется HMAC в RFC 6238 и поддерживается всеми приложениями
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметры одноразовых паролей, совместимые с приложениями-аутентификаторами.
+const (
+	Period     = 30
+	Digits     = 6
+	SecretSize = 20
+
+	// skew допустимое расхождение часов в шагах.
+	skew = 1
+
+	minDigits = 6
+	maxDigits = 8
+	maxPeriod = 3600
+)
+
+// Алгоритмы HMAC, поддерживаемые приложениями-аутентификаторами.
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+)
+
+var (
+	ErrInvalidSecret    = errors.New("invalid totp secret")
+	ErrInvalidAlgorithm = errors.New("invalid totp algorithm")
+	ErrInvalidDigits    = errors.New("invalid totp digits")
+	ErrInvalidPeriod    = errors.New("invalid totp period")
+	ErrInvalidURI       = errors.New("invalid otpauth uri")
+)
+
+// Options тип для параметров генерации одноразовых паролей.
+type Options struct {
+	Algorithm string
+	Digits    int
+	Period    int
+}
+
+// Key тип для ключа из ссылки otpauth://.
+type Key struct {
+	Issuer  string
+	Account string
+	Secret  []byte
+	Options
+}
+
+// DefaultOptions функция для получения параметров по умолчанию.
+func DefaultOptions() Options {
+	return Options{
+		Algorithm: AlgorithmSHA1,
+		Digits:    Digits,
+		Period:    Period,
+	}
+}
+
+// Validate функция для проверки параметров генерации одноразовых паролей.
+func (o Options) Validate() error {
+	if _, err := hashFunc(o.Algorithm); err != nil {
+		return err
+	}
+	if o.Digits < minDigits || o.Digits > maxDigits {
+		return ErrInvalidDigits
+	}
+	if o.Period <= 0 || o.Period > maxPeriod {
+		return ErrInvalidPeriod
+	}
+
+	return nil
+}
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret функция для создания случайного секрета.
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// EncodeSecret функция для представления секрета в base32, как его показывают пользователю.
+func EncodeSecret(secret []byte) string {
+	return encoding.EncodeToString(secret)
+}
+
+// DecodeSecret функция для разбора секрета в base32, пробелы, регистр и дополнение не учитываются.
+func DecodeSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	s = strings.TrimRight(s, "=")
+
+	secret, err := encoding.DecodeString(s)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrInvalidSecret
+	}
+
+	return secret, nil
+}
+
+// Step функция для получения номера шага для момента времени.
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Code функция для получения одноразового пароля для момента времени.
+func Code(secret []byte, t time.Time) string {
+	return code(sha1.New, secret, Step(t), Digits)
+}
+
+// Generate функция для получения одноразового пароля для момента времени с заданными параметрами.
+func Generate(secret []byte, t time.Time, opts Options) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
+	h, _ := hashFunc(opts.Algorithm)
+
+	return code(h, secret, t.Unix()/int64(opts.Period), opts.Digits), nil
+}
+
+// Remaining функция для получения времени действия одноразового пароля в секундах.
+func Remaining(t time.Time, period int) int {
+	return period - int(t.Unix()%int64(period))
+}
+
+// Validate функция для проверки одноразового пароля с учетом расхождения часов.
+// Возвращает шаг, которому соответствует пароль, чтобы его нельзя было использовать повторно.
+func Validate(secret []byte, passcode string, t time.Time) (int64, bool) {
+	passcode = strings.ReplaceAll(passcode, " ", "")
+	if len(passcode) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for step := current - skew; step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(code(sha1.New, secret, step, Digits)), []byte(passcode)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI функция для получения ссылки otpauth:// для приложений-аутентификаторов.
+func URI(issuer, account string, secret []byte) string {
+	params := url.Values{}
+	params.Set("secret", EncodeSecret(secret))
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(Period))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: params.Encode(),
+	}
+
+	return u.String()
+}
+
+// ParseURI функция для разбора ссылки otpauth:// из QR-кода приложения-аутентификатора.
+// Не указанные параметры принимают значения по умолчанию.
+func ParseURI(raw string) (Key, error) {
+	key := Key{Options: DefaultOptions()}
+
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme != "otpauth" || u.Host != "totp" {
+		return key, ErrInvalidURI
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		key.Issuer = strings.TrimSpace(issuer)
+		key.Account = strings.TrimSpace(account)
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+
+	params := u.Query()
+	if issuer := params.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+
+	key.Secret, err = DecodeSecret(params.Get("secret"))
+	if err != nil {
+		return key, err
+	}
+
+	if algorithm := params.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+	}
+	if digits := params.Get("digits"); digits != "" {
+		if key.Digits, err = strconv.Atoi(digits); err != nil {
+			return key, ErrInvalidDigits
+		}
+	}
+	if period := params.Get("period"); period != "" {
+		if key.Period, err = strconv.Atoi(period); err != nil {
+			return key, ErrInvalidPeriod
+		}
+	}
+
+	if err := key.Validate(); err != nil {
+		return key, err
+	}
+
+	return key, nil
+}
+
+// hashFunc возвращает хэш-функцию HMAC по названию алгоритма.
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case AlgorithmSHA1:
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, ErrInvalidAlgorithm
+	}
+}
+
+// code вычисляет одноразовый пароль по RFC 4226 для счетчика.
+func code(h func() hash.Hash, secret []byte, counter int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(h, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,214 @@
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret секрет из тестовых векторов RFC 6238 для SHA1.
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			assert.Equal(t, test.want, Code(rfcSecret, time.Unix(test.unix, 0)))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	t.Run("valid code", func(t *testing.T) {
+		step, ok := Validate(rfcSecret, "005924", now)
+
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+	})
+
+	t.Run("valid code from previous step", func(t *testing.T) {
+		prev := Code(rfcSecret, now.Add(-Period*time.Second))
+
+		step, ok := Validate(rfcSecret, prev, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, Step(now)-1, step)
+	})
+
+	t.Run("code too old", func(t *testing.T) {
+		old := Code(rfcSecret, now.Add(-2*Period*time.Second))
+
+		_, ok := Validate(rfcSecret, old, now)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		_, ok := Validate(rfcSecret, "59240", now)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestSecretEncoding(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, SecretSize)
+
+	encoded := EncodeSecret(secret)
+	assert.NotContains(t, encoded, "=")
+
+	decoded, err := DecodeSecret(" " + encoded[:4] + " " + encoded[4:] + "==")
+	require.NoError(t, err)
+	assert.Equal(t, secret, decoded)
+
+	_, err = DecodeSecret("not base32!")
+	require.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("GophKeeper", "user", rfcSecret)
+
+	assert.Equal(t,
+		"otpauth://totp/GophKeeper:user?algorithm=SHA1&digits=6&issuer=GophKeeper&period=30"+
+			"&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		uri)
+}
+
+func TestGenerate(t *testing.T) {
+	sha256Secret := []byte("12345678901234567890123456789012")
+	sha512Secret := []byte("1234567890123456789012345678901234567890123456789012345678901234")
+
+	tests := []struct {
+		name   string
+		secret []byte
+		opts   Options
+		unix   int64
+		want   string
+	}{
+		{
+			name:   "sha1",
+			secret: rfcSecret,
+			opts:   Options{Algorithm: AlgorithmSHA1, Digits: 8, Period: Period},
+			unix:   1111111109,
+			want:   "07081804",
+		},
+		{
+			name:   "sha256",
+			secret: sha256Secret,
+			opts:   Options{Algorithm: AlgorithmSHA256, Digits: 8, Period: Period},
+			unix:   1111111109,
+			want:   "68084774",
+		},
+		{
+			name:   "sha512",
+			secret: sha512Secret,
+			opts:   Options{Algorithm: AlgorithmSHA512, Digits: 8, Period: Period},
+			unix:   59,
+			want:   "90693936",
+		},
+		{
+			name:   "default options",
+			secret: rfcSecret,
+			opts:   DefaultOptions(),
+			unix:   1234567890,
+			want:   "005924",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Generate(test.secret, time.Unix(test.unix, 0), test.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := Generate(rfcSecret, time.Unix(59, 0), Options{Algorithm: "MD5", Digits: 6, Period: 30})
+		require.ErrorIs(t, err, ErrInvalidAlgorithm)
+
+		_, err = Generate(rfcSecret, time.Unix(59, 0), Options{Algorithm: AlgorithmSHA1, Digits: 4, Period: 30})
+		require.ErrorIs(t, err, ErrInvalidDigits)
+
+		_, err = Generate(rfcSecret, time.Unix(59, 0), Options{Algorithm: AlgorithmSHA1, Digits: 6, Period: 0})
+		require.ErrorIs(t, err, ErrInvalidPeriod)
+	})
+}
+
+func TestRemaining(t *testing.T) {
+	assert.Equal(t, 1, Remaining(time.Unix(59, 0), 30))
+	assert.Equal(t, 30, Remaining(time.Unix(60, 0), 30))
+	assert.Equal(t, 45, Remaining(time.Unix(75, 0), 60))
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    Key
+		wantErr error
+	}{
+		{
+			name: "uri generated by server",
+			uri:  URI("GophKeeper", "user", rfcSecret),
+			want: Key{Issuer: "GophKeeper", Account: "user", Secret: rfcSecret, Options: DefaultOptions()},
+		},
+		{
+			name: "uri with custom options",
+			uri:  "otpauth://totp/ACME%20Co:john@example.com?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&algorithm=sha256&digits=8&period=60",
+			want: Key{
+				Issuer:  "ACME Co",
+				Account: "john@example.com",
+				Secret:  rfcSecret,
+				Options: Options{Algorithm: AlgorithmSHA256, Digits: 8, Period: 60},
+			},
+		},
+		{
+			name: "uri without issuer in label",
+			uri:  "otpauth://totp/deploy-bot?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=GitHub",
+			want: Key{Issuer: "GitHub", Account: "deploy-bot", Secret: rfcSecret, Options: DefaultOptions()},
+		},
+		{
+			name:    "hotp uri",
+			uri:     "otpauth://hotp/user?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&counter=1",
+			wantErr: ErrInvalidURI,
+		},
+		{
+			name:    "uri without secret",
+			uri:     "otpauth://totp/user",
+			wantErr: ErrInvalidSecret,
+		},
+		{
+			name:    "uri with bad digits",
+			uri:     "otpauth://totp/user?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=six",
+			wantErr: ErrInvalidDigits,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseURI(test.uri)
+
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}